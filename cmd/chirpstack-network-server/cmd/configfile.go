@@ -340,6 +340,11 @@ get_downlink_data_delay="{{ .NetworkServer.GetDownlinkDataDelay }}"
   # The other channels (or channel / data-rate changes) will be (re)configured
   # using the NewChannelReq mac-command.
   #
+  # The optional downlink_frequency (Hz) sets the RX1 downlink frequency of
+  # the channel, which will be configured using the DlChannelReq mac-command
+  # (LoRaWAN 1.0.1+). When not set, the RX1 frequency equals the uplink
+  # frequency.
+  #
   # Example:
   # [[network_server.network_settings.extra_channels]]
   # frequency=867100000
@@ -365,11 +370,13 @@ get_downlink_data_delay="{{ .NetworkServer.GetDownlinkDataDelay }}"
   # frequency=867900000
   # min_dr=0
   # max_dr=5
+  # downlink_frequency=869525000
 {{ range $index, $element := .NetworkServer.NetworkSettings.ExtraChannels }}
   [[network_server.network_settings.extra_channels]]
   frequency={{ $element.Frequency }}
   min_dr={{ $element.MinDR }}
   max_dr={{ $element.MaxDR }}
+  downlink_frequency={{ $element.DownlinkFrequency }}
 {{ end }}
 
   # Class B settings
@@ -383,6 +390,42 @@ get_downlink_data_delay="{{ .NetworkServer.GetDownlinkDataDelay }}"
   # (which could be frequency hopping).
   ping_slot_frequency={{ .NetworkServer.NetworkSettings.ClassB.PingSlotFrequency }}
 
+    # Class-B beacon settings.
+    #
+    # When enabled, ChirpStack Network Server schedules the Class-B beacons
+    # itself, instead of relying on the gateways to emit the beacons. The
+    # beacons are sent as GPS epoch timed downlinks every 128 seconds, thus
+    # the gateways must have a GPS time source. Disable beaconing in the
+    # packet-forwarder of these gateways.
+    #
+    # Important: Class-B beacons must be sent with an implicit header and
+    # without CRC. The gateway downlink API can not express this, therefore
+    # only use this feature with gateways (or gateway bridge / packet-forwarder
+    # versions) which send GPS epoch timed downlinks on the beacon frequency
+    # as beacons. Other gateways send these frames with explicit header and
+    # CRC, in which case Class-B devices will not lock on the beacon.
+    [network_server.network_settings.class_b.beacon]
+    enabled={{ .NetworkServer.NetworkSettings.ClassB.Beacon.Enabled }}
+
+    # Beacon frequency (Hz).
+    #
+    # Set this to 0 to use the default beacon frequency of the configured
+    # region (which could be frequency hopping).
+    frequency={{ .NetworkServer.NetworkSettings.ClassB.Beacon.Frequency }}
+
+    # Gateway specific info.
+    #
+    # Valid options are:
+    #  * gps    GPS coordinates of the gateway (InfoDesc 0)
+    #  * net_id NetID + GatewayID (InfoDesc 3)
+    info_desc="{{ .NetworkServer.NetworkSettings.ClassB.Beacon.InfoDesc }}"
+
+    # Gateway-profile IDs.
+    #
+    # When set, only the gateways using one of these gateway-profiles emit
+    # beacons. When empty, all online gateways emit beacons.
+    gateway_profile_ids=[{{ range $index, $element := .NetworkServer.NetworkSettings.ClassB.Beacon.GatewayProfileIDs }}{{ if $index }}, {{ end }}"{{ $element }}"{{ end }}]
+
 
   # Rejoin-request settings
   #
@@ -403,6 +446,66 @@ get_downlink_data_delay="{{ .NetworkServer.GetDownlinkDataDelay }}"
   # 15 = about 1 year
   max_time_n={{ .NetworkServer.NetworkSettings.RejoinRequest.MaxTimeN }}
 
+  # Device duty-cycle accounting
+  #
+  # The time-on-air of each uplink is accounted within a rolling window per
+  # device. When the total airtime within this window exceeds the max.
+  # duty-cycle of the device-profile, a DutyCycleReq mac-command is sent to
+  # the device.
+  # Device-profiles without max. duty-cycle are not accounted.
+  [network_server.network_settings.duty_cycle]
+  # Rolling window duration.
+  window="{{ .NetworkServer.NetworkSettings.DutyCycle.Window }}"
+
+
+  # Frame-log settings.
+  #
+  # Besides publishing the uplink and downlink frames to the live frame-log
+  # streams, ChirpStack Network Server can keep a (capped) history of these
+  # frames per gateway and device.
+  [network_server.frame_log]
+  # Max. history length.
+  #
+  # This defines the (approximate) max. number of frames that are kept per
+  # gateway and device. Set this to 0 to disable the frame-log history.
+  # Note that when enabled, each frame is also written to the history.
+  history_max_len={{ .NetworkServer.FrameLog.HistoryMaxLen }}
+
+  # History TTL.
+  #
+  # The history of a gateway or device expires after no new frames have been
+  # logged within this duration.
+  history_ttl="{{ .NetworkServer.FrameLog.HistoryTTL }}"
+
+  # Stream replay count.
+  #
+  # The number of frames from the history that are sent when opening a
+  # frame-log stream, before the live frames are sent. This requires the
+  # frame-log history to be enabled.
+  stream_replay_count={{ .NetworkServer.FrameLog.StreamReplayCount }}
+
+
+  # Device-activation settings
+  #
+  # Each (re)join creates a device-activation record. For LoRaWAN 1.0 devices,
+  # these records are used to reject join-requests re-using a dev-nonce. For
+  # LoRaWAN 1.1 devices, the dev-nonce must be greater than the last used
+  # dev-nonce, which is stored separately.
+  [network_server.device_activation]
+  # Retention.
+  #
+  # Device-activations older than this duration are removed. The most recent
+  # device-activation of each device is always kept. Please note that pruning
+  # allows LoRaWAN 1.0 devices to re-use the dev-nonces of the removed
+  # device-activations. Set this to 0 to keep all device-activations.
+  retention="{{ .NetworkServer.DeviceActivation.Retention }}"
+
+  # Prune interval.
+  #
+  # The interval in which the expired device-activations are removed. This
+  # must be > 0 when the retention is set.
+  prune_interval="{{ .NetworkServer.DeviceActivation.PruneInterval }}"
+
 
   # Scheduler settings
   #
@@ -415,6 +518,25 @@ get_downlink_data_delay="{{ .NetworkServer.GetDownlinkDataDelay }}"
   # Class-C runs.
   scheduler_interval="{{ .NetworkServer.Scheduler.SchedulerInterval }}"
 
+    # Leader election settings.
+    #
+    # By default, every network-server instance runs the schedulers. When
+    # enabled, the instances elect a single active scheduler instance using a
+    # lease stored in Redis. When the active instance stops or fails to renew
+    # its lease, one of the other instances takes over.
+    [network_server.scheduler.leader_election]
+    # Enable leader election.
+    enabled={{ .NetworkServer.Scheduler.LeaderElection.Enabled }}
+
+    # Lease duration.
+    #
+    # The lease is renewed on every scheduler run and during long running
+    # batches, therefore this must be (much) larger than the
+    # scheduler_interval. This is also the maximum time it takes before an
+    # other instance takes over when the active instance disappears without
+    # releasing its lease.
+    lease_duration="{{ .NetworkServer.Scheduler.LeaderElection.LeaseDuration }}"
+
     # Class-C settings.
     [network_server.scheduler.class_c]
     # Downlink lock duration
@@ -466,6 +588,61 @@ get_downlink_data_delay="{{ .NetworkServer.GetDownlinkDataDelay }}"
   client_cert_lifetime="{{ .NetworkServer.Gateway.ClientCertLifetime }}"
 
 
+  # Downlink airtime budget.
+  #
+  # When enabled, ChirpStack Network Server keeps track of the downlink airtime
+  # per gateway and sub-band within a rolling window. Gateways that exceed the
+  # max. duty-cycle of a sub-band are deprioritized when selecting the gateway
+  # for a downlink.
+  [network_server.gateway.downlink_airtime]
+  enabled={{ .NetworkServer.Gateway.DownlinkAirtime.Enabled }}
+
+  # Rolling window duration.
+  window="{{ .NetworkServer.Gateway.DownlinkAirtime.Window }}"
+
+  # Sub-bands.
+  #
+  # The max. duty-cycle is expressed in percent. The min. frequency is
+  # inclusive, the max. frequency is exclusive. When no sub-bands are
+  # configured and the EU868 band is used, the ETSI EN 300 220 sub-bands
+  # are used.
+  #
+  # Example:
+  # [[network_server.gateway.downlink_airtime.sub_bands]]
+  # min_frequency=869400000
+  # max_frequency=869650000
+  # max_duty_cycle=10
+{{ range $index, $element := .NetworkServer.Gateway.DownlinkAirtime.SubBands }}
+  [[network_server.gateway.downlink_airtime.sub_bands]]
+  min_frequency={{ $element.MinFrequency }}
+  max_frequency={{ $element.MaxFrequency }}
+  max_duty_cycle={{ $element.MaxDutyCycle }}
+{{ end }}
+
+  # Gateway offline detection.
+  #
+  # When enabled, gateways that did not send stats for the given number of
+  # stats intervals are flagged as offline. The stats interval is taken from
+  # the gateway-profile. On each offline / online transition, a status event
+  # is published to the integration and (when configured) to the webhook.
+  [network_server.gateway.offline_detection]
+  enabled={{ .NetworkServer.Gateway.OfflineDetection.Enabled }}
+
+  # Interval in which the gateway states are checked (must be > 0).
+  interval="{{ .NetworkServer.Gateway.OfflineDetection.Interval }}"
+
+  # Number of missed stats intervals after which a gateway is offline.
+  missed_stats_intervals={{ .NetworkServer.Gateway.OfflineDetection.MissedStatsIntervals }}
+
+  # Stats interval used for gateways without gateway-profile.
+  default_stats_interval="{{ .NetworkServer.Gateway.OfflineDetection.DefaultStatsInterval }}"
+
+  # Webhook URL (optional).
+  #
+  # When set, the status event is POSTed as JSON to this URL, e.g.:
+  # {"gatewayID": "0102030405060708", "status": "offline", "lastSeenAt": "2020-01-01T00:00:00Z"}
+  webhook_url="{{ .NetworkServer.Gateway.OfflineDetection.WebhookURL }}"
+
   # Backend defines the gateway backend settings.
   #
   # The gateway backend handles the communication with the gateway(s) part of
@@ -679,11 +856,18 @@ get_downlink_data_delay="{{ .NetworkServer.GetDownlinkDataDelay }}"
 
   # Health check endpoint.
   #
-  # When set to true, the healthcheck endpoint will be served at '/health'.
-  # When requesting, this endpoint will perform the following actions to
-  # determine the health of this service:
-  #   * Ping PostgreSQL database
-  #   * Ping Redis database
+  # When set to true, the following healthcheck endpoints will be served:
+  #   * '/live': liveness, checks the scheduler loop heartbeats
+  #   * '/ready': readiness, checks the liveness and the backend dependencies
+  #     (PostgreSQL, Redis, gateway backend, join-server, application-server
+  #     clients and network-controller)
+  #   * '/health': alias for '/ready'
+  #
+  # The response contains the status per component as JSON. In case a
+  # join-server, application-server or network-controller check fails, the
+  # status is 'degraded', but the endpoint still returns 200 as these are
+  # external dependencies. For the other components, the endpoint returns
+  # 503 and the status is 'unavailable'.
   healthcheck_endpoint={{ .Monitoring.HealthcheckEndpoint }}
 
 
@@ -797,6 +981,81 @@ resolve_domain_suffix="{{ .JoinServer.ResolveDomainSuffix }}"
   tls_key="{{ .NetworkController.TLSKey }}"
 
 
+# Integration configuration.
+#
+# When configured, ChirpStack Network Server publishes network-level events
+# (uplinks, join-accepts, tx acknowledgements, mac-commands, ADR changes,
+# uplink rate-limits and gateway stats) to the configured integration.
+#
+# Uplinks exceeding the uplink rate of the service-profile are published as
+# ratelimit event. With the Mark policy, these uplinks are also forwarded to
+# the application-server with the "ns-uplink-rate-limited: true" gRPC
+# metadata set on the HandleUplinkData call.
+[integration]
+# Integration type.
+#
+# Valid values are:
+# * "" (disabled)
+# * mqtt
+type="{{ .Integration.Type }}"
+
+# Marshaler.
+#
+# This defines how the events are encoded. Valid values are:
+# * json
+# * protobuf
+marshaler="{{ .Integration.Marshaler }}"
+
+  # MQTT integration configuration.
+  [integration.mqtt]
+  # Event topic template.
+  #
+  # Use:
+  #   * "{{ "{{ .EventType }}" }}" as an substitution for the event type
+  #   * "{{ "{{ .DevEUI }}" }}" as an substitution for the device EUI
+  #   * "{{ "{{ .GatewayID }}" }}" as an substitution for the gateway ID
+  #
+  # The event types are: up, join, txack, mac, adr, ratelimit, stats and
+  # status. Note that the stats and status events only relate to a gateway
+  # and the other events only relate to a device.
+  event_topic_template="{{ .Integration.MQTT.EventTopicTemplate }}"
+
+  # MQTT server (e.g. scheme://host:port where scheme is tcp, ssl or ws)
+  server="{{ .Integration.MQTT.Server }}"
+
+  # Connect with the given username (optional)
+  username="{{ .Integration.MQTT.Username }}"
+
+  # Connect with the given password (optional)
+  password="{{ .Integration.MQTT.Password }}"
+
+  # Maximum interval that will be waited between reconnection attempts when connection is lost.
+  # Valid units are 'ms', 's', 'm', 'h'. Note that these values can be combined, e.g. '24h30m15s'.
+  max_reconnect_interval="{{ .Integration.MQTT.MaxReconnectInterval }}"
+
+  # Quality of service level
+  #
+  # 0: at most once
+  # 1: at least once
+  # 2: exactly once
+  qos={{ .Integration.MQTT.QOS }}
+
+  # Clean session
+  clean_session={{ .Integration.MQTT.CleanSession }}
+
+  # Client ID (optional)
+  client_id="{{ .Integration.MQTT.ClientID }}"
+
+  # CA certificate file (optional)
+  ca_cert="{{ .Integration.MQTT.CACert }}"
+
+  # TLS certificate file (optional)
+  tls_cert="{{ .Integration.MQTT.TLSCert }}"
+
+  # TLS key file (optional)
+  tls_key="{{ .Integration.MQTT.TLSKey }}"
+
+
 # Roaming settings (experimental).
 [roaming]
 
@@ -806,6 +1065,14 @@ resolve_domain_suffix="{{ .JoinServer.ResolveDomainSuffix }}"
 # using its NetID.
 resolve_netid_domain_suffix="{{ .Roaming.ResolveNetIDDomainSuffix }}"
 
+# Agreement reload interval.
+#
+# Besides the roaming agreements configured in this file (see
+# roaming.servers), roaming agreements can be stored in the database.
+# This defines the interval at which these are reloaded. Agreements
+# configured in this file take precedence over the database agreements.
+agreement_reload_interval="{{ .Roaming.AgreementReloadInterval }}"
+
   # Roaming API settings.
   [roaming.api]
   # Interface to bind the API to (ip:port).
@@ -852,6 +1119,25 @@ resolve_netid_domain_suffix="{{ .Roaming.ResolveNetIDDomainSuffix }}"
   # # are exchanged.
   # passive_roaming_kek_label=""
   #
+  # # Allow handover-roaming.
+  # #
+  # # When enabled, visiting devices of which the home network matches this
+  # # NetID are activated using handover-roaming instead of passive-roaming.
+  # # This network-server then becomes the serving network-server of these
+  # # devices. As home network-server, the device-queue items of devices
+  # # which have been handed over are forwarded to the serving network-server
+  # # on each uplink of the device.
+  # handover_roaming=false
+  #
+  # # Handover-roaming session lifetime.
+  # handover_roaming_lifetime="24h"
+  #
+  # # Handover-roaming KEK label (optional).
+  # #
+  # # When set, the session-keys will be encrypted using the given KEK when these
+  # # are exchanged.
+  # handover_roaming_kek_label=""
+  #
   # # Server (optional).
   # #
   # # When set, this will bypass the DNS resolving of the Network Server.
@@ -878,6 +1164,9 @@ resolve_netid_domain_suffix="{{ .Roaming.ResolveNetIDDomainSuffix }}"
   passive_roaming={{ $element.PassiveRoaming }}
   passive_roaming_lifetime="{{ $element.PassiveRoamingLifetime }}"
   passive_roaming_kek_label="{{ $element.PassiveRoamingKEKLabel }}"
+  handover_roaming={{ $element.HandoverRoaming }}
+  handover_roaming_lifetime="{{ $element.HandoverRoamingLifetime }}"
+  handover_roaming_kek_label="{{ $element.HandoverRoamingKEKLabel }}"
   server="{{ $element.Server }}"
   ca_cert="{{ $element.CACert }}"
   tls_cert="{{ $element.TLSCert }}"
@@ -898,6 +1187,9 @@ resolve_netid_domain_suffix="{{ .Roaming.ResolveNetIDDomainSuffix }}"
   passive_roaming={{ .Roaming.Default.PassiveRoaming }}
   passive_roaming_lifetime="{{ .Roaming.Default.PassiveRoamingLifetime }}"
   passive_roaming_kek_label="{{ .Roaming.Default.PassiveRoamingKEKLabel }}"
+  handover_roaming={{ .Roaming.Default.HandoverRoaming }}
+  handover_roaming_lifetime="{{ .Roaming.Default.HandoverRoamingLifetime }}"
+  handover_roaming_kek_label="{{ .Roaming.Default.HandoverRoamingKEKLabel }}"
   ca_cert="{{ .Roaming.Default.CACert }}"
   tls_cert="{{ .Roaming.Default.TLSCert }}"
   tls_key="{{ .Roaming.Default.TLSKey }}"
@@ -0,0 +1,309 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/lorawan"
+)
+
+// Device import / export file formats.
+const (
+	deviceRecordsCSV  = "csv"
+	deviceRecordsJSON = "json"
+)
+
+// deviceRecordColumns contains the CSV columns of a device record.
+var deviceRecordColumns = []string{
+	"dev_eui",
+	"device_profile_id",
+	"service_profile_id",
+	"routing_profile_id",
+	"mode",
+	"skip_fcnt_check",
+	"dev_addr",
+	"s_nwk_s_int_key",
+	"f_nwk_s_int_key",
+	"nwk_s_enc_key",
+	"f_cnt_up",
+	"n_f_cnt_down",
+	"a_f_cnt_down",
+}
+
+// deviceRecord contains a device as imported or exported.
+type deviceRecord struct {
+	DevEUI           lorawan.EUI64      `json:"devEUI"`
+	DeviceProfileID  uuid.UUID          `json:"deviceProfileID"`
+	ServiceProfileID uuid.UUID          `json:"serviceProfileID"`
+	RoutingProfileID uuid.UUID          `json:"routingProfileID"`
+	Mode             storage.DeviceMode `json:"mode,omitempty"`
+	SkipFCntCheck    bool               `json:"skipFCntCheck"`
+
+	// Session contains the session of an ABP activated device.
+	Session *deviceSessionRecord `json:"session,omitempty"`
+}
+
+// deviceSessionRecord contains the session keys and frame-counters of an ABP
+// activated device.
+type deviceSessionRecord struct {
+	DevAddr     lorawan.DevAddr   `json:"devAddr"`
+	SNwkSIntKey lorawan.AES128Key `json:"sNwkSIntKey"`
+	FNwkSIntKey lorawan.AES128Key `json:"fNwkSIntKey"`
+	NwkSEncKey  lorawan.AES128Key `json:"nwkSEncKey"`
+	FCntUp      uint32            `json:"fCntUp"`
+	NFCntDown   uint32            `json:"nFCntDown"`
+	AFCntDown   uint32            `json:"aFCntDown"`
+}
+
+// deviceRecordRow contains a device record or the error that occurred
+// while reading the record. Row is the 1-based record number (for CSV files,
+// the header is not counted).
+type deviceRecordRow struct {
+	Row    int
+	Record deviceRecord
+	Err    error
+}
+
+// getDeviceRecordsFormat returns the given format, or the format based on
+// the file extension when no format is given.
+func getDeviceRecordsFormat(format, path string) (string, error) {
+	if format == "" {
+		if strings.ToLower(filepath.Ext(path)) == ".json" {
+			return deviceRecordsJSON, nil
+		}
+		return deviceRecordsCSV, nil
+	}
+
+	switch format {
+	case deviceRecordsCSV, deviceRecordsJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unexpected format: %s", format)
+	}
+}
+
+// readDeviceRecords reads the device records from the given reader.
+// Errors related to a single record are returned as part of the row, so
+// that the remaining rows can still be processed.
+func readDeviceRecords(r io.Reader, format string) ([]deviceRecordRow, error) {
+	switch format {
+	case deviceRecordsJSON:
+		return readDeviceRecordsJSON(r)
+	default:
+		return readDeviceRecordsCSV(r)
+	}
+}
+
+func readDeviceRecordsJSON(r io.Reader) ([]deviceRecordRow, error) {
+	var raw []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, errors.Wrap(err, "decode json error")
+	}
+
+	var out []deviceRecordRow
+	for i, b := range raw {
+		row := deviceRecordRow{Row: i + 1}
+		row.Err = json.Unmarshal(b, &row.Record)
+		out = append(out, row)
+	}
+
+	return out, nil
+}
+
+func readDeviceRecordsCSV(r io.Reader) ([]deviceRecordRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.Wrap(err, "read csv header error")
+	}
+
+	columns := make(map[string]int)
+	for i, c := range header {
+		columns[strings.TrimSpace(c)] = i
+	}
+
+	for _, c := range []string{"dev_eui", "device_profile_id", "service_profile_id", "routing_profile_id"} {
+		if _, ok := columns[c]; !ok {
+			return nil, fmt.Errorf("csv column %s is missing", c)
+		}
+	}
+
+	var out []deviceRecordRow
+	for i := 1; ; i++ {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		row := deviceRecordRow{Row: i}
+		if err != nil {
+			row.Err = errors.Wrap(err, "read csv error")
+		} else {
+			row.Err = parseDeviceRecordCSV(columns, fields, &row.Record)
+		}
+		out = append(out, row)
+	}
+
+	return out, nil
+}
+
+func parseDeviceRecordCSV(columns map[string]int, fields []string, r *deviceRecord) error {
+	get := func(c string) string {
+		i, ok := columns[c]
+		if !ok || i >= len(fields) {
+			return ""
+		}
+		return strings.TrimSpace(fields[i])
+	}
+
+	if err := r.DevEUI.UnmarshalText([]byte(get("dev_eui"))); err != nil {
+		return errors.Wrap(err, "decode dev_eui error")
+	}
+	if err := r.DeviceProfileID.UnmarshalText([]byte(get("device_profile_id"))); err != nil {
+		return errors.Wrap(err, "decode device_profile_id error")
+	}
+	if err := r.ServiceProfileID.UnmarshalText([]byte(get("service_profile_id"))); err != nil {
+		return errors.Wrap(err, "decode service_profile_id error")
+	}
+	if err := r.RoutingProfileID.UnmarshalText([]byte(get("routing_profile_id"))); err != nil {
+		return errors.Wrap(err, "decode routing_profile_id error")
+	}
+
+	r.Mode = storage.DeviceMode(strings.ToUpper(get("mode")))
+
+	if v := get("skip_fcnt_check"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return errors.Wrap(err, "decode skip_fcnt_check error")
+		}
+		r.SkipFCntCheck = b
+	}
+
+	// The session is optional and only set for ABP devices.
+	if get("dev_addr") == "" {
+		return nil
+	}
+
+	var s deviceSessionRecord
+	if err := s.DevAddr.UnmarshalText([]byte(get("dev_addr"))); err != nil {
+		return errors.Wrap(err, "decode dev_addr error")
+	}
+
+	for c, key := range map[string]*lorawan.AES128Key{
+		"s_nwk_s_int_key": &s.SNwkSIntKey,
+		"f_nwk_s_int_key": &s.FNwkSIntKey,
+		"nwk_s_enc_key":   &s.NwkSEncKey,
+	} {
+		if err := key.UnmarshalText([]byte(get(c))); err != nil {
+			return errors.Wrapf(err, "decode %s error", c)
+		}
+	}
+
+	for c, fCnt := range map[string]*uint32{
+		"f_cnt_up":     &s.FCntUp,
+		"n_f_cnt_down": &s.NFCntDown,
+		"a_f_cnt_down": &s.AFCntDown,
+	} {
+		v := get(c)
+		if v == "" {
+			continue
+		}
+
+		i, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return errors.Wrapf(err, "decode %s error", c)
+		}
+		*fCnt = uint32(i)
+	}
+
+	r.Session = &s
+
+	return nil
+}
+
+// deviceRecordsWriter writes device records to the underlying writer.
+type deviceRecordsWriter struct {
+	format  string
+	csv     *csv.Writer
+	w       io.Writer
+	records []deviceRecord
+}
+
+func newDeviceRecordsWriter(w io.Writer, format string) (*deviceRecordsWriter, error) {
+	dw := deviceRecordsWriter{
+		format: format,
+		w:      w,
+	}
+
+	if format == deviceRecordsCSV {
+		dw.csv = csv.NewWriter(w)
+		if err := dw.csv.Write(deviceRecordColumns); err != nil {
+			return nil, errors.Wrap(err, "write csv header error")
+		}
+	}
+
+	return &dw, nil
+}
+
+// Write writes the given record. For JSON, the records are written on Flush.
+func (dw *deviceRecordsWriter) Write(r deviceRecord) error {
+	if dw.format == deviceRecordsJSON {
+		dw.records = append(dw.records, r)
+		return nil
+	}
+
+	fields := []string{
+		r.DevEUI.String(),
+		r.DeviceProfileID.String(),
+		r.ServiceProfileID.String(),
+		r.RoutingProfileID.String(),
+		string(r.Mode),
+		strconv.FormatBool(r.SkipFCntCheck),
+		"", "", "", "", "", "", "",
+	}
+
+	if s := r.Session; s != nil {
+		fields[6] = s.DevAddr.String()
+		fields[7] = s.SNwkSIntKey.String()
+		fields[8] = s.FNwkSIntKey.String()
+		fields[9] = s.NwkSEncKey.String()
+		fields[10] = strconv.FormatUint(uint64(s.FCntUp), 10)
+		fields[11] = strconv.FormatUint(uint64(s.NFCntDown), 10)
+		fields[12] = strconv.FormatUint(uint64(s.AFCntDown), 10)
+	}
+
+	return dw.csv.Write(fields)
+}
+
+// Flush flushes the written records to the underlying writer.
+func (dw *deviceRecordsWriter) Flush() error {
+	if dw.format == deviceRecordsJSON {
+		records := dw.records
+		if records == nil {
+			records = []deviceRecord{}
+		}
+
+		b, err := json.MarshalIndent(records, "", "    ")
+		if err != nil {
+			return errors.Wrap(err, "json marshal error")
+		}
+
+		_, err = fmt.Fprintln(dw.w, string(b))
+		return err
+	}
+
+	dw.csv.Flush()
+	return dw.csv.Error()
+}
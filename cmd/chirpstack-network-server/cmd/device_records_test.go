@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/lorawan"
+)
+
+func TestGetDeviceRecordsFormat(t *testing.T) {
+	tests := []struct {
+		Name           string
+		Format         string
+		Path           string
+		ExpectedFormat string
+		ExpectedError  bool
+	}{
+		{"csv extension", "", "devices.csv", deviceRecordsCSV, false},
+		{"json extension", "", "devices.JSON", deviceRecordsJSON, false},
+		{"unknown extension", "", "devices.txt", deviceRecordsCSV, false},
+		{"format overrides extension", deviceRecordsJSON, "devices.csv", deviceRecordsJSON, false},
+		{"invalid format", "xml", "devices.xml", "", true},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.Name, func(t *testing.T) {
+			assert := require.New(t)
+
+			format, err := getDeviceRecordsFormat(tst.Format, tst.Path)
+			if tst.ExpectedError {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.Equal(tst.ExpectedFormat, format)
+		})
+	}
+}
+
+func TestReadDeviceRecordsCSV(t *testing.T) {
+	dpID := uuid.Must(uuid.NewV4())
+	spID := uuid.Must(uuid.NewV4())
+	rpID := uuid.Must(uuid.NewV4())
+
+	t.Run("Missing column", func(t *testing.T) {
+		assert := require.New(t)
+
+		_, err := readDeviceRecords(strings.NewReader("dev_eui,device_profile_id\n"), deviceRecordsCSV)
+		assert.EqualError(err, "csv column service_profile_id is missing")
+	})
+
+	t.Run("Rows", func(t *testing.T) {
+		assert := require.New(t)
+
+		in := strings.Join([]string{
+			"dev_eui, device_profile_id, service_profile_id, routing_profile_id, mode, skip_fcnt_check, dev_addr, s_nwk_s_int_key, f_nwk_s_int_key, nwk_s_enc_key, f_cnt_up, n_f_cnt_down, a_f_cnt_down",
+			"0102030405060708, " + dpID.String() + ", " + spID.String() + ", " + rpID.String() + ", c, true",
+			"0102030405060709, " + dpID.String() + ", " + spID.String() + ", " + rpID.String() + ", , , 01020304, 01010101010101010101010101010101, 02020202020202020202020202020202, 03030303030303030303030303030303, 10, 20, 30",
+			"invalid, " + dpID.String() + ", " + spID.String() + ", " + rpID.String(),
+			"010203040506070a, " + dpID.String() + ", " + spID.String() + ", " + rpID.String() + ", , , 0102030z",
+		}, "\n")
+
+		rows, err := readDeviceRecords(strings.NewReader(in), deviceRecordsCSV)
+		assert.NoError(err)
+		assert.Len(rows, 4)
+
+		assert.NoError(rows[0].Err)
+		assert.Equal(1, rows[0].Row)
+		assert.Equal(deviceRecord{
+			DevEUI:           lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8},
+			DeviceProfileID:  dpID,
+			ServiceProfileID: spID,
+			RoutingProfileID: rpID,
+			Mode:             storage.DeviceModeC,
+			SkipFCntCheck:    true,
+		}, rows[0].Record)
+
+		assert.NoError(rows[1].Err)
+		assert.Equal(&deviceSessionRecord{
+			DevAddr:     lorawan.DevAddr{1, 2, 3, 4},
+			SNwkSIntKey: lorawan.AES128Key{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+			FNwkSIntKey: lorawan.AES128Key{2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2},
+			NwkSEncKey:  lorawan.AES128Key{3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3},
+			FCntUp:      10,
+			NFCntDown:   20,
+			AFCntDown:   30,
+		}, rows[1].Record.Session)
+
+		// errors are reported per row
+		assert.Equal(3, rows[2].Row)
+		assert.Error(rows[2].Err)
+		assert.Contains(rows[2].Err.Error(), "decode dev_eui error")
+
+		assert.Error(rows[3].Err)
+		assert.Contains(rows[3].Err.Error(), "decode dev_addr error")
+	})
+}
+
+func TestReadDeviceRecordsJSON(t *testing.T) {
+	t.Run("Invalid JSON", func(t *testing.T) {
+		assert := require.New(t)
+
+		_, err := readDeviceRecords(strings.NewReader("{"), deviceRecordsJSON)
+		assert.Error(err)
+	})
+
+	t.Run("Rows", func(t *testing.T) {
+		assert := require.New(t)
+
+		in := `[
+			{"devEUI": "0102030405060708", "mode": "A", "session": {"devAddr": "01020304", "fCntUp": 10}},
+			{"devEUI": "invalid"}
+		]`
+
+		rows, err := readDeviceRecords(strings.NewReader(in), deviceRecordsJSON)
+		assert.NoError(err)
+		assert.Len(rows, 2)
+
+		assert.NoError(rows[0].Err)
+		assert.Equal(lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8}, rows[0].Record.DevEUI)
+		assert.Equal(storage.DeviceModeA, rows[0].Record.Mode)
+		assert.Equal(lorawan.DevAddr{1, 2, 3, 4}, rows[0].Record.Session.DevAddr)
+		assert.EqualValues(10, rows[0].Record.Session.FCntUp)
+
+		assert.Equal(2, rows[1].Row)
+		assert.Error(rows[1].Err)
+	})
+}
+
+func TestDeviceRecordsWriter(t *testing.T) {
+	records := []deviceRecord{
+		{
+			DevEUI:           lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8},
+			DeviceProfileID:  uuid.Must(uuid.NewV4()),
+			ServiceProfileID: uuid.Must(uuid.NewV4()),
+			RoutingProfileID: uuid.Must(uuid.NewV4()),
+			Mode:             storage.DeviceModeA,
+		},
+		{
+			DevEUI:           lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 9},
+			DeviceProfileID:  uuid.Must(uuid.NewV4()),
+			ServiceProfileID: uuid.Must(uuid.NewV4()),
+			RoutingProfileID: uuid.Must(uuid.NewV4()),
+			Mode:             storage.DeviceModeC,
+			SkipFCntCheck:    true,
+			Session: &deviceSessionRecord{
+				DevAddr:     lorawan.DevAddr{1, 2, 3, 4},
+				SNwkSIntKey: lorawan.AES128Key{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+				FNwkSIntKey: lorawan.AES128Key{2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2},
+				NwkSEncKey:  lorawan.AES128Key{3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3},
+				FCntUp:      10,
+				NFCntDown:   20,
+				AFCntDown:   30,
+			},
+		},
+	}
+
+	for _, format := range []string{deviceRecordsCSV, deviceRecordsJSON} {
+		t.Run(format, func(t *testing.T) {
+			assert := require.New(t)
+
+			var buf bytes.Buffer
+			dw, err := newDeviceRecordsWriter(&buf, format)
+			assert.NoError(err)
+
+			for _, r := range records {
+				assert.NoError(dw.Write(r))
+			}
+			assert.NoError(dw.Flush())
+
+			// the written records can be imported again
+			rows, err := readDeviceRecords(&buf, format)
+			assert.NoError(err)
+			assert.Len(rows, len(records))
+
+			for i, row := range rows {
+				assert.NoError(row.Err)
+				assert.Equal(records[i], row.Record)
+			}
+		})
+	}
+
+	t.Run("Empty JSON", func(t *testing.T) {
+		assert := require.New(t)
+
+		var buf bytes.Buffer
+		dw, err := newDeviceRecordsWriter(&buf, deviceRecordsJSON)
+		assert.NoError(err)
+		assert.NoError(dw.Flush())
+		assert.Equal("[]\n", buf.String())
+	})
+}
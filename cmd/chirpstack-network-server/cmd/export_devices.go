@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/brocaar/chirpstack-network-server/internal/config"
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+)
+
+// exportDevicesPageSize defines the number of devices read per query.
+const exportDevicesPageSize = 1000
+
+var exportDevicesFormat string
+
+var exportDevicesCmd = &cobra.Command{
+	Use:   "export-devices",
+	Short: "Export devices (and ABP sessions) to a CSV or JSON file",
+	Long: `Export devices to a CSV or JSON file (or to stdout when no file is given).
+
+For ABP devices, the session keys and frame-counters are exported too. The
+output can be imported using import-devices.`,
+	Example: `chirpstack-network-server export-devices devices.csv
+chirpstack-network-server export-devices --format json > devices.json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 1 {
+			log.Fatalf("at most one output file can be given as an argument")
+		}
+
+		if err := storage.Setup(config.C); err != nil {
+			log.Fatal(err)
+		}
+
+		var path string
+		var w io.Writer = os.Stdout
+		if len(args) == 1 {
+			path = args[0]
+
+			f, err := os.Create(path)
+			if err != nil {
+				log.WithError(err).Fatal("create file error")
+			}
+			defer f.Close()
+			w = f
+		}
+
+		format, err := getDeviceRecordsFormat(exportDevicesFormat, path)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if err := exportDevices(context.Background(), w, format); err != nil {
+			log.WithError(err).Fatal("export devices error")
+		}
+	},
+}
+
+func init() {
+	exportDevicesCmd.Flags().StringVar(&exportDevicesFormat, "format", "", "file format, csv or json (default based on the file extension)")
+}
+
+func exportDevices(ctx context.Context, w io.Writer, format string) error {
+	dw, err := newDeviceRecordsWriter(w, format)
+	if err != nil {
+		return err
+	}
+
+	deviceProfiles := make(map[uuid.UUID]storage.DeviceProfile)
+
+	for offset := 0; ; offset += exportDevicesPageSize {
+		devices, err := storage.GetDevices(ctx, storage.DB(), exportDevicesPageSize, offset)
+		if err != nil {
+			return errors.Wrap(err, "get devices error")
+		}
+
+		for _, d := range devices {
+			r := deviceRecord{
+				DevEUI:           d.DevEUI,
+				DeviceProfileID:  d.DeviceProfileID,
+				ServiceProfileID: d.ServiceProfileID,
+				RoutingProfileID: d.RoutingProfileID,
+				Mode:             d.Mode,
+				SkipFCntCheck:    d.SkipFCntCheck,
+			}
+
+			dp, ok := deviceProfiles[d.DeviceProfileID]
+			if !ok {
+				dp, err = storage.GetDeviceProfile(ctx, storage.DB(), d.DeviceProfileID)
+				if err != nil {
+					return errors.Wrap(err, "get device-profile error")
+				}
+				deviceProfiles[d.DeviceProfileID] = dp
+			}
+
+			// The session is only exported for ABP devices, OTAA devices
+			// will obtain a new session by joining the network.
+			if !dp.SupportsJoin {
+				ds, err := storage.GetDeviceSession(ctx, d.DevEUI)
+				if err != nil && errors.Cause(err) != storage.ErrDoesNotExist {
+					return errors.Wrap(err, "get device-session error")
+				}
+
+				if err == nil {
+					r.Session = &deviceSessionRecord{
+						DevAddr:     ds.DevAddr,
+						SNwkSIntKey: ds.SNwkSIntKey,
+						FNwkSIntKey: ds.FNwkSIntKey,
+						NwkSEncKey:  ds.NwkSEncKey,
+						FCntUp:      ds.FCntUp,
+						NFCntDown:   ds.NFCntDown,
+						AFCntDown:   ds.AFCntDown,
+					}
+				}
+			}
+
+			if err := dw.Write(r); err != nil {
+				return errors.Wrap(err, "write device error")
+			}
+		}
+
+		if len(devices) < exportDevicesPageSize {
+			break
+		}
+	}
+
+	return dw.Flush()
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/brocaar/chirpstack-network-server/internal/config"
+	"github.com/brocaar/chirpstack-network-server/internal/maccommand"
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/lorawan"
+)
+
+var (
+	forceRejoinDevEUI          string
+	forceRejoinDeviceProfileID string
+	forceRejoinType            int
+	forceRejoinDR              int
+	forceRejoinMaxRetries      int
+	forceRejoinPeriod          int
+)
+
+var forceRejoinCmd = &cobra.Command{
+	Use:   "force-rejoin",
+	Short: "Request LoRaWAN 1.1 devices to rejoin using the ForceRejoinReq mac-command",
+	Long: `Request LoRaWAN 1.1 devices to rejoin using the ForceRejoinReq mac-command.
+
+The ForceRejoinReq mac-command is enqueued for the given DevEUI or for all the
+activated LoRaWAN 1.1 devices using the given device-profile. The mac-command
+is sent with the next downlink. The progress can be inspected using the
+print-force-rejoin command. A force-rejoin is completed once the resulting
+rejoin-request has been processed.`,
+	Example: `chirpstack-network-server force-rejoin --dev-eui 0102030405060708 --rejoin-type 2 --dr 3 --max-retries 2 --period 1
+chirpstack-network-server force-rejoin --device-profile-id 7a0a9ab4-4ef1-4ebd-a1b4-7d47aa2bd7d2 --rejoin-type 0 --dr 3`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if (forceRejoinDevEUI == "") == (forceRejoinDeviceProfileID == "") {
+			log.Fatal("either --dev-eui or --device-profile-id must be given")
+		}
+
+		if err := storage.Setup(config.C); err != nil {
+			log.Fatal(err)
+		}
+
+		if forceRejoinDevEUI != "" {
+			var devEUI lorawan.EUI64
+			if err := devEUI.UnmarshalText([]byte(forceRejoinDevEUI)); err != nil {
+				log.WithError(err).Fatal("decode DevEUI error")
+			}
+
+			if err := maccommand.EnqueueForceRejoin(context.Background(), storage.DB(), devEUI, forceRejoinType, forceRejoinDR, forceRejoinMaxRetries, forceRejoinPeriod); err != nil {
+				log.WithError(err).Fatal("enqueue force-rejoin error")
+			}
+
+			fr, err := storage.GetForceRejoin(context.Background(), storage.DB(), devEUI)
+			if err != nil {
+				log.WithError(err).Fatal("get force-rejoin error")
+			}
+
+			printForceRejoins([]storage.ForceRejoin{fr})
+			return
+		}
+
+		dpID, err := uuid.FromString(forceRejoinDeviceProfileID)
+		if err != nil {
+			log.WithError(err).Fatal("decode device-profile ID error")
+		}
+
+		devEUIs, err := maccommand.EnqueueForceRejoinForDeviceProfile(context.Background(), dpID, forceRejoinType, forceRejoinDR, forceRejoinMaxRetries, forceRejoinPeriod)
+		if err != nil {
+			log.WithError(err).Fatal("enqueue force-rejoin error")
+		}
+
+		log.WithFields(log.Fields{
+			"device_profile_id": dpID,
+			"device_count":      len(devEUIs),
+		}).Info("force-rejoin enqueued")
+	},
+}
+
+var printForceRejoinCmd = &cobra.Command{
+	Use:   "print-force-rejoin",
+	Short: "Print the force-rejoin progress as JSON",
+	Example: `chirpstack-network-server print-force-rejoin --dev-eui 0102030405060708
+chirpstack-network-server print-force-rejoin --device-profile-id 7a0a9ab4-4ef1-4ebd-a1b4-7d47aa2bd7d2`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if (forceRejoinDevEUI == "") == (forceRejoinDeviceProfileID == "") {
+			log.Fatal("either --dev-eui or --device-profile-id must be given")
+		}
+
+		if err := storage.Setup(config.C); err != nil {
+			log.Fatal(err)
+		}
+
+		if forceRejoinDevEUI != "" {
+			var devEUI lorawan.EUI64
+			if err := devEUI.UnmarshalText([]byte(forceRejoinDevEUI)); err != nil {
+				log.WithError(err).Fatal("decode DevEUI error")
+			}
+
+			fr, err := storage.GetForceRejoin(context.Background(), storage.DB(), devEUI)
+			if err != nil {
+				log.WithError(err).Fatal("get force-rejoin error")
+			}
+
+			printForceRejoins([]storage.ForceRejoin{fr})
+			return
+		}
+
+		dpID, err := uuid.FromString(forceRejoinDeviceProfileID)
+		if err != nil {
+			log.WithError(err).Fatal("decode device-profile ID error")
+		}
+
+		items, err := storage.GetForceRejoinsForDeviceProfile(context.Background(), storage.DB(), dpID)
+		if err != nil {
+			log.WithError(err).Fatal("get force-rejoins error")
+		}
+
+		printForceRejoins(items)
+	},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{forceRejoinCmd, printForceRejoinCmd} {
+		c.Flags().StringVar(&forceRejoinDevEUI, "dev-eui", "", "DevEUI (HEX encoded)")
+		c.Flags().StringVar(&forceRejoinDeviceProfileID, "device-profile-id", "", "device-profile ID")
+	}
+
+	forceRejoinCmd.Flags().IntVar(&forceRejoinType, "rejoin-type", 0, "rejoin-request type (0 or 2)")
+	forceRejoinCmd.Flags().IntVar(&forceRejoinDR, "dr", 0, "data-rate to use for the rejoin-request")
+	forceRejoinCmd.Flags().IntVar(&forceRejoinMaxRetries, "max-retries", 0, "max. number of retransmissions of the rejoin-request (0 - 7)")
+	forceRejoinCmd.Flags().IntVar(&forceRejoinPeriod, "period", 0, "delay between retransmissions, 32s * 2^period + rand(0-32s) (0 - 7)")
+}
+
+func printForceRejoins(items []storage.ForceRejoin) {
+	type forceRejoin struct {
+		DevEUI      lorawan.EUI64 `json:"devEUI"`
+		RejoinType  int           `json:"rejoinType"`
+		DR          int           `json:"dr"`
+		MaxRetries  int           `json:"maxRetries"`
+		Period      int           `json:"period"`
+		CreatedAt   time.Time     `json:"createdAt"`
+		SentAt      *time.Time    `json:"sentAt"`
+		CompletedAt *time.Time    `json:"completedAt"`
+	}
+
+	out := make([]forceRejoin, 0, len(items))
+	for _, fr := range items {
+		out = append(out, forceRejoin{
+			DevEUI:      fr.DevEUI,
+			RejoinType:  fr.RejoinType,
+			DR:          fr.DR,
+			MaxRetries:  fr.MaxRetries,
+			Period:      fr.Period,
+			CreatedAt:   fr.CreatedAt,
+			SentAt:      fr.SentAt,
+			CompletedAt: fr.CompletedAt,
+		})
+	}
+
+	b, err := json.MarshalIndent(out, "", "    ")
+	if err != nil {
+		log.WithError(err).Fatal("json marshal error")
+	}
+
+	fmt.Println(string(b))
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/brocaar/chirpstack-network-server/internal/config"
+	"github.com/brocaar/chirpstack-network-server/internal/downlink/multicast"
+	"github.com/brocaar/chirpstack-network-server/internal/fragmentation"
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+)
+
+var (
+	fragSessionMulticastGroupID string
+	fragSessionFragIndex        uint8
+	fragSessionFragmentSize     int
+	fragSessionRedundancy       int
+)
+
+var createFragSessionCmd = &cobra.Command{
+	Use:   "create-fragmentation-session",
+	Short: "Create a fragmented data block transport session (e.g. FUOTA) for a multicast-group",
+	Long: `Create a fragmented data block transport session for a multicast-group.
+
+The given file is split into fragments, extended with the given number of
+parity fragments (forward error correction). The running network-server
+enqueues the fragments one by one (FPort 201) to the multicast-group.
+
+The fragmentation-session must be setup on the devices (FragSessionSetupReq)
+using the printed fragmentation index, fragment size, number of fragments
+and padding.`,
+	Example: `chirpstack-network-server create-fragmentation-session --multicast-group-id 7a0a9ab4-4ef1-4ebd-a1b4-7d47aa2bd7d2 --fragment-size 48 --redundancy 10 firmware.bin`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			log.Fatalf("path to the data file must be given as an argument")
+		}
+
+		if err := setupBand(); err != nil {
+			log.Fatal(err)
+		}
+
+		if err := storage.Setup(config.C); err != nil {
+			log.Fatal(err)
+		}
+
+		mgID, err := uuid.FromString(fragSessionMulticastGroupID)
+		if err != nil {
+			log.WithError(err).Fatal("decode multicast-group ID error")
+		}
+
+		data, err := ioutil.ReadFile(args[0])
+		if err != nil {
+			log.WithError(err).Fatal("read file error")
+		}
+
+		fs := storage.FragmentationSession{
+			MulticastGroupID: mgID,
+			FragIndex:        fragSessionFragIndex,
+			FragmentSize:     fragSessionFragmentSize,
+			Redundancy:       fragSessionRedundancy,
+			Data:             data,
+		}
+
+		err = storage.Transaction(func(tx sqlx.Ext) error {
+			return multicast.CreateFragmentationSession(context.Background(), tx, &fs)
+		})
+		if err != nil {
+			log.WithError(err).Fatal("create fragmentation-session error")
+		}
+
+		printFragmentationSession(fs)
+	},
+}
+
+var printFragSessionCmd = &cobra.Command{
+	Use:     "print-fragmentation-session",
+	Short:   "Print the fragmentation-session parameters and progress as JSON",
+	Example: `chirpstack-network-server print-fragmentation-session 3b5a6a44-cd41-4f5e-8a0b-5ad4e0a86d2f`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			log.Fatalf("fragmentation-session ID must be given as an argument")
+		}
+
+		if err := storage.Setup(config.C); err != nil {
+			log.Fatal(err)
+		}
+
+		id, err := uuid.FromString(args[0])
+		if err != nil {
+			log.WithError(err).Fatal("decode fragmentation-session ID error")
+		}
+
+		fs, err := storage.GetFragmentationSession(context.Background(), storage.DB(), id)
+		if err != nil {
+			log.WithError(err).Fatal("get fragmentation-session error")
+		}
+
+		printFragmentationSession(fs)
+	},
+}
+
+func init() {
+	createFragSessionCmd.Flags().StringVar(&fragSessionMulticastGroupID, "multicast-group-id", "", "multicast-group ID")
+	createFragSessionCmd.Flags().Uint8Var(&fragSessionFragIndex, "frag-index", 0, "fragmentation index (0 - 3)")
+	createFragSessionCmd.Flags().IntVar(&fragSessionFragmentSize, "fragment-size", 0, "fragment size (bytes)")
+	createFragSessionCmd.Flags().IntVar(&fragSessionRedundancy, "redundancy", 0, "number of parity fragments")
+}
+
+func printFragmentationSession(fs storage.FragmentationSession) {
+	out := struct {
+		ID                uuid.UUID  `json:"id"`
+		MulticastGroupID  uuid.UUID  `json:"multicastGroupID"`
+		FragIndex         uint8      `json:"fragIndex"`
+		FragmentSize      int        `json:"fragmentSize"`
+		NbFrag            int        `json:"nbFrag"`
+		Padding           int        `json:"padding"`
+		Redundancy        int        `json:"redundancy"`
+		FragmentsEnqueued int        `json:"fragmentsEnqueued"`
+		FragmentCount     int        `json:"fragmentCount"`
+		CreatedAt         time.Time  `json:"createdAt"`
+		CompletedAt       *time.Time `json:"completedAt"`
+	}{
+		ID:                fs.ID,
+		MulticastGroupID:  fs.MulticastGroupID,
+		FragIndex:         fs.FragIndex,
+		FragmentSize:      fs.FragmentSize,
+		NbFrag:            fragmentation.FragmentCount(len(fs.Data), fs.FragmentSize),
+		Padding:           fragmentation.Padding(len(fs.Data), fs.FragmentSize),
+		Redundancy:        fs.Redundancy,
+		FragmentsEnqueued: fs.FragmentsEnqueued,
+		FragmentCount:     fs.FragmentCount,
+		CreatedAt:         fs.CreatedAt,
+		CompletedAt:       fs.CompletedAt,
+	}
+
+	b, err := json.MarshalIndent(out, "", "    ")
+	if err != nil {
+		log.WithError(err).Fatal("json marshal error")
+	}
+
+	fmt.Println(string(b))
+}
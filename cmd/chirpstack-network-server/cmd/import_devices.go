@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/brocaar/chirpstack-network-server/internal/config"
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/lorawan"
+)
+
+// errDryRun is used to rollback the transaction in dry-run mode.
+var errDryRun = errors.New("dry-run")
+
+var (
+	importDevicesFormat string
+	importDevicesDryRun bool
+)
+
+var importDevicesCmd = &cobra.Command{
+	Use:   "import-devices",
+	Short: "Import devices (and ABP sessions) from a CSV or JSON file",
+	Long: `Import devices from a CSV or JSON file.
+
+The CSV file must start with a header containing the column names:
+  dev_eui, device_profile_id, service_profile_id, routing_profile_id, mode,
+  skip_fcnt_check, dev_addr, s_nwk_s_int_key, f_nwk_s_int_key, nwk_s_enc_key,
+  f_cnt_up, n_f_cnt_down, a_f_cnt_down
+
+The mode, skip_fcnt_check and ABP session columns are optional. When the
+dev_addr is set, the device is activated using the given session keys and
+frame-counters. The JSON format matches the output of export-devices.`,
+	Example: `chirpstack-network-server import-devices devices.csv
+chirpstack-network-server import-devices --dry-run devices.json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			log.Fatalf("path to the input file must be given as an argument")
+		}
+
+		for _, f := range []func() error{setupBand, setRXParameters} {
+			if err := f(); err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		if err := storage.Setup(config.C); err != nil {
+			log.Fatal(err)
+		}
+
+		format, err := getDeviceRecordsFormat(importDevicesFormat, args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			log.WithError(err).Fatal("open file error")
+		}
+		defer f.Close()
+
+		rows, err := readDeviceRecords(f, format)
+		if err != nil {
+			log.WithError(err).Fatal("read devices error")
+		}
+
+		var imported, failed int
+		for _, row := range rows {
+			err := row.Err
+			if err == nil {
+				err = importDevice(context.Background(), row.Record, importDevicesDryRun)
+			}
+
+			if err != nil {
+				failed++
+				log.WithError(err).WithFields(log.Fields{
+					"row":     row.Row,
+					"dev_eui": row.Record.DevEUI,
+				}).Error("import device error")
+				continue
+			}
+
+			imported++
+			log.WithFields(log.Fields{
+				"row":     row.Row,
+				"dev_eui": row.Record.DevEUI,
+				"dry_run": importDevicesDryRun,
+			}).Info("device imported")
+		}
+
+		log.WithFields(log.Fields{
+			"imported": imported,
+			"failed":   failed,
+			"dry_run":  importDevicesDryRun,
+		}).Info("import devices completed")
+
+		if failed != 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	importDevicesCmd.Flags().StringVar(&importDevicesFormat, "format", "", "file format, csv or json (default based on the file extension)")
+	importDevicesCmd.Flags().BoolVar(&importDevicesDryRun, "dry-run", false, "validate the devices without storing them")
+}
+
+// importDevice creates the device and in case of an ABP device, its
+// device-session. In dry-run mode, the device-session is validated but the
+// transaction is rolled back and the device-session is not stored.
+func importDevice(ctx context.Context, r deviceRecord, dryRun bool) error {
+	var ds *storage.DeviceSession
+
+	err := storage.Transaction(func(tx sqlx.Ext) error {
+		dp, err := storage.GetDeviceProfile(ctx, tx, r.DeviceProfileID)
+		if err != nil {
+			return errors.Wrap(err, "get device-profile error")
+		}
+
+		d := storage.Device{
+			DevEUI:           r.DevEUI,
+			DeviceProfileID:  r.DeviceProfileID,
+			ServiceProfileID: r.ServiceProfileID,
+			RoutingProfileID: r.RoutingProfileID,
+			SkipFCntCheck:    r.SkipFCntCheck,
+			Mode:             r.Mode,
+		}
+
+		// The same logic as the ActivateDevice API is used when no mode is
+		// given.
+		switch d.Mode {
+		case storage.DeviceModeA, storage.DeviceModeB, storage.DeviceModeC:
+		case "":
+			if dp.SupportsClassC {
+				d.Mode = storage.DeviceModeC
+			} else {
+				d.Mode = storage.DeviceModeA
+			}
+		default:
+			return errors.Errorf("invalid mode: %s", d.Mode)
+		}
+
+		if err := storage.CreateDevice(ctx, tx, &d); err != nil {
+			return errors.Wrap(err, "create device error")
+		}
+
+		if r.Session != nil {
+			s, err := getImportDeviceSession(dp, d, *r.Session)
+			if err != nil {
+				return err
+			}
+			ds = &s
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+
+		return nil
+	})
+	if err == errDryRun {
+		return nil
+	}
+	if err != nil || ds == nil {
+		return err
+	}
+
+	// The device-session is stored after the commit, so that no session
+	// is left in Redis when the device could not be created.
+	if err := storage.SaveDeviceSession(ctx, *ds); err != nil {
+		return errors.Wrap(err, "device created, save device-session error")
+	}
+
+	return nil
+}
+
+// getImportDeviceSession returns the ABP device-session for the given
+// device and session record.
+func getImportDeviceSession(dp storage.DeviceProfile, d storage.Device, s deviceSessionRecord) (storage.DeviceSession, error) {
+	if dp.SupportsJoin {
+		return storage.DeviceSession{}, errors.New("session can not be imported, device-profile supports OTAA")
+	}
+
+	if s.DevAddr == (lorawan.DevAddr{}) {
+		return storage.DeviceSession{}, errors.New("dev_addr must not be empty")
+	}
+
+	ds := storage.DeviceSession{
+		DeviceProfileID:  d.DeviceProfileID,
+		ServiceProfileID: d.ServiceProfileID,
+		RoutingProfileID: d.RoutingProfileID,
+
+		DevEUI:             d.DevEUI,
+		DevAddr:            s.DevAddr,
+		SNwkSIntKey:        s.SNwkSIntKey,
+		FNwkSIntKey:        s.FNwkSIntKey,
+		NwkSEncKey:         s.NwkSEncKey,
+		FCntUp:             s.FCntUp,
+		NFCntDown:          s.NFCntDown,
+		AFCntDown:          s.AFCntDown,
+		SkipFCntValidation: d.SkipFCntCheck,
+
+		RXWindow: storage.RX1,
+
+		MACVersion: dp.MACVersion,
+
+		IsDisabled: d.IsDisabled,
+	}
+	ds.ResetToBootParameters(dp)
+
+	return ds, nil
+}
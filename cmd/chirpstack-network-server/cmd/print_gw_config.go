@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang/protobuf/jsonpb"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/brocaar/chirpstack-network-server/internal/band"
+	"github.com/brocaar/chirpstack-network-server/internal/config"
+	"github.com/brocaar/chirpstack-network-server/internal/gateway/channelplan"
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/lorawan"
+)
+
+var printGWConfigCmd = &cobra.Command{
+	Use:     "print-gw-config",
+	Short:   "Validate the gateway-profile channel-plan and print the generated gateway configuration as JSON",
+	Example: `chirpstack-network-server print-gw-config 7a0a9ab4-4ef1-4ebd-a1b4-7d47aa2bd7d2`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			log.Fatalf("gateway-profile ID must be given as an argument")
+		}
+
+		if err := band.Setup(config.C); err != nil {
+			log.Fatal(err)
+		}
+
+		if err := storage.Setup(config.C); err != nil {
+			log.Fatal(err)
+		}
+
+		gpID, err := uuid.FromString(args[0])
+		if err != nil {
+			log.WithError(err).Fatal("decode gateway-profile ID error")
+		}
+
+		gp, err := storage.GetGatewayProfile(context.Background(), storage.DB(), gpID)
+		if err != nil {
+			log.WithError(err).Fatal("get gateway-profile error")
+		}
+
+		if err := channelplan.Validate(gp); err != nil {
+			log.WithError(err).Error("invalid channel-plan")
+		}
+
+		conf, err := channelplan.GatewayConfiguration(lorawan.EUI64{}, gp)
+		if err != nil {
+			log.WithError(err).Fatal("get gateway configuration error")
+		}
+
+		m := jsonpb.Marshaler{
+			Indent: "    ",
+		}
+		str, err := m.MarshalToString(&conf)
+		if err != nil {
+			log.WithError(err).Fatal("json marshal error")
+		}
+
+		fmt.Println(str)
+	},
+}
@@ -74,14 +74,26 @@ func init() {
 	viper.SetDefault("network_server.network_settings.downlink_tx_power", -1)
 	viper.SetDefault("network_server.network_settings.disable_adr", false)
 	viper.SetDefault("network_server.network_settings.max_mac_command_error_count", 3)
+	viper.SetDefault("network_server.network_settings.duty_cycle.window", time.Hour)
 
 	viper.SetDefault("network_server.gateway.backend.type", "mqtt")
 
+	viper.SetDefault("network_server.frame_log.history_max_len", 0)
+	viper.SetDefault("network_server.frame_log.history_ttl", 24*time.Hour)
+	viper.SetDefault("network_server.frame_log.stream_replay_count", 10)
+	viper.SetDefault("network_server.device_activation.prune_interval", time.Hour)
+
 	viper.SetDefault("network_server.scheduler.scheduler_interval", 1*time.Second)
+	viper.SetDefault("network_server.scheduler.leader_election.lease_duration", 10*time.Second)
 	viper.SetDefault("network_server.scheduler.class_c.downlink_lock_duration", 2*time.Second)
 	viper.SetDefault("network_server.scheduler.class_c.multicast_gateway_delay", 2*time.Second)
 
 	viper.SetDefault("network_server.gateway.client_cert_lifetime", time.Hour*24*365)
+	viper.SetDefault("network_server.gateway.downlink_airtime.window", time.Hour)
+	viper.SetDefault("network_server.network_settings.class_b.beacon.info_desc", "gps")
+	viper.SetDefault("network_server.gateway.offline_detection.interval", time.Minute)
+	viper.SetDefault("network_server.gateway.offline_detection.missed_stats_intervals", 3)
+	viper.SetDefault("network_server.gateway.offline_detection.default_stats_interval", 30*time.Second)
 	viper.SetDefault("network_server.gateway.backend.mqtt.event_topic", "gateway/+/event/+")
 	viper.SetDefault("network_server.gateway.backend.mqtt.command_topic_template", "gateway/{{ .GatewayID }}/command/{{ .CommandType }}")
 	viper.SetDefault("network_server.gateway.backend.mqtt.clean_session", true)
@@ -95,6 +107,13 @@ func init() {
 	viper.SetDefault("join_server.default.server", "http://localhost:8003")
 
 	viper.SetDefault("roaming.resolve_netid_domain_suffix", ".netids.lora-alliance.org")
+	viper.SetDefault("roaming.agreement_reload_interval", time.Minute)
+
+	viper.SetDefault("integration.marshaler", "json")
+	viper.SetDefault("integration.mqtt.server", "tcp://localhost:1883")
+	viper.SetDefault("integration.mqtt.clean_session", true)
+	viper.SetDefault("integration.mqtt.max_reconnect_interval", time.Minute)
+	viper.SetDefault("integration.mqtt.event_topic_template", "network-server/event/{{ .EventType }}")
 
 	viper.SetDefault("network_server.gateway.backend.gcp_pub_sub.uplink_retention_duration", time.Hour*24)
 
@@ -108,6 +127,13 @@ func init() {
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(printDSCmd)
+	rootCmd.AddCommand(printGWConfigCmd)
+	rootCmd.AddCommand(createFragSessionCmd)
+	rootCmd.AddCommand(printFragSessionCmd)
+	rootCmd.AddCommand(forceRejoinCmd)
+	rootCmd.AddCommand(printForceRejoinCmd)
+	rootCmd.AddCommand(importDevicesCmd)
+	rootCmd.AddCommand(exportDevicesCmd)
 }
 
 // Execute executes the root command.
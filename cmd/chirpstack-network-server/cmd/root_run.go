@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
@@ -8,6 +9,7 @@ import (
 	"os"
 	"os/signal"
 	"runtime/pprof"
+	"sync"
 	"syscall"
 
 	"github.com/jmoiron/sqlx"
@@ -16,6 +18,7 @@ import (
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/balancer/roundrobin"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials"
 
 	"github.com/brocaar/chirpstack-api/go/v3/nc"
@@ -29,20 +32,28 @@ import (
 	"github.com/brocaar/chirpstack-network-server/internal/backend/gateway/azureiothub"
 	"github.com/brocaar/chirpstack-network-server/internal/backend/gateway/gcppubsub"
 	"github.com/brocaar/chirpstack-network-server/internal/backend/gateway/mqtt"
+	"github.com/brocaar/chirpstack-network-server/internal/backend/integration"
+	integrationmqtt "github.com/brocaar/chirpstack-network-server/internal/backend/integration/mqtt"
 	"github.com/brocaar/chirpstack-network-server/internal/backend/joinserver"
 	"github.com/brocaar/chirpstack-network-server/internal/band"
 	"github.com/brocaar/chirpstack-network-server/internal/config"
 	"github.com/brocaar/chirpstack-network-server/internal/downlink"
+	"github.com/brocaar/chirpstack-network-server/internal/downlink/beacon"
+	"github.com/brocaar/chirpstack-network-server/internal/framelog"
 	"github.com/brocaar/chirpstack-network-server/internal/gateway"
 	"github.com/brocaar/chirpstack-network-server/internal/migrations/code"
 	"github.com/brocaar/chirpstack-network-server/internal/monitoring"
 	"github.com/brocaar/chirpstack-network-server/internal/roaming"
 	"github.com/brocaar/chirpstack-network-server/internal/storage"
 	"github.com/brocaar/chirpstack-network-server/internal/uplink"
+	"github.com/brocaar/chirpstack-network-server/internal/uplink/join"
 )
 
 func run(cmd *cobra.Command, args []string) error {
 	var server = new(uplink.Server)
+	var schedulerWG sync.WaitGroup
+	schedulerCtx, schedulerCancel := context.WithCancel(context.Background())
+	defer schedulerCancel()
 
 	if cpuprofile != "" {
 		f, err := os.Create(cpuprofile)
@@ -66,11 +77,13 @@ func run(cmd *cobra.Command, args []string) error {
 		setupMonitoring,
 		enableUplinkChannels,
 		setupStorage,
+		setupFrameLog,
 		setGatewayBackend,
 		setupApplicationServer,
 		setupADR,
 		setupJoinServer,
 		setupNetworkController,
+		setupIntegration,
 		setupUplink,
 		setupDownlink,
 		fixV2RedisCache,
@@ -80,7 +93,11 @@ func run(cmd *cobra.Command, args []string) error {
 		setupRoaming,
 		setupGateways,
 		startLoRaServer(server),
-		startQueueScheduler,
+		startQueueScheduler(schedulerCtx, &schedulerWG),
+		startGatewayOfflineDetection(schedulerCtx, &schedulerWG),
+		startDeviceActivationPruning(schedulerCtx, &schedulerWG),
+		startRoamingAgreementReload(schedulerCtx, &schedulerWG),
+		setupHealthChecks,
 	}
 
 	for _, t := range tasks {
@@ -95,12 +112,18 @@ func run(cmd *cobra.Command, args []string) error {
 	log.WithField("signal", <-sigChan).Info("signal received")
 	go func() {
 		log.Warning("stopping chirpstack-network-server")
+		// stop the schedulers first, as these emit downlinks
+		schedulerCancel()
+		schedulerWG.Wait()
 		if err := server.Stop(); err != nil {
 			log.Fatal(err)
 		}
 		if err := gateway.Stop(); err != nil {
 			log.Fatal(err)
 		}
+		if err := integration.GetIntegration().Close(); err != nil {
+			log.Fatal(err)
+		}
 		exitChan <- struct{}{}
 	}()
 	select {
@@ -198,6 +221,13 @@ func setupStorage() error {
 	return nil
 }
 
+func setupFrameLog() error {
+	if err := framelog.Setup(config.C); err != nil {
+		return errors.Wrap(err, "setup framelog error")
+	}
+	return nil
+}
+
 func setupADR() error {
 	if err := adr.Setup(config.C); err != nil {
 		return errors.Wrap(err, "setup adr error")
@@ -272,8 +302,36 @@ func setupNetworkController() error {
 		}
 		ncClient := nc.NewNetworkControllerServiceClient(ncConn)
 		controller.SetClient(ncClient)
+
+		monitoring.RegisterReadinessCheck("network_controller", false, func(ctx context.Context) error {
+			if state := ncConn.GetState(); state == connectivity.TransientFailure || state == connectivity.Shutdown {
+				return fmt.Errorf("network-controller connection state: %s", state)
+			}
+			return nil
+		})
+	}
+
+	return nil
+}
+
+func setupIntegration() error {
+	var i integration.Integration
+	var err error
+
+	switch config.C.Integration.Type {
+	case "":
+		return nil
+	case "mqtt":
+		i, err = integrationmqtt.NewBackend(config.C)
+	default:
+		return fmt.Errorf("unexpected integration type: %s", config.C.Integration.Type)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "integration setup failed")
 	}
 
+	integration.SetIntegration(i)
 	return nil
 }
 
@@ -308,6 +366,29 @@ func setupRoaming() error {
 		return errors.Wrap(err, "setup roaming error")
 	}
 
+	if err := roaming.ReloadAgreements(context.Background()); err != nil {
+		return errors.Wrap(err, "load roaming agreements error")
+	}
+
+	return nil
+}
+
+func setupHealthChecks() error {
+	if hc, ok := gwbackend.Backend().(monitoring.HealthChecker); ok {
+		monitoring.RegisterReadinessCheck("gateway_backend", true, func(ctx context.Context) error {
+			return hc.HealthCheck()
+		})
+	}
+
+	if hc, ok := applicationserver.Pool().(monitoring.HealthChecker); ok {
+		monitoring.RegisterReadinessCheck("application_server", false, func(ctx context.Context) error {
+			return hc.HealthCheck()
+		})
+	}
+
+	monitoring.RegisterReadinessCheck("join_server", false, joinserver.HealthCheck)
+	monitoring.RegisterLivenessCheck("scheduler", downlink.SchedulerHealthCheck)
+
 	return nil
 }
 
@@ -325,14 +406,80 @@ func setupGateways() error {
 	return nil
 }
 
-func startQueueScheduler() error {
-	log.Info("starting downlink device-queue scheduler")
-	go downlink.DeviceQueueSchedulerLoop()
+func startQueueScheduler(ctx context.Context, wg *sync.WaitGroup) func() error {
+	return func() error {
+		log.Info("starting downlink device-queue scheduler")
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			downlink.DeviceQueueSchedulerLoop(ctx)
+		}()
+
+		log.Info("starting multicast scheduler")
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			downlink.MulticastQueueSchedulerLoop(ctx)
+		}()
+
+		if beacon.Enabled() {
+			log.Info("starting class-b beacon scheduler")
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				downlink.BeaconSchedulerLoop(ctx)
+			}()
+		}
 
-	log.Info("starting multicast scheduler")
-	go downlink.MulticastQueueSchedulerLoop()
+		return nil
+	}
+}
 
-	return nil
+func startGatewayOfflineDetection(ctx context.Context, wg *sync.WaitGroup) func() error {
+	return func() error {
+		if !config.C.NetworkServer.Gateway.OfflineDetection.Enabled {
+			return nil
+		}
+
+		log.Info("starting gateway offline detection")
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			gateway.OfflineDetectionLoop(ctx)
+		}()
+
+		return nil
+	}
+}
+
+func startRoamingAgreementReload(ctx context.Context, wg *sync.WaitGroup) func() error {
+	return func() error {
+		log.Info("starting roaming agreement reload")
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			roaming.AgreementReloadLoop(ctx)
+		}()
+
+		return nil
+	}
+}
+
+func startDeviceActivationPruning(ctx context.Context, wg *sync.WaitGroup) func() error {
+	return func() error {
+		if !join.PruneEnabled() {
+			return nil
+		}
+
+		log.Info("starting device-activation pruning")
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			join.DeviceActivationPruneLoop(ctx)
+		}()
+
+		return nil
+	}
 }
 
 func mustGetTransportCredentials(tlsCert, tlsKey, caCert string, verifyClientCert bool) credentials.TransportCredentials {
@@ -3,6 +3,9 @@ package adr
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
+	"sync"
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -14,6 +17,9 @@ import (
 	"github.com/brocaar/lorawan"
 )
 
+// maxNbTrans defines the max. NbTrans value that is used.
+const maxNbTrans = 3
+
 var pktLossRateTable = [][3]uint8{
 	{1, 1, 2},
 	{1, 2, 3},
@@ -35,64 +41,107 @@ func Setup(c config.Config) error {
 	return nil
 }
 
-// HandleADR handles ADR in case requested by the node and configured
-// in the device-session.
-func HandleADR(ctx context.Context, sp storage.ServiceProfile, ds storage.DeviceSession, linkADRReqBlock *storage.MACCommandBlock) ([]storage.MACCommandBlock, error) {
+// Handler defines the interface of an ADR algorithm.
+type Handler interface {
+	// ID returns the identifier of the algorithm. This is the value that
+	// must be set in the device-profile to select the algorithm.
+	ID() string
 
-	// if the node has ADR disabled or it's disabled gloablly
-	if !ds.ADR || disableADR {
-		return nil, nil
-	}
+	// Name returns the human-readable name of the algorithm.
+	Name() string
 
-	// get the max SNR from the UplinkHistory
-	var snrM float64 = -999
-	var historyCount int
-	for _, uh := range ds.UplinkHistory {
-		if uh.TXPowerIndex == ds.TXPowerIndex {
-			historyCount++
+	// Handle returns the desired data-rate, tx-power index and nb-trans.
+	Handle(req HandleRequest) (HandleResponse, error)
+}
 
-			if uh.MaxSNR > snrM {
-				snrM = uh.MaxSNR
-			}
-		}
-	}
+// HandleRequest contains the input of an ADR algorithm.
+type HandleRequest struct {
+	DeviceSession  storage.DeviceSession
+	ServiceProfile storage.ServiceProfile
+	UplinkHistory  []storage.UplinkHistory
+}
 
-	dr, err := band.Band().GetDataRate(ds.DR)
-	if err != nil {
-		return nil, errors.Wrap(err, "get data-rate error")
-	}
+// HandleResponse contains the output of an ADR algorithm.
+type HandleResponse struct {
+	DR           int
+	TXPowerIndex int
+	NbTrans      uint8
+}
 
-	requiredSNR, err := getRequiredSNRForSF(dr.SpreadFactor)
-	if err != nil {
-		return nil, err
+var (
+	handlersMux sync.RWMutex
+	handlers    = map[string]Handler{}
+)
+
+func init() {
+	RegisterHandler(&DefaultHandler{})
+	RegisterHandler(&ConservativeHandler{})
+	RegisterHandler(&LossOptimizedHandler{})
+}
+
+// RegisterHandler registers the given ADR handler. In case a handler with
+// the same ID was already registered, it will be replaced.
+func RegisterHandler(h Handler) {
+	handlersMux.Lock()
+	defer handlersMux.Unlock()
+
+	handlers[h.ID()] = h
+}
+
+// GetHandler returns the ADR handler for the given ID. In case no handler
+// is registered for the given ID, the default handler is returned.
+func GetHandler(id string) Handler {
+	handlersMux.RLock()
+	defer handlersMux.RUnlock()
+
+	if h, ok := handlers[id]; ok {
+		return h
 	}
+	return handlers[DefaultHandlerID]
+}
 
-	snrMargin := snrM - requiredSNR - installationMargin
-	nStep := int(snrMargin / 3)
+// GetHandlers returns all the registered ADR handlers, sorted by ID.
+func GetHandlers() []Handler {
+	handlersMux.RLock()
+	defer handlersMux.RUnlock()
 
-	// In case of negative steps the ADR algorithm will increase the TXPower
-	// if possible. To avoid up / down / up / down TXPower changes, wait until
-	// we have a full history table before making adjustments.
-	if nStep < 0 && historyCount != storage.UplinkHistorySize {
-		return nil, nil
+	var out []Handler
+	for _, h := range handlers {
+		out = append(out, h)
 	}
 
-	maxSupportedDR := sp.DRMax
-	maxSupportedTXPowerOffsetIndex := getMaxSupportedTXPowerOffsetIndexForDevice(ds)
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].ID() < out[j].ID()
+	})
 
-	var idealTXPowerIndex, idealDR int
+	return out
+}
 
-	if ds.DR > maxSupportedDR {
-		idealDR = maxSupportedDR
-		idealTXPowerIndex = ds.TXPowerIndex
-	} else {
-		idealTXPowerIndex, idealDR = getIdealTXPowerOffsetAndDR(nStep, ds.TXPowerIndex, ds.DR, ds.MinSupportedTXPowerIndex, maxSupportedTXPowerOffsetIndex, maxSupportedDR)
+// HandleADR handles ADR in case requested by the node and configured
+// in the device-session. The ADR algorithm is selected by the ADRAlgorithmID
+// of the device-profile. The chosen NbTrans is stored in the ADRNbTrans
+// field of the given device-session.
+func HandleADR(ctx context.Context, dp storage.DeviceProfile, sp storage.ServiceProfile, ds *storage.DeviceSession, linkADRReqBlock *storage.MACCommandBlock) ([]storage.MACCommandBlock, error) {
+
+	// if the node has ADR disabled or it's disabled gloablly
+	if !ds.ADR || disableADR {
+		return nil, nil
 	}
 
-	idealNbRep := getNbRep(ds.NbTrans, ds.GetPacketLossPercentage())
+	h := GetHandler(dp.ADRAlgorithmID)
+	resp, err := h.Handle(HandleRequest{
+		DeviceSession:  *ds,
+		ServiceProfile: sp,
+		UplinkHistory:  ds.UplinkHistory,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "handle adr error (algorithm: %s)", h.ID())
+	}
+
+	ds.ADRNbTrans = resp.NbTrans
 
 	// there is nothing to adjust
-	if ds.TXPowerIndex == idealTXPowerIndex && ds.DR == idealDR && ds.NbTrans == idealNbRep {
+	if ds.TXPowerIndex == resp.TXPowerIndex && ds.DR == resp.DR && ds.NbTrans == resp.NbTrans {
 		return nil, nil
 	}
 
@@ -119,12 +168,12 @@ func HandleADR(ctx context.Context, sp storage.ServiceProfile, ds storage.Device
 				{
 					CID: lorawan.LinkADRReq,
 					Payload: &lorawan.LinkADRReqPayload{
-						DataRate: uint8(idealDR),
-						TXPower:  uint8(idealTXPowerIndex),
+						DataRate: uint8(resp.DR),
+						TXPower:  uint8(resp.TXPowerIndex),
 						ChMask:   chMask,
 						Redundancy: lorawan.Redundancy{
 							ChMaskCntl: uint8(chMaskCntl),
-							NbRep:      uint8(idealNbRep),
+							NbRep:      resp.NbTrans,
 						},
 					},
 				},
@@ -140,25 +189,152 @@ func HandleADR(ctx context.Context, sp storage.ServiceProfile, ds storage.Device
 			return nil, fmt.Errorf("expected *lorawan.LinkADRReqPayload, got %T", lastMAC.Payload)
 		}
 
-		lastMACPl.DataRate = uint8(idealDR)
-		lastMACPl.TXPower = uint8(idealTXPowerIndex)
-		lastMACPl.Redundancy.NbRep = uint8(idealNbRep)
+		lastMACPl.DataRate = uint8(resp.DR)
+		lastMACPl.TXPower = uint8(resp.TXPowerIndex)
+		lastMACPl.Redundancy.NbRep = resp.NbTrans
 	}
 
 	log.WithFields(log.Fields{
 		"dev_eui":          ds.DevEUI,
+		"adr_algorithm_id": h.ID(),
 		"dr":               ds.DR,
-		"req_dr":           idealDR,
+		"req_dr":           resp.DR,
 		"tx_power":         ds.TXPowerIndex,
-		"req_tx_power_idx": idealTXPowerIndex,
+		"req_tx_power_idx": resp.TXPowerIndex,
 		"nb_trans":         ds.NbTrans,
-		"req_nb_trans":     idealNbRep,
+		"req_nb_trans":     resp.NbTrans,
+		"measured_per":     ds.PacketErrorRate,
+		"target_per":       sp.TargetPER,
 		"ctx_id":           ctx.Value(logging.ContextIDKey),
 	}).Info("adr request added to mac-command queue")
 
 	return []storage.MACCommandBlock{*linkADRReqBlock}, nil
 }
 
+// uplinkHistorySNR contains the SNR of the uplink history items that were
+// received using the current tx-power index of the device.
+type uplinkHistorySNR struct {
+	Count int
+	Max   float64
+	Avg   float64
+}
+
+// getUplinkHistorySNR returns the SNR of the uplink history items that were
+// received using the given tx-power index. When there are no such items,
+// Max is set to -999.
+func getUplinkHistorySNR(history []storage.UplinkHistory, txPowerIndex int) uplinkHistorySNR {
+	out := uplinkHistorySNR{
+		Max: -999,
+	}
+
+	var sum float64
+	for _, uh := range history {
+		if uh.TXPowerIndex != txPowerIndex {
+			continue
+		}
+
+		out.Count++
+		sum += uh.MaxSNR
+
+		if uh.MaxSNR > out.Max {
+			out.Max = uh.MaxSNR
+		}
+	}
+
+	if out.Count > 0 {
+		out.Avg = sum / float64(out.Count)
+	}
+
+	return out
+}
+
+// getTXPowerIndexAndDR returns the tx-power index and data-rate after
+// applying the given number of ADR steps. When the data-rate of the device
+// exceeds the max. data-rate of the service-profile, only the data-rate is
+// lowered.
+func getTXPowerIndexAndDR(req HandleRequest, nStep int) (int, int) {
+	ds := req.DeviceSession
+	maxSupportedDR := req.ServiceProfile.DRMax
+
+	if ds.DR > maxSupportedDR {
+		return ds.TXPowerIndex, maxSupportedDR
+	}
+
+	return getIdealTXPowerOffsetAndDR(nStep, ds.TXPowerIndex, ds.DR, ds.MinSupportedTXPowerIndex, getMaxSupportedTXPowerOffsetIndexForDevice(ds), maxSupportedDR)
+}
+
+// getNStep returns the number of ADR steps given the SNR of the uplink(s)
+// and the current data-rate of the device.
+func getNStep(dr int, snr float64) (int, error) {
+	dataRate, err := band.Band().GetDataRate(dr)
+	if err != nil {
+		return 0, errors.Wrap(err, "get data-rate error")
+	}
+
+	requiredSNR, err := getRequiredSNRForSF(dataRate.SpreadFactor)
+	if err != nil {
+		return 0, err
+	}
+
+	snrMargin := snr - requiredSNR - installationMargin
+	return int(snrMargin / 3), nil
+}
+
+// getNbTrans returns the NbTrans for the device. When the service-profile
+// defines a target PER, the NbTrans is adjusted to reach the target PER,
+// else the packet-loss table is used.
+func getNbTrans(ds storage.DeviceSession, sp storage.ServiceProfile, pktLossRate float64) uint8 {
+	if sp.TargetPER <= 0 {
+		return getNbRep(ds.NbTrans, pktLossRate)
+	}
+
+	// the packet-loss rate is only known once the history table is full
+	if len(ds.UplinkHistory) < storage.UplinkHistorySize {
+		return clampNbTrans(ds.NbTrans)
+	}
+
+	return getNbTransForTargetPER(ds.NbTrans, pktLossRate, float64(sp.TargetPER))
+}
+
+// getNbTransForTargetPER returns the NbTrans given the measured PER (in
+// percent) and the target PER (in percent). It adds a transmission when the
+// measured PER is above the target and removes one when the PER predicted
+// for one transmission less is still within the target.
+func getNbTransForTargetPER(currentNbTrans uint8, per, targetPER float64) uint8 {
+	currentNbTrans = clampNbTrans(currentNbTrans)
+
+	if per > targetPER {
+		if currentNbTrans < maxNbTrans {
+			return currentNbTrans + 1
+		}
+		return currentNbTrans
+	}
+
+	if currentNbTrans > 1 {
+		// A frame is lost when all its transmissions are lost. Estimate the
+		// error rate of a single transmission to predict the PER when
+		// using one transmission less.
+		txErrorRate := math.Pow(per/100, 1/float64(currentNbTrans))
+		predictedPER := math.Pow(txErrorRate, float64(currentNbTrans-1)) * 100
+
+		if predictedPER <= targetPER {
+			return currentNbTrans - 1
+		}
+	}
+
+	return currentNbTrans
+}
+
+func clampNbTrans(nbTrans uint8) uint8 {
+	if nbTrans < 1 {
+		return 1
+	}
+	if nbTrans > maxNbTrans {
+		return maxNbTrans
+	}
+	return nbTrans
+}
+
 func getNbRep(currentNbRep uint8, pktLossRate float64) uint8 {
 	if currentNbRep < 1 {
 		currentNbRep = 1
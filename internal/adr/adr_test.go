@@ -40,6 +40,31 @@ func TestADR(t *testing.T) {
 			}
 		})
 
+		Convey("Given a testtable for getNbTransForTargetPER", func() {
+			testTable := []struct {
+				PER             float64
+				TargetPER       float64
+				CurrentNbTrans  uint8
+				ExpectedNbTrans uint8
+			}{
+				{20, 10, 1, 2},
+				{20, 10, 3, 3},
+				{10, 10, 1, 1},
+				{0, 10, 2, 1},
+				{5, 10, 2, 2},   // predicted PER at NbTrans 1: 22.4%
+				{0.5, 10, 2, 1}, // predicted PER at NbTrans 1: 7.1%
+				{5, 10, 3, 3},   // predicted PER at NbTrans 2: 13.6%
+				{0, 10, 0, 1},   // invalid NbTrans
+				{50, 10, 5, 3},  // invalid NbTrans
+			}
+
+			for i, tst := range testTable {
+				Convey(fmt.Sprintf("Given PER: %f, TargetPER: %f, Current NbTrans: %d [%d]", tst.PER, tst.TargetPER, tst.CurrentNbTrans, i), func() {
+					So(getNbTransForTargetPER(tst.CurrentNbTrans, tst.PER, tst.TargetPER), ShouldEqual, tst.ExpectedNbTrans)
+				})
+			}
+		})
+
 		Convey("getMaxTXPowerOffsetIndex returns 7", func() {
 			So(getMaxTXPowerOffsetIndex(), ShouldEqual, 7)
 		})
@@ -604,7 +629,8 @@ func TestADR(t *testing.T) {
 
 				for i, tst := range testTable {
 					Convey(fmt.Sprintf("Test: %s [%d]", tst.Name, i), func() {
-						blocks, err := HandleADR(context.Background(), tst.ServiceProfile, tst.DeviceSession, tst.LinkADRReqBlock)
+						ds := tst.DeviceSession
+						blocks, err := HandleADR(context.Background(), storage.DeviceProfile{}, tst.ServiceProfile, &ds, tst.LinkADRReqBlock)
 						if tst.ExpectedError != nil {
 							So(err, ShouldNotBeNil)
 							So(err, ShouldResemble, tst.ExpectedError)
@@ -612,6 +638,12 @@ func TestADR(t *testing.T) {
 						So(err, ShouldBeNil)
 
 						So(blocks, ShouldResemble, tst.Expected)
+
+						if len(tst.Expected) != 0 {
+							macs := tst.Expected[0].MACCommands
+							pl := macs[len(macs)-1].Payload.(*lorawan.LinkADRReqPayload)
+							So(ds.ADRNbTrans, ShouldEqual, pl.Redundancy.NbRep)
+						}
 					})
 				}
 			})
@@ -651,7 +683,7 @@ func TestADR(t *testing.T) {
 					},
 				}
 
-				blocks, err := HandleADR(context.Background(), sp, ds, larb)
+				blocks, err := HandleADR(context.Background(), storage.DeviceProfile{}, sp, &ds, larb)
 
 				So(err, ShouldBeNil)
 				So(blocks, ShouldBeNil)
@@ -659,3 +691,112 @@ func TestADR(t *testing.T) {
 		})
 	})
 }
+
+func TestADRHandlers(t *testing.T) {
+	conf := test.GetConfig()
+	conf.NetworkServer.NetworkSettings.InstallationMargin = 5
+	if err := Setup(conf); err != nil {
+		t.Fatal(err)
+	}
+
+	Convey("Testing the ADR handlers", t, func() {
+		Convey("Then the built-in handlers are registered", func() {
+			var ids []string
+			for _, h := range GetHandlers() {
+				ids = append(ids, h.ID())
+			}
+			So(ids, ShouldResemble, []string{ConservativeHandlerID, DefaultHandlerID, LossOptimizedHandlerID})
+		})
+
+		Convey("Then getUplinkHistorySNR only takes the current tx-power index into account", func() {
+			snr := getUplinkHistorySNR([]storage.UplinkHistory{
+				{MaxSNR: 3, TXPowerIndex: 1},
+				{MaxSNR: 10, TXPowerIndex: 0},
+				{MaxSNR: 5, TXPowerIndex: 1},
+			}, 1)
+			So(snr, ShouldResemble, uplinkHistorySNR{Count: 2, Max: 5, Avg: 4})
+
+			snr = getUplinkHistorySNR(nil, 0)
+			So(snr, ShouldResemble, uplinkHistorySNR{Max: -999})
+		})
+
+		Convey("Then GetHandler falls back to the default handler for an unknown ID", func() {
+			So(GetHandler("").ID(), ShouldEqual, DefaultHandlerID)
+			So(GetHandler("unknown").ID(), ShouldEqual, DefaultHandlerID)
+			So(GetHandler(ConservativeHandlerID).ID(), ShouldEqual, ConservativeHandlerID)
+		})
+
+		sp := storage.ServiceProfile{
+			DRMin: 0,
+			DRMax: 5,
+		}
+
+		Convey("Given a testtable", func() {
+			var fullHistory, lossyHistory []storage.UplinkHistory
+			for i := 0; i < storage.UplinkHistorySize; i++ {
+				fullHistory = append(fullHistory, storage.UplinkHistory{FCnt: uint32(i), MaxSNR: 20})
+				lossyHistory = append(lossyHistory, storage.UplinkHistory{FCnt: uint32(i * 2), MaxSNR: -7})
+			}
+
+			testTable := []struct {
+				Name          string
+				Handler       Handler
+				DeviceSession storage.DeviceSession
+				Expected      HandleResponse
+			}{
+				{
+					Name:    "conservative: incomplete history",
+					Handler: &ConservativeHandler{},
+					DeviceSession: storage.DeviceSession{
+						DR:            2,
+						NbTrans:       1,
+						UplinkHistory: fullHistory[:10],
+					},
+					Expected: HandleResponse{DR: 2, TXPowerIndex: 0, NbTrans: 1},
+				},
+				{
+					Name:    "conservative: increase data-rate by one step",
+					Handler: &ConservativeHandler{},
+					DeviceSession: storage.DeviceSession{
+						DR:            2,
+						NbTrans:       1,
+						UplinkHistory: fullHistory,
+					},
+					Expected: HandleResponse{DR: 3, TXPowerIndex: 0, NbTrans: 1},
+				},
+				{
+					Name:    "default: increase data-rate to max",
+					Handler: &DefaultHandler{},
+					DeviceSession: storage.DeviceSession{
+						DR:            2,
+						NbTrans:       1,
+						UplinkHistory: fullHistory,
+					},
+					Expected: HandleResponse{DR: 5, TXPowerIndex: 7, NbTrans: 1},
+				},
+				{
+					Name:    "loss-optimized: decrease data-rate on high packet-loss",
+					Handler: &LossOptimizedHandler{},
+					DeviceSession: storage.DeviceSession{
+						DR:            3,
+						NbTrans:       1,
+						UplinkHistory: lossyHistory,
+					},
+					Expected: HandleResponse{DR: 2, TXPowerIndex: 0, NbTrans: 3},
+				},
+			}
+
+			for i, tst := range testTable {
+				Convey(fmt.Sprintf("Test: %s [%d]", tst.Name, i), func() {
+					resp, err := tst.Handler.Handle(HandleRequest{
+						DeviceSession:  tst.DeviceSession,
+						ServiceProfile: sp,
+						UplinkHistory:  tst.DeviceSession.UplinkHistory,
+					})
+					So(err, ShouldBeNil)
+					So(resp, ShouldResemble, tst.Expected)
+				})
+			}
+		})
+	})
+}
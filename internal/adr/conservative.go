@@ -0,0 +1,60 @@
+package adr
+
+import (
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+)
+
+// ConservativeHandlerID contains the ID of the conservative ADR algorithm.
+const ConservativeHandlerID = "conservative"
+
+// ConservativeHandler implements a conservative ADR algorithm. Instead of
+// the max. SNR, it uses the average SNR of the uplink history, it only
+// makes adjustments once the uplink history is complete and it changes the
+// data-rate or tx-power by at most one step at a time. This is intended for
+// devices with a varying link quality (e.g. mobile devices).
+type ConservativeHandler struct{}
+
+// ID returns the ID of the algorithm.
+func (h *ConservativeHandler) ID() string {
+	return ConservativeHandlerID
+}
+
+// Name returns the name of the algorithm.
+func (h *ConservativeHandler) Name() string {
+	return "Conservative ADR algorithm (averaged SNR)"
+}
+
+// Handle handles the ADR request.
+func (h *ConservativeHandler) Handle(req HandleRequest) (HandleResponse, error) {
+	ds := req.DeviceSession
+	resp := HandleResponse{
+		DR:           ds.DR,
+		TXPowerIndex: ds.TXPowerIndex,
+		NbTrans:      ds.NbTrans,
+	}
+
+	snr := getUplinkHistorySNR(req.UplinkHistory, ds.TXPowerIndex)
+
+	// wait until we have a full history table before making adjustments
+	if snr.Count != storage.UplinkHistorySize {
+		return resp, nil
+	}
+
+	nStep, err := getNStep(ds.DR, snr.Avg)
+	if err != nil {
+		return resp, err
+	}
+
+	if nStep > 1 {
+		nStep = 1
+	}
+	if nStep < -1 {
+		nStep = -1
+	}
+
+	resp.TXPowerIndex, resp.DR = getTXPowerIndexAndDR(req, nStep)
+
+	resp.NbTrans = getNbTrans(ds, req.ServiceProfile, ds.GetPacketLossPercentage())
+
+	return resp, nil
+}
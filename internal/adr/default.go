@@ -0,0 +1,52 @@
+package adr
+
+import (
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+)
+
+// DefaultHandlerID contains the ID of the default ADR algorithm.
+const DefaultHandlerID = "default"
+
+// DefaultHandler implements the default ADR algorithm. It uses the max. SNR
+// of the uplink history to calculate the ideal data-rate and tx-power.
+type DefaultHandler struct{}
+
+// ID returns the ID of the algorithm.
+func (h *DefaultHandler) ID() string {
+	return DefaultHandlerID
+}
+
+// Name returns the name of the algorithm.
+func (h *DefaultHandler) Name() string {
+	return "Default ADR algorithm"
+}
+
+// Handle handles the ADR request.
+func (h *DefaultHandler) Handle(req HandleRequest) (HandleResponse, error) {
+	ds := req.DeviceSession
+	resp := HandleResponse{
+		DR:           ds.DR,
+		TXPowerIndex: ds.TXPowerIndex,
+		NbTrans:      ds.NbTrans,
+	}
+
+	snr := getUplinkHistorySNR(req.UplinkHistory, ds.TXPowerIndex)
+
+	nStep, err := getNStep(ds.DR, snr.Max)
+	if err != nil {
+		return resp, err
+	}
+
+	// In case of negative steps the ADR algorithm will increase the TXPower
+	// if possible. To avoid up / down / up / down TXPower changes, wait until
+	// we have a full history table before making adjustments.
+	if nStep < 0 && snr.Count != storage.UplinkHistorySize {
+		return resp, nil
+	}
+
+	resp.TXPowerIndex, resp.DR = getTXPowerIndexAndDR(req, nStep)
+
+	resp.NbTrans = getNbTrans(ds, req.ServiceProfile, ds.GetPacketLossPercentage())
+
+	return resp, nil
+}
@@ -0,0 +1,72 @@
+package adr
+
+import (
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+)
+
+// LossOptimizedHandlerID contains the ID of the loss-optimized ADR algorithm.
+const LossOptimizedHandlerID = "loss_optimized"
+
+// lossOptimizedHoldPktLossRate defines the packet-loss rate (%) from which
+// the data-rate will not be increased and the tx-power will not be decreased.
+const lossOptimizedHoldPktLossRate = 10
+
+// lossOptimizedBackoffPktLossRate defines the packet-loss rate (%) from
+// which the data-rate will be decreased.
+const lossOptimizedBackoffPktLossRate = 30
+
+// LossOptimizedHandler implements an ADR algorithm which gives priority to
+// a low packet-loss over airtime and energy consumption. It is based on the
+// default algorithm, but it will not increase the data-rate (or decrease
+// the tx-power) when packet-loss is observed and it will decrease the
+// data-rate on a high packet-loss rate.
+type LossOptimizedHandler struct{}
+
+// ID returns the ID of the algorithm.
+func (h *LossOptimizedHandler) ID() string {
+	return LossOptimizedHandlerID
+}
+
+// Name returns the name of the algorithm.
+func (h *LossOptimizedHandler) Name() string {
+	return "Loss-optimized ADR algorithm"
+}
+
+// Handle handles the ADR request.
+func (h *LossOptimizedHandler) Handle(req HandleRequest) (HandleResponse, error) {
+	ds := req.DeviceSession
+	resp := HandleResponse{
+		DR:           ds.DR,
+		TXPowerIndex: ds.TXPowerIndex,
+		NbTrans:      ds.NbTrans,
+	}
+
+	snr := getUplinkHistorySNR(req.UplinkHistory, ds.TXPowerIndex)
+
+	nStep, err := getNStep(ds.DR, snr.Max)
+	if err != nil {
+		return resp, err
+	}
+
+	pktLossRate := ds.GetPacketLossPercentage()
+	if pktLossRate >= lossOptimizedHoldPktLossRate && nStep > 0 {
+		nStep = 0
+	}
+
+	// In case of negative steps the ADR algorithm will increase the TXPower
+	// if possible. To avoid up / down / up / down TXPower changes, wait until
+	// we have a full history table before making adjustments.
+	if nStep < 0 && snr.Count != storage.UplinkHistorySize {
+		return resp, nil
+	}
+
+	resp.TXPowerIndex, resp.DR = getTXPowerIndexAndDR(req, nStep)
+
+	if pktLossRate >= lossOptimizedBackoffPktLossRate && resp.DR > req.ServiceProfile.DRMin {
+		resp.DR--
+	}
+
+	resp.NbTrans = getNbTrans(ds, req.ServiceProfile, pktLossRate)
+
+	return resp, nil
+}
@@ -0,0 +1,103 @@
+// Package airtime implements the time-on-air calculation for LoRa and FSK
+// modulated frames.
+package airtime
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/brocaar/lorawan/band"
+)
+
+const (
+	// loraPreambleSymbols defines the number of preamble symbols used for
+	// LoRaWAN frames.
+	loraPreambleSymbols = 8
+
+	// loraCodingRate defines the LoRaWAN coding-rate (4/5).
+	loraCodingRate = 1
+
+	// fskOverheadBytes defines the FSK overhead in bytes: 5 bytes preamble,
+	// 3 bytes sync word, 1 byte length field and 2 bytes CRC.
+	fskOverheadBytes = 5 + 3 + 1 + 2
+)
+
+// CalculateLoRaAirtime returns the time-on-air of a LoRa modulated frame,
+// using the formula as described in the Semtech SX1276 datasheet.
+// The bandwidth must be given in Hz.
+func CalculateLoRaAirtime(payloadSize, sf, bandwidth, preambleNumber, codingRate int, headerEnabled, crcEnabled, lowDataRateOptimization bool) (time.Duration, error) {
+	if payloadSize < 0 {
+		return 0, fmt.Errorf("invalid payload size: %d", payloadSize)
+	}
+	if sf < 6 || sf > 12 {
+		return 0, fmt.Errorf("invalid spreading-factor: %d", sf)
+	}
+	if bandwidth <= 0 {
+		return 0, fmt.Errorf("invalid bandwidth: %d", bandwidth)
+	}
+	if codingRate < 1 || codingRate > 4 {
+		return 0, fmt.Errorf("invalid coding-rate: %d", codingRate)
+	}
+
+	symbolDuration := math.Pow(2, float64(sf)) / float64(bandwidth)
+	preambleDuration := (float64(preambleNumber) + 4.25) * symbolDuration
+
+	var crc, ih, de float64
+	if crcEnabled {
+		crc = 1
+	}
+	if !headerEnabled {
+		ih = 1
+	}
+	if lowDataRateOptimization {
+		de = 1
+	}
+
+	payloadSymbols := math.Ceil((8*float64(payloadSize)-4*float64(sf)+28+16*crc-20*ih)/(4*(float64(sf)-2*de))) * float64(codingRate+4)
+	payloadSymbols = 8 + math.Max(payloadSymbols, 0)
+	payloadDuration := payloadSymbols * symbolDuration
+
+	return time.Duration((preambleDuration + payloadDuration) * float64(time.Second)), nil
+}
+
+// CalculateFSKAirtime returns the time-on-air of a FSK modulated frame.
+// The bitrate must be given in bits / second.
+func CalculateFSKAirtime(payloadSize, bitRate int) (time.Duration, error) {
+	if payloadSize < 0 {
+		return 0, fmt.Errorf("invalid payload size: %d", payloadSize)
+	}
+	if bitRate <= 0 {
+		return 0, fmt.Errorf("invalid bitrate: %d", bitRate)
+	}
+
+	bits := float64((payloadSize + fskOverheadBytes) * 8)
+	return time.Duration(bits / float64(bitRate) * float64(time.Second)), nil
+}
+
+// CalculateUplinkAirtime returns the time-on-air of an uplink PHYPayload
+// of the given size, sent using the given band data-rate.
+func CalculateUplinkAirtime(dr band.DataRate, payloadSize int) (time.Duration, error) {
+	return calculateAirtime(dr, payloadSize, true)
+}
+
+// CalculateDownlinkAirtime returns the time-on-air of a downlink PHYPayload
+// of the given size, sent using the given band data-rate. Note that LoRaWAN
+// downlinks are sent without payload CRC.
+func CalculateDownlinkAirtime(dr band.DataRate, payloadSize int) (time.Duration, error) {
+	return calculateAirtime(dr, payloadSize, false)
+}
+
+func calculateAirtime(dr band.DataRate, payloadSize int, crc bool) (time.Duration, error) {
+	switch dr.Modulation {
+	case band.LoRaModulation:
+		// the low data-rate optimization is mandated for symbol durations
+		// exceeding 16ms (e.g. SF11 and SF12 at 125kHz)
+		ldro := math.Pow(2, float64(dr.SpreadFactor))/float64(dr.Bandwidth) > 16
+		return CalculateLoRaAirtime(payloadSize, dr.SpreadFactor, dr.Bandwidth*1000, loraPreambleSymbols, loraCodingRate, true, crc, ldro)
+	case band.FSKModulation:
+		return CalculateFSKAirtime(payloadSize, dr.BitRate)
+	default:
+		return 0, fmt.Errorf("unknown modulation: %s", dr.Modulation)
+	}
+}
@@ -0,0 +1,69 @@
+package airtime
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/brocaar/lorawan/band"
+)
+
+func TestCalculateUplinkAirtime(t *testing.T) {
+	tests := []struct {
+		DataRate    band.DataRate
+		PayloadSize int
+		Airtime     time.Duration
+		Error       string
+	}{
+		{
+			DataRate:    band.DataRate{Modulation: band.LoRaModulation, SpreadFactor: 7, Bandwidth: 125},
+			PayloadSize: 13,
+			Airtime:     46336 * time.Microsecond,
+		},
+		{
+			DataRate:    band.DataRate{Modulation: band.LoRaModulation, SpreadFactor: 12, Bandwidth: 125},
+			PayloadSize: 13,
+			Airtime:     1155072 * time.Microsecond,
+		},
+		{
+			DataRate:    band.DataRate{Modulation: band.LoRaModulation, SpreadFactor: 7, Bandwidth: 250},
+			PayloadSize: 13,
+			Airtime:     23168 * time.Microsecond,
+		},
+		{
+			DataRate:    band.DataRate{Modulation: band.FSKModulation, BitRate: 50000},
+			PayloadSize: 13,
+			Airtime:     3840 * time.Microsecond,
+		},
+		{
+			DataRate:    band.DataRate{Modulation: band.LoRaModulation, SpreadFactor: 13, Bandwidth: 125},
+			PayloadSize: 13,
+			Error:       "invalid spreading-factor: 13",
+		},
+	}
+
+	for i, tst := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			assert := require.New(t)
+
+			airtime, err := CalculateUplinkAirtime(tst.DataRate, tst.PayloadSize)
+			if tst.Error != "" {
+				assert.EqualError(err, tst.Error)
+				return
+			}
+
+			assert.NoError(err)
+			assert.Equal(tst.Airtime, airtime.Round(time.Microsecond))
+		})
+	}
+}
+
+func TestCalculateDownlinkAirtime(t *testing.T) {
+	assert := require.New(t)
+
+	airtime, err := CalculateDownlinkAirtime(band.DataRate{Modulation: band.LoRaModulation, SpreadFactor: 7, Bandwidth: 125}, 13)
+	assert.NoError(err)
+	assert.Equal(41216*time.Microsecond, airtime.Round(time.Microsecond))
+}
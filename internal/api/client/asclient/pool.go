@@ -5,6 +5,9 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -13,6 +16,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/balancer/roundrobin"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials"
 
 	"github.com/brocaar/chirpstack-api/go/v3/as"
@@ -132,3 +136,25 @@ func (p *pool) createClient(hostname string, caCert, tlsCert, tlsKey []byte) (*g
 
 	return asClient, as.NewApplicationServerServiceClient(asClient), nil
 }
+
+// HealthCheck returns an error when one or multiple application-server
+// connections are in the TransientFailure or Shutdown state.
+func (p *pool) HealthCheck() error {
+	p.RLock()
+	defer p.RUnlock()
+
+	var failed []string
+	for hostname, c := range p.clients {
+		switch c.clientConn.GetState() {
+		case connectivity.TransientFailure, connectivity.Shutdown:
+			failed = append(failed, hostname)
+		}
+	}
+
+	if len(failed) != 0 {
+		sort.Strings(failed)
+		return fmt.Errorf("application-server connection failure: %s", strings.Join(failed, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,5 @@
+//go:generate protoc -I=/protobuf/src -I=/tmp/chirpstack-api/protobuf -I=. --go_out=plugins=grpc:. extapi.proto
+
+// Package extapi contains the network-server API methods which are not
+// (yet) part of the ChirpStack Network Server API (chirpstack-api).
+package extapi
@@ -12,6 +12,7 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/brocaar/chirpstack-api/go/v3/ns"
+	"github.com/brocaar/chirpstack-network-server/internal/api/extapi"
 	"github.com/brocaar/chirpstack-network-server/internal/config"
 	"github.com/brocaar/chirpstack-network-server/internal/logging"
 	"github.com/brocaar/chirpstack-network-server/internal/tls"
@@ -41,6 +42,7 @@ func Setup(c config.Config) error {
 	gs := grpc.NewServer(opts...)
 	nsAPI := NewNetworkServerAPI()
 	ns.RegisterNetworkServerServiceServer(gs, nsAPI)
+	extapi.RegisterNetworkServerExtensionServiceServer(gs, NewExtensionAPI())
 
 	ln, err := net.Listen("tcp", apiConfig.Bind)
 	if err != nil {
@@ -8,6 +8,7 @@ import (
 	"github.com/brocaar/chirpstack-network-server/internal/downlink/data"
 	"github.com/brocaar/chirpstack-network-server/internal/downlink/multicast"
 	"github.com/brocaar/chirpstack-network-server/internal/downlink/proprietary"
+	"github.com/brocaar/chirpstack-network-server/internal/maccommand"
 	"github.com/brocaar/chirpstack-network-server/internal/storage"
 )
 
@@ -22,6 +23,8 @@ var errToCode = map[error]codes.Code{
 
 	multicast.ErrInvalidFCnt: codes.InvalidArgument,
 
+	maccommand.ErrForceRejoinNotSupported: codes.FailedPrecondition,
+
 	storage.ErrAlreadyExists:              codes.AlreadyExists,
 	storage.ErrDoesNotExist:               codes.NotFound,
 	storage.ErrInvalidName:                codes.InvalidArgument,
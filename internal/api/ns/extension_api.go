@@ -0,0 +1,711 @@
+package ns
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/duration"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"github.com/brocaar/chirpstack-network-server/internal/adr"
+	"github.com/brocaar/chirpstack-network-server/internal/api/extapi"
+	"github.com/brocaar/chirpstack-network-server/internal/downlink/multicast"
+	"github.com/brocaar/chirpstack-network-server/internal/fragmentation"
+	"github.com/brocaar/chirpstack-network-server/internal/framelog"
+	"github.com/brocaar/chirpstack-network-server/internal/gateway/channelplan"
+	"github.com/brocaar/chirpstack-network-server/internal/logging"
+	"github.com/brocaar/chirpstack-network-server/internal/maccommand"
+	"github.com/brocaar/chirpstack-network-server/internal/roaming"
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/lorawan"
+)
+
+// ExtensionAPI implements the network-server API methods which are not
+// (yet) part of the ChirpStack Network Server API.
+type ExtensionAPI struct{}
+
+// NewExtensionAPI returns a new ExtensionAPI.
+func NewExtensionAPI() *ExtensionAPI {
+	return &ExtensionAPI{}
+}
+
+// CreateRoamingAgreement creates the given roaming agreement.
+func (a *ExtensionAPI) CreateRoamingAgreement(ctx context.Context, req *extapi.CreateRoamingAgreementRequest) (*empty.Empty, error) {
+	if req.RoamingAgreement == nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, "roaming_agreement must not be nil")
+	}
+
+	ra, err := roamingAgreementFromPB(req.RoamingAgreement)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := roaming.ValidateRoamingAgreement(ra); err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	if err := storage.CreateRoamingAgreement(ctx, storage.DB(), &ra); err != nil {
+		return nil, errToRPCError(err)
+	}
+
+	reloadRoamingAgreements(ctx)
+
+	return &empty.Empty{}, nil
+}
+
+// GetRoamingAgreement returns the roaming agreement for the given NetID.
+func (a *ExtensionAPI) GetRoamingAgreement(ctx context.Context, req *extapi.GetRoamingAgreementRequest) (*extapi.GetRoamingAgreementResponse, error) {
+	netID, err := netIDFromBytes(req.NetId)
+	if err != nil {
+		return nil, err
+	}
+
+	ra, err := storage.GetRoamingAgreement(ctx, storage.DB(), netID)
+	if err != nil {
+		return nil, errToRPCError(err)
+	}
+
+	resp := extapi.GetRoamingAgreementResponse{
+		RoamingAgreement: roamingAgreementToPB(ra),
+	}
+
+	resp.CreatedAt, err = ptypes.TimestampProto(ra.CreatedAt)
+	if err != nil {
+		return nil, errToRPCError(err)
+	}
+
+	resp.UpdatedAt, err = ptypes.TimestampProto(ra.UpdatedAt)
+	if err != nil {
+		return nil, errToRPCError(err)
+	}
+
+	return &resp, nil
+}
+
+// UpdateRoamingAgreement updates the given roaming agreement.
+func (a *ExtensionAPI) UpdateRoamingAgreement(ctx context.Context, req *extapi.UpdateRoamingAgreementRequest) (*empty.Empty, error) {
+	if req.RoamingAgreement == nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, "roaming_agreement must not be nil")
+	}
+
+	ra, err := roamingAgreementFromPB(req.RoamingAgreement)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := storage.GetRoamingAgreement(ctx, storage.DB(), ra.NetID)
+	if err != nil {
+		return nil, errToRPCError(err)
+	}
+
+	// The TLS key is never returned, an empty value keeps the stored key.
+	if ra.TLSKey == "" {
+		ra.TLSKey = current.TLSKey
+	}
+
+	if ra.TLSCert == "" {
+		ra.TLSKey = ""
+	}
+
+	if err := roaming.ValidateRoamingAgreement(ra); err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	if err := storage.UpdateRoamingAgreement(ctx, storage.DB(), &ra); err != nil {
+		return nil, errToRPCError(err)
+	}
+
+	reloadRoamingAgreements(ctx)
+
+	return &empty.Empty{}, nil
+}
+
+// DeleteRoamingAgreement deletes the roaming agreement for the given NetID.
+func (a *ExtensionAPI) DeleteRoamingAgreement(ctx context.Context, req *extapi.DeleteRoamingAgreementRequest) (*empty.Empty, error) {
+	netID, err := netIDFromBytes(req.NetId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := storage.DeleteRoamingAgreement(ctx, storage.DB(), netID); err != nil {
+		return nil, errToRPCError(err)
+	}
+
+	reloadRoamingAgreements(ctx)
+
+	return &empty.Empty{}, nil
+}
+
+// ListRoamingAgreements returns all the roaming agreements.
+func (a *ExtensionAPI) ListRoamingAgreements(ctx context.Context, req *empty.Empty) (*extapi.ListRoamingAgreementsResponse, error) {
+	ras, err := storage.GetRoamingAgreements(ctx, storage.DB())
+	if err != nil {
+		return nil, errToRPCError(err)
+	}
+
+	var resp extapi.ListRoamingAgreementsResponse
+	for _, ra := range ras {
+		resp.Result = append(resp.Result, roamingAgreementToPB(ra))
+	}
+
+	return &resp, nil
+}
+
+// GetFrameLogsForGateway returns the frame-log history of the given gateway.
+func (a *ExtensionAPI) GetFrameLogsForGateway(ctx context.Context, req *extapi.GetFrameLogsForGatewayRequest) (*extapi.GetFrameLogsResponse, error) {
+	var gatewayID lorawan.EUI64
+	copy(gatewayID[:], req.GatewayId)
+
+	page, err := frameLogHistoryPage(req.Start, req.End, req.After, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	frameLogs, err := framelog.GetFrameLogHistoryForGateway(ctx, gatewayID, page)
+	if err != nil {
+		return nil, errToRPCError(err)
+	}
+
+	return frameLogsToPB(frameLogs)
+}
+
+// GetFrameLogsForDevice returns the frame-log history of the given device.
+func (a *ExtensionAPI) GetFrameLogsForDevice(ctx context.Context, req *extapi.GetFrameLogsForDeviceRequest) (*extapi.GetFrameLogsResponse, error) {
+	var devEUI lorawan.EUI64
+	copy(devEUI[:], req.DevEui)
+
+	page, err := frameLogHistoryPage(req.Start, req.End, req.After, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	frameLogs, err := framelog.GetFrameLogHistoryForDevice(ctx, devEUI, page)
+	if err != nil {
+		return nil, errToRPCError(err)
+	}
+
+	return frameLogsToPB(frameLogs)
+}
+
+// GetGatewayStatus returns the status of the given gateway.
+func (a *ExtensionAPI) GetGatewayStatus(ctx context.Context, req *extapi.GetGatewayStatusRequest) (*extapi.GetGatewayStatusResponse, error) {
+	var id lorawan.EUI64
+	copy(id[:], req.GatewayId)
+
+	gw, err := storage.GetGateway(ctx, storage.DB(), id)
+	if err != nil {
+		return nil, errToRPCError(err)
+	}
+
+	resp := extapi.GetGatewayStatusResponse{
+		Offline: gw.Offline,
+	}
+
+	if gw.LastSeenAt != nil {
+		resp.LastSeenAt, err = ptypes.TimestampProto(*gw.LastSeenAt)
+		if err != nil {
+			return nil, errToRPCError(err)
+		}
+	}
+
+	return &resp, nil
+}
+
+// ListADRAlgorithms returns the registered ADR algorithms.
+func (a *ExtensionAPI) ListADRAlgorithms(ctx context.Context, req *empty.Empty) (*extapi.ListADRAlgorithmsResponse, error) {
+	var resp extapi.ListADRAlgorithmsResponse
+	for _, h := range adr.GetHandlers() {
+		resp.Result = append(resp.Result, &extapi.ADRAlgorithm{
+			Id:   h.ID(),
+			Name: h.Name(),
+		})
+	}
+
+	return &resp, nil
+}
+
+// GetDeviceProfileADRAlgorithm returns the ADR algorithm of the given device-profile.
+func (a *ExtensionAPI) GetDeviceProfileADRAlgorithm(ctx context.Context, req *extapi.GetDeviceProfileADRAlgorithmRequest) (*extapi.GetDeviceProfileADRAlgorithmResponse, error) {
+	var dpID uuid.UUID
+	copy(dpID[:], req.DeviceProfileId)
+
+	dp, err := storage.GetDeviceProfile(ctx, storage.DB(), dpID)
+	if err != nil {
+		return nil, errToRPCError(err)
+	}
+
+	return &extapi.GetDeviceProfileADRAlgorithmResponse{
+		AdrAlgorithmId: dp.ADRAlgorithmID,
+	}, nil
+}
+
+// SetDeviceProfileADRAlgorithm sets the ADR algorithm of the given device-profile.
+func (a *ExtensionAPI) SetDeviceProfileADRAlgorithm(ctx context.Context, req *extapi.SetDeviceProfileADRAlgorithmRequest) (*empty.Empty, error) {
+	// adr.GetHandler falls back to the default algorithm for unknown IDs
+	if req.AdrAlgorithmId != "" && adr.GetHandler(req.AdrAlgorithmId).ID() != req.AdrAlgorithmId {
+		return nil, grpc.Errorf(codes.InvalidArgument, "unknown adr_algorithm_id: %s", req.AdrAlgorithmId)
+	}
+
+	var dpID uuid.UUID
+	copy(dpID[:], req.DeviceProfileId)
+
+	dp, err := storage.GetDeviceProfile(ctx, storage.DB(), dpID)
+	if err != nil {
+		return nil, errToRPCError(err)
+	}
+
+	dp.ADRAlgorithmID = req.AdrAlgorithmId
+
+	if err := storage.FlushDeviceProfileCache(ctx, dp.ID); err != nil {
+		return nil, errToRPCError(err)
+	}
+
+	if err := storage.UpdateDeviceProfile(ctx, storage.DB(), &dp); err != nil {
+		return nil, errToRPCError(err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// GetDeviceADRState returns the ADR state of the given device.
+func (a *ExtensionAPI) GetDeviceADRState(ctx context.Context, req *extapi.GetDeviceADRStateRequest) (*extapi.GetDeviceADRStateResponse, error) {
+	var devEUI lorawan.EUI64
+	copy(devEUI[:], req.DevEui)
+
+	ds, err := storage.GetDeviceSession(ctx, devEUI)
+	if err != nil {
+		return nil, errToRPCError(err)
+	}
+
+	sp, err := storage.GetServiceProfile(ctx, storage.DB(), ds.ServiceProfileID)
+	if err != nil {
+		return nil, errToRPCError(err)
+	}
+
+	return &extapi.GetDeviceADRStateResponse{
+		PacketErrorRate: ds.PacketErrorRate,
+		TargetPer:       uint32(sp.TargetPER),
+		NbTrans:         uint32(ds.NbTrans),
+		AdrNbTrans:      uint32(ds.ADRNbTrans),
+	}, nil
+}
+
+// ForceRejoin enqueues a ForceRejoinReq mac-command for the given LoRaWAN 1.1 device.
+func (a *ExtensionAPI) ForceRejoin(ctx context.Context, req *extapi.ForceRejoinRequest) (*empty.Empty, error) {
+	var devEUI lorawan.EUI64
+	copy(devEUI[:], req.DevEui)
+
+	p, err := forceRejoinParametersFromPB(req.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := maccommand.EnqueueForceRejoin(ctx, storage.DB(), devEUI, p.RejoinType, p.DR, p.MaxRetries, p.Period); err != nil {
+		return nil, errToRPCError(err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// ForceRejoinForDeviceProfile enqueues a ForceRejoinReq mac-command for all
+// the activated LoRaWAN 1.1 devices using the given device-profile.
+func (a *ExtensionAPI) ForceRejoinForDeviceProfile(ctx context.Context, req *extapi.ForceRejoinForDeviceProfileRequest) (*extapi.ForceRejoinForDeviceProfileResponse, error) {
+	var dpID uuid.UUID
+	copy(dpID[:], req.DeviceProfileId)
+
+	p, err := forceRejoinParametersFromPB(req.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	devEUIs, err := maccommand.EnqueueForceRejoinForDeviceProfile(ctx, dpID, p.RejoinType, p.DR, p.MaxRetries, p.Period)
+	if err != nil {
+		return nil, errToRPCError(err)
+	}
+
+	var resp extapi.ForceRejoinForDeviceProfileResponse
+	for i := range devEUIs {
+		resp.DevEuis = append(resp.DevEuis, devEUIs[i][:])
+	}
+
+	return &resp, nil
+}
+
+// GetForceRejoin returns the force-rejoin progress of the given device.
+func (a *ExtensionAPI) GetForceRejoin(ctx context.Context, req *extapi.GetForceRejoinRequest) (*extapi.GetForceRejoinResponse, error) {
+	var devEUI lorawan.EUI64
+	copy(devEUI[:], req.DevEui)
+
+	fr, err := storage.GetForceRejoin(ctx, storage.DB(), devEUI)
+	if err != nil {
+		return nil, errToRPCError(err)
+	}
+
+	pb, err := forceRejoinToPB(fr)
+	if err != nil {
+		return nil, errToRPCError(err)
+	}
+
+	return &extapi.GetForceRejoinResponse{
+		ForceRejoin: pb,
+	}, nil
+}
+
+// ListForceRejoinsForDeviceProfile returns the force-rejoin progress of the
+// devices using the given device-profile.
+func (a *ExtensionAPI) ListForceRejoinsForDeviceProfile(ctx context.Context, req *extapi.ListForceRejoinsForDeviceProfileRequest) (*extapi.ListForceRejoinsResponse, error) {
+	var dpID uuid.UUID
+	copy(dpID[:], req.DeviceProfileId)
+
+	items, err := storage.GetForceRejoinsForDeviceProfile(ctx, storage.DB(), dpID)
+	if err != nil {
+		return nil, errToRPCError(err)
+	}
+
+	var resp extapi.ListForceRejoinsResponse
+	for _, fr := range items {
+		pb, err := forceRejoinToPB(fr)
+		if err != nil {
+			return nil, errToRPCError(err)
+		}
+		resp.Result = append(resp.Result, pb)
+	}
+
+	return &resp, nil
+}
+
+// CreateFragmentationSession creates a fragmented data block transport
+// session (e.g. FUOTA) for the given multicast-group.
+func (a *ExtensionAPI) CreateFragmentationSession(ctx context.Context, req *extapi.CreateFragmentationSessionRequest) (*extapi.CreateFragmentationSessionResponse, error) {
+	if req.FragmentationSession == nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, "fragmentation_session must not be nil")
+	}
+
+	pb := req.FragmentationSession
+	if pb.FragIndex > 3 {
+		return nil, grpc.Errorf(codes.InvalidArgument, "frag_index must be between 0 and 3")
+	}
+
+	fs := storage.FragmentationSession{
+		FragIndex:    uint8(pb.FragIndex),
+		FragmentSize: int(pb.FragmentSize),
+		Redundancy:   int(pb.Redundancy),
+		Data:         req.Data,
+	}
+	copy(fs.ID[:], pb.Id)
+	copy(fs.MulticastGroupID[:], pb.MulticastGroupId)
+
+	err := storage.Transaction(func(tx sqlx.Ext) error {
+		return multicast.CreateFragmentationSession(ctx, tx, &fs)
+	})
+	if err != nil {
+		if errors.Cause(err) == multicast.ErrInvalidFragmentationSession {
+			return nil, grpc.Errorf(codes.InvalidArgument, err.Error())
+		}
+		return nil, errToRPCError(err)
+	}
+
+	out, err := fragmentationSessionToPB(fs)
+	if err != nil {
+		return nil, errToRPCError(err)
+	}
+
+	return &extapi.CreateFragmentationSessionResponse{
+		FragmentationSession: out,
+	}, nil
+}
+
+// GetFragmentationSession returns the fragmentation-session for the given ID.
+func (a *ExtensionAPI) GetFragmentationSession(ctx context.Context, req *extapi.GetFragmentationSessionRequest) (*extapi.GetFragmentationSessionResponse, error) {
+	var id uuid.UUID
+	copy(id[:], req.Id)
+
+	fs, err := storage.GetFragmentationSession(ctx, storage.DB(), id)
+	if err != nil {
+		return nil, errToRPCError(err)
+	}
+
+	out, err := fragmentationSessionToPB(fs)
+	if err != nil {
+		return nil, errToRPCError(err)
+	}
+
+	return &extapi.GetFragmentationSessionResponse{
+		FragmentationSession: out,
+	}, nil
+}
+
+// ListFragmentationSessionsForMulticastGroup returns the fragmentation-sessions
+// of the given multicast-group.
+func (a *ExtensionAPI) ListFragmentationSessionsForMulticastGroup(ctx context.Context, req *extapi.ListFragmentationSessionsForMulticastGroupRequest) (*extapi.ListFragmentationSessionsResponse, error) {
+	var mgID uuid.UUID
+	copy(mgID[:], req.MulticastGroupId)
+
+	sessions, err := storage.GetFragmentationSessionsForMulticastGroup(ctx, storage.DB(), mgID)
+	if err != nil {
+		return nil, errToRPCError(err)
+	}
+
+	var resp extapi.ListFragmentationSessionsResponse
+	for _, fs := range sessions {
+		out, err := fragmentationSessionToPB(fs)
+		if err != nil {
+			return nil, errToRPCError(err)
+		}
+		resp.Result = append(resp.Result, out)
+	}
+
+	return &resp, nil
+}
+
+// PreviewGatewayConfiguration validates the channel-plan of the given
+// gateway-profile and returns the generated gateway configuration.
+func (a *ExtensionAPI) PreviewGatewayConfiguration(ctx context.Context, req *extapi.PreviewGatewayConfigurationRequest) (*extapi.PreviewGatewayConfigurationResponse, error) {
+	var gpID uuid.UUID
+	copy(gpID[:], req.GatewayProfileId)
+
+	var gatewayID lorawan.EUI64
+	copy(gatewayID[:], req.GatewayId)
+
+	gp, err := storage.GetGatewayProfile(ctx, storage.DB(), gpID)
+	if err != nil {
+		return nil, errToRPCError(err)
+	}
+
+	if err := channelplan.Validate(gp); err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, "invalid channel-plan: %s", err)
+	}
+
+	conf, err := channelplan.GatewayConfiguration(gatewayID, gp)
+	if err != nil {
+		return nil, errToRPCError(err)
+	}
+
+	return &extapi.PreviewGatewayConfigurationResponse{
+		GatewayConfiguration: &conf,
+	}, nil
+}
+
+// reloadRoamingAgreements makes the roaming changes effective on this
+// instance directly. Other instances pick up the changes at the next
+// agreement reload.
+func reloadRoamingAgreements(ctx context.Context) {
+	if err := roaming.ReloadAgreements(ctx); err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"ctx_id": ctx.Value(logging.ContextIDKey),
+		}).Error("api: reload roaming agreements error")
+	}
+}
+
+func netIDFromBytes(b []byte) (lorawan.NetID, error) {
+	var netID lorawan.NetID
+	if len(b) != len(netID) {
+		return netID, grpc.Errorf(codes.InvalidArgument, "net_id must be exactly %d bytes", len(netID))
+	}
+	copy(netID[:], b)
+	return netID, nil
+}
+
+func roamingAgreementFromPB(pb *extapi.RoamingAgreement) (storage.RoamingAgreement, error) {
+	ra := storage.RoamingAgreement{
+		Server:                  pb.Server,
+		CACert:                  pb.CaCert,
+		TLSCert:                 pb.TlsCert,
+		TLSKey:                  pb.TlsKey,
+		Async:                   pb.Async,
+		PassiveRoaming:          pb.PassiveRoaming,
+		PassiveRoamingKEKLabel:  pb.PassiveRoamingKekLabel,
+		HandoverRoaming:         pb.HandoverRoaming,
+		HandoverRoamingKEKLabel: pb.HandoverRoamingKekLabel,
+	}
+
+	var err error
+	ra.NetID, err = netIDFromBytes(pb.NetId)
+	if err != nil {
+		return ra, err
+	}
+
+	for _, d := range []struct {
+		name string
+		pb   *duration.Duration
+		out  *time.Duration
+	}{
+		{"async_timeout", pb.AsyncTimeout, &ra.AsyncTimeout},
+		{"passive_roaming_lifetime", pb.PassiveRoamingLifetime, &ra.PassiveRoamingLifetime},
+		{"handover_roaming_lifetime", pb.HandoverRoamingLifetime, &ra.HandoverRoamingLifetime},
+	} {
+		if d.pb == nil {
+			continue
+		}
+
+		*d.out, err = ptypes.Duration(d.pb)
+		if err != nil {
+			return ra, grpc.Errorf(codes.InvalidArgument, "%s: %s", d.name, err)
+		}
+	}
+
+	return ra, nil
+}
+
+func roamingAgreementToPB(ra storage.RoamingAgreement) *extapi.RoamingAgreement {
+	return &extapi.RoamingAgreement{
+		NetId:                   ra.NetID[:],
+		Server:                  ra.Server,
+		CaCert:                  ra.CACert,
+		TlsCert:                 ra.TLSCert,
+		TlsKeySet:               ra.TLSKey != "",
+		Async:                   ra.Async,
+		AsyncTimeout:            ptypes.DurationProto(ra.AsyncTimeout),
+		PassiveRoaming:          ra.PassiveRoaming,
+		PassiveRoamingLifetime:  ptypes.DurationProto(ra.PassiveRoamingLifetime),
+		PassiveRoamingKekLabel:  ra.PassiveRoamingKEKLabel,
+		HandoverRoaming:         ra.HandoverRoaming,
+		HandoverRoamingLifetime: ptypes.DurationProto(ra.HandoverRoamingLifetime),
+		HandoverRoamingKekLabel: ra.HandoverRoamingKEKLabel,
+	}
+}
+
+func frameLogHistoryPage(start, end *timestamp.Timestamp, after string, limit uint32) (framelog.HistoryPage, error) {
+	page := framelog.HistoryPage{
+		After: after,
+		Limit: int64(limit),
+	}
+
+	var err error
+	if start != nil {
+		page.Start, err = ptypes.Timestamp(start)
+		if err != nil {
+			return page, grpc.Errorf(codes.InvalidArgument, "start: %s", err)
+		}
+	}
+
+	if end != nil {
+		page.End, err = ptypes.Timestamp(end)
+		if err != nil {
+			return page, grpc.Errorf(codes.InvalidArgument, "end: %s", err)
+		}
+	}
+
+	return page, nil
+}
+
+func frameLogsToPB(frameLogs []framelog.FrameLog) (*extapi.GetFrameLogsResponse, error) {
+	var resp extapi.GetFrameLogsResponse
+
+	for _, fl := range frameLogs {
+		t, err := ptypes.TimestampProto(fl.Time)
+		if err != nil {
+			return nil, errToRPCError(err)
+		}
+
+		resp.Result = append(resp.Result, &extapi.FrameLog{
+			Id:                  fl.ID,
+			Time:                t,
+			UplinkFrame:         fl.UplinkFrame,
+			DownlinkFrame:       fl.DownlinkFrame,
+			UplinkRateLimited:   fl.UplinkRateLimited,
+			DownlinkRateLimited: fl.DownlinkRateLimited,
+		})
+	}
+
+	return &resp, nil
+}
+
+func forceRejoinParametersFromPB(pb *extapi.ForceRejoinParameters) (storage.ForceRejoin, error) {
+	if pb == nil {
+		return storage.ForceRejoin{}, grpc.Errorf(codes.InvalidArgument, "parameters must not be nil")
+	}
+
+	fr := storage.ForceRejoin{
+		RejoinType: int(pb.RejoinType),
+		DR:         int(pb.Dr),
+		MaxRetries: int(pb.MaxRetries),
+		Period:     int(pb.Period),
+	}
+
+	if err := maccommand.ValidateForceRejoinParameters(fr.RejoinType, fr.DR, fr.MaxRetries, fr.Period); err != nil {
+		return fr, grpc.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	return fr, nil
+}
+
+func forceRejoinToPB(fr storage.ForceRejoin) (*extapi.ForceRejoin, error) {
+	out := extapi.ForceRejoin{
+		DevEui: fr.DevEUI[:],
+		Parameters: &extapi.ForceRejoinParameters{
+			RejoinType: uint32(fr.RejoinType),
+			Dr:         uint32(fr.DR),
+			MaxRetries: uint32(fr.MaxRetries),
+			Period:     uint32(fr.Period),
+		},
+	}
+
+	var err error
+	out.CreatedAt, err = ptypes.TimestampProto(fr.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if fr.SentAt != nil {
+		out.SentAt, err = ptypes.TimestampProto(*fr.SentAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if fr.CompletedAt != nil {
+		out.CompletedAt, err = ptypes.TimestampProto(*fr.CompletedAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &out, nil
+}
+
+// fragmentationSessionToPB converts the given fragmentation-session. As the
+// data is not returned when listing sessions, the number of uncoded fragments
+// is derived from the fragment count and the padding is only set when the
+// data is present.
+func fragmentationSessionToPB(fs storage.FragmentationSession) (*extapi.FragmentationSession, error) {
+	nbFrag := fs.FragmentCount - fs.Redundancy
+
+	out := extapi.FragmentationSession{
+		Id:                fs.ID.Bytes(),
+		MulticastGroupId:  fs.MulticastGroupID.Bytes(),
+		FragIndex:         uint32(fs.FragIndex),
+		FragmentSize:      uint32(fs.FragmentSize),
+		Redundancy:        uint32(fs.Redundancy),
+		NbFrag:            uint32(nbFrag),
+		FragmentCount:     uint32(fs.FragmentCount),
+		FragmentsEnqueued: uint32(fs.FragmentsEnqueued),
+	}
+
+	if fs.Data != nil {
+		out.Padding = uint32(fragmentation.Padding(len(fs.Data), fs.FragmentSize))
+	}
+
+	var err error
+	out.CreatedAt, err = ptypes.TimestampProto(fs.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if fs.CompletedAt != nil {
+		out.CompletedAt, err = ptypes.TimestampProto(*fs.CompletedAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &out, nil
+}
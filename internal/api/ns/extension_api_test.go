@@ -0,0 +1,695 @@
+package ns
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"github.com/brocaar/chirpstack-api/go/v3/ns"
+	"github.com/brocaar/chirpstack-network-server/internal/adr"
+	"github.com/brocaar/chirpstack-network-server/internal/api/extapi"
+	"github.com/brocaar/chirpstack-network-server/internal/framelog"
+	"github.com/brocaar/chirpstack-network-server/internal/roaming"
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/chirpstack-network-server/internal/test"
+	"github.com/brocaar/lorawan"
+)
+
+type ExtensionAPITestSuite struct {
+	suite.Suite
+	api extapi.NetworkServerExtensionServiceServer
+}
+
+func (ts *ExtensionAPITestSuite) SetupSuite() {
+	assert := require.New(ts.T())
+	conf := test.GetConfig()
+	assert.NoError(storage.Setup(conf))
+	assert.NoError(roaming.Setup(conf))
+	test.MustResetDB(storage.DB().DB)
+	ts.api = NewExtensionAPI()
+}
+
+func (ts *ExtensionAPITestSuite) SetupTest() {
+	storage.RedisClient().FlushAll()
+}
+
+func (ts *ExtensionAPITestSuite) TestRoamingAgreement() {
+	ra := extapi.RoamingAgreement{
+		NetId:                  []byte{6, 6, 6},
+		Server:                 "https://example.com:1234/",
+		Async:                  true,
+		AsyncTimeout:           ptypes.DurationProto(time.Second),
+		PassiveRoaming:         true,
+		PassiveRoamingLifetime: ptypes.DurationProto(time.Hour),
+		PassiveRoamingKekLabel: "pr-kek",
+		HandoverRoaming:        false,
+		// zero durations are returned as a 0s duration
+		HandoverRoamingLifetime: ptypes.DurationProto(0),
+	}
+
+	ts.T().Run("Create invalid NetID", func(t *testing.T) {
+		assert := require.New(t)
+
+		_, err := ts.api.CreateRoamingAgreement(context.Background(), &extapi.CreateRoamingAgreementRequest{
+			RoamingAgreement: &extapi.RoamingAgreement{
+				NetId: []byte{1, 2},
+			},
+		})
+		assert.Equal(codes.InvalidArgument, grpc.Code(err))
+	})
+
+	ts.T().Run("Create invalid CA certificate", func(t *testing.T) {
+		assert := require.New(t)
+
+		_, err := ts.api.CreateRoamingAgreement(context.Background(), &extapi.CreateRoamingAgreementRequest{
+			RoamingAgreement: &extapi.RoamingAgreement{
+				NetId:  []byte{6, 6, 6},
+				CaCert: "invalid",
+			},
+		})
+		assert.Equal(codes.InvalidArgument, grpc.Code(err))
+
+		_, err = ts.api.GetRoamingAgreement(context.Background(), &extapi.GetRoamingAgreementRequest{
+			NetId: []byte{6, 6, 6},
+		})
+		assert.Equal(codes.NotFound, grpc.Code(err))
+	})
+
+	ts.T().Run("Create", func(t *testing.T) {
+		assert := require.New(t)
+
+		_, err := ts.api.CreateRoamingAgreement(context.Background(), &extapi.CreateRoamingAgreementRequest{
+			RoamingAgreement: &ra,
+		})
+		assert.NoError(err)
+
+		// the agreement is effective directly
+		assert.Equal(time.Hour, roaming.GetPassiveRoamingLifetime(lorawan.NetID{6, 6, 6}))
+
+		t.Run("Get", func(t *testing.T) {
+			assert := require.New(t)
+
+			resp, err := ts.api.GetRoamingAgreement(context.Background(), &extapi.GetRoamingAgreementRequest{
+				NetId: []byte{6, 6, 6},
+			})
+			assert.NoError(err)
+			assert.Equal(&ra, resp.RoamingAgreement)
+			assert.NotNil(resp.CreatedAt)
+			assert.NotNil(resp.UpdatedAt)
+		})
+
+		t.Run("List", func(t *testing.T) {
+			assert := require.New(t)
+
+			resp, err := ts.api.ListRoamingAgreements(context.Background(), &empty.Empty{})
+			assert.NoError(err)
+			assert.Len(resp.Result, 1)
+			assert.Equal(&ra, resp.Result[0])
+		})
+
+		t.Run("Update", func(t *testing.T) {
+			assert := require.New(t)
+
+			ra.PassiveRoamingLifetime = ptypes.DurationProto(time.Minute)
+			ra.HandoverRoaming = true
+			ra.HandoverRoamingKekLabel = "hr-kek"
+
+			_, err := ts.api.UpdateRoamingAgreement(context.Background(), &extapi.UpdateRoamingAgreementRequest{
+				RoamingAgreement: &ra,
+			})
+			assert.NoError(err)
+
+			resp, err := ts.api.GetRoamingAgreement(context.Background(), &extapi.GetRoamingAgreementRequest{
+				NetId: []byte{6, 6, 6},
+			})
+			assert.NoError(err)
+			assert.Equal(&ra, resp.RoamingAgreement)
+			assert.Equal(time.Minute, roaming.GetPassiveRoamingLifetime(lorawan.NetID{6, 6, 6}))
+		})
+
+		t.Run("Update invalid TLS key", func(t *testing.T) {
+			assert := require.New(t)
+
+			invalid := ra
+			invalid.TlsCert, _ = newTestTLSCertificate(t)
+			invalid.TlsKey = "invalid"
+
+			_, err := ts.api.UpdateRoamingAgreement(context.Background(), &extapi.UpdateRoamingAgreementRequest{
+				RoamingAgreement: &invalid,
+			})
+			assert.Equal(codes.InvalidArgument, grpc.Code(err))
+		})
+
+		t.Run("Update TLS certificate", func(t *testing.T) {
+			assert := require.New(t)
+
+			ra.TlsCert, ra.TlsKey = newTestTLSCertificate(t)
+
+			_, err := ts.api.UpdateRoamingAgreement(context.Background(), &extapi.UpdateRoamingAgreementRequest{
+				RoamingAgreement: &ra,
+			})
+			assert.NoError(err)
+
+			// the TLS key is never returned
+			ra.TlsKey = ""
+			ra.TlsKeySet = true
+
+			resp, err := ts.api.GetRoamingAgreement(context.Background(), &extapi.GetRoamingAgreementRequest{
+				NetId: []byte{6, 6, 6},
+			})
+			assert.NoError(err)
+			assert.Equal(&ra, resp.RoamingAgreement)
+
+			t.Run("Update without TLS key", func(t *testing.T) {
+				assert := require.New(t)
+
+				// an empty TLS key keeps the stored key
+				_, err := ts.api.UpdateRoamingAgreement(context.Background(), &extapi.UpdateRoamingAgreementRequest{
+					RoamingAgreement: &ra,
+				})
+				assert.NoError(err)
+
+				resp, err := ts.api.GetRoamingAgreement(context.Background(), &extapi.GetRoamingAgreementRequest{
+					NetId: []byte{6, 6, 6},
+				})
+				assert.NoError(err)
+				assert.True(resp.RoamingAgreement.TlsKeySet)
+				assert.Equal("", resp.RoamingAgreement.TlsKey)
+			})
+		})
+
+		t.Run("Delete", func(t *testing.T) {
+			assert := require.New(t)
+
+			_, err := ts.api.DeleteRoamingAgreement(context.Background(), &extapi.DeleteRoamingAgreementRequest{
+				NetId: []byte{6, 6, 6},
+			})
+			assert.NoError(err)
+
+			_, err = ts.api.GetRoamingAgreement(context.Background(), &extapi.GetRoamingAgreementRequest{
+				NetId: []byte{6, 6, 6},
+			})
+			assert.Equal(codes.NotFound, grpc.Code(err))
+
+			_, err = roaming.GetClientForNetID(lorawan.NetID{6, 6, 6})
+			assert.Equal(roaming.ErrNoAgreement, errors.Cause(err))
+		})
+	})
+}
+
+func (ts *ExtensionAPITestSuite) TestFrameLogs() {
+	assert := require.New(ts.T())
+
+	conf := test.GetConfig()
+	conf.NetworkServer.FrameLog.HistoryMaxLen = 10
+	assert.NoError(framelog.Setup(conf))
+	defer framelog.Setup(test.GetConfig())
+
+	devEUI := lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8}
+	uplinkFrameLog := ns.UplinkFrameLog{
+		PhyPayload: []byte{1, 2, 3, 4},
+	}
+	downlinkFrameLog := ns.DownlinkFrameLog{
+		PhyPayload: []byte{4, 3, 2, 1},
+	}
+
+	assert.NoError(framelog.LogUplinkFrameForDevEUI(context.Background(), devEUI, uplinkFrameLog, true))
+	assert.NoError(framelog.LogDownlinkFrameForDevEUI(context.Background(), devEUI, downlinkFrameLog, true))
+
+	ts.T().Run("GetFrameLogsForDevice", func(t *testing.T) {
+		assert := require.New(t)
+
+		resp, err := ts.api.GetFrameLogsForDevice(context.Background(), &extapi.GetFrameLogsForDeviceRequest{
+			DevEui: devEUI[:],
+			Limit:  1,
+		})
+		assert.NoError(err)
+		assert.Len(resp.Result, 1)
+		assert.True(proto.Equal(&uplinkFrameLog, resp.Result[0].UplinkFrame))
+		assert.True(resp.Result[0].UplinkRateLimited)
+		assert.NotNil(resp.Result[0].Time)
+
+		resp, err = ts.api.GetFrameLogsForDevice(context.Background(), &extapi.GetFrameLogsForDeviceRequest{
+			DevEui: devEUI[:],
+			After:  resp.Result[0].Id,
+		})
+		assert.NoError(err)
+		assert.Len(resp.Result, 1)
+		assert.True(proto.Equal(&downlinkFrameLog, resp.Result[0].DownlinkFrame))
+		assert.True(resp.Result[0].DownlinkRateLimited)
+	})
+
+	ts.T().Run("GetFrameLogsForDevice time range", func(t *testing.T) {
+		assert := require.New(t)
+
+		start, _ := ptypes.TimestampProto(time.Now().Add(-2 * time.Hour))
+		end, _ := ptypes.TimestampProto(time.Now().Add(-time.Hour))
+
+		resp, err := ts.api.GetFrameLogsForDevice(context.Background(), &extapi.GetFrameLogsForDeviceRequest{
+			DevEui: devEUI[:],
+			Start:  start,
+			End:    end,
+		})
+		assert.NoError(err)
+		assert.Len(resp.Result, 0)
+	})
+}
+
+func (ts *ExtensionAPITestSuite) TestGatewayStatus() {
+	assert := require.New(ts.T())
+
+	rp := storage.RoutingProfile{}
+	assert.NoError(storage.CreateRoutingProfile(context.Background(), storage.DB(), &rp))
+
+	gw := storage.Gateway{
+		GatewayID:        lorawan.EUI64{8, 7, 6, 5, 4, 3, 2, 1},
+		RoutingProfileID: rp.ID,
+	}
+	assert.NoError(storage.CreateGateway(context.Background(), storage.DB(), &gw))
+
+	ts.T().Run("Online", func(t *testing.T) {
+		assert := require.New(t)
+
+		resp, err := ts.api.GetGatewayStatus(context.Background(), &extapi.GetGatewayStatusRequest{
+			GatewayId: gw.GatewayID[:],
+		})
+		assert.NoError(err)
+		assert.False(resp.Offline)
+		assert.Nil(resp.LastSeenAt)
+	})
+
+	ts.T().Run("Offline", func(t *testing.T) {
+		assert := require.New(t)
+
+		changed, err := storage.SetGatewayOffline(context.Background(), storage.DB(), gw.GatewayID, true)
+		assert.NoError(err)
+		assert.True(changed)
+
+		resp, err := ts.api.GetGatewayStatus(context.Background(), &extapi.GetGatewayStatusRequest{
+			GatewayId: gw.GatewayID[:],
+		})
+		assert.NoError(err)
+		assert.True(resp.Offline)
+	})
+
+	ts.T().Run("Not found", func(t *testing.T) {
+		assert := require.New(t)
+
+		_, err := ts.api.GetGatewayStatus(context.Background(), &extapi.GetGatewayStatusRequest{
+			GatewayId: []byte{1, 1, 1, 1, 1, 1, 1, 1},
+		})
+		assert.Equal(codes.NotFound, grpc.Code(err))
+	})
+}
+
+func (ts *ExtensionAPITestSuite) TestADRAlgorithm() {
+	assert := require.New(ts.T())
+
+	dp := storage.DeviceProfile{}
+	assert.NoError(storage.CreateDeviceProfile(context.Background(), storage.DB(), &dp))
+
+	ts.T().Run("List", func(t *testing.T) {
+		assert := require.New(t)
+
+		resp, err := ts.api.ListADRAlgorithms(context.Background(), &empty.Empty{})
+		assert.NoError(err)
+
+		var ids []string
+		for _, a := range resp.Result {
+			ids = append(ids, a.Id)
+		}
+		assert.Contains(ids, adr.DefaultHandlerID)
+	})
+
+	ts.T().Run("Set", func(t *testing.T) {
+		assert := require.New(t)
+
+		_, err := ts.api.SetDeviceProfileADRAlgorithm(context.Background(), &extapi.SetDeviceProfileADRAlgorithmRequest{
+			DeviceProfileId: dp.ID.Bytes(),
+			AdrAlgorithmId:  adr.DefaultHandlerID,
+		})
+		assert.NoError(err)
+
+		resp, err := ts.api.GetDeviceProfileADRAlgorithm(context.Background(), &extapi.GetDeviceProfileADRAlgorithmRequest{
+			DeviceProfileId: dp.ID.Bytes(),
+		})
+		assert.NoError(err)
+		assert.Equal(adr.DefaultHandlerID, resp.AdrAlgorithmId)
+	})
+
+	ts.T().Run("Set unknown algorithm", func(t *testing.T) {
+		assert := require.New(t)
+
+		_, err := ts.api.SetDeviceProfileADRAlgorithm(context.Background(), &extapi.SetDeviceProfileADRAlgorithmRequest{
+			DeviceProfileId: dp.ID.Bytes(),
+			AdrAlgorithmId:  "unknown",
+		})
+		assert.Equal(codes.InvalidArgument, grpc.Code(err))
+	})
+
+	ts.T().Run("Get unknown device-profile", func(t *testing.T) {
+		assert := require.New(t)
+
+		_, err := ts.api.GetDeviceProfileADRAlgorithm(context.Background(), &extapi.GetDeviceProfileADRAlgorithmRequest{
+			DeviceProfileId: make([]byte, 16),
+		})
+		assert.Equal(codes.NotFound, grpc.Code(err))
+	})
+}
+
+func (ts *ExtensionAPITestSuite) TestDeviceADRState() {
+	assert := require.New(ts.T())
+
+	sp := storage.ServiceProfile{
+		TargetPER: 10,
+	}
+	assert.NoError(storage.CreateServiceProfile(context.Background(), storage.DB(), &sp))
+
+	ds := storage.DeviceSession{
+		DevEUI:           lorawan.EUI64{2, 2, 3, 4, 5, 6, 7, 8},
+		ServiceProfileID: sp.ID,
+		NbTrans:          1,
+		ADRNbTrans:       2,
+		PacketErrorRate:  15,
+	}
+	assert.NoError(storage.SaveDeviceSession(context.Background(), ds))
+
+	ts.T().Run("Get", func(t *testing.T) {
+		assert := require.New(t)
+
+		resp, err := ts.api.GetDeviceADRState(context.Background(), &extapi.GetDeviceADRStateRequest{
+			DevEui: ds.DevEUI[:],
+		})
+		assert.NoError(err)
+		assert.Equal(&extapi.GetDeviceADRStateResponse{
+			PacketErrorRate: 15,
+			TargetPer:       10,
+			NbTrans:         1,
+			AdrNbTrans:      2,
+		}, resp)
+	})
+
+	ts.T().Run("No device-session", func(t *testing.T) {
+		assert := require.New(t)
+
+		_, err := ts.api.GetDeviceADRState(context.Background(), &extapi.GetDeviceADRStateRequest{
+			DevEui: []byte{1, 1, 1, 1, 1, 1, 1, 1},
+		})
+		assert.Equal(codes.NotFound, grpc.Code(err))
+	})
+}
+
+func (ts *ExtensionAPITestSuite) TestForceRejoin() {
+	assert := require.New(ts.T())
+
+	rp := storage.RoutingProfile{}
+	assert.NoError(storage.CreateRoutingProfile(context.Background(), storage.DB(), &rp))
+
+	sp := storage.ServiceProfile{}
+	assert.NoError(storage.CreateServiceProfile(context.Background(), storage.DB(), &sp))
+
+	dp := storage.DeviceProfile{}
+	assert.NoError(storage.CreateDeviceProfile(context.Background(), storage.DB(), &dp))
+
+	d := storage.Device{
+		DevEUI:           lorawan.EUI64{3, 2, 3, 4, 5, 6, 7, 8},
+		RoutingProfileID: rp.ID,
+		ServiceProfileID: sp.ID,
+		DeviceProfileID:  dp.ID,
+		Mode:             storage.DeviceModeA,
+	}
+	assert.NoError(storage.CreateDevice(context.Background(), storage.DB(), &d))
+	assert.NoError(storage.SaveDeviceSession(context.Background(), storage.DeviceSession{
+		DevEUI:     d.DevEUI,
+		DevAddr:    lorawan.DevAddr{1, 2, 3, 4},
+		MACVersion: "1.1.0",
+	}))
+
+	params := extapi.ForceRejoinParameters{
+		RejoinType: 2,
+		Dr:         3,
+		MaxRetries: 2,
+		Period:     1,
+	}
+
+	ts.T().Run("Invalid parameters", func(t *testing.T) {
+		assert := require.New(t)
+
+		_, err := ts.api.ForceRejoin(context.Background(), &extapi.ForceRejoinRequest{
+			DevEui: d.DevEUI[:],
+			Parameters: &extapi.ForceRejoinParameters{
+				RejoinType: 1,
+			},
+		})
+		assert.Equal(codes.InvalidArgument, grpc.Code(err))
+	})
+
+	ts.T().Run("ForceRejoin", func(t *testing.T) {
+		assert := require.New(t)
+
+		_, err := ts.api.ForceRejoin(context.Background(), &extapi.ForceRejoinRequest{
+			DevEui:     d.DevEUI[:],
+			Parameters: &params,
+		})
+		assert.NoError(err)
+
+		items, err := storage.GetMACCommandQueueItems(context.Background(), d.DevEUI)
+		assert.NoError(err)
+		assert.Len(items, 1)
+
+		resp, err := ts.api.GetForceRejoin(context.Background(), &extapi.GetForceRejoinRequest{
+			DevEui: d.DevEUI[:],
+		})
+		assert.NoError(err)
+		assert.Equal(d.DevEUI[:], resp.ForceRejoin.DevEui)
+		assert.Equal(&params, resp.ForceRejoin.Parameters)
+		assert.NotNil(resp.ForceRejoin.CreatedAt)
+		assert.Nil(resp.ForceRejoin.SentAt)
+		assert.Nil(resp.ForceRejoin.CompletedAt)
+	})
+
+	ts.T().Run("ForceRejoinForDeviceProfile", func(t *testing.T) {
+		assert := require.New(t)
+
+		assert.NoError(storage.FlushMACCommandQueue(context.Background(), d.DevEUI))
+
+		resp, err := ts.api.ForceRejoinForDeviceProfile(context.Background(), &extapi.ForceRejoinForDeviceProfileRequest{
+			DeviceProfileId: dp.ID.Bytes(),
+			Parameters:      &params,
+		})
+		assert.NoError(err)
+		assert.Equal([][]byte{d.DevEUI[:]}, resp.DevEuis)
+
+		items, err := storage.GetMACCommandQueueItems(context.Background(), d.DevEUI)
+		assert.NoError(err)
+		assert.Len(items, 1)
+
+		listResp, err := ts.api.ListForceRejoinsForDeviceProfile(context.Background(), &extapi.ListForceRejoinsForDeviceProfileRequest{
+			DeviceProfileId: dp.ID.Bytes(),
+		})
+		assert.NoError(err)
+		assert.Len(listResp.Result, 1)
+		assert.Equal(d.DevEUI[:], listResp.Result[0].DevEui)
+	})
+
+	ts.T().Run("LoRaWAN 1.0 device", func(t *testing.T) {
+		assert := require.New(t)
+
+		assert.NoError(storage.SaveDeviceSession(context.Background(), storage.DeviceSession{
+			DevEUI:     d.DevEUI,
+			DevAddr:    lorawan.DevAddr{1, 2, 3, 4},
+			MACVersion: "1.0.3",
+		}))
+
+		_, err := ts.api.ForceRejoin(context.Background(), &extapi.ForceRejoinRequest{
+			DevEui:     d.DevEUI[:],
+			Parameters: &params,
+		})
+		assert.Equal(codes.FailedPrecondition, grpc.Code(err))
+	})
+}
+
+func (ts *ExtensionAPITestSuite) TestFragmentationSession() {
+	assert := require.New(ts.T())
+
+	rp := storage.RoutingProfile{}
+	assert.NoError(storage.CreateRoutingProfile(context.Background(), storage.DB(), &rp))
+
+	sp := storage.ServiceProfile{}
+	assert.NoError(storage.CreateServiceProfile(context.Background(), storage.DB(), &sp))
+
+	mg := storage.MulticastGroup{
+		MCAddr:           lorawan.DevAddr{1, 2, 3, 4},
+		GroupType:        storage.MulticastGroupC,
+		DR:               3,
+		Frequency:        868100000,
+		ServiceProfileID: sp.ID,
+		RoutingProfileID: rp.ID,
+	}
+	assert.NoError(storage.CreateMulticastGroup(context.Background(), storage.DB(), &mg))
+
+	ts.T().Run("Create invalid fragment size", func(t *testing.T) {
+		assert := require.New(t)
+
+		_, err := ts.api.CreateFragmentationSession(context.Background(), &extapi.CreateFragmentationSessionRequest{
+			FragmentationSession: &extapi.FragmentationSession{
+				MulticastGroupId: mg.ID.Bytes(),
+				FragmentSize:     200,
+			},
+			Data: make([]byte, 100),
+		})
+		assert.Equal(codes.InvalidArgument, grpc.Code(err))
+	})
+
+	ts.T().Run("Create", func(t *testing.T) {
+		assert := require.New(t)
+
+		resp, err := ts.api.CreateFragmentationSession(context.Background(), &extapi.CreateFragmentationSessionRequest{
+			FragmentationSession: &extapi.FragmentationSession{
+				MulticastGroupId: mg.ID.Bytes(),
+				FragIndex:        1,
+				FragmentSize:     10,
+				Redundancy:       2,
+			},
+			Data: make([]byte, 25),
+		})
+		assert.NoError(err)
+
+		fs := resp.FragmentationSession
+		assert.Len(fs.Id, 16)
+		assert.Equal(mg.ID.Bytes(), fs.MulticastGroupId)
+		assert.EqualValues(3, fs.NbFrag)
+		assert.EqualValues(5, fs.Padding)
+		assert.EqualValues(5, fs.FragmentCount)
+		assert.EqualValues(0, fs.FragmentsEnqueued)
+		assert.NotNil(fs.CreatedAt)
+		assert.Nil(fs.CompletedAt)
+
+		t.Run("Get", func(t *testing.T) {
+			assert := require.New(t)
+
+			getResp, err := ts.api.GetFragmentationSession(context.Background(), &extapi.GetFragmentationSessionRequest{
+				Id: fs.Id,
+			})
+			assert.NoError(err)
+			assert.Equal(fs.Id, getResp.FragmentationSession.Id)
+			assert.EqualValues(1, getResp.FragmentationSession.FragIndex)
+			assert.EqualValues(10, getResp.FragmentationSession.FragmentSize)
+			assert.EqualValues(2, getResp.FragmentationSession.Redundancy)
+			assert.EqualValues(3, getResp.FragmentationSession.NbFrag)
+			assert.EqualValues(5, getResp.FragmentationSession.Padding)
+			assert.EqualValues(5, getResp.FragmentationSession.FragmentCount)
+		})
+
+		t.Run("List", func(t *testing.T) {
+			assert := require.New(t)
+
+			listResp, err := ts.api.ListFragmentationSessionsForMulticastGroup(context.Background(), &extapi.ListFragmentationSessionsForMulticastGroupRequest{
+				MulticastGroupId: mg.ID.Bytes(),
+			})
+			assert.NoError(err)
+			assert.Len(listResp.Result, 1)
+			assert.Equal(fs.Id, listResp.Result[0].Id)
+			assert.EqualValues(3, listResp.Result[0].NbFrag)
+		})
+	})
+
+	ts.T().Run("Get does not exist", func(t *testing.T) {
+		assert := require.New(t)
+
+		_, err := ts.api.GetFragmentationSession(context.Background(), &extapi.GetFragmentationSessionRequest{
+			Id: make([]byte, 16),
+		})
+		assert.Equal(codes.NotFound, grpc.Code(err))
+	})
+}
+
+func (ts *ExtensionAPITestSuite) TestPreviewGatewayConfiguration() {
+	assert := require.New(ts.T())
+
+	gp := storage.GatewayProfile{
+		Channels: []int64{0, 1, 2},
+	}
+	assert.NoError(storage.CreateGatewayProfile(context.Background(), storage.DB(), &gp))
+
+	invalidGP := storage.GatewayProfile{
+		Channels: []int64{0, 1, 99},
+	}
+	assert.NoError(storage.CreateGatewayProfile(context.Background(), storage.DB(), &invalidGP))
+
+	ts.T().Run("Valid channel-plan", func(t *testing.T) {
+		assert := require.New(t)
+
+		resp, err := ts.api.PreviewGatewayConfiguration(context.Background(), &extapi.PreviewGatewayConfigurationRequest{
+			GatewayProfileId: gp.ID.Bytes(),
+			GatewayId:        []byte{1, 2, 3, 4, 5, 6, 7, 8},
+		})
+		assert.NoError(err)
+		assert.Equal([]byte{1, 2, 3, 4, 5, 6, 7, 8}, resp.GatewayConfiguration.GatewayId)
+		assert.Len(resp.GatewayConfiguration.Channels, 3)
+		assert.EqualValues(868100000, resp.GatewayConfiguration.Channels[0].Frequency)
+	})
+
+	ts.T().Run("Invalid channel-plan", func(t *testing.T) {
+		assert := require.New(t)
+
+		_, err := ts.api.PreviewGatewayConfiguration(context.Background(), &extapi.PreviewGatewayConfigurationRequest{
+			GatewayProfileId: invalidGP.ID.Bytes(),
+		})
+		assert.Equal(codes.InvalidArgument, grpc.Code(err))
+	})
+
+	ts.T().Run("Gateway-profile does not exist", func(t *testing.T) {
+		assert := require.New(t)
+
+		_, err := ts.api.PreviewGatewayConfiguration(context.Background(), &extapi.PreviewGatewayConfigurationRequest{
+			GatewayProfileId: make([]byte, 16),
+		})
+		assert.Equal(codes.NotFound, grpc.Code(err))
+	})
+}
+
+func TestExtensionAPI(t *testing.T) {
+	suite.Run(t, new(ExtensionAPITestSuite))
+}
+
+// newTestTLSCertificate returns a PEM encoded self-signed TLS certificate and
+// key.
+func newTestTLSCertificate(t *testing.T) (string, string) {
+	assert := require.New(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(err)
+
+	tmpl := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	cert, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, &key.PublicKey, key)
+	assert.NoError(err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
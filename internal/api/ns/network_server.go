@@ -21,6 +21,7 @@ import (
 	proprietarydown "github.com/brocaar/chirpstack-network-server/internal/downlink/proprietary"
 	"github.com/brocaar/chirpstack-network-server/internal/framelog"
 	"github.com/brocaar/chirpstack-network-server/internal/gateway"
+	"github.com/brocaar/chirpstack-network-server/internal/gateway/channelplan"
 	"github.com/brocaar/chirpstack-network-server/internal/gps"
 	"github.com/brocaar/chirpstack-network-server/internal/helpers"
 	"github.com/brocaar/chirpstack-network-server/internal/storage"
@@ -582,6 +583,8 @@ func (n *NetworkServerAPI) UpdateDevice(ctx context.Context, req *ns.UpdateDevic
 		return nil, errToRPCError(err)
 	}
 
+	oldDPID := d.DeviceProfileID
+
 	d.DeviceProfileID = dpID
 	d.ServiceProfileID = spID
 	d.RoutingProfileID = rpID
@@ -594,6 +597,12 @@ func (n *NetworkServerAPI) UpdateDevice(ctx context.Context, req *ns.UpdateDevic
 			return err
 		}
 
+		if oldDPID != dpID {
+			if err := resetLastDevNonceOnMACVersionChange(ctx, tx, devEUI, oldDPID, dpID); err != nil {
+				return err
+			}
+		}
+
 		// if there is a device-session, set the is disabled field
 		ds, err := storage.GetDeviceSession(ctx, devEUI)
 		if err == nil {
@@ -612,6 +621,27 @@ func (n *NetworkServerAPI) UpdateDevice(ctx context.Context, req *ns.UpdateDevic
 	return &empty.Empty{}, nil
 }
 
+// resetLastDevNonceOnMACVersionChange removes the last used dev-nonce of the
+// given device when the MAC version of the old and new device-profile differ,
+// as this implies a re-provisioned device with a new dev-nonce counter.
+func resetLastDevNonceOnMACVersionChange(ctx context.Context, tx sqlx.Ext, devEUI lorawan.EUI64, oldDPID, newDPID uuid.UUID) error {
+	oldDP, err := storage.GetDeviceProfile(ctx, tx, oldDPID)
+	if err != nil {
+		return err
+	}
+
+	newDP, err := storage.GetDeviceProfile(ctx, tx, newDPID)
+	if err != nil {
+		return err
+	}
+
+	if oldDP.MACVersion == newDP.MACVersion {
+		return nil
+	}
+
+	return storage.DeleteLastDevNonce(ctx, tx, devEUI)
+}
+
 // DeleteDevice deletes the device matching the given DevEUI.
 func (n *NetworkServerAPI) DeleteDevice(ctx context.Context, req *ns.DeleteDeviceRequest) (*empty.Empty, error) {
 	var devEUI lorawan.EUI64
@@ -1210,6 +1240,10 @@ func (n *NetworkServerAPI) CreateGatewayProfile(ctx context.Context, req *ns.Cre
 		gc.ExtraChannels = append(gc.ExtraChannels, c)
 	}
 
+	if err := channelplan.Validate(gc); err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, "invalid channel-plan: %s", err)
+	}
+
 	err := storage.Transaction(func(tx sqlx.Ext) error {
 		return storage.CreateGatewayProfile(ctx, tx, &gc)
 	})
@@ -1325,6 +1359,10 @@ func (n *NetworkServerAPI) UpdateGatewayProfile(ctx context.Context, req *ns.Upd
 		gc.ExtraChannels = append(gc.ExtraChannels, c)
 	}
 
+	if err := channelplan.Validate(gc); err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, "invalid channel-plan: %s", err)
+	}
+
 	err = storage.Transaction(func(tx sqlx.Ext) error {
 		return storage.UpdateGatewayProfile(ctx, tx, &gc)
 	})
@@ -580,6 +580,8 @@ func (ts *NetworkServerAPITestSuite) TestDevice() {
 					PingSlotDR:           5,
 					PingSlotFrequency:    868100000,
 					NbTrans:              1,
+					ADRAckLimitExp:       storage.DefaultADRAckLimitExp,
+					ADRAckDelayExp:       storage.DefaultADRAckDelayExp,
 					MACVersion:           "1.0.2",
 					MACCommandErrorCount: make(map[lorawan.CID]int),
 					IsDisabled:           true,
@@ -803,6 +805,25 @@ func (ts *NetworkServerAPITestSuite) TestDevice() {
 			ds, err = storage.GetDeviceSession(context.Background(), devEUI)
 			assert.NoError(err)
 			assert.True(ds.IsDisabled)
+
+			t.Run("MAC version change resets last dev-nonce", func(t *testing.T) {
+				assert := require.New(t)
+
+				dp2 := storage.DeviceProfile{
+					MACVersion: "1.0.3",
+				}
+				assert.NoError(storage.CreateDeviceProfile(context.Background(), storage.DB(), &dp2))
+				assert.NoError(storage.SetLastDevNonce(context.Background(), storage.DB(), devEUI, 10))
+
+				d.DeviceProfileId = dp2.ID.Bytes()
+				_, err := ts.api.UpdateDevice(context.Background(), &ns.UpdateDeviceRequest{
+					Device: d,
+				})
+				assert.NoError(err)
+
+				_, err = storage.GetLastDevNonce(context.Background(), storage.DB(), devEUI)
+				assert.Equal(storage.ErrDoesNotExist, err)
+			})
 		})
 
 		t.Run("Delete", func(t *testing.T) {
@@ -957,6 +978,25 @@ func (ts *NetworkServerAPITestSuite) TestGateway() {
 					},
 				},
 			}
+			t.Run("Invalid channel-plan", func(t *testing.T) {
+				assert := require.New(t)
+
+				_, err := ts.api.CreateGatewayProfile(context.Background(), &ns.CreateGatewayProfileRequest{
+					GatewayProfile: &ns.GatewayProfile{
+						Channels: []uint32{0, 1, 2},
+						ExtraChannels: []*ns.GatewayProfileExtraChannel{
+							{
+								Modulation:       common.Modulation_LORA,
+								Frequency:        868100000,
+								Bandwidth:        125,
+								SpreadingFactors: []uint32{7, 8},
+							},
+						},
+					},
+				})
+				assert.Equal(codes.InvalidArgument, grpc.Code(err))
+			})
+
 			createResp, err := ts.api.CreateGatewayProfile(context.Background(), &req)
 			assert.NoError(err)
 			assert.Len(createResp.Id, 16)
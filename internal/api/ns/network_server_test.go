@@ -134,7 +134,7 @@ func TestNetworkServerAPI(t *testing.T) {
 			}()
 
 			Convey("When logging a downlink device frame", func() {
-				So(framelog.LogDownlinkFrameForDevEUI(context.Background(), devEUI, ns.DownlinkFrameLog{}), ShouldBeNil)
+				So(framelog.LogDownlinkFrameForDevEUI(context.Background(), devEUI, ns.DownlinkFrameLog{}, false), ShouldBeNil)
 
 				Convey("Then the frame-log was received by the client", func() {
 					resp := <-respChan
@@ -144,7 +144,7 @@ func TestNetworkServerAPI(t *testing.T) {
 			})
 
 			Convey("When logging an uplink device frame", func() {
-				So(framelog.LogUplinkFrameForDevEUI(context.Background(), devEUI, ns.UplinkFrameLog{}), ShouldBeNil)
+				So(framelog.LogUplinkFrameForDevEUI(context.Background(), devEUI, ns.UplinkFrameLog{}, false), ShouldBeNil)
 
 				Convey("Then the frame-log was received by the client", func() {
 					resp := <-respChan
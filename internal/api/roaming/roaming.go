@@ -289,6 +289,14 @@ func (a *API) handleRequest(ctx context.Context, client backend.Client, basePL b
 		ans, err = a.handleXmitDataReq(ctx, basePL, b)
 	case backend.XmitDataAns:
 		err = a.handleXmitDataAns(ctx, client, basePL, b)
+	case roaming.HRStartReq:
+		ans, err = a.handleHRStartReq(ctx, basePL, b)
+	case roaming.HRStartAns:
+		err = a.handleHRStartAns(ctx, client, basePL, b)
+	case roaming.HRStopReq:
+		ans, err = a.handleHRStopReq(ctx, basePL, b)
+	case roaming.HRStopAns:
+		err = a.handleHRStopAns(ctx, client, basePL, b)
 	default:
 		ans = a.getBasePayloadResult(basePL, backend.MalformedRequest, fmt.Sprintf("MessageType %s is not expected", basePL.MessageType))
 	}
@@ -366,6 +374,11 @@ func (a *API) handlePRStartReqData(ctx context.Context, basePL backend.BasePaylo
 	var nwkSKey *backend.KeyEnvelope
 
 	if lifetime != 0 {
+		// keep track of the fNS, so that the session can be stopped on re-join
+		if err := storage.SavePassiveRoamingNetIDForDevEUI(ctx, ds.DevEUI, netID, roaming.GetPassiveRoamingLifetime(netID)); err != nil {
+			return nil, errors.Wrap(err, "save passive-roaming netid error")
+		}
+
 		// sess keys
 		kekLabel := roaming.GetPassiveRoamingKEKLabel(netID)
 		var kekKey []byte
@@ -450,8 +463,101 @@ func (a *API) handlePRStopAns(ctx context.Context, client backend.Client, basePL
 	return nil
 }
 
-func (a *API) handlePRStopReq(ctx context.Context, basePL backend.BasePayload, pl []byte) (backend.Answer, error) {
-	return nil, nil
+func (a *API) handlePRStopReq(ctx context.Context, basePL backend.BasePayload, b []byte) (backend.Answer, error) {
+	var pl backend.PRStopReqPayload
+	if err := json.Unmarshal(b, &pl); err != nil {
+		return nil, errors.Wrap(err, "unmarshal json error")
+	}
+
+	// decode requester netid
+	var netID lorawan.NetID
+	if err := netID.UnmarshalText([]byte(basePL.SenderID)); err != nil {
+		return nil, errors.Wrap(err, "unmarshal netid error")
+	}
+
+	sessions, err := getPassiveRoamingDeviceSessionsForPRStopReq(ctx, netID, pl.DevEUI)
+	if err != nil {
+		return nil, errors.Wrap(err, "get passive-roaming device-sessions error")
+	}
+
+	for _, ds := range sessions {
+		if err := storage.DeletePassiveRoamingDeviceSession(ctx, ds.SessionID); err != nil {
+			return nil, errors.Wrap(err, "delete passive-roaming device-session error")
+		}
+	}
+
+	if len(sessions) == 0 {
+		return backend.PRStopAnsPayload{
+			BasePayloadResult: a.getBasePayloadResult(basePL, backend.UnknownDevEUI, fmt.Sprintf("no passive-roaming session for DevEUI %s", pl.DevEUI)),
+		}, nil
+	}
+
+	log.WithFields(log.Fields{
+		"dev_eui": pl.DevEUI,
+		"net_id":  netID,
+		"ctx_id":  ctx.Value(logging.ContextIDKey),
+	}).Info("api/roaming: passive-roaming stopped")
+
+	return backend.PRStopAnsPayload{
+		BasePayloadResult: a.getBasePayloadResult(basePL, backend.Success, ""),
+	}, nil
+}
+
+// getPassiveRoamingDeviceSessionsForPRStopReq returns the passive-roaming
+// device-sessions for the given DevEUI, started by the given NetID (only the
+// hNS which started a session is allowed to stop it). As the DevEUI of the
+// PRStartAns is optional, sessions might only be stored by DevAddr. The
+// PRStopReq does not contain the DevAddr, therefore these sessions are
+// matched using the DevAddr pointers of the sessions found by DevEUI.
+func getPassiveRoamingDeviceSessionsForPRStopReq(ctx context.Context, netID lorawan.NetID, devEUI lorawan.EUI64) ([]storage.PassiveRoamingDeviceSession, error) {
+	ids, err := storage.GetPassiveRoamingIDsForDevEUI(ctx, devEUI)
+	if err != nil {
+		return nil, errors.Wrap(err, "get passive-roaming session ids error")
+	}
+
+	var out []storage.PassiveRoamingDeviceSession
+	seen := make(map[uuid.UUID]struct{})
+	devAddrs := make(map[lorawan.DevAddr]struct{})
+
+	for _, id := range ids {
+		ds, err := storage.GetPassiveRoamingDeviceSession(ctx, id)
+		if err != nil {
+			if errors.Cause(err) == storage.ErrDoesNotExist {
+				continue
+			}
+			return nil, errors.Wrap(err, "get passive-roaming device-session error")
+		}
+
+		if ds.NetID != netID {
+			continue
+		}
+
+		seen[ds.SessionID] = struct{}{}
+		devAddrs[ds.DevAddr] = struct{}{}
+		out = append(out, ds)
+	}
+
+	for devAddr := range devAddrs {
+		sessions, err := storage.GetPassiveRoamingDeviceSessionsForDevAddr(ctx, devAddr)
+		if err != nil {
+			return nil, errors.Wrap(err, "get passive-roaming device-sessions for devaddr error")
+		}
+
+		for _, ds := range sessions {
+			if _, ok := seen[ds.SessionID]; ok {
+				continue
+			}
+
+			if ds.NetID != netID || ds.DevEUI != (lorawan.EUI64{}) {
+				continue
+			}
+
+			seen[ds.SessionID] = struct{}{}
+			out = append(out, ds)
+		}
+	}
+
+	return out, nil
 }
 
 func (a *API) handleProfileAns(ctx context.Context, client backend.Client, basePL backend.BasePayload, b []byte) error {
@@ -467,8 +573,54 @@ func (a *API) handleProfileAns(ctx context.Context, client backend.Client, baseP
 	return nil
 }
 
-func (a *API) handleProfileReq(ctx context.Context, basePL backend.BasePayload, pl []byte) (backend.Answer, error) {
-	return nil, nil
+func (a *API) handleProfileReq(ctx context.Context, basePL backend.BasePayload, b []byte) (backend.Answer, error) {
+	var pl backend.ProfileReqPayload
+	if err := json.Unmarshal(b, &pl); err != nil {
+		return nil, errors.Wrap(err, "unmarshal json error")
+	}
+
+	d, err := storage.GetDevice(ctx, storage.DB(), pl.DevEUI)
+	if err != nil {
+		if errors.Cause(err) == storage.ErrDoesNotExist {
+			return backend.ProfileAnsPayload{
+				BasePayloadResult: a.getBasePayloadResult(basePL, backend.UnknownDevEUI, fmt.Sprintf("unknown DevEUI %s", pl.DevEUI)),
+			}, nil
+		}
+		return nil, errors.Wrap(err, "get device error")
+	}
+
+	sp, err := storage.GetServiceProfile(ctx, storage.DB(), d.ServiceProfileID)
+	if err != nil {
+		return nil, errors.Wrap(err, "get service-profile error")
+	}
+
+	// Passive-roaming is preferred over handover-roaming as it does not
+	// require the device to re-join.
+	var roamingType backend.RoamingType
+	switch {
+	case sp.PRAllowed:
+		roamingType = backend.Passive
+	case sp.HRAllowed:
+		roamingType = backend.Handover
+	default:
+		return backend.ProfileAnsPayload{
+			BasePayloadResult: a.getBasePayloadResult(basePL, backend.DevRoamingDisallowed, "roaming is not allowed by the service-profile"),
+		}, nil
+	}
+
+	dp, err := storage.GetDeviceProfile(ctx, storage.DB(), d.DeviceProfileID)
+	if err != nil {
+		return nil, errors.Wrap(err, "get device-profile error")
+	}
+
+	dpTimestamp := backend.ISO8601Time(dp.UpdatedAt)
+
+	return backend.ProfileAnsPayload{
+		BasePayloadResult:      a.getBasePayloadResult(basePL, backend.Success, ""),
+		DeviceProfile:          roaming.DeviceProfileToBackend(dp),
+		DeviceProfileTimestamp: &dpTimestamp,
+		RoamingActivationType:  &roamingType,
+	}, nil
 }
 
 func (a *API) handleXmitDataAns(ctx context.Context, client backend.Client, basePL backend.BasePayload, b []byte) error {
@@ -500,6 +652,9 @@ func (a *API) handleXmitDataReq(ctx context.Context, basePL backend.BasePayload,
 		if err := downdata.HandleRoamingFNS(ctx, pl); err != nil {
 			return nil, errors.Wrap(err, "handle passive-roaming downlink error")
 		}
+	} else if pl.DLMetaData != nil && pl.DLMetaData.FPort != nil {
+		// Handover Roaming downlink
+		return a.handleXmitDataReqHandoverRoaming(ctx, basePL, pl)
 	} else {
 		return nil, errors.New("unexpected payload")
 	}
@@ -507,6 +662,204 @@ func (a *API) handleXmitDataReq(ctx context.Context, basePL backend.BasePayload,
 	return a.getBasePayloadResult(basePL, backend.Success, ""), nil
 }
 
+// handleXmitDataReqHandoverRoaming queues the application payload sent by the
+// hNS, it is sent by the sNS on the next downlink opportunity of the device.
+func (a *API) handleXmitDataReqHandoverRoaming(ctx context.Context, basePL backend.BasePayload, pl backend.XmitDataReqPayload) (backend.Answer, error) {
+	// decode requester netid
+	var netID lorawan.NetID
+	if err := netID.UnmarshalText([]byte(basePL.SenderID)); err != nil {
+		return nil, errors.Wrap(err, "unmarshal netid error")
+	}
+
+	if pl.DLMetaData.DevEUI == nil || pl.DLMetaData.FCntDown == nil {
+		return a.getBasePayloadResult(basePL, backend.MalformedRequest, "DevEUI and FCntDown must be set"), nil
+	}
+	devEUI := *pl.DLMetaData.DevEUI
+
+	ds, err := storage.GetDeviceSession(ctx, devEUI)
+	if err != nil {
+		if errors.Cause(err) == storage.ErrDoesNotExist {
+			return a.getBasePayloadResult(basePL, backend.UnknownDevEUI, fmt.Sprintf("no handover-roaming session for DevEUI %s", devEUI)), nil
+		}
+		return nil, errors.Wrap(err, "get device-session error")
+	}
+
+	hrDS, err := storage.GetHandoverRoamingDeviceSessionForDevEUI(ctx, devEUI, ds.DevAddr)
+	if err != nil {
+		if errors.Cause(err) == storage.ErrDoesNotExist {
+			return a.getBasePayloadResult(basePL, backend.UnknownDevEUI, fmt.Sprintf("no handover-roaming session for DevEUI %s", devEUI)), nil
+		}
+		return nil, errors.Wrap(err, "get handover-roaming device-session error")
+	}
+
+	// Only the hNS which handed over the device is allowed to send downlinks.
+	if hrDS.NetID != netID {
+		return a.getBasePayloadResult(basePL, backend.UnknownDevEUI, fmt.Sprintf("no handover-roaming session for DevEUI %s", devEUI)), nil
+	}
+
+	qi := storage.HandoverRoamingDeviceQueueItem{
+		FPort:      *pl.DLMetaData.FPort,
+		FCnt:       *pl.DLMetaData.FCntDown,
+		Confirmed:  pl.DLMetaData.Confirmed,
+		FRMPayload: pl.FRMPayload[:],
+	}
+
+	if err := storage.CreateHandoverRoamingDeviceQueueItem(ctx, devEUI, qi, hrDS.Lifetime); err != nil {
+		return nil, errors.Wrap(err, "create handover-roaming device-queue item error")
+	}
+
+	return a.getBasePayloadResult(basePL, backend.Success, ""), nil
+}
+
+func (a *API) handleHRStartReq(ctx context.Context, basePL backend.BasePayload, b []byte) (backend.Answer, error) {
+	var pl roaming.HRStartReqPayload
+	if err := json.Unmarshal(b, &pl); err != nil {
+		return nil, errors.Wrap(err, "unmarshal json error")
+	}
+
+	// decode requester netid
+	var netID lorawan.NetID
+	if err := netID.UnmarshalText([]byte(basePL.SenderID)); err != nil {
+		return nil, errors.Wrap(err, "unmarshal netid error")
+	}
+
+	if !roaming.IsHandoverRoaming(netID) {
+		return roaming.HRStartAnsPayload{
+			BasePayloadResult: a.getBasePayloadResult(basePL, backend.RoamingActDisallowed, fmt.Sprintf("handover-roaming is not allowed for NetID %s", netID)),
+		}, nil
+	}
+
+	var phy lorawan.PHYPayload
+	if err := phy.UnmarshalBinary(pl.PHYPayload[:]); err != nil {
+		return nil, errors.Wrap(err, "unmarshal phypayload error")
+	}
+
+	if phy.MHDR.MType != lorawan.JoinRequest {
+		return roaming.HRStartAnsPayload{
+			BasePayloadResult: a.getBasePayloadResult(basePL, backend.MalformedRequest, "PHYPayload must be a join-request"),
+		}, nil
+	}
+
+	rxInfo, err := roaming.ULMetaDataToRXInfo(pl.ULMetaData)
+	if err != nil {
+		return nil, errors.Wrap(err, "ULMetaData to RXInfo error")
+	}
+
+	txInfo, err := roaming.ULMetaDataToTXInfo(pl.ULMetaData)
+	if err != nil {
+		return nil, errors.Wrap(err, "ULMetaData to TXInfo error")
+	}
+
+	rxPacket := models.RXPacket{
+		PHYPayload: phy,
+		TXInfo:     txInfo,
+		RXInfoSet:  rxInfo,
+	}
+	if pl.ULMetaData.DataRate != nil {
+		rxPacket.DR = *pl.ULMetaData.DataRate
+	}
+
+	ans, err := join.HandleStartHRHNS(ctx, pl, rxPacket)
+	if err != nil {
+		if errors.Cause(err) == join.ErrHandoverRoamingDisallowed {
+			return roaming.HRStartAnsPayload{
+				BasePayloadResult: a.getBasePayloadResult(basePL, backend.DevRoamingDisallowed, err.Error()),
+			}, nil
+		}
+		return nil, errors.Wrap(err, "handle otaa error")
+	}
+
+	ans.BasePayloadResult = a.getBasePayloadResult(basePL, backend.Success, "")
+	return ans, nil
+}
+
+func (a *API) handleHRStartAns(ctx context.Context, client backend.Client, basePL backend.BasePayload, b []byte) error {
+	var pl roaming.HRStartAnsPayload
+	if err := json.Unmarshal(b, &pl); err != nil {
+		return errors.Wrap(err, "unmarshal json error")
+	}
+
+	if err := client.HandleAnswer(ctx, pl); err != nil {
+		return errors.Wrap(err, "handle answer error")
+	}
+
+	return nil
+}
+
+func (a *API) handleHRStopReq(ctx context.Context, basePL backend.BasePayload, b []byte) (backend.Answer, error) {
+	var pl roaming.HRStopReqPayload
+	if err := json.Unmarshal(b, &pl); err != nil {
+		return nil, errors.Wrap(err, "unmarshal json error")
+	}
+
+	// decode requester netid
+	var netID lorawan.NetID
+	if err := netID.UnmarshalText([]byte(basePL.SenderID)); err != nil {
+		return nil, errors.Wrap(err, "unmarshal netid error")
+	}
+
+	ids, err := storage.GetHandoverRoamingIDsForDevEUI(ctx, pl.DevEUI)
+	if err != nil {
+		return nil, errors.Wrap(err, "get handover-roaming session ids error")
+	}
+
+	var deleted int
+	for _, id := range ids {
+		ds, err := storage.GetHandoverRoamingDeviceSession(ctx, id)
+		if err != nil {
+			if errors.Cause(err) == storage.ErrDoesNotExist {
+				continue
+			}
+			return nil, errors.Wrap(err, "get handover-roaming device-session error")
+		}
+
+		// Only the hNS which handed over the device is allowed to stop it.
+		if ds.NetID != netID {
+			continue
+		}
+
+		if err := storage.DeleteHandoverRoamingDeviceSession(ctx, id); err != nil {
+			return nil, errors.Wrap(err, "delete handover-roaming device-session error")
+		}
+		deleted++
+	}
+
+	if deleted == 0 {
+		return roaming.HRStopAnsPayload{
+			BasePayloadResult: a.getBasePayloadResult(basePL, backend.UnknownDevEUI, fmt.Sprintf("no handover-roaming session for DevEUI %s", pl.DevEUI)),
+		}, nil
+	}
+
+	// The device-session is created by the sNS on handover, it is removed so
+	// that uplinks of the device are no longer handled.
+	if err := storage.DeleteDeviceSession(ctx, pl.DevEUI); err != nil && errors.Cause(err) != storage.ErrDoesNotExist {
+		return nil, errors.Wrap(err, "delete device-session error")
+	}
+
+	log.WithFields(log.Fields{
+		"dev_eui": pl.DevEUI,
+		"net_id":  netID,
+		"ctx_id":  ctx.Value(logging.ContextIDKey),
+	}).Info("api/roaming: handover-roaming stopped")
+
+	return roaming.HRStopAnsPayload{
+		BasePayloadResult: a.getBasePayloadResult(basePL, backend.Success, ""),
+	}, nil
+}
+
+func (a *API) handleHRStopAns(ctx context.Context, client backend.Client, basePL backend.BasePayload, b []byte) error {
+	var pl roaming.HRStopAnsPayload
+	if err := json.Unmarshal(b, &pl); err != nil {
+		return errors.Wrap(err, "unmarshal json error")
+	}
+
+	if err := client.HandleAnswer(ctx, pl); err != nil {
+		return errors.Wrap(err, "handle answer error")
+	}
+
+	return nil
+}
+
 func (a *API) getBasePayloadResult(basePLReq backend.BasePayload, resCode backend.ResultCode, resDesc string) backend.BasePayloadResult {
 	var mType backend.MessageType
 
@@ -519,6 +872,10 @@ func (a *API) getBasePayloadResult(basePLReq backend.BasePayload, resCode backen
 		mType = backend.ProfileAns
 	case backend.XmitDataReq:
 		mType = backend.XmitDataAns
+	case roaming.HRStartReq:
+		mType = roaming.HRStartAns
+	case roaming.HRStopReq:
+		mType = roaming.HRStopAns
 	}
 
 	return backend.BasePayloadResult{
@@ -2,6 +2,14 @@ package applicationserver
 
 import "github.com/brocaar/chirpstack-network-server/internal/api/client/asclient"
 
+// UplinkRateLimitedMetadataKey defines the gRPC metadata key which is set
+// on the HandleUplinkData call when the uplink exceeded the uplink rate of
+// the service-profile (Mark policy). Its value is "true". When the uplink
+// is within the rate, the key is not set. As the HandleUplinkDataRequest
+// message has no field for this flag, application-servers must read it
+// from the incoming gRPC metadata.
+const UplinkRateLimitedMetadataKey = "ns-uplink-rate-limited"
+
 var pool asclient.Pool
 
 // SetPool sets the given Pool.
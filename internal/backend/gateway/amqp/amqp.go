@@ -125,6 +125,14 @@ func (b *Backend) Close() error {
 	return b.chPool.close()
 }
 
+// HealthCheck returns an error when the AMQP connection is closed.
+func (b *Backend) HealthCheck() error {
+	if !b.chPool.isConnected() {
+		return errors.New("amqp connection is closed")
+	}
+	return nil
+}
+
 func (b *Backend) publishCommand(fields log.Fields, gatewayID lorawan.EUI64, command string, t marshaler.Type, data []byte) error {
 	ch, err := b.chPool.get()
 	if err != nil {
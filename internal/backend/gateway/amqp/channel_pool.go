@@ -81,6 +81,13 @@ func (p *pool) getChansAndConn() (chan *amqp.Channel, *amqp.Connection) {
 	return chans, conn
 }
 
+// isConnected returns true when the connection is open. Note that this
+// blocks while (re)connecting.
+func (p *pool) isConnected() bool {
+	_, conn := p.getChansAndConn()
+	return conn != nil && !conn.IsClosed()
+}
+
 func (p *pool) wrapChan(ch *amqp.Channel) *poolChannel {
 	return &poolChannel{
 		ch: ch,
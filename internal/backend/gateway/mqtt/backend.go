@@ -128,6 +128,14 @@ func (b *Backend) Close() error {
 	return nil
 }
 
+// HealthCheck returns an error when the MQTT client is not connected.
+func (b *Backend) HealthCheck() error {
+	if !b.conn.IsConnectionOpen() {
+		return errors.New("mqtt client is not connected")
+	}
+	return nil
+}
+
 // RXPacketChan returns the uplink-frame channel.
 func (b *Backend) RXPacketChan() chan gw.UplinkFrame {
 	return b.rxPacketChan
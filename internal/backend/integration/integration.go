@@ -0,0 +1,188 @@
+// Package integration implements the integration sink, which publishes
+// network-level events (uplinks, join-accepts, tx acknowledgements, ...) to
+// an external message bus.
+package integration
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brocaar/chirpstack-network-server/internal/logging"
+	"github.com/brocaar/lorawan"
+)
+
+// Event types.
+const (
+	EventUp         = "up"
+	EventJoin       = "join"
+	EventTXAck      = "txack"
+	EventMACCommand = "mac"
+	EventADR        = "adr"
+	EventStats      = "stats"
+	EventStatus     = "status"
+	EventRateLimit  = "ratelimit"
+)
+
+// Event defines an integration event.
+type Event struct {
+	// Type contains the event type.
+	Type string
+
+	// DevEUI and GatewayID contain the device and / or gateway to which the
+	// event relates. These are used by the integrations for routing the
+	// event, e.g. in the MQTT topic.
+	DevEUI    lorawan.EUI64
+	GatewayID lorawan.EUI64
+
+	// Message contains the event payload.
+	Message proto.Message
+}
+
+// Integration defines the interface that an integration must implement.
+type Integration interface {
+	PublishEvent(ctx context.Context, event Event) error // publish the given event
+	Close() error                                        // close the integration
+}
+
+// eventQueueSize defines the max. number of events waiting to be published.
+// When the queue is full, new events are dropped so that a slow or
+// unavailable integration does not pile up goroutines.
+const eventQueueSize = 1000
+
+type queuedEvent struct {
+	ctx   context.Context
+	event Event
+}
+
+var (
+	integration Integration
+	eventQueue  chan queuedEvent
+)
+
+// init sets the NopIntegration by default, as the integration is optional.
+func init() {
+	integration = &NopIntegration{}
+}
+
+// SetIntegration sets the given integration and starts the worker which
+// publishes the queued events.
+func SetIntegration(i Integration) {
+	if eventQueue != nil {
+		close(eventQueue)
+		eventQueue = nil
+	}
+
+	integration = i
+	if _, ok := i.(*NopIntegration); ok {
+		return
+	}
+
+	eventQueue = make(chan queuedEvent, eventQueueSize)
+	go publishEvents(i, eventQueue)
+}
+
+// GetIntegration returns the integration.
+func GetIntegration() Integration {
+	return integration
+}
+
+// PublishEvent queues the given event for publishing by the configured
+// integration. Errors are logged.
+func PublishEvent(ctx context.Context, event Event) {
+	if eventQueue == nil {
+		return
+	}
+
+	select {
+	case eventQueue <- queuedEvent{ctx: ctx, event: event}:
+	default:
+		log.WithFields(log.Fields{
+			"event":  event.Type,
+			"ctx_id": ctx.Value(logging.ContextIDKey),
+		}).Error("integration: event queue is full, dropping event")
+	}
+}
+
+// publishEvents publishes the events of the given queue, in order, until the
+// queue is closed.
+func publishEvents(i Integration, queue chan queuedEvent) {
+	for qe := range queue {
+		if err := i.PublishEvent(qe.ctx, qe.event); err != nil {
+			log.WithError(err).WithFields(log.Fields{
+				"event":  qe.event.Type,
+				"ctx_id": qe.ctx.Value(logging.ContextIDKey),
+			}).Error("integration: publish event error")
+		}
+	}
+}
+
+// ADRMessage returns the event payload for an acknowledged ADR change.
+func ADRMessage(devEUI lorawan.EUI64, dr, txPowerIndex, nbTrans int, enabledChannels []int) *structpb.Struct {
+	var channels []*structpb.Value
+	for _, c := range enabledChannels {
+		channels = append(channels, numberValue(float64(c)))
+	}
+
+	return &structpb.Struct{
+		Fields: map[string]*structpb.Value{
+			"devEUI":       {Kind: &structpb.Value_StringValue{StringValue: devEUI.String()}},
+			"dr":           numberValue(float64(dr)),
+			"txPowerIndex": numberValue(float64(txPowerIndex)),
+			"nbTrans":      numberValue(float64(nbTrans)),
+			"enabledChannels": {Kind: &structpb.Value_ListValue{ListValue: &structpb.ListValue{
+				Values: channels,
+			}}},
+		},
+	}
+}
+
+// GatewayStatusMessage returns the event payload for a gateway offline /
+// online transition.
+func GatewayStatusMessage(gatewayID lorawan.EUI64, status string, lastSeenAt time.Time) *structpb.Struct {
+	return &structpb.Struct{
+		Fields: map[string]*structpb.Value{
+			"gatewayID":  stringValue(gatewayID.String()),
+			"status":     stringValue(status),
+			"lastSeenAt": stringValue(lastSeenAt.UTC().Format(time.RFC3339Nano)),
+		},
+	}
+}
+
+// UplinkRateLimitMessage returns the event payload for an uplink exceeding
+// the uplink rate of the service-profile.
+func UplinkRateLimitMessage(devEUI lorawan.EUI64, fCnt uint32, policy string) *structpb.Struct {
+	return &structpb.Struct{
+		Fields: map[string]*structpb.Value{
+			"devEUI":    stringValue(devEUI.String()),
+			"direction": stringValue("uplink"),
+			"fCnt":      numberValue(float64(fCnt)),
+			"policy":    stringValue(policy),
+		},
+	}
+}
+
+func stringValue(v string) *structpb.Value {
+	return &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: v}}
+}
+
+func numberValue(v float64) *structpb.Value {
+	return &structpb.Value{Kind: &structpb.Value_NumberValue{NumberValue: v}}
+}
+
+// NopIntegration is a dummy integration which is used when no integration
+// is configured.
+type NopIntegration struct{}
+
+// PublishEvent does nothing.
+func (n *NopIntegration) PublishEvent(ctx context.Context, event Event) error {
+	return nil
+}
+
+// Close does nothing.
+func (n *NopIntegration) Close() error {
+	return nil
+}
@@ -0,0 +1,83 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/brocaar/lorawan"
+)
+
+type testIntegration struct {
+	events chan Event
+	block  chan struct{}
+}
+
+func (i *testIntegration) PublishEvent(ctx context.Context, event Event) error {
+	<-i.block
+	i.events <- event
+	return nil
+}
+
+func (i *testIntegration) Close() error {
+	return nil
+}
+
+func TestPublishEvent(t *testing.T) {
+	i := testIntegration{
+		events: make(chan Event, eventQueueSize+1),
+		block:  make(chan struct{}),
+	}
+	SetIntegration(&i)
+	defer SetIntegration(&NopIntegration{})
+
+	t.Run("Events are published in order", func(t *testing.T) {
+		assert := require.New(t)
+
+		PublishEvent(context.Background(), Event{Type: EventUp, DevEUI: lorawan.EUI64{1}})
+		PublishEvent(context.Background(), Event{Type: EventUp, DevEUI: lorawan.EUI64{2}})
+
+		i.block <- struct{}{}
+		i.block <- struct{}{}
+
+		assert.Equal(lorawan.EUI64{1}, (<-i.events).DevEUI)
+		assert.Equal(lorawan.EUI64{2}, (<-i.events).DevEUI)
+	})
+
+	t.Run("Full queue does not block", func(t *testing.T) {
+		assert := require.New(t)
+
+		done := make(chan struct{})
+		go func() {
+			for n := 0; n < eventQueueSize+2; n++ {
+				PublishEvent(context.Background(), Event{Type: EventUp})
+			}
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			assert.Fail("PublishEvent blocked")
+		}
+
+		close(i.block)
+
+		var count int
+	loop:
+		for {
+			select {
+			case <-i.events:
+				count++
+			case <-time.After(100 * time.Millisecond):
+				break loop
+			}
+		}
+
+		// depending on the worker taking the first event before the
+		// queue is full, one or two events are dropped
+		assert.True(count >= eventQueueSize && count <= eventQueueSize+1, "count: %d", count)
+	})
+}
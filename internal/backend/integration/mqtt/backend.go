@@ -0,0 +1,177 @@
+// Package mqtt implements a MQTT integration.
+package mqtt
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"text/template"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brocaar/chirpstack-network-server/internal/backend/gateway/marshaler"
+	"github.com/brocaar/chirpstack-network-server/internal/backend/integration"
+	"github.com/brocaar/chirpstack-network-server/internal/config"
+	"github.com/brocaar/chirpstack-network-server/internal/logging"
+	"github.com/brocaar/lorawan"
+)
+
+// publishTimeout defines the max. time to wait for an event to be published,
+// so that an unavailable broker does not block the event queue.
+const publishTimeout = 10 * time.Second
+
+// Backend implements a MQTT integration.
+type Backend struct {
+	conn               paho.Client
+	eventTopicTemplate *template.Template
+	qos                uint8
+	marshaler          marshaler.Type
+}
+
+// NewBackend creates a new Backend.
+func NewBackend(c config.Config) (integration.Integration, error) {
+	conf := c.Integration.MQTT
+	var err error
+
+	b := Backend{
+		qos: conf.QOS,
+	}
+
+	switch c.Integration.Marshaler {
+	case "json":
+		b.marshaler = marshaler.JSON
+	case "protobuf":
+		b.marshaler = marshaler.Protobuf
+	default:
+		return nil, fmt.Errorf("integration/mqtt: unexpected marshaler: %s", c.Integration.Marshaler)
+	}
+
+	b.eventTopicTemplate, err = template.New("event").Parse(conf.EventTopicTemplate)
+	if err != nil {
+		return nil, errors.Wrap(err, "integration/mqtt: parse event topic template error")
+	}
+
+	opts := paho.NewClientOptions()
+	opts.AddBroker(conf.Server)
+	opts.SetUsername(conf.Username)
+	opts.SetPassword(conf.Password)
+	opts.SetCleanSession(conf.CleanSession)
+	opts.SetClientID(conf.ClientID)
+	opts.SetOnConnectHandler(b.onConnected)
+	opts.SetConnectionLostHandler(b.onConnectionLost)
+	opts.SetMaxReconnectInterval(conf.MaxReconnectInterval)
+
+	tlsconfig, err := newTLSConfig(conf.CACert, conf.TLSCert, conf.TLSKey)
+	if err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"ca_cert":  conf.CACert,
+			"tls_cert": conf.TLSCert,
+			"tls_key":  conf.TLSKey,
+		}).Fatal("integration/mqtt: error loading mqtt certificate files")
+	}
+	if tlsconfig != nil {
+		opts.SetTLSConfig(tlsconfig)
+	}
+
+	log.WithField("server", conf.Server).Info("integration/mqtt: connecting to mqtt broker")
+	b.conn = paho.NewClient(opts)
+	for {
+		if token := b.conn.Connect(); token.Wait() && token.Error() != nil {
+			log.Errorf("integration/mqtt: connecting to mqtt broker failed, will retry in 2s: %s", token.Error())
+			time.Sleep(2 * time.Second)
+		} else {
+			break
+		}
+	}
+
+	return &b, nil
+}
+
+// PublishEvent publishes the given event.
+func (b *Backend) PublishEvent(ctx context.Context, event integration.Event) error {
+	bb, err := marshaler.MarshalCommand(b.marshaler, event.Message)
+	if err != nil {
+		return errors.Wrap(err, "integration/mqtt: marshal event error")
+	}
+
+	templateCtx := struct {
+		EventType string
+		DevEUI    lorawan.EUI64
+		GatewayID lorawan.EUI64
+	}{event.Type, event.DevEUI, event.GatewayID}
+	topic := bytes.NewBuffer(nil)
+	if err := b.eventTopicTemplate.Execute(topic, templateCtx); err != nil {
+		return errors.Wrap(err, "execute event topic template error")
+	}
+
+	log.WithFields(log.Fields{
+		"event":  event.Type,
+		"qos":    b.qos,
+		"topic":  topic.String(),
+		"ctx_id": ctx.Value(logging.ContextIDKey),
+	}).Debug("integration/mqtt: publishing event")
+
+	token := b.conn.Publish(topic.String(), b.qos, false, bb)
+	if !token.WaitTimeout(publishTimeout) {
+		return errors.New("integration/mqtt: publish event timeout")
+	}
+	if token.Error() != nil {
+		return errors.Wrap(token.Error(), "integration/mqtt: publish event error")
+	}
+
+	return nil
+}
+
+// Close closes the backend.
+func (b *Backend) Close() error {
+	log.Info("integration/mqtt: closing backend")
+	b.conn.Disconnect(250)
+	return nil
+}
+
+func (b *Backend) onConnected(c paho.Client) {
+	log.Info("integration/mqtt: connected to mqtt server")
+}
+
+func (b *Backend) onConnectionLost(c paho.Client, reason error) {
+	log.Errorf("integration/mqtt: mqtt connection error: %s", reason)
+}
+
+func newTLSConfig(cafile, certFile, certKeyFile string) (*tls.Config, error) {
+	if cafile == "" && certFile == "" && certKeyFile == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{}
+
+	// Import trusted certificates from CAfile.pem.
+	if cafile != "" {
+		cacert, err := ioutil.ReadFile(cafile)
+		if err != nil {
+			log.WithError(err).Error("integration/mqtt: could not load ca certificate")
+			return nil, err
+		}
+		certpool := x509.NewCertPool()
+		certpool.AppendCertsFromPEM(cacert)
+
+		tlsConfig.RootCAs = certpool // RootCAs = certs used to verify server cert.
+	}
+
+	// Import certificate and the key
+	if certFile != "" && certKeyFile != "" {
+		kp, err := tls.LoadX509KeyPair(certFile, certKeyFile)
+		if err != nil {
+			log.WithError(err).Error("integration/mqtt: could not load mqtt tls key-pair")
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{kp}
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,92 @@
+package mqtt
+
+import (
+	"context"
+	"testing"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/brocaar/chirpstack-api/go/v3/nc"
+	"github.com/brocaar/chirpstack-network-server/internal/backend/integration"
+	"github.com/brocaar/chirpstack-network-server/internal/test"
+	"github.com/brocaar/lorawan"
+)
+
+type BackendTestSuite struct {
+	suite.Suite
+
+	backend    integration.Integration
+	mqttClient paho.Client
+}
+
+func (ts *BackendTestSuite) SetupSuite() {
+	assert := require.New(ts.T())
+
+	conf := test.GetConfig()
+	conf.Integration.Marshaler = "json"
+	conf.Integration.MQTT.Server = conf.NetworkServer.Gateway.Backend.MQTT.Server
+	conf.Integration.MQTT.Username = conf.NetworkServer.Gateway.Backend.MQTT.Username
+	conf.Integration.MQTT.Password = conf.NetworkServer.Gateway.Backend.MQTT.Password
+	conf.Integration.MQTT.CleanSession = true
+	conf.Integration.MQTT.EventTopicTemplate = "network-server/device/{{ .DevEUI }}/event/{{ .EventType }}"
+
+	opts := paho.NewClientOptions().
+		AddBroker(conf.NetworkServer.Gateway.Backend.MQTT.Server).
+		SetUsername(conf.NetworkServer.Gateway.Backend.MQTT.Username).
+		SetPassword(conf.NetworkServer.Gateway.Backend.MQTT.Password)
+	ts.mqttClient = paho.NewClient(opts)
+	token := ts.mqttClient.Connect()
+	token.Wait()
+	assert.NoError(token.Error())
+
+	var err error
+	ts.backend, err = NewBackend(conf)
+	assert.NoError(err)
+}
+
+func (ts *BackendTestSuite) TearDownSuite() {
+	assert := require.New(ts.T())
+
+	ts.mqttClient.Disconnect(0)
+	assert.NoError(ts.backend.Close())
+}
+
+func (ts *BackendTestSuite) TestPublishEvent() {
+	assert := require.New(ts.T())
+
+	devEUI := lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8}
+	msg := nc.HandleUplinkMACCommandRequest{
+		DevEui:   devEUI[:],
+		Cid:      3,
+		Commands: [][]byte{{3, 7}},
+	}
+
+	eventChan := make(chan paho.Message)
+	token := ts.mqttClient.Subscribe("network-server/device/+/event/+", 0, func(c paho.Client, msg paho.Message) {
+		eventChan <- msg
+	})
+	token.Wait()
+	assert.NoError(token.Error())
+	defer ts.mqttClient.Unsubscribe("network-server/device/+/event/+").Wait()
+
+	assert.NoError(ts.backend.PublishEvent(context.Background(), integration.Event{
+		Type:    integration.EventMACCommand,
+		DevEUI:  devEUI,
+		Message: &msg,
+	}))
+
+	event := <-eventChan
+	assert.Equal("network-server/device/0102030405060708/event/mac", event.Topic())
+
+	var received nc.HandleUplinkMACCommandRequest
+	assert.NoError(jsonpb.UnmarshalString(string(event.Payload()), &received))
+	assert.True(proto.Equal(&msg, &received))
+}
+
+func TestBackend(t *testing.T) {
+	suite.Run(t, new(BackendTestSuite))
+}
@@ -1,8 +1,11 @@
 package joinserver
 
 import (
+	"context"
 	"encoding/hex"
 	"fmt"
+	"net"
+	"net/url"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -167,3 +170,31 @@ func joinEUIToServer(joinEUI lorawan.EUI64, domain string) string {
 
 	return "https://" + strings.Join(nibbles, ".") + domain
 }
+
+// HealthCheck returns an error when the default join-server can not be
+// reached. Note that this only validates that a TCP connection can be
+// established.
+func HealthCheck(ctx context.Context) error {
+	u, err := url.Parse(defaultServer)
+	if err != nil {
+		return errors.Wrap(err, "parse default join-server url error")
+	}
+
+	host := u.Host
+	if u.Port() == "" {
+		switch u.Scheme {
+		case "https":
+			host = net.JoinHostPort(u.Hostname(), "443")
+		default:
+			host = net.JoinHostPort(u.Hostname(), "80")
+		}
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return errors.Wrap(err, "dial default join-server error")
+	}
+
+	return conn.Close()
+}
@@ -67,14 +67,22 @@ type Config struct {
 			MaxMACCommandErrorCount int     `mapstructure:"max_mac_command_error_count"`
 
 			ExtraChannels []struct {
-				Frequency int `mapstructure:"frequency"`
-				MinDR     int `mapstructure:"min_dr"`
-				MaxDR     int `mapstructure:"max_dr"`
+				Frequency         int `mapstructure:"frequency"`
+				MinDR             int `mapstructure:"min_dr"`
+				MaxDR             int `mapstructure:"max_dr"`
+				DownlinkFrequency int `mapstructure:"downlink_frequency"`
 			} `mapstructure:"extra_channels"`
 
 			ClassB struct {
 				PingSlotDR        int `mapstructure:"ping_slot_dr"`
 				PingSlotFrequency int `mapstructure:"ping_slot_frequency"`
+
+				Beacon struct {
+					Enabled           bool     `mapstructure:"enabled"`
+					Frequency         int      `mapstructure:"frequency"`
+					InfoDesc          string   `mapstructure:"info_desc"`
+					GatewayProfileIDs []string `mapstructure:"gateway_profile_ids"`
+				} `mapstructure:"beacon"`
 			} `mapstructure:"class_b"`
 
 			RejoinRequest struct {
@@ -82,11 +90,31 @@ type Config struct {
 				MaxCountN int  `mapstructure:"max_count_n"`
 				MaxTimeN  int  `mapstructure:"max_time_n"`
 			} `mapstructure:"rejoin_request"`
+
+			DutyCycle struct {
+				Window time.Duration `mapstructure:"window"`
+			} `mapstructure:"duty_cycle"`
 		} `mapstructure:"network_settings"`
 
+		FrameLog struct {
+			HistoryMaxLen     int64         `mapstructure:"history_max_len"`
+			HistoryTTL        time.Duration `mapstructure:"history_ttl"`
+			StreamReplayCount int64         `mapstructure:"stream_replay_count"`
+		} `mapstructure:"frame_log"`
+
+		DeviceActivation struct {
+			Retention     time.Duration `mapstructure:"retention"`
+			PruneInterval time.Duration `mapstructure:"prune_interval"`
+		} `mapstructure:"device_activation"`
+
 		Scheduler struct {
 			SchedulerInterval time.Duration `mapstructure:"scheduler_interval"`
 
+			LeaderElection struct {
+				Enabled       bool          `mapstructure:"enabled"`
+				LeaseDuration time.Duration `mapstructure:"lease_duration"`
+			} `mapstructure:"leader_election"`
+
 			ClassC struct {
 				DownlinkLockDuration  time.Duration `mapstructure:"downlink_lock_duration"`
 				MulticastGatewayDelay time.Duration `mapstructure:"multicast_gateway_delay"`
@@ -110,6 +138,24 @@ type Config struct {
 			CAKey              string        `mapstructure:"ca_key"`
 			ClientCertLifetime time.Duration `mapstructure:"client_cert_lifetime"`
 
+			DownlinkAirtime struct {
+				Enabled  bool          `mapstructure:"enabled"`
+				Window   time.Duration `mapstructure:"window"`
+				SubBands []struct {
+					MinFrequency int     `mapstructure:"min_frequency"`
+					MaxFrequency int     `mapstructure:"max_frequency"`
+					MaxDutyCycle float64 `mapstructure:"max_duty_cycle"`
+				} `mapstructure:"sub_bands"`
+			} `mapstructure:"downlink_airtime"`
+
+			OfflineDetection struct {
+				Enabled              bool          `mapstructure:"enabled"`
+				Interval             time.Duration `mapstructure:"interval"`
+				MissedStatsIntervals int           `mapstructure:"missed_stats_intervals"`
+				DefaultStatsInterval time.Duration `mapstructure:"default_stats_interval"`
+				WebhookURL           string        `mapstructure:"webhook_url"`
+			} `mapstructure:"offline_detection"`
+
 			Backend struct {
 				Type                 string `mapstructure:"type"`
 				MultiDownlinkFeature string `mapstructure:"multi_downlink_feature"`
@@ -178,7 +224,8 @@ type Config struct {
 	} `mapstructure:"join_server"`
 
 	Roaming struct {
-		ResolveNetIDDomainSuffix string `mapstructure:"resolve_netid_domain_suffix"`
+		ResolveNetIDDomainSuffix string        `mapstructure:"resolve_netid_domain_suffix"`
+		AgreementReloadInterval  time.Duration `mapstructure:"agreement_reload_interval"`
 
 		API struct {
 			Bind    string `mapstructure:"bind"`
@@ -204,6 +251,25 @@ type Config struct {
 		TLSKey  string `mapstructure:"tls_key"`
 	} `mapstructure:"network_controller"`
 
+	Integration struct {
+		Type      string `mapstructure:"type"`
+		Marshaler string `mapstructure:"marshaler"`
+
+		MQTT struct {
+			Server               string        `mapstructure:"server"`
+			Username             string        `mapstructure:"username"`
+			Password             string        `mapstructure:"password"`
+			MaxReconnectInterval time.Duration `mapstructure:"max_reconnect_interval"`
+			QOS                  uint8         `mapstructure:"qos"`
+			CleanSession         bool          `mapstructure:"clean_session"`
+			ClientID             string        `mapstructure:"client_id"`
+			CACert               string        `mapstructure:"ca_cert"`
+			TLSCert              string        `mapstructure:"tls_cert"`
+			TLSKey               string        `mapstructure:"tls_key"`
+			EventTopicTemplate   string        `mapstructure:"event_topic_template"`
+		} `mapstructure:"mqtt"`
+	} `mapstructure:"integration"`
+
 	Metrics struct {
 		Timezone string `mapstructure:"timezone"`
 
@@ -223,30 +289,36 @@ type Config struct {
 }
 
 type RoamingServer struct {
-	NetID                  lorawan.NetID
-	NetIDString            string        `mapstructure:"net_id"`
-	Async                  bool          `mapstructure:"async"`
-	AsyncTimeout           time.Duration `mapstructure:"async_timeout"`
-	PassiveRoaming         bool          `mapstructure:"passive_roaming"`
-	PassiveRoamingLifetime time.Duration `mapstructure:"passive_roaming_lifetime"`
-	PassiveRoamingKEKLabel string        `mapstructure:"passive_roaming_kek_label"`
-	Server                 string        `mapstructure:"server"`
-	CACert                 string        `mapstructure:"ca_cert"`
-	TLSCert                string        `mapstructure:"tls_cert"`
-	TLSKey                 string        `mapstructure:"tls_key"`
+	NetID                   lorawan.NetID
+	NetIDString             string        `mapstructure:"net_id"`
+	Async                   bool          `mapstructure:"async"`
+	AsyncTimeout            time.Duration `mapstructure:"async_timeout"`
+	PassiveRoaming          bool          `mapstructure:"passive_roaming"`
+	PassiveRoamingLifetime  time.Duration `mapstructure:"passive_roaming_lifetime"`
+	PassiveRoamingKEKLabel  string        `mapstructure:"passive_roaming_kek_label"`
+	HandoverRoaming         bool          `mapstructure:"handover_roaming"`
+	HandoverRoamingLifetime time.Duration `mapstructure:"handover_roaming_lifetime"`
+	HandoverRoamingKEKLabel string        `mapstructure:"handover_roaming_kek_label"`
+	Server                  string        `mapstructure:"server"`
+	CACert                  string        `mapstructure:"ca_cert"`
+	TLSCert                 string        `mapstructure:"tls_cert"`
+	TLSKey                  string        `mapstructure:"tls_key"`
 }
 
 type DefaultRoamingServer struct {
-	Enabled                bool          `mapstructure:"enabled"`
-	Async                  bool          `mapstructure:"async"`
-	AsyncTimeout           time.Duration `mapstructure:"async_timeout"`
-	PassiveRoaming         bool          `mapstructure:"passive_roaming"`
-	PassiveRoamingLifetime time.Duration `mapstructure:"passive_roaming_lifetime"`
-	PassiveRoamingKEKLabel string        `mapstructure:"passive_roaming_kek_label"`
-	Server                 string        `mapstructure:"server"`
-	CACert                 string        `mapstructure:"ca_cert"`
-	TLSCert                string        `mapstructure:"tls_cert"`
-	TLSKey                 string        `mapstructure:"tls_key"`
+	Enabled                 bool          `mapstructure:"enabled"`
+	Async                   bool          `mapstructure:"async"`
+	AsyncTimeout            time.Duration `mapstructure:"async_timeout"`
+	PassiveRoaming          bool          `mapstructure:"passive_roaming"`
+	PassiveRoamingLifetime  time.Duration `mapstructure:"passive_roaming_lifetime"`
+	PassiveRoamingKEKLabel  string        `mapstructure:"passive_roaming_kek_label"`
+	HandoverRoaming         bool          `mapstructure:"handover_roaming"`
+	HandoverRoamingLifetime time.Duration `mapstructure:"handover_roaming_lifetime"`
+	HandoverRoamingKEKLabel string        `mapstructure:"handover_roaming_kek_label"`
+	Server                  string        `mapstructure:"server"`
+	CACert                  string        `mapstructure:"ca_cert"`
+	TLSCert                 string        `mapstructure:"tls_cert"`
+	TLSKey                  string        `mapstructure:"tls_key"`
 }
 
 type KEK struct {
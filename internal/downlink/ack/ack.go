@@ -16,6 +16,8 @@ import (
 	"github.com/brocaar/chirpstack-api/go/v3/ns"
 	"github.com/brocaar/chirpstack-network-server/internal/backend/controller"
 	"github.com/brocaar/chirpstack-network-server/internal/backend/gateway"
+	"github.com/brocaar/chirpstack-network-server/internal/backend/integration"
+	dwngateway "github.com/brocaar/chirpstack-network-server/internal/downlink/gateway"
 	"github.com/brocaar/chirpstack-network-server/internal/framelog"
 	"github.com/brocaar/chirpstack-network-server/internal/helpers"
 	"github.com/brocaar/chirpstack-network-server/internal/logging"
@@ -30,6 +32,7 @@ var handleDownlinkTXAckTasks = []func(*ackContext) error{
 	getToken,
 	getDownlinkFrame,
 	decodePHYPayload,
+	updateGatewayAirtime,
 	onError(
 		sendErrorToApplicationServerOnLastFrame,
 		sendDownlinkFrame,
@@ -195,6 +198,23 @@ func decodePHYPayload(ctx *ackContext) error {
 	return nil
 }
 
+// updateGatewayAirtime confirms the reserved downlink airtime of the gateway
+// when the downlink was transmitted, or releases it on error.
+func updateGatewayAirtime(ctx *ackContext) error {
+	if ctx.DownlinkFrame.DownlinkFrame == nil {
+		return nil
+	}
+
+	transmitted := ctx.DownlinkTXAckStatus == gw.TxAckStatus_OK
+	if err := dwngateway.ConfirmDownlinkAirtime(ctx.ctx, *ctx.DownlinkFrame.DownlinkFrame, ctx.DownlinkFrameItem, transmitted); err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"ctx_id": ctx.ctx.Value(logging.ContextIDKey),
+		}).Error("confirm gateway downlink airtime error")
+	}
+
+	return nil
+}
+
 func sendDownlinkMetaDataToNetworkController(ctx *ackContext) error {
 	req := nc.HandleDownlinkMetaDataRequest{
 		GatewayId:           ctx.DownlinkFrame.DownlinkFrame.GatewayId,
@@ -232,6 +252,17 @@ func sendDownlinkMetaDataToNetworkController(ctx *ackContext) error {
 		}
 	}
 
+	event := integration.Event{
+		Type:    integration.EventTXAck,
+		Message: &req,
+	}
+	if ctx.MHDR.MType == lorawan.JoinAccept {
+		event.Type = integration.EventJoin
+	}
+	copy(event.DevEUI[:], ctx.DownlinkFrame.DevEui)
+	copy(event.GatewayID[:], ctx.DownlinkFrame.DownlinkFrame.GatewayId)
+	integration.PublishEvent(ctx.ctx, event)
+
 	// send async to controller
 	go func() {
 		_, err := controller.Client().HandleDownlinkMetaData(ctx.ctx, &req)
@@ -332,16 +363,24 @@ func sendDownlinkFrame(ctx *ackContext) error {
 
 	// send the next item
 	item := ctx.DownlinkFrame.DownlinkFrame.Items[1]
-	if err := gateway.Backend().SendTXPacket(gw.DownlinkFrame{
+	df := gw.DownlinkFrame{
 		GatewayId:  ctx.DownlinkFrame.DownlinkFrame.GatewayId,
 		Token:      ctx.DownlinkFrame.DownlinkFrame.Token,
 		DownlinkId: ctx.DownlinkFrame.DownlinkFrame.DownlinkId,
 		Items: []*gw.DownlinkFrameItem{
 			item,
 		},
-	}); err != nil {
+	}
+	if err := gateway.Backend().SendTXPacket(df); err != nil {
 		return errors.Wrap(err, "send downlink-frame to gateway error")
 	}
+
+	if err := dwngateway.ReserveDownlinkAirtime(ctx.ctx, df); err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"ctx_id": ctx.ctx.Value(logging.ContextIDKey),
+		}).Error("reserve gateway downlink airtime error")
+	}
+
 	return nil
 }
 
@@ -410,7 +449,7 @@ func logDownlinkFrame(ctx *ackContext) error {
 		Token:      ctx.DownlinkFrame.DownlinkFrame.Token,
 		DownlinkId: ctx.DownlinkFrame.DownlinkFrame.DownlinkId,
 		GatewayId:  ctx.DownlinkFrame.DownlinkFrame.GatewayId,
-	}); err != nil {
+	}, ctx.DownlinkFrame.RateLimited); err != nil {
 		log.WithError(err).WithFields(log.Fields{
 			"ctx_id": ctx.ctx.Value(logging.ContextIDKey),
 		}).Error("log downlink frame for device error")
@@ -0,0 +1,362 @@
+// Package beacon implements the scheduling of the Class-B beacons by the
+// network-server.
+package beacon
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brocaar/chirpstack-api/go/v3/gw"
+	"github.com/brocaar/chirpstack-network-server/internal/backend/gateway"
+	"github.com/brocaar/chirpstack-network-server/internal/band"
+	"github.com/brocaar/chirpstack-network-server/internal/config"
+	"github.com/brocaar/chirpstack-network-server/internal/downlink/data/classb"
+	"github.com/brocaar/chirpstack-network-server/internal/gps"
+	"github.com/brocaar/chirpstack-network-server/internal/helpers"
+	"github.com/brocaar/chirpstack-network-server/internal/logging"
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/lorawan"
+)
+
+const (
+	beaconPeriod = 128 * time.Second
+
+	// beaconTXDelay defines the delay between the beacon-period start and
+	// the beacon transmission (TBeaconDelay).
+	beaconTXDelay = 1500 * time.Microsecond
+
+	// scheduleMargin defines how long before the beacon transmission the
+	// beacon frames are sent to the gateways.
+	scheduleMargin = 10 * time.Second
+
+	beaconLeaseTempl = "class-b-beacon:%d"
+)
+
+// Gateway specific info descriptors.
+const (
+	InfoDescGPS   = "gps"
+	InfoDescNetID = "net_id"
+)
+
+// params contains the regional beacon parameters.
+type params struct {
+	dr          int
+	frequencies []int // frequency hopping when len > 1
+	rfu1        int
+	rfu2        int
+}
+
+// bandParams contains the beacon parameters per band name.
+var bandParams = map[string]params{
+	"AS923": {dr: 3, frequencies: []int{923400000}, rfu1: 2},
+	"AU915": {dr: 8, frequencies: hoppingFrequencies(923300000, 600000, 8), rfu1: 5, rfu2: 3},
+	"CN470": {dr: 2, frequencies: hoppingFrequencies(508300000, 200000, 8), rfu1: 3, rfu2: 1},
+	"CN779": {dr: 3, frequencies: []int{785000000}, rfu1: 2},
+	"EU433": {dr: 3, frequencies: []int{434665000}, rfu1: 2},
+	"EU868": {dr: 3, frequencies: []int{869525000}, rfu1: 2},
+	"IN865": {dr: 4, frequencies: []int{866550000}, rfu1: 1, rfu2: 2},
+	"KR920": {dr: 3, frequencies: []int{923100000}, rfu1: 2},
+	"RU864": {dr: 3, frequencies: []int{869100000}, rfu1: 2},
+	"US915": {dr: 8, frequencies: hoppingFrequencies(923300000, 600000, 8), rfu1: 5, rfu2: 3},
+}
+
+var (
+	enabled           bool
+	frequency         int
+	infoDesc          string
+	gatewayProfileIDs []uuid.UUID
+	netID             lorawan.NetID
+	downlinkTXPower   int
+	instanceID        string
+
+	// lastBeacon contains the last beacon start (since GPS epoch) handled
+	// by this instance.
+	lastBeacon time.Duration
+)
+
+// Setup sets up the beacon package.
+func Setup(conf config.Config) error {
+	beaconConf := conf.NetworkServer.NetworkSettings.ClassB.Beacon
+
+	enabled = beaconConf.Enabled
+	frequency = beaconConf.Frequency
+	infoDesc = beaconConf.InfoDesc
+	netID = conf.NetworkServer.NetID
+	downlinkTXPower = conf.NetworkServer.NetworkSettings.DownlinkTXPower
+
+	if !enabled {
+		return nil
+	}
+
+	switch infoDesc {
+	case InfoDescGPS, InfoDescNetID:
+	default:
+		return fmt.Errorf("invalid beacon info_desc: %s", infoDesc)
+	}
+
+	gatewayProfileIDs = nil
+	for _, idStr := range beaconConf.GatewayProfileIDs {
+		id, err := uuid.FromString(idStr)
+		if err != nil {
+			return errors.Wrap(err, "decode gateway_profile_ids error")
+		}
+		gatewayProfileIDs = append(gatewayProfileIDs, id)
+	}
+
+	if _, ok := bandParams[band.Band().Name()]; !ok {
+		return fmt.Errorf("beacon parameters for band %s are not implemented", band.Band().Name())
+	}
+
+	id, err := uuid.NewV4()
+	if err != nil {
+		return errors.Wrap(err, "new uuid error")
+	}
+	instanceID = id.String()
+
+	log.Warning("downlink/beacon: beacons are sent as regular downlinks, make sure the gateways send these with implicit header and without crc")
+
+	return nil
+}
+
+// Enabled returns true when the network-server schedules the beacons.
+func Enabled() bool {
+	return enabled
+}
+
+// ScheduleNextBeacon sends the frames of the next beacon to the gateways,
+// once the beacon is within the schedule margin. It is safe to call this
+// function repeatedly and from multiple network-server instances, each
+// beacon is scheduled only once.
+func ScheduleNextBeacon(ctx context.Context) error {
+	if !enabled {
+		return nil
+	}
+
+	beaconStart := classb.GetBeaconStartForTime(time.Now()) + beaconPeriod
+	if beaconStart == lastBeacon {
+		return nil
+	}
+
+	txTime := time.Time(gps.NewFromTimeSinceGPSEpoch(beaconStart + beaconTXDelay))
+	if time.Until(txTime) > scheduleMargin {
+		return nil
+	}
+
+	// make sure that only one instance schedules the beacon
+	scheduled, err := storage.AcquireLease(ctx, fmt.Sprintf(beaconLeaseTempl, beaconStart/time.Second), instanceID, beaconPeriod)
+	if err != nil {
+		return errors.Wrap(err, "acquire beacon lease error")
+	}
+	lastBeacon = beaconStart
+
+	if !scheduled {
+		return nil
+	}
+
+	return ScheduleBeacon(ctx, beaconStart)
+}
+
+// ScheduleBeacon sends the beacon frames for the given beacon start (since
+// GPS epoch) to the beacon gateways.
+func ScheduleBeacon(ctx context.Context, beaconStart time.Duration) error {
+	gws, err := storage.GetBeaconGateways(ctx, storage.DB(), gatewayProfileIDs)
+	if err != nil {
+		return errors.Wrap(err, "get beacon gateways error")
+	}
+
+	for _, g := range gws {
+		df, err := getDownlinkFrame(ctx, beaconStart, g)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"gateway_id": g.GatewayID,
+				"ctx_id":     ctx.Value(logging.ContextIDKey),
+			}).WithError(err).Error("downlink/beacon: get beacon frame error")
+			continue
+		}
+
+		if err := gateway.Backend().SendTXPacket(df); err != nil {
+			log.WithFields(log.Fields{
+				"gateway_id": g.GatewayID,
+				"ctx_id":     ctx.Value(logging.ContextIDKey),
+			}).WithError(err).Error("downlink/beacon: send beacon frame error")
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"beacon_time": int64(beaconStart / time.Second),
+		"gateways":    len(gws),
+		"ctx_id":      ctx.Value(logging.ContextIDKey),
+	}).Info("downlink/beacon: beacon scheduled")
+
+	return nil
+}
+
+func getDownlinkFrame(ctx context.Context, beaconStart time.Duration, g storage.Gateway) (gw.DownlinkFrame, error) {
+	p := bandParams[band.Band().Name()]
+
+	gwSpecific, err := getGWSpecific(g)
+	if err != nil {
+		return gw.DownlinkFrame{}, errors.Wrap(err, "get gateway specific info error")
+	}
+
+	freq := frequency
+	if freq == 0 {
+		freq = getFrequency(p, beaconStart)
+	}
+
+	txInfo := gw.DownlinkTXInfo{
+		Frequency: uint32(freq),
+		Timing:    gw.DownlinkTiming_GPS_EPOCH,
+		TimingInfo: &gw.DownlinkTXInfo_GpsEpochTimingInfo{
+			GpsEpochTimingInfo: &gw.GPSEpochTimingInfo{
+				TimeSinceGpsEpoch: ptypes.DurationProto(beaconStart + beaconTXDelay),
+			},
+		},
+	}
+
+	if err := helpers.SetDownlinkTXInfoDataRate(&txInfo, p.dr, band.Band()); err != nil {
+		return gw.DownlinkFrame{}, errors.Wrap(err, "set data-rate error")
+	}
+
+	// Beacons must be sent with implicit header and without CRC. As this can
+	// not be expressed by the gateway API, this depends on the gateway
+	// implementation (see the beacon configuration documentation).
+
+	// beacons are sent without polarization inversion
+	if modInfo := txInfo.GetLoraModulationInfo(); modInfo != nil {
+		modInfo.PolarizationInversion = false
+	}
+
+	if downlinkTXPower != -1 {
+		txInfo.Power = int32(downlinkTXPower)
+	} else {
+		txInfo.Power = int32(band.Band().GetDownlinkTXPower(freq))
+	}
+
+	b := make([]byte, 2)
+	if _, err := rand.Read(b); err != nil {
+		return gw.DownlinkFrame{}, errors.Wrap(err, "read random error")
+	}
+
+	var downID uuid.UUID
+	if ctxID := ctx.Value(logging.ContextIDKey); ctxID != nil {
+		if id, ok := ctxID.(uuid.UUID); ok {
+			downID = id
+		}
+	}
+
+	return gw.DownlinkFrame{
+		Token:      uint32(binary.BigEndian.Uint16(b)),
+		DownlinkId: downID[:],
+		GatewayId:  g.GatewayID[:],
+		Items: []*gw.DownlinkFrameItem{
+			{
+				PhyPayload: getBeaconPayload(p, beaconStart, gwSpecific),
+				TxInfo:     &txInfo,
+			},
+		},
+	}, nil
+}
+
+// getGWSpecific returns the gateway specific part of the beacon
+// (InfoDesc + Info).
+func getGWSpecific(g storage.Gateway) ([7]byte, error) {
+	var out [7]byte
+
+	switch infoDesc {
+	case InfoDescNetID:
+		out[0] = 3
+		netIDB, err := netID.MarshalBinary()
+		if err != nil {
+			return out, errors.Wrap(err, "marshal netid error")
+		}
+		copy(out[1:4], netIDB)
+		// least significant bytes of the gateway ID, little endian
+		for i := 0; i < 3; i++ {
+			out[4+i] = g.GatewayID[7-i]
+		}
+	default:
+		out[0] = 0
+		lat := int32(g.Location.Latitude / 90 * (1 << 23))
+		lng := int32(g.Location.Longitude / 180 * (1 << 23))
+		putInt24(out[1:4], lat)
+		putInt24(out[4:7], lng)
+	}
+
+	return out, nil
+}
+
+// getBeaconPayload returns the beacon payload for the given beacon start
+// (since GPS epoch) and gateway specific info.
+func getBeaconPayload(p params, beaconStart time.Duration, gwSpecific [7]byte) []byte {
+	b := make([]byte, p.rfu1+4+2+len(gwSpecific)+p.rfu2+2)
+
+	// network common part
+	binary.LittleEndian.PutUint32(b[p.rfu1:p.rfu1+4], uint32(int64(beaconStart/time.Second)%(1<<32)))
+	binary.LittleEndian.PutUint16(b[p.rfu1+4:p.rfu1+6], crc16(b[0:p.rfu1+4]))
+
+	// gateway specific part
+	gwStart := p.rfu1 + 6
+	gwEnd := gwStart + len(gwSpecific) + p.rfu2
+	copy(b[gwStart:], gwSpecific[:])
+	binary.LittleEndian.PutUint16(b[gwEnd:], crc16(b[gwStart:gwEnd]))
+
+	return b
+}
+
+// getFrequency returns the beacon frequency for the given beacon start.
+func getFrequency(p params, beaconStart time.Duration) int {
+	if len(p.frequencies) == 1 {
+		return p.frequencies[0]
+	}
+
+	return p.frequencies[int(beaconStart/beaconPeriod)%len(p.frequencies)]
+}
+
+func hoppingFrequencies(start, step, count int) []int {
+	var out []int
+	for i := 0; i < count; i++ {
+		out = append(out, start+i*step)
+	}
+	return out
+}
+
+// putInt24 writes the 24 bit two's complement little endian representation
+// of v into b, clamping v to the 24 bit range.
+func putInt24(b []byte, v int32) {
+	if v > 0x7fffff {
+		v = 0x7fffff
+	}
+	if v < -0x800000 {
+		v = -0x800000
+	}
+
+	b[0] = byte(v)
+	b[1] = byte(v >> 8)
+	b[2] = byte(v >> 16)
+}
+
+// crc16 returns the CRC-16/CCITT (polynomial 0x1021, initial value 0x0000)
+// of the given bytes.
+func crc16(b []byte) uint16 {
+	var crc uint16
+	for _, v := range b {
+		crc ^= uint16(v) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,59 @@
+package beacon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/lorawan"
+)
+
+func TestGetBeaconPayload(t *testing.T) {
+	assert := require.New(t)
+
+	// example from the LoRaWAN Class-B specification
+	b := getBeaconPayload(bandParams["EU868"], 0xcc020000*time.Second, [7]byte{0x00, 0x01, 0x20, 0x00, 0x00, 0x81, 0x03})
+	assert.Equal([]byte{0x00, 0x00, 0x00, 0x00, 0x02, 0xcc, 0xa2, 0x7e, 0x00, 0x01, 0x20, 0x00, 0x00, 0x81, 0x03, 0xde, 0x55}, b)
+
+	b = getBeaconPayload(bandParams["US915"], 0xcc020000*time.Second, [7]byte{})
+	assert.Len(b, 23)
+}
+
+func TestGetGWSpecific(t *testing.T) {
+	g := storage.Gateway{
+		GatewayID: lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8},
+		Location: storage.GPSPoint{
+			Latitude:  45,
+			Longitude: -90,
+		},
+	}
+
+	t.Run("GPS", func(t *testing.T) {
+		assert := require.New(t)
+		infoDesc = InfoDescGPS
+
+		b, err := getGWSpecific(g)
+		assert.NoError(err)
+		assert.Equal([7]byte{0x00, 0x00, 0x00, 0x40, 0x00, 0x00, 0xc0}, b)
+	})
+
+	t.Run("NetID", func(t *testing.T) {
+		assert := require.New(t)
+		infoDesc = InfoDescNetID
+		netID = lorawan.NetID{1, 2, 3}
+
+		b, err := getGWSpecific(g)
+		assert.NoError(err)
+		assert.Equal([7]byte{0x03, 0x03, 0x02, 0x01, 0x08, 0x07, 0x06}, b)
+	})
+}
+
+func TestGetFrequency(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal(869525000, getFrequency(bandParams["EU868"], 10*beaconPeriod))
+	assert.Equal(923300000, getFrequency(bandParams["US915"], 8*beaconPeriod))
+	assert.Equal(923900000, getFrequency(bandParams["US915"], 9*beaconPeriod))
+}
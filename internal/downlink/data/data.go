@@ -38,7 +38,7 @@ type incompatibleCIDMapping struct {
 }
 
 var incompatibleMACCommands = []incompatibleCIDMapping{
-	{CID: lorawan.NewChannelReq, IncompatibleCIDs: []lorawan.CID{lorawan.LinkADRReq}},
+	{CID: lorawan.NewChannelReq, IncompatibleCIDs: []lorawan.CID{lorawan.LinkADRReq, lorawan.DlChannelReq}},
 	{CID: lorawan.LinkADRReq, IncompatibleCIDs: []lorawan.CID{lorawan.NewChannelReq}},
 }
 
@@ -87,14 +87,20 @@ var (
 
 	// Prefer gateways with min uplink SNR margin
 	gatewayPreferMinMargin float64
+
+	// RX1 downlink frequency by uplink frequency (extra channels).
+	downlinkFrequencies map[int]int
 )
 
 var setMACCommandsSet = setMACCommands(
 	requestCustomChannelReconfiguration,
+	requestDlChannelReconfiguration,
 	requestChannelMaskReconfiguration,
 	requestADRChange,
 	requestDevStatus,
 	requestRejoinParamSetup,
+	requestADRParamSetup,
+	requestDutyCycle,
 	setPingSlotParameters,
 	setRXParameters,
 	setTXParameters,
@@ -118,6 +124,7 @@ var responseTasks = []func(*dataContext) error{
 	isRoaming(true,
 		sendDownlinkFramePassiveRoaming,
 	),
+	takeDownlinkRateToken,
 	saveDeviceSession,
 	saveDownlinkFrame,
 }
@@ -126,6 +133,7 @@ var scheduleNextQueueItemTasks = []func(*dataContext) error{
 	getDeviceProfile,
 	getServiceProfile,
 	checkLastDownlinkTimestamp,
+	abortOnHandedOverDevice,
 	setDeviceGatewayRXInfo,
 	selectDownlinkGateway,
 	forClass(storage.DeviceModeC,
@@ -144,6 +152,7 @@ var scheduleNextQueueItemTasks = []func(*dataContext) error{
 	stopOnNothingToSend,
 	setPHYPayloads,
 	sendDownlinkFrame,
+	takeDownlinkRateToken,
 	saveDeviceSession,
 	saveDownlinkFrame,
 }
@@ -186,6 +195,13 @@ func Setup(conf config.Config) error {
 	maxMACCommandErrorCount = conf.NetworkServer.NetworkSettings.MaxMACCommandErrorCount
 	gatewayPreferMinMargin = conf.NetworkServer.NetworkSettings.GatewayPreferMinMargin
 
+	downlinkFrequencies = make(map[int]int)
+	for _, c := range nsConf.ExtraChannels {
+		if c.DownlinkFrequency != 0 {
+			downlinkFrequencies[c.Frequency] = c.DownlinkFrequency
+		}
+	}
+
 	return nil
 }
 
@@ -205,6 +221,10 @@ type dataContext struct {
 	// the downlink data.
 	DeviceSession storage.DeviceSession
 
+	// HandoverRoamingDeviceSession holds the handover-roaming device-session
+	// in case the device is served as sNS.
+	HandoverRoamingDeviceSession *storage.HandoverRoamingDeviceSession
+
 	// DeviceGatewayRXInfo contains the RXInfo of one or multiple gateways
 	// within reach of the device. These gateways can be used for transmitting
 	// downlinks.
@@ -229,6 +249,10 @@ type dataContext struct {
 	// MoreData defines if there is more data pending.
 	MoreData bool
 
+	// DownlinkRateLimited is set when the device-queue item is sent while
+	// the device exceeded the downlink rate (Mark policy).
+	DownlinkRateLimited bool
+
 	// Data contains the bytes to send. Note that this requires FPort to be a
 	// value other than 0.
 	Data []byte
@@ -394,6 +418,36 @@ func requestRejoinParamSetup(ctx *dataContext) error {
 	return nil
 }
 
+func requestADRParamSetup(ctx *dataContext) error {
+	if ctx.DeviceSession.GetMACVersion() == lorawan.LoRaWAN1_0 {
+		return nil
+	}
+
+	limitExp := ctx.DeviceSession.ADRAckLimitExp
+	delayExp := ctx.DeviceSession.ADRAckDelayExp
+	if ctx.DeviceProfile.ADRAckLimitExp != nil {
+		limitExp = *ctx.DeviceProfile.ADRAckLimitExp
+	}
+	if ctx.DeviceProfile.ADRAckDelayExp != nil {
+		delayExp = *ctx.DeviceProfile.ADRAckDelayExp
+	}
+
+	if ctx.DeviceSession.ADRAckLimitExp != limitExp || ctx.DeviceSession.ADRAckDelayExp != delayExp {
+		ctx.MACCommands = append(ctx.MACCommands, maccommand.RequestADRParamSetup(limitExp, delayExp))
+	}
+
+	return nil
+}
+
+func requestDutyCycle(ctx *dataContext) error {
+	maxDCycle := maccommand.GetMaxDCycle(ctx.DeviceProfile.MaxDutyCycle)
+	if ctx.DeviceSession.MaxDCycle != maxDCycle || ctx.DeviceSession.DutyCycleExceeded {
+		ctx.MACCommands = append(ctx.MACCommands, maccommand.RequestDutyCycle(maxDCycle))
+	}
+
+	return nil
+}
+
 func setPingSlotParameters(ctx *dataContext) error {
 	if !ctx.DeviceProfile.SupportsClassB {
 		return nil
@@ -523,8 +577,12 @@ func preferRX2LinkBudget(ctx *dataContext) (b bool, err error) {
 }
 
 func selectDownlinkGateway(ctx *dataContext) error {
-	var err error
-	ctx.DownlinkGateway, err = dwngateway.SelectDownlinkGateway(gatewayPreferMinMargin, ctx.DeviceSession.DR, ctx.DeviceGatewayRXInfo)
+	freq, err := getDownlinkFrequency(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx.DownlinkGateway, err = dwngateway.SelectDownlinkGateway(ctx.ctx, gatewayPreferMinMargin, ctx.DeviceSession.DR, freq, ctx.DeviceGatewayRXInfo)
 	if err != nil {
 		return err
 	}
@@ -534,22 +592,57 @@ func selectDownlinkGateway(ctx *dataContext) error {
 	return nil
 }
 
-func setDataTXInfo(ctx *dataContext) error {
-	preferRX2overRX1, err := preferRX2DR(ctx)
+// preferRX2overRX1 returns true when the RX2 receive-window must be used
+// as the first downlink opportunity.
+func preferRX2overRX1(ctx *dataContext) (bool, error) {
+	prefer, err := preferRX2DR(ctx)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	if rx2PreferOnLinkBudget {
-		prefer, err := preferRX2LinkBudget(ctx)
+		preferLinkBudget, err := preferRX2LinkBudget(ctx)
 		if err != nil {
-			return err
+			return false, err
 		}
-		preferRX2overRX1 = prefer || preferRX2overRX1
+		prefer = preferLinkBudget || prefer
 	}
 
 	// RX2 is prefered and the RX window is set to automatic.
-	if preferRX2overRX1 && rxWindow == 0 {
+	return prefer && rxWindow == 0, nil
+}
+
+// getDownlinkFrequency returns the frequency of the first downlink
+// opportunity.
+func getDownlinkFrequency(ctx *dataContext) (int, error) {
+	// Class-A response
+	if ctx.RXPacket != nil {
+		preferRX2, err := preferRX2overRX1(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		if preferRX2 || rxWindow == 2 {
+			return ctx.DeviceSession.RX2Frequency, nil
+		}
+
+		return getRX1Frequency(ctx)
+	}
+
+	if ctx.DeviceMode == storage.DeviceModeB {
+		return ctx.DeviceSession.PingSlotFrequency, nil
+	}
+
+	return ctx.DeviceSession.RX2Frequency, nil
+}
+
+func setDataTXInfo(ctx *dataContext) error {
+	preferRX2, err := preferRX2overRX1(ctx)
+	if err != nil {
+		return err
+	}
+
+	if preferRX2 {
 		// RX2
 		if err := setTXInfoForRX2(ctx); err != nil {
 			return err
@@ -596,9 +689,9 @@ func setTXInfoForRX1(ctx *dataContext) error {
 	}
 
 	// get rx1 frequency
-	freq, err := band.Band().GetRX1FrequencyForUplinkFrequency(int(ctx.RXPacket.TXInfo.Frequency))
+	freq, err := getRX1Frequency(ctx)
 	if err != nil {
-		return errors.Wrap(err, "get rx1 frequency error")
+		return err
 	}
 	txInfo.Frequency = uint32(freq)
 
@@ -637,6 +730,24 @@ func setTXInfoForRX1(ctx *dataContext) error {
 	return nil
 }
 
+func getRX1Frequency(ctx *dataContext) (int, error) {
+	freq, err := band.Band().GetRX1FrequencyForUplinkFrequency(int(ctx.RXPacket.TXInfo.Frequency))
+	if err != nil {
+		return 0, errors.Wrap(err, "get rx1 frequency error")
+	}
+
+	// use the rx1 frequency as configured by the DlChannelReq mac-command
+	if len(ctx.DeviceSession.DownlinkFrequencies) != 0 {
+		if i, err := band.Band().GetUplinkChannelIndex(int(ctx.RXPacket.TXInfo.Frequency), false); err == nil {
+			if f, ok := ctx.DeviceSession.DownlinkFrequencies[i]; ok {
+				freq = f
+			}
+		}
+	}
+
+	return freq, nil
+}
+
 func setImmediately(ctx *dataContext) error {
 	ctx.Immediately = true
 	return nil
@@ -760,6 +871,34 @@ func getNextDeviceQueueItem(ctx *dataContext) error {
 		return errors.Wrap(err, "get next device-queue item for max payload error")
 	}
 
+	// The token is taken by takeDownlinkRateToken, once the downlink has
+	// been sent.
+	ok, err := storage.HasDeviceDownlinkRateToken(ctx.ctx, ctx.DeviceSession.DevEUI, ctx.ServiceProfile)
+	if err != nil {
+		return errors.Wrap(err, "get downlink rate token error")
+	}
+	if !ok {
+		logFields := log.Fields{
+			"dev_eui":        ctx.DeviceSession.DevEUI,
+			"dl_rate":        ctx.ServiceProfile.DLRate,
+			"dl_bucket_size": ctx.ServiceProfile.DLBucketSize,
+			"dl_rate_policy": ctx.ServiceProfile.DLRatePolicy,
+			"ctx_id":         ctx.ctx.Value(logging.ContextIDKey),
+		}
+
+		// In case of the Drop policy, the item is kept in the queue so that
+		// it can be sent once the rate allows. FPending is set so that the
+		// device knows there is data pending.
+		if ctx.ServiceProfile.DLRatePolicy == storage.Drop {
+			log.WithFields(logFields).Warning("downlink/data: downlink rate exceeded, keeping device-queue item queued")
+			ctx.MoreData = true
+			return nil
+		}
+
+		log.WithFields(logFields).Warning("downlink/data: downlink rate exceeded, marking downlink")
+		ctx.DownlinkRateLimited = true
+	}
+
 	ctx.Confirmed = qi.Confirmed
 	ctx.Data = qi.FRMPayload
 	ctx.FPort = qi.FPort
@@ -933,6 +1072,13 @@ func setMACCommands(funcs ...func(*dataContext) error) func(*dataContext) error
 					return errors.Wrap(err, "delete mac-command block from queue error")
 				}
 			}
+
+			// track the progress of a requested force-rejoin
+			if block.CID == lorawan.ForceRejoinReq {
+				if err := storage.SetForceRejoinSent(ctx.ctx, storage.DB(), ctx.DeviceSession.DevEUI); err != nil {
+					return errors.Wrap(err, "set force-rejoin sent error")
+				}
+			}
 		}
 
 		return nil
@@ -954,6 +1100,7 @@ func requestCustomChannelReconfiguration(ctx *dataContext) error {
 	for k := range ctx.DeviceSession.ExtraUplinkChannels {
 		if _, ok := wantedChannels[k]; !ok {
 			delete(ctx.DeviceSession.ExtraUplinkChannels, k)
+			delete(ctx.DeviceSession.DownlinkFrequencies, k)
 		}
 	}
 
@@ -965,6 +1112,32 @@ func requestCustomChannelReconfiguration(ctx *dataContext) error {
 	return nil
 }
 
+func requestDlChannelReconfiguration(ctx *dataContext) error {
+	// the DlChannelReq mac-command was introduced by LoRaWAN 1.0.1
+	if ctx.DeviceSession.MACVersion == "1.0.0" {
+		return nil
+	}
+
+	// Only channels known by the device can be configured. Channels for
+	// which the downlink frequency has been removed from the configuration
+	// are reset to the uplink frequency.
+	wantedFrequencies := make(map[int]int)
+	for i, c := range ctx.DeviceSession.ExtraUplinkChannels {
+		if f, ok := downlinkFrequencies[c.Frequency]; ok {
+			wantedFrequencies[i] = f
+		} else if _, ok := ctx.DeviceSession.DownlinkFrequencies[i]; ok {
+			wantedFrequencies[i] = c.Frequency
+		}
+	}
+
+	block := maccommand.RequestDlChannels(3, ctx.DeviceSession.DownlinkFrequencies, wantedFrequencies)
+	if block != nil {
+		ctx.MACCommands = append(ctx.MACCommands, *block)
+	}
+
+	return nil
+}
+
 func requestChannelMaskReconfiguration(ctx *dataContext) error {
 	// handle channel configuration
 	// note that this must come before ADR!
@@ -989,7 +1162,7 @@ func requestADRChange(ctx *dataContext) error {
 		}
 	}
 
-	blocks, err := adr.HandleADR(ctx.ctx, ctx.ServiceProfile, ctx.DeviceSession, linkADRReq)
+	blocks, err := adr.HandleADR(ctx.ctx, ctx.DeviceProfile, ctx.ServiceProfile, &ctx.DeviceSession, linkADRReq)
 	if err != nil {
 		log.WithError(err).WithFields(log.Fields{
 			"dev_eui": ctx.DeviceSession.DevEUI,
@@ -1149,12 +1322,35 @@ func sendDownlinkFrame(ctx *dataContext) error {
 		return errors.Wrap(err, "send downlink-frame to gateway error")
 	}
 
+	if err := dwngateway.ReserveDownlinkAirtime(ctx.ctx, ctx.DownlinkFrame); err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"ctx_id": ctx.ctx.Value(logging.ContextIDKey),
+		}).Error("downlink/data: reserve downlink airtime error")
+	}
+
 	// set last downlink tx timestamp
 	ctx.DeviceSession.LastDownlinkTX = time.Now()
 
 	return nil
 }
 
+// takeDownlinkRateToken takes a token from the downlink token-bucket of the
+// device, in case a device-queue item has been sent.
+func takeDownlinkRateToken(ctx *dataContext) error {
+	if ctx.FPort == 0 {
+		return nil
+	}
+
+	if _, err := storage.TakeDeviceDownlinkRateToken(ctx.ctx, ctx.DeviceSession.DevEUI, ctx.ServiceProfile); err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"dev_eui": ctx.DeviceSession.DevEUI,
+			"ctx_id":  ctx.ctx.Value(logging.ContextIDKey),
+		}).Error("downlink/data: take downlink rate token error")
+	}
+
+	return nil
+}
+
 func sendDownlinkFramePassiveRoaming(ctx *dataContext) error {
 	var netID lorawan.NetID
 	if err := netID.UnmarshalText([]byte(ctx.RXPacket.RoamingMetaData.BasePayload.SenderID)); err != nil {
@@ -1324,6 +1520,26 @@ func checkLastDownlinkTimestamp(ctx *dataContext) error {
 	return nil
 }
 
+// abortOnHandedOverDevice aborts the scheduling of Class-B and Class-C
+// downlinks for devices which have been handed over to a sNS. The
+// device-queue items of these devices are forwarded to the sNS on uplink.
+func abortOnHandedOverDevice(ctx *dataContext) error {
+	_, err := storage.GetHandoverRoamingNetIDForDevEUI(ctx.ctx, ctx.DeviceSession.DevEUI)
+	if err != nil {
+		if errors.Cause(err) == storage.ErrDoesNotExist {
+			return nil
+		}
+		return errors.Wrap(err, "get handover-roaming netid error")
+	}
+
+	log.WithFields(log.Fields{
+		"dev_eui": ctx.DeviceSession.DevEUI,
+		"ctx_id":  ctx.ctx.Value(logging.ContextIDKey),
+	}).Debug("downlink/data: skip next downlink queue scheduling of handed-over device")
+
+	return ErrAbort
+}
+
 func saveDownlinkFrame(ctx *dataContext) error {
 	var fCnt uint32
 	if ctx.DeviceSession.GetMACVersion() == lorawan.LoRaWAN1_0 || ctx.FPort == 0 {
@@ -1342,6 +1558,7 @@ func saveDownlinkFrame(ctx *dataContext) error {
 		EncryptedFopts:   ctx.DeviceSession.GetMACVersion() != lorawan.LoRaWAN1_0,
 		NwkSEncKey:       ctx.DeviceSession.NwkSEncKey[:],
 		DownlinkFrame:    &ctx.DownlinkFrame,
+		RateLimited:      ctx.DownlinkRateLimited,
 	}
 
 	if err := storage.SaveDownlinkFrame(ctx.ctx, df); err != nil {
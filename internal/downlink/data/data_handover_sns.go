@@ -0,0 +1,144 @@
+package data
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brocaar/chirpstack-network-server/internal/logging"
+	"github.com/brocaar/chirpstack-network-server/internal/models"
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/lorawan"
+)
+
+// handoverRoamingResponseTasks contains the response tasks for a visiting
+// device, for which this network-server is the sNS. The sNS controls the
+// MAC-layer of the device, the device-queue is owned by the hNS which
+// forwards its items to the sNS.
+var handoverRoamingResponseTasks = []func(*dataContext) error{
+	setDeviceGatewayRXInfo,
+	selectDownlinkGateway,
+	setDataTXInfo,
+	setToken,
+	getHandoverRoamingDeviceQueueItem,
+	setMACCommandsSet,
+	stopOnNothingToSend,
+	setPHYPayloads,
+	sendDownlinkFrame,
+	saveHandoverRoamingDeviceSession,
+	saveDownlinkFrame,
+}
+
+// HandleHandoverRoamingResponse handles a downlink response as the sNS of a
+// handover-roaming device. As the device and its profiles are not stored by
+// the sNS, the device-profile and service-profile are used as received
+// from the hNS.
+func HandleHandoverRoamingResponse(ctx context.Context, rxPacket models.RXPacket, hrDS storage.HandoverRoamingDeviceSession, ds storage.DeviceSession, mustSend, ack bool, macCommands []storage.MACCommandBlock) error {
+	rctx := dataContext{
+		ctx:                          ctx,
+		DeviceProfile:                hrDS.DeviceProfile,
+		ServiceProfile:               hrDS.ServiceProfile,
+		HandoverRoamingDeviceSession: &hrDS,
+		DeviceSession:                ds,
+		ACK:                          ack,
+		MustSend:                     mustSend,
+		RXPacket:                     &rxPacket,
+		MACCommands:                  macCommands,
+	}
+
+	for _, t := range handoverRoamingResponseTasks {
+		if err := t(&rctx); err != nil {
+			if err == ErrAbort {
+				return nil
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// saveHandoverRoamingDeviceSession saves the device-session, expiring it
+// together with the handover-roaming device-session.
+func saveHandoverRoamingDeviceSession(ctx *dataContext) error {
+	if err := storage.SaveDeviceSessionWithTTL(ctx.ctx, ctx.DeviceSession, time.Until(ctx.HandoverRoamingDeviceSession.Lifetime)); err != nil {
+		return errors.Wrap(err, "save device-session error")
+	}
+	return nil
+}
+
+// getHandoverRoamingDeviceQueueItem sets the next application payload
+// forwarded by the hNS. Items which can not be sent (frame-counter or payload
+// size) are discarded, the hNS will not receive an acknowledgement for these.
+func getHandoverRoamingDeviceQueueItem(ctx *dataContext) error {
+	// the first downlink opportunity will be used to decide the
+	// max payload size
+	var remainingPayloadSize int
+	if len(ctx.DownlinkFrameItems) > 0 {
+		remainingPayloadSize = ctx.DownlinkFrameItems[0].RemainingPayloadSize
+	}
+
+	for {
+		qi, err := storage.PopHandoverRoamingDeviceQueueItem(ctx.ctx, ctx.DeviceSession.DevEUI)
+		if err != nil {
+			if errors.Cause(err) == storage.ErrDoesNotExist {
+				return nil
+			}
+			return errors.Wrap(err, "get handover-roaming device-queue item error")
+		}
+
+		// In case of LoRaWAN 1.0 the frame-counter is shared with the
+		// mac-command only downlinks of the sNS.
+		if ctx.DeviceSession.GetMACVersion() == lorawan.LoRaWAN1_0 && qi.FCnt < ctx.DeviceSession.NFCntDown {
+			log.WithFields(log.Fields{
+				"dev_eui":                ctx.DeviceSession.DevEUI,
+				"device_session_fcnt":    ctx.DeviceSession.NFCntDown,
+				"device_queue_item_fcnt": qi.FCnt,
+				"ctx_id":                 ctx.ctx.Value(logging.ContextIDKey),
+			}).Warning("downlink/data: handover-roaming device-queue item discarded due to invalid fCnt")
+			continue
+		}
+
+		if len(qi.FRMPayload) > remainingPayloadSize {
+			log.WithFields(log.Fields{
+				"dev_eui":                        ctx.DeviceSession.DevEUI,
+				"max_payload_size":               remainingPayloadSize,
+				"device_queue_item_payload_size": len(qi.FRMPayload),
+				"ctx_id":                         ctx.ctx.Value(logging.ContextIDKey),
+			}).Warning("downlink/data: handover-roaming device-queue item discarded as it exceeds the max payload size")
+			continue
+		}
+
+		ctx.Confirmed = qi.Confirmed
+		ctx.Data = qi.FRMPayload
+		ctx.FPort = qi.FPort
+
+		for i := range ctx.DownlinkFrameItems {
+			ctx.DownlinkFrameItems[i].RemainingPayloadSize = ctx.DownlinkFrameItems[i].RemainingPayloadSize - len(ctx.Data)
+		}
+
+		count, err := storage.GetHandoverRoamingDeviceQueueItemCount(ctx.ctx, ctx.DeviceSession.DevEUI)
+		if err != nil {
+			return errors.Wrap(err, "get handover-roaming device-queue item count error")
+		}
+		ctx.MoreData = count > 0
+
+		// The payload has been encrypted by the AS of the hNS using the
+		// frame-counter of the item.
+		if ctx.DeviceSession.GetMACVersion() == lorawan.LoRaWAN1_0 {
+			ctx.DeviceSession.NFCntDown = qi.FCnt
+		} else {
+			ctx.DeviceSession.AFCntDown = qi.FCnt
+		}
+
+		// The ACK is validated by the sNS (MIC) and forwarded to the hNS.
+		if qi.Confirmed {
+			ctx.DeviceSession.ConfFCnt = qi.FCnt
+		}
+
+		return nil
+	}
+}
@@ -67,6 +67,7 @@ func (ts *GetNextDeviceQueueItemTestSuite) TestGetNextDeviceQueueItem() {
 	tests := []struct {
 		Name                        string
 		DeviceQueueItems            []storage.DeviceQueueItem
+		DownlinkRateExceeded        bool
 		DataContext                 dataContext
 		ExpectedDataContext         dataContext
 		ExpectedNextDeviceQueueItem *storage.DeviceQueueItem
@@ -223,6 +224,109 @@ func (ts *GetNextDeviceQueueItemTestSuite) TestGetNextDeviceQueueItem() {
 				IsPending:  true,
 			},
 		},
+		{
+			Name: "downlink rate exceeded (service-profile: drop)",
+			DeviceQueueItems: []storage.DeviceQueueItem{
+				{
+					DevEUI:     ts.Device.DevEUI,
+					FRMPayload: []byte{1, 2, 3, 4},
+					FCnt:       10,
+					FPort:      1,
+				},
+			},
+			DownlinkRateExceeded: true,
+			DataContext: dataContext{
+				ServiceProfile: storage.ServiceProfile{
+					DLRate:       1,
+					DLBucketSize: 1,
+					DLRatePolicy: storage.Drop,
+				},
+				DeviceSession: storage.DeviceSession{
+					RoutingProfileID: ts.Device.RoutingProfileID,
+					DevEUI:           ts.Device.DevEUI,
+					NFCntDown:        10,
+				},
+				DownlinkFrameItems: []downlinkFrameItem{
+					{
+						RemainingPayloadSize: 242,
+					},
+				},
+			},
+			ExpectedDataContext: dataContext{
+				ServiceProfile: storage.ServiceProfile{
+					DLRate:       1,
+					DLBucketSize: 1,
+					DLRatePolicy: storage.Drop,
+				},
+				DeviceSession: storage.DeviceSession{
+					RoutingProfileID: ts.Device.RoutingProfileID,
+					DevEUI:           ts.Device.DevEUI,
+					NFCntDown:        10,
+				},
+				MoreData: true,
+				DownlinkFrameItems: []downlinkFrameItem{
+					{
+						RemainingPayloadSize: 242,
+					},
+				},
+			},
+			// the item is kept in the queue
+			ExpectedNextDeviceQueueItem: &storage.DeviceQueueItem{
+				DevEUI:     ts.Device.DevEUI,
+				FRMPayload: []byte{1, 2, 3, 4},
+				FPort:      1,
+				FCnt:       10,
+			},
+		},
+		{
+			Name: "downlink rate exceeded (service-profile: mark)",
+			DeviceQueueItems: []storage.DeviceQueueItem{
+				{
+					DevEUI:     ts.Device.DevEUI,
+					FRMPayload: []byte{1, 2, 3, 4},
+					FCnt:       10,
+					FPort:      1,
+				},
+			},
+			DownlinkRateExceeded: true,
+			DataContext: dataContext{
+				ServiceProfile: storage.ServiceProfile{
+					DLRate:       1,
+					DLBucketSize: 1,
+					DLRatePolicy: storage.Mark,
+				},
+				DeviceSession: storage.DeviceSession{
+					RoutingProfileID: ts.Device.RoutingProfileID,
+					DevEUI:           ts.Device.DevEUI,
+					NFCntDown:        10,
+				},
+				DownlinkFrameItems: []downlinkFrameItem{
+					{
+						RemainingPayloadSize: 242,
+					},
+				},
+			},
+			ExpectedDataContext: dataContext{
+				ServiceProfile: storage.ServiceProfile{
+					DLRate:       1,
+					DLBucketSize: 1,
+					DLRatePolicy: storage.Mark,
+				},
+				DeviceSession: storage.DeviceSession{
+					RoutingProfileID: ts.Device.RoutingProfileID,
+					DevEUI:           ts.Device.DevEUI,
+					NFCntDown:        10,
+				},
+				Data:                []byte{1, 2, 3, 4},
+				FPort:               1,
+				DownlinkRateLimited: true,
+				DownlinkFrameItems: []downlinkFrameItem{
+					{
+						RemainingPayloadSize: 242 - 4,
+					},
+				},
+			},
+		},
 	}
 
 	for _, tst := range tests {
@@ -236,6 +340,11 @@ func (ts *GetNextDeviceQueueItemTestSuite) TestGetNextDeviceQueueItem() {
 				assert.NoError(storage.CreateDeviceQueueItem(context.Background(), storage.DB(), &tst.DeviceQueueItems[i]))
 			}
 
+			if tst.DownlinkRateExceeded {
+				_, err := storage.TakeDeviceDownlinkRateToken(ctx, ts.Device.DevEUI, tst.DataContext.ServiceProfile)
+				assert.NoError(err)
+			}
+
 			assert.NoError(getNextDeviceQueueItem(&tst.DataContext))
 
 			tst.DataContext.ctx = nil
@@ -272,6 +381,10 @@ func (ts *SetMACCommandsSetTestSuite) SetupSuite() {
 }
 
 func (ts *SetMACCommandsSetTestSuite) TestSetMACCommandsSet() {
+	adrAckLimitExp := 7
+	adrAckDelayExp := 4
+	adrAckExpZero := 0
+
 	tests := []struct {
 		Name                string
 		BeforeFunc          func() error
@@ -825,6 +938,240 @@ func (ts *SetMACCommandsSetTestSuite) TestSetMACCommandsSet() {
 				},
 			},
 		},
+		{
+			BeforeFunc: func() error {
+				downlinkFrequencies = map[int]int{
+					867100000: 869525000,
+				}
+				return band.Band().AddChannel(867100000, 0, 5)
+			},
+			Name: "trigger dl channel request",
+			DataContext: dataContext{
+				ServiceProfile: storage.ServiceProfile{
+					DRMax: 5,
+				},
+				DeviceSession: storage.DeviceSession{
+					EnabledUplinkChannels: []int{0, 1, 2, 3},
+					ExtraUplinkChannels: map[int]loraband.Channel{
+						3: {Frequency: 867100000, MinDR: 0, MaxDR: 5},
+					},
+					RX2Frequency: 869525000,
+					MACVersion:   "1.0.3",
+				},
+				DownlinkFrameItems: []downlinkFrameItem{
+					{
+						RemainingPayloadSize: 200,
+					},
+				},
+			},
+			ExpectedMACCommands: []storage.MACCommandBlock{
+				{
+					CID: lorawan.DlChannelReq,
+					MACCommands: storage.MACCommands{
+						{
+							CID: lorawan.DlChannelReq,
+							Payload: &lorawan.DlChannelReqPayload{
+								ChIndex: 3,
+								Freq:    869525000,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "trigger duty cycle request",
+			DataContext: dataContext{
+				ServiceProfile: storage.ServiceProfile{
+					DRMax: 5,
+				},
+				DeviceProfile: storage.DeviceProfile{
+					MaxDutyCycle: 10,
+				},
+				DeviceSession: storage.DeviceSession{
+					EnabledUplinkChannels: []int{0, 1, 2},
+					RX2Frequency:          869525000,
+				},
+				DownlinkFrameItems: []downlinkFrameItem{
+					{
+						RemainingPayloadSize: 200,
+					},
+				},
+			},
+			ExpectedMACCommands: []storage.MACCommandBlock{
+				{
+					CID: lorawan.DutyCycleReq,
+					MACCommands: []lorawan.MACCommand{
+						{
+							CID: lorawan.DutyCycleReq,
+							Payload: &lorawan.DutyCycleReqPayload{
+								MaxDCycle: 4,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "trigger duty cycle request are in sync",
+			DataContext: dataContext{
+				ServiceProfile: storage.ServiceProfile{
+					DRMax: 5,
+				},
+				DeviceProfile: storage.DeviceProfile{
+					MaxDutyCycle: 10,
+				},
+				DeviceSession: storage.DeviceSession{
+					EnabledUplinkChannels: []int{0, 1, 2},
+					RX2Frequency:          869525000,
+					MaxDCycle:             4,
+				},
+				DownlinkFrameItems: []downlinkFrameItem{
+					{
+						RemainingPayloadSize: 200,
+					},
+				},
+			},
+		},
+		{
+			Name: "trigger duty cycle request are in sync, duty-cycle exceeded",
+			DataContext: dataContext{
+				ServiceProfile: storage.ServiceProfile{
+					DRMax: 5,
+				},
+				DeviceProfile: storage.DeviceProfile{
+					MaxDutyCycle: 10,
+				},
+				DeviceSession: storage.DeviceSession{
+					EnabledUplinkChannels: []int{0, 1, 2},
+					RX2Frequency:          869525000,
+					MaxDCycle:             4,
+					DutyCycleExceeded:     true,
+				},
+				DownlinkFrameItems: []downlinkFrameItem{
+					{
+						RemainingPayloadSize: 200,
+					},
+				},
+			},
+			ExpectedMACCommands: []storage.MACCommandBlock{
+				{
+					CID: lorawan.DutyCycleReq,
+					MACCommands: []lorawan.MACCommand{
+						{
+							CID: lorawan.DutyCycleReq,
+							Payload: &lorawan.DutyCycleReqPayload{
+								MaxDCycle: 4,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "trigger adr param setup request",
+			DataContext: dataContext{
+				ServiceProfile: storage.ServiceProfile{
+					DRMax: 5,
+				},
+				DeviceProfile: storage.DeviceProfile{
+					ADRAckLimitExp: &adrAckLimitExp,
+					ADRAckDelayExp: &adrAckDelayExp,
+				},
+				DeviceSession: storage.DeviceSession{
+					EnabledUplinkChannels: []int{0, 1, 2},
+					RX2Frequency:          869525000,
+					MACVersion:            "1.1.0",
+					ADRAckLimitExp:        storage.DefaultADRAckLimitExp,
+					ADRAckDelayExp:        storage.DefaultADRAckDelayExp,
+				},
+				DownlinkFrameItems: []downlinkFrameItem{
+					{
+						RemainingPayloadSize: 200,
+					},
+				},
+			},
+			ExpectedMACCommands: []storage.MACCommandBlock{
+				{
+					CID: lorawan.ADRParamSetupReq,
+					MACCommands: []lorawan.MACCommand{
+						{
+							CID: lorawan.ADRParamSetupReq,
+							Payload: &lorawan.ADRParamSetupReqPayload{
+								ADRParam: lorawan.ADRParam{
+									LimitExp: 7,
+									DelayExp: 4,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "trigger adr param setup request are in sync",
+			DataContext: dataContext{
+				ServiceProfile: storage.ServiceProfile{
+					DRMax: 5,
+				},
+				DeviceProfile: storage.DeviceProfile{
+					ADRAckLimitExp: &adrAckLimitExp,
+					ADRAckDelayExp: &adrAckDelayExp,
+				},
+				DeviceSession: storage.DeviceSession{
+					EnabledUplinkChannels: []int{0, 1, 2},
+					RX2Frequency:          869525000,
+					MACVersion:            "1.1.0",
+					ADRAckLimitExp:        7,
+					ADRAckDelayExp:        4,
+				},
+				DownlinkFrameItems: []downlinkFrameItem{
+					{
+						RemainingPayloadSize: 200,
+					},
+				},
+			},
+		},
+		{
+			Name: "trigger adr param setup request with both exponents 0",
+			DataContext: dataContext{
+				ServiceProfile: storage.ServiceProfile{
+					DRMax: 5,
+				},
+				DeviceProfile: storage.DeviceProfile{
+					ADRAckLimitExp: &adrAckExpZero,
+					ADRAckDelayExp: &adrAckExpZero,
+				},
+				DeviceSession: storage.DeviceSession{
+					EnabledUplinkChannels: []int{0, 1, 2},
+					RX2Frequency:          869525000,
+					MACVersion:            "1.1.0",
+					ADRAckLimitExp:        storage.DefaultADRAckLimitExp,
+					ADRAckDelayExp:        storage.DefaultADRAckDelayExp,
+				},
+				DownlinkFrameItems: []downlinkFrameItem{
+					{
+						RemainingPayloadSize: 200,
+					},
+				},
+			},
+			ExpectedMACCommands: []storage.MACCommandBlock{
+				{
+					CID: lorawan.ADRParamSetupReq,
+					MACCommands: []lorawan.MACCommand{
+						{
+							CID: lorawan.ADRParamSetupReq,
+							Payload: &lorawan.ADRParamSetupReqPayload{
+								ADRParam: lorawan.ADRParam{
+									LimitExp: 0,
+									DelayExp: 0,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tst := range tests {
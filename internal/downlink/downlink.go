@@ -3,10 +3,13 @@ package downlink
 import (
 	"time"
 
+	"github.com/gofrs/uuid"
 	"github.com/pkg/errors"
 
 	"github.com/brocaar/chirpstack-network-server/internal/config"
+	"github.com/brocaar/chirpstack-network-server/internal/downlink/beacon"
 	"github.com/brocaar/chirpstack-network-server/internal/downlink/data"
+	"github.com/brocaar/chirpstack-network-server/internal/downlink/gateway"
 	"github.com/brocaar/chirpstack-network-server/internal/downlink/join"
 	"github.com/brocaar/chirpstack-network-server/internal/downlink/multicast"
 	"github.com/brocaar/chirpstack-network-server/internal/downlink/proprietary"
@@ -15,12 +18,28 @@ import (
 var (
 	schedulerBatchSize = 100
 	schedulerInterval  time.Duration
+
+	schedulerLeaderElection bool
+	schedulerLeaseDuration  time.Duration
+	schedulerInstanceID     string
 )
 
 // Setup sets up the downlink.
 func Setup(conf config.Config) error {
 	nsConfig := conf.NetworkServer
 	schedulerInterval = nsConfig.Scheduler.SchedulerInterval
+	schedulerLeaderElection = nsConfig.Scheduler.LeaderElection.Enabled
+	schedulerLeaseDuration = nsConfig.Scheduler.LeaderElection.LeaseDuration
+
+	instanceID, err := uuid.NewV4()
+	if err != nil {
+		return errors.Wrap(err, "new uuid error")
+	}
+	schedulerInstanceID = instanceID.String()
+
+	if err := gateway.Setup(conf); err != nil {
+		return errors.Wrap(err, "setup downlink/gateway error")
+	}
 
 	if err := data.Setup(conf); err != nil {
 		return errors.Wrap(err, "setup downlink/data error")
@@ -38,5 +57,9 @@ func Setup(conf config.Config) error {
 		return errors.Wrap(err, "setup downlink/proprietary error")
 	}
 
+	if err := beacon.Setup(conf); err != nil {
+		return errors.Wrap(err, "setup downlink/beacon error")
+	}
+
 	return nil
 }
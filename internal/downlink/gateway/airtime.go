@@ -0,0 +1,202 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brocaar/chirpstack-api/go/v3/common"
+	"github.com/brocaar/chirpstack-api/go/v3/gw"
+	"github.com/brocaar/chirpstack-network-server/internal/airtime"
+	"github.com/brocaar/chirpstack-network-server/internal/config"
+	"github.com/brocaar/chirpstack-network-server/internal/helpers"
+	"github.com/brocaar/chirpstack-network-server/internal/logging"
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/lorawan"
+	loraband "github.com/brocaar/lorawan/band"
+)
+
+// subBand defines a frequency range with its max. duty-cycle. The
+// MinFrequency is inclusive, the MaxFrequency is exclusive.
+type subBand struct {
+	MinFrequency int
+	MaxFrequency int
+	MaxDutyCycle float64 // in percent
+}
+
+// ID returns the identifier of the sub-band, as used by the airtime ledger.
+func (s subBand) ID() string {
+	return fmt.Sprintf("%d-%d", s.MinFrequency, s.MaxFrequency)
+}
+
+// Budget returns the max. airtime within the given window.
+func (s subBand) Budget(window time.Duration) time.Duration {
+	return time.Duration(float64(window) * s.MaxDutyCycle / 100)
+}
+
+// eu868SubBands contains the ETSI EN 300 220 sub-bands.
+var eu868SubBands = []subBand{
+	{MinFrequency: 863000000, MaxFrequency: 865000000, MaxDutyCycle: 0.1},
+	{MinFrequency: 865000000, MaxFrequency: 868000000, MaxDutyCycle: 1},
+	{MinFrequency: 868000000, MaxFrequency: 868600000, MaxDutyCycle: 1},
+	{MinFrequency: 868700000, MaxFrequency: 869200000, MaxDutyCycle: 0.1},
+	{MinFrequency: 869400000, MaxFrequency: 869650000, MaxDutyCycle: 10},
+	{MinFrequency: 869700000, MaxFrequency: 870000000, MaxDutyCycle: 1},
+}
+
+var (
+	airtimeEnabled  bool
+	airtimeWindow   time.Duration
+	airtimeSubBands []subBand
+)
+
+// Setup configures the package.
+func Setup(conf config.Config) error {
+	airtimeConf := conf.NetworkServer.Gateway.DownlinkAirtime
+
+	airtimeEnabled = airtimeConf.Enabled
+	airtimeWindow = airtimeConf.Window
+	airtimeSubBands = nil
+
+	for _, sb := range airtimeConf.SubBands {
+		airtimeSubBands = append(airtimeSubBands, subBand{
+			MinFrequency: sb.MinFrequency,
+			MaxFrequency: sb.MaxFrequency,
+			MaxDutyCycle: sb.MaxDutyCycle,
+		})
+	}
+
+	if len(airtimeSubBands) == 0 && conf.NetworkServer.Band.Name == loraband.EU868 {
+		airtimeSubBands = eu868SubBands
+	}
+
+	return nil
+}
+
+// IsOutOfAirtimeBudget returns true when the gateway has exceeded the
+// airtime budget of the sub-band containing the given frequency.
+func IsOutOfAirtimeBudget(ctx context.Context, gatewayID lorawan.EUI64, frequency int) (bool, error) {
+	if !airtimeEnabled {
+		return false, nil
+	}
+
+	sb, ok := getSubBandForFrequency(frequency)
+	if !ok {
+		return false, nil
+	}
+
+	used, err := storage.GetGatewayDownlinkAirtime(ctx, gatewayID, sb.ID(), airtimeWindow)
+	if err != nil {
+		return false, errors.Wrap(err, "get gateway downlink airtime error")
+	}
+
+	return used >= sb.Budget(airtimeWindow), nil
+}
+
+// ReserveDownlinkAirtime adds the airtime of the first downlink opportunity
+// of the given downlink frame to the airtime ledger of the gateway. This
+// reservation is replaced or removed by ConfirmDownlinkAirtime once the
+// gateway acknowledges the downlink.
+func ReserveDownlinkAirtime(ctx context.Context, df gw.DownlinkFrame) error {
+	if !airtimeEnabled || len(df.Items) == 0 {
+		return nil
+	}
+
+	return addDownlinkAirtime(ctx, df, df.Items[0])
+}
+
+// ConfirmDownlinkAirtime updates the airtime ledger of the gateway based on
+// the downlink TX acknowledgement. The reservation is removed and in case the
+// downlink was transmitted, the airtime of the transmitted item is added.
+func ConfirmDownlinkAirtime(ctx context.Context, df gw.DownlinkFrame, item *gw.DownlinkFrameItem, transmitted bool) error {
+	if !airtimeEnabled {
+		return nil
+	}
+
+	if err := storage.DeleteGatewayDownlinkAirtime(ctx, helpers.GetDownlinkID(&df)); err != nil {
+		return errors.Wrap(err, "delete gateway downlink airtime error")
+	}
+
+	if !transmitted || item == nil {
+		return nil
+	}
+
+	return addDownlinkAirtime(ctx, df, item)
+}
+
+func addDownlinkAirtime(ctx context.Context, df gw.DownlinkFrame, item *gw.DownlinkFrameItem) error {
+	txInfo := item.GetTxInfo()
+	if txInfo == nil {
+		return nil
+	}
+
+	sb, ok := getSubBandForFrequency(int(txInfo.Frequency))
+	if !ok {
+		return nil
+	}
+
+	dr, err := getDataRateForDownlinkTXInfo(txInfo)
+	if err != nil {
+		return errors.Wrap(err, "get data-rate error")
+	}
+
+	toa, err := airtime.CalculateDownlinkAirtime(dr, len(item.PhyPayload))
+	if err != nil {
+		return errors.Wrap(err, "calculate airtime error")
+	}
+
+	gatewayID := helpers.GetGatewayID(&df)
+	if err := storage.AddGatewayDownlinkAirtime(ctx, gatewayID, sb.ID(), helpers.GetDownlinkID(&df), time.Now(), toa, airtimeWindow); err != nil {
+		return errors.Wrap(err, "add gateway downlink airtime error")
+	}
+
+	log.WithFields(log.Fields{
+		"gateway_id": gatewayID,
+		"sub_band":   sb.ID(),
+		"airtime":    toa,
+		"ctx_id":     ctx.Value(logging.ContextIDKey),
+	}).Debug("downlink/gateway: downlink airtime added to ledger")
+
+	return nil
+}
+
+func getSubBandForFrequency(frequency int) (subBand, bool) {
+	for _, sb := range airtimeSubBands {
+		if frequency >= sb.MinFrequency && frequency < sb.MaxFrequency {
+			return sb, true
+		}
+	}
+
+	return subBand{}, false
+}
+
+func getDataRateForDownlinkTXInfo(txInfo *gw.DownlinkTXInfo) (loraband.DataRate, error) {
+	switch txInfo.Modulation {
+	case common.Modulation_LORA:
+		modInfo := txInfo.GetLoraModulationInfo()
+		if modInfo == nil {
+			return loraband.DataRate{}, errors.New("lora_modulation_info must not be nil")
+		}
+
+		return loraband.DataRate{
+			Modulation:   loraband.LoRaModulation,
+			SpreadFactor: int(modInfo.SpreadingFactor),
+			Bandwidth:    int(modInfo.Bandwidth),
+		}, nil
+	case common.Modulation_FSK:
+		modInfo := txInfo.GetFskModulationInfo()
+		if modInfo == nil {
+			return loraband.DataRate{}, errors.New("fsk_modulation_info must not be nil")
+		}
+
+		return loraband.DataRate{
+			Modulation: loraband.FSKModulation,
+			BitRate:    int(modInfo.Datarate),
+		}, nil
+	default:
+		return loraband.DataRate{}, fmt.Errorf("unknown modulation: %s", txInfo.Modulation)
+	}
+}
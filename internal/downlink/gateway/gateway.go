@@ -1,14 +1,17 @@
 package gateway
 
 import (
+	"context"
 	"math/rand"
 	"sort"
 	"time"
 
 	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
 
 	"github.com/brocaar/chirpstack-network-server/internal/band"
 	"github.com/brocaar/chirpstack-network-server/internal/config"
+	"github.com/brocaar/chirpstack-network-server/internal/logging"
 	"github.com/brocaar/chirpstack-network-server/internal/storage"
 	loraband "github.com/brocaar/lorawan/band"
 )
@@ -38,11 +41,14 @@ func (s BySignal) Less(i, j int) bool {
 
 // SelectDownlinkGateway returns, given a slice of DeviceGatewayRXInfo
 // elements the gateway (as a DeviceGatewayRXInfo) to use for downlink.
+// Gateways that exceeded their downlink airtime budget for the sub-band of
+// the given TX frequency are only considered when all gateways exceeded
+// their budget.
 // In the current implementation it will sort the given slice based on SNR / RSSI,
 // and return:
 //  * A random item from the elements with an SNR > minSNR
 //  * The first item of the sorted slice (failing the above)
-func SelectDownlinkGateway(minSNRMargin float64, rxDR int, rxInfo []storage.DeviceGatewayRXInfo) (storage.DeviceGatewayRXInfo, error) {
+func SelectDownlinkGateway(ctx context.Context, minSNRMargin float64, rxDR int, txFrequency int, rxInfo []storage.DeviceGatewayRXInfo) (storage.DeviceGatewayRXInfo, error) {
 	if len(rxInfo) == 0 {
 		return storage.DeviceGatewayRXInfo{}, errors.New("device gateway rx-info slice is empty")
 	}
@@ -51,6 +57,11 @@ func SelectDownlinkGateway(minSNRMargin float64, rxDR int, rxInfo []storage.Devi
 		return storage.DeviceGatewayRXInfo{}, errors.Wrap(err, "get data-rate error")
 	}
 
+	rxInfo, err = filterOutOfAirtimeBudget(ctx, txFrequency, rxInfo)
+	if err != nil {
+		return storage.DeviceGatewayRXInfo{}, errors.Wrap(err, "filter out of airtime budget error")
+	}
+
 	// Sort by SNR.
 	sort.Sort(BySignal(rxInfo))
 
@@ -71,3 +82,37 @@ func SelectDownlinkGateway(minSNRMargin float64, rxDR int, rxInfo []storage.Devi
 	rand.Seed(time.Now().UnixNano())
 	return newRxInfo[rand.Intn(len(newRxInfo))], nil
 }
+
+// filterOutOfAirtimeBudget returns the items for which the gateway did not
+// exceed its downlink airtime budget for the given frequency. In case all
+// gateways exceeded their budget, the given slice is returned.
+func filterOutOfAirtimeBudget(ctx context.Context, frequency int, rxInfo []storage.DeviceGatewayRXInfo) ([]storage.DeviceGatewayRXInfo, error) {
+	if !airtimeEnabled {
+		return rxInfo, nil
+	}
+
+	var out []storage.DeviceGatewayRXInfo
+	for i := range rxInfo {
+		outOfBudget, err := IsOutOfAirtimeBudget(ctx, rxInfo[i].GatewayID, frequency)
+		if err != nil {
+			return nil, err
+		}
+
+		if outOfBudget {
+			log.WithFields(log.Fields{
+				"gateway_id": rxInfo[i].GatewayID,
+				"frequency":  frequency,
+				"ctx_id":     ctx.Value(logging.ContextIDKey),
+			}).Info("downlink/gateway: gateway exceeded downlink airtime budget")
+			continue
+		}
+
+		out = append(out, rxInfo[i])
+	}
+
+	if len(out) == 0 {
+		return rxInfo, nil
+	}
+
+	return out, nil
+}
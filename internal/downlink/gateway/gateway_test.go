@@ -1,9 +1,12 @@
 package gateway
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/gofrs/uuid"
 	"github.com/stretchr/testify/require"
 
 	"github.com/brocaar/chirpstack-network-server/internal/band"
@@ -130,7 +133,7 @@ func TestSelectDownlinkGateway(t *testing.T) {
 				outMap := make(map[lorawan.EUI64]struct{})
 
 				for i := 0; i < 100*len(tst.ExpectedIn); i++ {
-					out, err := SelectDownlinkGateway(tst.MinSNRMargin, tst.DR, tst.RxInfo)
+					out, err := SelectDownlinkGateway(context.Background(), tst.MinSNRMargin, tst.DR, 868100000, tst.RxInfo)
 					if tst.ExpectedError != nil {
 						assert.Equal(tst.ExpectedError.Error(), err.Error())
 						return
@@ -147,3 +150,94 @@ func TestSelectDownlinkGateway(t *testing.T) {
 		})
 	}
 }
+
+func TestSelectDownlinkGatewayAirtimeBudget(t *testing.T) {
+	assert := require.New(t)
+
+	conf := test.GetConfig()
+	conf.NetworkServer.Gateway.DownlinkAirtime.Enabled = true
+	conf.NetworkServer.Gateway.DownlinkAirtime.Window = time.Hour
+
+	assert.NoError(band.Setup(conf))
+	assert.NoError(storage.Setup(conf))
+	assert.NoError(Setup(conf))
+	defer Setup(test.GetConfig())
+
+	storage.RedisClient().FlushAll()
+
+	rxInfo := []storage.DeviceGatewayRXInfo{
+		{
+			GatewayID: lorawan.EUI64{1, 1, 1, 1, 1, 1, 1, 1},
+			LoRaSNR:   5,
+		},
+		{
+			GatewayID: lorawan.EUI64{2, 2, 2, 2, 2, 2, 2, 2},
+			LoRaSNR:   -5,
+		},
+	}
+
+	// 1% of one hour is 36 seconds
+	assert.NoError(storage.AddGatewayDownlinkAirtime(context.Background(), rxInfo[0].GatewayID, "868000000-868600000", uuid.Must(uuid.NewV4()), time.Now(), 37*time.Second, time.Hour))
+
+	t.Run("Gateway out of budget is deprioritized", func(t *testing.T) {
+		assert := require.New(t)
+
+		outOfBudget, err := IsOutOfAirtimeBudget(context.Background(), rxInfo[0].GatewayID, 868100000)
+		assert.NoError(err)
+		assert.True(outOfBudget)
+
+		outOfBudget, err = IsOutOfAirtimeBudget(context.Background(), rxInfo[0].GatewayID, 869525000)
+		assert.NoError(err)
+		assert.False(outOfBudget)
+
+		for i := 0; i < 10; i++ {
+			out, err := SelectDownlinkGateway(context.Background(), 0, 0, 868100000, rxInfo)
+			assert.NoError(err)
+			assert.Equal(rxInfo[1].GatewayID, out.GatewayID)
+		}
+	})
+
+	t.Run("Gateway within budget of the tx sub-band", func(t *testing.T) {
+		assert := require.New(t)
+
+		// only the 868.0 - 868.6 MHz sub-band is out of budget, both gateways
+		// meet the margin
+		selected := make(map[lorawan.EUI64]bool)
+		for i := 0; i < 100; i++ {
+			out, err := SelectDownlinkGateway(context.Background(), -100, 0, 869525000, rxInfo)
+			assert.NoError(err)
+			selected[out.GatewayID] = true
+		}
+		assert.True(selected[rxInfo[0].GatewayID])
+	})
+
+	t.Run("Sub-band upper bound is exclusive", func(t *testing.T) {
+		assert := require.New(t)
+
+		// 868000000 belongs to the 868.0 - 868.6 MHz sub-band, not to the
+		// 865.0 - 868.0 MHz sub-band
+		sb, ok := getSubBandForFrequency(868000000)
+		assert.True(ok)
+		assert.Equal("868000000-868600000", sb.ID())
+
+		outOfBudget, err := IsOutOfAirtimeBudget(context.Background(), rxInfo[0].GatewayID, 868000000)
+		assert.NoError(err)
+		assert.True(outOfBudget)
+
+		outOfBudget, err = IsOutOfAirtimeBudget(context.Background(), rxInfo[0].GatewayID, 867900000)
+		assert.NoError(err)
+		assert.False(outOfBudget)
+	})
+
+	t.Run("All gateways out of budget", func(t *testing.T) {
+		assert := require.New(t)
+
+		assert.NoError(storage.AddGatewayDownlinkAirtime(context.Background(), rxInfo[1].GatewayID, "868000000-868600000", uuid.Must(uuid.NewV4()), time.Now(), 37*time.Second, time.Hour))
+
+		// none of the gateways meets the margin, the gateway with the best
+		// SNR is returned
+		out, err := SelectDownlinkGateway(context.Background(), 100, 0, 868100000, rxInfo)
+		assert.NoError(err)
+		assert.Equal(rxInfo[0].GatewayID, out.GatewayID)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/gofrs/uuid"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
 
 	"github.com/brocaar/chirpstack-api/go/v3/gw"
 	"github.com/brocaar/chirpstack-network-server/internal/backend/gateway"
@@ -99,7 +100,17 @@ func setDeviceGatewayRXInfo(ctx *joinContext) error {
 
 func selectDownlinkGateway(ctx *joinContext) error {
 	var err error
-	ctx.DownlinkGateway, err = dwngateway.SelectDownlinkGateway(gatewayPreferMinMargin, ctx.RXPacket.DR, ctx.DeviceGatewayRXInfo)
+
+	// frequency of the first downlink opportunity
+	freq := band.Band().GetDefaults().RX2Frequency
+	if rxWindow == 0 || rxWindow == 1 {
+		freq, err = band.Band().GetRX1FrequencyForUplinkFrequency(int(ctx.RXPacket.TXInfo.Frequency))
+		if err != nil {
+			return errors.Wrap(err, "get rx1 frequency error")
+		}
+	}
+
+	ctx.DownlinkGateway, err = dwngateway.SelectDownlinkGateway(ctx.ctx, gatewayPreferMinMargin, ctx.RXPacket.DR, freq, ctx.DeviceGatewayRXInfo)
 	if err != nil {
 		return err
 	}
@@ -249,6 +260,12 @@ func sendJoinAcceptResponse(ctx *joinContext) error {
 		return errors.Wrap(err, "send downlink frame error")
 	}
 
+	if err := dwngateway.ReserveDownlinkAirtime(ctx.ctx, ctx.DownlinkFrame); err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"ctx_id": ctx.ctx.Value(logging.ContextIDKey),
+		}).Error("downlink/join: reserve downlink airtime error")
+	}
+
 	return nil
 }
 
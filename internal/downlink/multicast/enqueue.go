@@ -8,8 +8,10 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/brocaar/chirpstack-network-server/internal/downlink/data/classb"
+	dwngateway "github.com/brocaar/chirpstack-network-server/internal/downlink/gateway"
 	"github.com/brocaar/chirpstack-network-server/internal/gps"
 	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/lorawan"
 )
 
 // EnqueueQueueItem selects the gateways that must be used to cover all devices
@@ -17,35 +19,47 @@ import (
 // gateway.
 // Note that an enqueue action increments the frame-counter of the multicast-group.
 func EnqueueQueueItem(ctx context.Context, db sqlx.Ext, qi storage.MulticastQueueItem) error {
+	_, err := enqueueQueueItem(ctx, db, qi)
+	return err
+}
+
+// enqueueQueueItem implements EnqueueQueueItem and returns the number of
+// created queue-items (one per gateway).
+func enqueueQueueItem(ctx context.Context, db sqlx.Ext, qi storage.MulticastQueueItem) (int, error) {
 	// Get multicast-group and lock it.
 	mg, err := storage.GetMulticastGroup(ctx, db, qi.MulticastGroupID, true)
 	if err != nil {
-		return errors.Wrap(err, "get multicast-group error")
+		return 0, errors.Wrap(err, "get multicast-group error")
 	}
 
 	if qi.FCnt < mg.FCnt {
-		return ErrInvalidFCnt
+		return 0, ErrInvalidFCnt
 	}
 
 	mg.FCnt = qi.FCnt + 1
 	if err := storage.UpdateMulticastGroup(ctx, db, &mg); err != nil {
-		return errors.Wrap(err, "update multicast-group error")
+		return 0, errors.Wrap(err, "update multicast-group error")
 	}
 
 	// get DevEUIs within the multicast-group.
 	devEUIs, err := storage.GetDevEUIsForMulticastGroup(ctx, db, qi.MulticastGroupID)
 	if err != nil {
-		return errors.Wrap(err, "get deveuis for multicast-group error")
+		return 0, errors.Wrap(err, "get deveuis for multicast-group error")
 	}
 
 	rxInfoSets, err := storage.GetDeviceGatewayRXInfoSetForDevEUIs(ctx, devEUIs)
 	if err != nil {
-		return errors.Wrap(err, "get device gateway rx-info set for deveuis errors")
+		return 0, errors.Wrap(err, "get device gateway rx-info set for deveuis errors")
+	}
+
+	rxInfoSets, err = filterOutOfAirtimeBudget(ctx, rxInfoSets, mg.Frequency)
+	if err != nil {
+		return 0, errors.Wrap(err, "filter out of airtime budget error")
 	}
 
 	gatewayIDs, err := GetMinimumGatewaySet(rxInfoSets)
 	if err != nil {
-		return errors.Wrap(err, "get minimum gateway set error")
+		return 0, errors.Wrap(err, "get minimum gateway set error")
 	}
 
 	// for each gateway we increment the schedule_at timestamp with one second
@@ -53,7 +67,7 @@ func EnqueueQueueItem(ctx context.Context, db sqlx.Ext, qi storage.MulticastQueu
 	if mg.GroupType == storage.MulticastGroupC {
 		ts, err := storage.GetMaxScheduleAtForMulticastGroup(ctx, db, mg.ID)
 		if err != nil {
-			return errors.Wrap(err, "get maximum schedule at error")
+			return 0, errors.Wrap(err, "get maximum schedule at error")
 		}
 
 		if ts.IsZero() {
@@ -65,7 +79,7 @@ func EnqueueQueueItem(ctx context.Context, db sqlx.Ext, qi storage.MulticastQueu
 			qi.GatewayID = gatewayID
 			qi.ScheduleAt = ts
 			if err = storage.CreateMulticastQueueItem(ctx, db, &qi); err != nil {
-				return errors.Wrap(err, "create multicast queue-item error")
+				return 0, errors.Wrap(err, "create multicast queue-item error")
 			}
 		}
 	}
@@ -79,7 +93,7 @@ func EnqueueQueueItem(ctx context.Context, db sqlx.Ext, qi storage.MulticastQueu
 
 		scheduleTS, err := storage.GetMaxEmitAtTimeSinceGPSEpochForMulticastGroup(ctx, db, mg.ID)
 		if err != nil {
-			return errors.Wrap(err, "get maximum emit at time since gps epoch error")
+			return 0, errors.Wrap(err, "get maximum emit at time since gps epoch error")
 		}
 
 		if scheduleTS == 0 {
@@ -89,7 +103,7 @@ func EnqueueQueueItem(ctx context.Context, db sqlx.Ext, qi storage.MulticastQueu
 		for _, gatewayID := range gatewayIDs {
 			scheduleTS, err = classb.GetNextPingSlotAfter(scheduleTS, mg.MCAddr, pingSlotNb)
 			if err != nil {
-				return errors.Wrap(err, "get next ping-slot after error")
+				return 0, errors.Wrap(err, "get next ping-slot after error")
 			}
 
 			qi.EmitAtTimeSinceGPSEpoch = &scheduleTS
@@ -97,10 +111,49 @@ func EnqueueQueueItem(ctx context.Context, db sqlx.Ext, qi storage.MulticastQueu
 			qi.GatewayID = gatewayID
 
 			if err = storage.CreateMulticastQueueItem(ctx, db, &qi); err != nil {
-				return errors.Wrap(err, "create multicast queue-item error")
+				return 0, errors.Wrap(err, "create multicast queue-item error")
 			}
 		}
 	}
 
-	return nil
+	return len(gatewayIDs), nil
+}
+
+// filterOutOfAirtimeBudget removes the gateways that exceeded their downlink
+// airtime budget for the given frequency from the rx-info sets. Gateways are
+// only removed for devices that are covered by at least one other gateway
+// within budget, so that each device remains covered.
+func filterOutOfAirtimeBudget(ctx context.Context, rxInfoSets []storage.DeviceGatewayRXInfoSet, frequency int) ([]storage.DeviceGatewayRXInfoSet, error) {
+	outOfBudget := make(map[lorawan.EUI64]bool)
+	for _, rxInfoSet := range rxInfoSets {
+		for _, item := range rxInfoSet.Items {
+			if _, ok := outOfBudget[item.GatewayID]; ok {
+				continue
+			}
+
+			b, err := dwngateway.IsOutOfAirtimeBudget(ctx, item.GatewayID, frequency)
+			if err != nil {
+				return nil, errors.Wrap(err, "get gateway airtime budget error")
+			}
+			outOfBudget[item.GatewayID] = b
+		}
+	}
+
+	var out []storage.DeviceGatewayRXInfoSet
+	for _, rxInfoSet := range rxInfoSets {
+		var items []storage.DeviceGatewayRXInfo
+		for _, item := range rxInfoSet.Items {
+			if !outOfBudget[item.GatewayID] {
+				items = append(items, item)
+			}
+		}
+
+		if len(items) != 0 {
+			rxInfoSet.Items = items
+		}
+
+		out = append(out, rxInfoSet)
+	}
+
+	return out, nil
 }
@@ -179,6 +179,29 @@ func (ts *EnqueueQueueItemTestCase) TestClassB() {
 	assert.Equal(qi.FCnt+1, mg.FCnt)
 }
 
+func (ts *EnqueueQueueItemTestCase) TestFragmentationSession() {
+	assert := require.New(ts.T())
+
+	fs := storage.FragmentationSession{
+		MulticastGroupID: ts.MulticastGroup.ID,
+		FragmentSize:     4,
+		Data:             []byte{1, 2, 3, 4, 5, 6, 7, 8},
+	}
+	assert.NoError(CreateFragmentationSession(context.Background(), ts.tx, &fs))
+
+	ts.T().Run("No gateways", func(t *testing.T) {
+		assert := require.New(t)
+		storage.RedisClient().FlushAll()
+
+		err := HandleFragmentationSession(context.Background(), ts.tx, fs)
+		assert.Equal(ErrNoQueueItemsCreated, err)
+
+		items, err := storage.GetMulticastQueueItemsForMulticastGroup(context.Background(), ts.tx, ts.MulticastGroup.ID)
+		assert.NoError(err)
+		assert.Len(items, 0)
+	})
+}
+
 func TestEnqueueQueueItem(t *testing.T) {
 	suite.Run(t, new(EnqueueQueueItemTestCase))
 }
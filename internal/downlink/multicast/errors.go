@@ -4,5 +4,7 @@ import "github.com/pkg/errors"
 
 // Errors
 var (
-	ErrInvalidFCnt = errors.New("invalid frame-counter value")
+	ErrInvalidFCnt                 = errors.New("invalid frame-counter value")
+	ErrInvalidFragmentationSession = errors.New("invalid fragmentation-session")
+	ErrNoQueueItemsCreated         = errors.New("no multicast queue-items created, no gateways to cover the multicast-group")
 )
@@ -0,0 +1,132 @@
+package multicast
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brocaar/chirpstack-network-server/internal/band"
+	"github.com/brocaar/chirpstack-network-server/internal/fragmentation"
+	"github.com/brocaar/chirpstack-network-server/internal/logging"
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+)
+
+// CreateFragmentationSession validates and creates the given
+// fragmentation-session. The fragments are enqueued one by one by the
+// multicast scheduler, each time the multicast-queue of the multicast-group
+// is empty.
+// Note that the fragmentation-session must be setup on the devices
+// (FragSessionSetupReq) by the application layer, using the same
+// fragmentation index, fragment size and number of (uncoded) fragments.
+func CreateFragmentationSession(ctx context.Context, db sqlx.Ext, fs *storage.FragmentationSession) error {
+	mg, err := storage.GetMulticastGroup(ctx, db, fs.MulticastGroupID, false)
+	if err != nil {
+		return errors.Wrap(err, "get multicast-group error")
+	}
+
+	if len(fs.Data) == 0 {
+		return errors.Wrap(ErrInvalidFragmentationSession, "data must not be empty")
+	}
+
+	if fs.FragIndex > 3 {
+		return errors.Wrap(ErrInvalidFragmentationSession, "fragmentation index must be between 0 and 3")
+	}
+
+	if fs.FragmentSize <= 0 {
+		return errors.Wrap(ErrInvalidFragmentationSession, "fragment size must be > 0")
+	}
+
+	if fs.Redundancy < 0 {
+		return errors.Wrap(ErrInvalidFragmentationSession, "redundancy must be >= 0")
+	}
+
+	maxSize, err := band.Band().GetMaxPayloadSizeForDataRateIndex("", "", mg.DR)
+	if err != nil {
+		return errors.Wrap(err, "get max payload-size for data-rate index error")
+	}
+
+	if fs.FragmentSize+fragmentation.DataFragmentOverhead > maxSize.N {
+		return errors.Wrapf(ErrInvalidFragmentationSession, "fragment size exceeds max. payload size of %d bytes for the multicast-group data-rate", maxSize.N-fragmentation.DataFragmentOverhead)
+	}
+
+	fs.FragmentCount = fragmentation.FragmentCount(len(fs.Data), fs.FragmentSize) + fs.Redundancy
+	if fs.FragmentCount > fragmentation.MaxFragments {
+		return errors.Wrapf(ErrInvalidFragmentationSession, "number of fragments exceeds %d", fragmentation.MaxFragments)
+	}
+
+	fs.FragmentsEnqueued = 0
+	fs.CompletedAt = nil
+
+	if err := storage.CreateFragmentationSession(ctx, db, fs); err != nil {
+		return errors.Wrap(err, "create fragmentation-session error")
+	}
+
+	return nil
+}
+
+// HandleFragmentationSession enqueues the next fragment of the given
+// fragmentation-session, or marks the session as completed once all
+// fragments have been sent. ErrNoQueueItemsCreated is returned when the
+// fragment could not be enqueued for any gateway, in which case the changes
+// made by this function must be rolled back.
+func HandleFragmentationSession(ctx context.Context, db sqlx.Ext, fs storage.FragmentationSession) error {
+	if fs.FragmentsEnqueued >= fs.FragmentCount {
+		now := time.Now()
+		fs.CompletedAt = &now
+		if err := storage.UpdateFragmentationSession(ctx, db, &fs); err != nil {
+			return errors.Wrap(err, "update fragmentation-session error")
+		}
+
+		log.WithFields(log.Fields{
+			"id":                 fs.ID,
+			"multicast_group_id": fs.MulticastGroupID,
+			"fragment_count":     fs.FragmentCount,
+			"ctx_id":             ctx.Value(logging.ContextIDKey),
+		}).Info("fragmentation-session completed")
+
+		return nil
+	}
+
+	n := fs.FragmentsEnqueued + 1
+	fragment, err := fragmentation.Fragment(fs.Data, fs.FragmentSize, n)
+	if err != nil {
+		return errors.Wrap(err, "get fragment error")
+	}
+
+	b, err := fragmentation.DataFragmentPayload(fs.FragIndex, n, fragment)
+	if err != nil {
+		return errors.Wrap(err, "get data-fragment payload error")
+	}
+
+	mg, err := storage.GetMulticastGroup(ctx, db, fs.MulticastGroupID, false)
+	if err != nil {
+		return errors.Wrap(err, "get multicast-group error")
+	}
+
+	qi := storage.MulticastQueueItem{
+		MulticastGroupID: mg.ID,
+		FCnt:             mg.FCnt,
+		FPort:            fragmentation.FPort,
+		FRMPayload:       b,
+	}
+	count, err := enqueueQueueItem(ctx, db, qi)
+	if err != nil {
+		return errors.Wrap(err, "enqueue multicast queue-item error")
+	}
+
+	// The fragment is retried on the next run, as the multicast-queue
+	// remains empty.
+	if count == 0 {
+		return ErrNoQueueItemsCreated
+	}
+
+	fs.FragmentsEnqueued = n
+	if err := storage.UpdateFragmentationSession(ctx, db, &fs); err != nil {
+		return errors.Wrap(err, "update fragmentation-session error")
+	}
+
+	return nil
+}
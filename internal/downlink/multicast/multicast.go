@@ -16,6 +16,7 @@ import (
 	"github.com/brocaar/chirpstack-network-server/internal/backend/gateway"
 	"github.com/brocaar/chirpstack-network-server/internal/band"
 	"github.com/brocaar/chirpstack-network-server/internal/config"
+	dwngateway "github.com/brocaar/chirpstack-network-server/internal/downlink/gateway"
 	"github.com/brocaar/chirpstack-network-server/internal/helpers"
 	"github.com/brocaar/chirpstack-network-server/internal/logging"
 	"github.com/brocaar/chirpstack-network-server/internal/storage"
@@ -245,6 +246,12 @@ func sendDownlinkData(ctx *multicastContext) error {
 		return errors.Wrap(err, "send downlink frame to gateway error")
 	}
 
+	if err := dwngateway.ReserveDownlinkAirtime(ctx.ctx, ctx.DownlinkFrame); err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"ctx_id": ctx.ctx.Value(logging.ContextIDKey),
+		}).Error("downlink/multicast: reserve downlink airtime error")
+	}
+
 	return nil
 }
 
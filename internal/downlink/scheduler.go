@@ -2,64 +2,190 @@ package downlink
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"time"
 
-	"github.com/gofrs/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/brocaar/chirpstack-network-server/internal/downlink/beacon"
 	"github.com/brocaar/chirpstack-network-server/internal/downlink/data"
 	"github.com/brocaar/chirpstack-network-server/internal/downlink/multicast"
+	"github.com/brocaar/chirpstack-network-server/internal/helpers"
 	"github.com/brocaar/chirpstack-network-server/internal/logging"
 	"github.com/brocaar/chirpstack-network-server/internal/storage"
 )
 
-// DeviceQueueSchedulerLoop starts an infinit loop calling the scheduler loop for Class-B
-// and Class-C sheduling.
-func DeviceQueueSchedulerLoop() {
-	for {
-		ctx := context.Background()
-		ctxID, err := uuid.NewV4()
-		if err != nil {
-			log.WithError(err).Error("get new uuid error")
-		}
-		ctx = context.WithValue(ctx, logging.ContextIDKey, ctxID)
+const (
+	deviceQueueSchedulerLease    = "device-queue-scheduler"
+	multicastQueueSchedulerLease = "multicast-queue-scheduler"
+	beaconSchedulerLease         = "beacon-scheduler"
+)
 
-		log.WithFields(log.Fields{
-			"ctx_id": ctxID,
-		}).Debug("running class-b / class-c scheduler batch")
+// schedulerHeartbeatTimeout defines the max. duration (on top of the
+// scheduler interval) between two scheduler runs, before the scheduler is
+// considered stalled.
+const schedulerHeartbeatTimeout = time.Minute
+
+var (
+	schedulerHeartbeatsMux sync.RWMutex
+	schedulerHeartbeats    = make(map[string]time.Time)
+)
+
+// SchedulerHealthCheck returns an error when one of the running scheduler
+// loops did not run within the expected interval.
+func SchedulerHealthCheck(ctx context.Context) error {
+	schedulerHeartbeatsMux.RLock()
+	defer schedulerHeartbeatsMux.RUnlock()
 
-		if err := ScheduleDeviceQueueBatch(ctx, schedulerBatchSize); err != nil {
-			log.WithFields(log.Fields{
-				"ctx_id": ctxID,
-			}).WithError(err).Error("class-b / class-c scheduler error")
+	for name, hb := range schedulerHeartbeats {
+		if since := time.Since(hb); since > schedulerInterval+schedulerHeartbeatTimeout {
+			return fmt.Errorf("%s scheduler did not run for %s", name, since)
 		}
-		time.Sleep(schedulerInterval)
 	}
+
+	return nil
 }
 
-// MulticastQueueSchedulerLoop starts an infinit loop calling the multicast
-// scheduler loop.
-func MulticastQueueSchedulerLoop() {
-	for {
-		ctx := context.Background()
-		ctxID, err := uuid.NewV4()
-		if err != nil {
-			log.WithError(err).Error("get new uuid error")
+func setSchedulerHeartbeat(name string) {
+	schedulerHeartbeatsMux.Lock()
+	defer schedulerHeartbeatsMux.Unlock()
+
+	schedulerHeartbeats[name] = time.Now()
+}
+
+func deleteSchedulerHeartbeat(name string) {
+	schedulerHeartbeatsMux.Lock()
+	defer schedulerHeartbeatsMux.Unlock()
+
+	delete(schedulerHeartbeats, name)
+}
+
+// DeviceQueueSchedulerLoop starts a loop calling the scheduler for Class-B
+// and Class-C sheduling. The loop returns once the given context is
+// cancelled. A running batch is always completed.
+func DeviceQueueSchedulerLoop(ctx context.Context) {
+	schedulerLoop(ctx, "class-b / class-c", deviceQueueSchedulerLease, ScheduleDeviceQueueBatch)
+}
+
+// MulticastQueueSchedulerLoop starts a loop calling the multicast scheduler.
+// The loop returns once the given context is cancelled. A running batch is
+// always completed.
+func MulticastQueueSchedulerLoop(ctx context.Context) {
+	schedulerLoop(ctx, "multicast", multicastQueueSchedulerLease, func(ctx context.Context, size int) error {
+		if err := ScheduleFragmentationSessionBatch(ctx, size); err != nil {
+			return errors.Wrap(err, "schedule fragmentation-session batch error")
+		}
+
+		return ScheduleMulticastQueueBatch(ctx, size)
+	})
+}
+
+// BeaconSchedulerLoop starts a loop calling the Class-B beacon scheduler.
+// The loop returns once the given context is cancelled.
+func BeaconSchedulerLoop(ctx context.Context) {
+	schedulerLoop(ctx, "class-b beacon", beaconSchedulerLease, func(ctx context.Context, _ int) error {
+		return beacon.ScheduleNextBeacon(ctx)
+	})
+}
+
+func schedulerLoop(ctx context.Context, name, lease string, f func(context.Context, int) error) {
+	var isLeader bool
+
+	helpers.RunLoop(ctx, name+" scheduler", schedulerInterval, func(ctx context.Context) error {
+		setSchedulerHeartbeat(name)
+
+		if schedulerLeaderElection {
+			leader, err := storage.AcquireLease(ctx, lease, schedulerInstanceID, schedulerLeaseDuration)
+			if err != nil {
+				log.WithFields(log.Fields{
+					"ctx_id": ctx.Value(logging.ContextIDKey),
+				}).WithError(err).Errorf("%s scheduler acquire lease error", name)
+			}
+
+			if leader != isLeader {
+				log.WithFields(log.Fields{
+					"ctx_id":      ctx.Value(logging.ContextIDKey),
+					"instance_id": schedulerInstanceID,
+					"leader":      leader,
+				}).Infof("%s scheduler leader status changed", name)
+			}
+
+			isLeader = leader
+			if !leader {
+				return nil
+			}
 		}
-		ctx = context.WithValue(ctx, logging.ContextIDKey, ctxID)
 
 		log.WithFields(log.Fields{
-			"ctx_id": ctxID,
-		}).Debug("running multicast scheduler batch")
+			"ctx_id": ctx.Value(logging.ContextIDKey),
+		}).Debugf("running %s scheduler batch", name)
+
+		stopLeaseRenewal := renewLeaseDuringBatch(ctx, name, lease)
+		defer stopLeaseRenewal()
+
+		return f(ctx, schedulerBatchSize)
+	})
 
-		if err := ScheduleMulticastQueueBatch(ctx, schedulerBatchSize); err != nil {
-			log.WithFields(log.Fields{
-				"ctx_id": ctxID,
-			}).WithError(err).Error("multicast scheduler error")
+	if isLeader {
+		// release the lease so that an other instance can take over
+		// without waiting for the lease to expire
+		if err := storage.ReleaseLease(context.Background(), lease, schedulerInstanceID); err != nil {
+			log.WithError(err).Errorf("%s scheduler release lease error", name)
 		}
-		time.Sleep(schedulerInterval)
+	}
+
+	deleteSchedulerHeartbeat(name)
+}
+
+// renewLeaseDuringBatch renews the lease at a third of the lease duration
+// while the batch is running, so that an other instance does not take over
+// in the middle of a batch that runs longer than the lease duration. The
+// returned function stops the renewal.
+func renewLeaseDuringBatch(ctx context.Context, name, lease string) func() {
+	interval := schedulerLeaseDuration / 3
+	if !schedulerLeaderElection || interval <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				leader, err := storage.AcquireLease(ctx, lease, schedulerInstanceID, schedulerLeaseDuration)
+				if err != nil {
+					log.WithFields(log.Fields{
+						"ctx_id": ctx.Value(logging.ContextIDKey),
+					}).WithError(err).Errorf("%s scheduler renew lease error", name)
+					continue
+				}
+
+				if !leader {
+					log.WithFields(log.Fields{
+						"ctx_id":      ctx.Value(logging.ContextIDKey),
+						"instance_id": schedulerInstanceID,
+					}).Warningf("%s scheduler lost lease during batch", name)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		wg.Wait()
 	}
 }
 
@@ -94,6 +220,54 @@ func ScheduleDeviceQueueBatch(ctx context.Context, size int) error {
 	})
 }
 
+// ScheduleFragmentationSessionBatch enqueues the next fragment of the
+// fragmentation-sessions for which the multicast-queue is empty.
+func ScheduleFragmentationSessionBatch(ctx context.Context, size int) error {
+	return storage.Transaction(func(tx sqlx.Ext) error {
+		// this locks the selected sessions so that this query can be
+		// executed by other instances in parallel.
+		sessions, err := storage.GetSchedulableFragmentationSessions(ctx, tx, size)
+		if err != nil {
+			return errors.Wrap(err, "get fragmentation-sessions error")
+		}
+
+		for _, fs := range sessions {
+			if err := handleFragmentationSession(ctx, tx, fs); err != nil {
+				log.WithFields(log.Fields{
+					"id":                 fs.ID,
+					"multicast_group_id": fs.MulticastGroupID,
+					"ctx_id":             ctx.Value(logging.ContextIDKey),
+				}).WithError(err).Error("handle fragmentation-session error")
+			}
+		}
+
+		return nil
+	})
+}
+
+// handleFragmentationSession handles the given fragmentation-session within
+// a savepoint. On error, the changes of this session are rolled back (e.g.
+// the incremented multicast-group frame-counter), without affecting the
+// other sessions of the batch.
+func handleFragmentationSession(ctx context.Context, tx sqlx.Ext, fs storage.FragmentationSession) error {
+	if _, err := tx.Exec("savepoint fragmentation_session"); err != nil {
+		return errors.Wrap(err, "create savepoint error")
+	}
+
+	if err := multicast.HandleFragmentationSession(ctx, tx, fs); err != nil {
+		if _, rbErr := tx.Exec("rollback to savepoint fragmentation_session"); rbErr != nil {
+			return errors.Wrap(rbErr, "rollback to savepoint error")
+		}
+		return err
+	}
+
+	if _, err := tx.Exec("release savepoint fragmentation_session"); err != nil {
+		return errors.Wrap(err, "release savepoint error")
+	}
+
+	return nil
+}
+
 // ScheduleMulticastQueueBatch schedules a donwlink multicast batch (Class-B & -C).
 func ScheduleMulticastQueueBatch(ctx context.Context, size int) error {
 	return storage.Transaction(func(tx sqlx.Ext) error {
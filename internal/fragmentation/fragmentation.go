@@ -0,0 +1,158 @@
+// Package fragmentation implements the fragment encoding of the LoRaWAN
+// Fragmented Data Block Transport specification (TS004), including the
+// forward error correction (parity) fragments.
+package fragmentation
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// FPort defines the fragmentation package FPort.
+const FPort = 201
+
+// dataFragmentCID defines the DataFragment command identifier.
+const dataFragmentCID = 0x08
+
+// MaxFragments defines the max. number of fragments (uncoded + parity)
+// within a fragmentation session.
+const MaxFragments = 1<<14 - 1
+
+// DataFragmentOverhead defines the number of bytes added to each fragment
+// by the DataFragment command (CID + IndexAndN).
+const DataFragmentOverhead = 3
+
+// FragmentCount returns the number of uncoded fragments for the given data
+// and fragment size.
+func FragmentCount(dataSize, fragmentSize int) int {
+	return (dataSize + fragmentSize - 1) / fragmentSize
+}
+
+// Padding returns the number of padding bytes added to the last uncoded
+// fragment.
+func Padding(dataSize, fragmentSize int) int {
+	return FragmentCount(dataSize, fragmentSize)*fragmentSize - dataSize
+}
+
+// Fragment returns fragment n (1-based) for the given data and fragment
+// size. Fragments 1 to M (the number of uncoded fragments) contain the
+// (padded) data, fragments > M are parity fragments.
+func Fragment(data []byte, fragmentSize, n int) ([]byte, error) {
+	if fragmentSize <= 0 {
+		return nil, errors.New("fragment size must be > 0")
+	}
+
+	m := FragmentCount(len(data), fragmentSize)
+	if m == 0 {
+		return nil, errors.New("data must not be empty")
+	}
+
+	if n < 1 || n > MaxFragments {
+		return nil, fmt.Errorf("fragment number must be between 1 and %d", MaxFragments)
+	}
+
+	if n <= m {
+		return uncodedFragment(data, fragmentSize, n-1), nil
+	}
+
+	out := make([]byte, fragmentSize)
+	for i, set := range matrixLine(n-m, m) {
+		if !set {
+			continue
+		}
+
+		for j, b := range uncodedFragment(data, fragmentSize, i) {
+			out[j] ^= b
+		}
+	}
+
+	return out, nil
+}
+
+// Encode returns the uncoded fragments followed by the given number of
+// parity fragments.
+func Encode(data []byte, fragmentSize, redundancy int) ([][]byte, error) {
+	count := FragmentCount(len(data), fragmentSize) + redundancy
+
+	var out [][]byte
+	for n := 1; n <= count; n++ {
+		f, err := Fragment(data, fragmentSize, n)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fragment %d error", n)
+		}
+		out = append(out, f)
+	}
+
+	return out, nil
+}
+
+// DataFragmentPayload returns the DataFragment command payload for the given
+// fragmentation session index, fragment number (1-based) and fragment.
+func DataFragmentPayload(fragIndex uint8, n int, fragment []byte) ([]byte, error) {
+	if fragIndex > 3 {
+		return nil, errors.New("fragmentation index must be between 0 and 3")
+	}
+
+	if n < 1 || n > MaxFragments {
+		return nil, fmt.Errorf("fragment number must be between 1 and %d", MaxFragments)
+	}
+
+	b := make([]byte, DataFragmentOverhead+len(fragment))
+	b[0] = dataFragmentCID
+	binary.LittleEndian.PutUint16(b[1:3], uint16(fragIndex)<<14|uint16(n))
+	copy(b[3:], fragment)
+
+	return b, nil
+}
+
+// uncodedFragment returns the uncoded fragment with index i (0-based),
+// padded with zeros.
+func uncodedFragment(data []byte, fragmentSize, i int) []byte {
+	out := make([]byte, fragmentSize)
+
+	start := i * fragmentSize
+	end := start + fragmentSize
+	if end > len(data) {
+		end = len(data)
+	}
+	copy(out, data[start:end])
+
+	return out
+}
+
+// matrixLine returns line n (1-based) of the parity matrix for m uncoded
+// fragments, as defined by the specification.
+func matrixLine(n, m int) []bool {
+	line := make([]bool, m)
+
+	var mm uint32
+	if isPowerOf2(m) {
+		mm = 1
+	}
+
+	x := uint32(1 + 1001*n)
+	for nbCoeff := 0; nbCoeff < m/2; nbCoeff++ {
+		r := uint32(1 << 16)
+		for r >= uint32(m) {
+			x = prbs23(x)
+			r = x % (uint32(m) + mm)
+		}
+		line[r] = true
+	}
+
+	return line
+}
+
+// prbs23 implements the pseudo-random binary sequence generator as defined
+// by the specification.
+func prbs23(x uint32) uint32 {
+	b0 := x & 1
+	b1 := (x & 32) / 32
+	return (x / 2) + ((b0 ^ b1) << 22)
+}
+
+func isPowerOf2(v int) bool {
+	return v > 0 && v&(v-1) == 0
+}
@@ -0,0 +1,120 @@
+package fragmentation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	// the expected parity fragments have been generated using the
+	// reference implementation of the TS004 specification
+	tests := []struct {
+		name         string
+		data         []byte
+		fragmentSize int
+		redundancy   int
+		expected     [][]byte
+	}{
+		{
+			name:         "three fragments with padding",
+			data:         []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			fragmentSize: 4,
+			redundancy:   2,
+			expected: [][]byte{
+				{1, 2, 3, 4},
+				{5, 6, 7, 8},
+				{9, 10, 0, 0},
+				{5, 6, 7, 8},
+				{1, 2, 3, 4},
+			},
+		},
+		{
+			name: "eight fragments (power of two)",
+			data: []byte{
+				1, 2, 3, 4, 5, 6, 7, 8, 9, 10,
+				11, 12, 13, 14, 15, 16, 17, 18, 19, 20,
+				21, 22, 23, 24, 25, 26, 27, 28, 29, 30,
+				31, 32, 33, 34, 35, 36, 37, 38, 39, 40,
+			},
+			fragmentSize: 5,
+			redundancy:   3,
+			expected: [][]byte{
+				{1, 2, 3, 4, 5},
+				{6, 7, 8, 9, 10},
+				{11, 12, 13, 14, 15},
+				{16, 17, 18, 19, 20},
+				{21, 22, 23, 24, 25},
+				{26, 27, 28, 29, 30},
+				{31, 32, 33, 34, 35},
+				{36, 37, 38, 39, 40},
+				{13, 51, 61, 55, 53},
+				{48, 49, 50, 59, 52},
+				{8, 52, 56, 60, 56},
+			},
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			fragments, err := Encode(tst.data, tst.fragmentSize, tst.redundancy)
+			assert.NoError(err)
+			assert.Equal(tst.expected, fragments)
+		})
+	}
+
+	t.Run("Padding", func(t *testing.T) {
+		assert := require.New(t)
+
+		assert.Equal(3, FragmentCount(10, 4))
+		assert.Equal(2, Padding(10, 4))
+	})
+}
+
+func TestFragment(t *testing.T) {
+	assert := require.New(t)
+
+	_, err := Fragment(nil, 4, 1)
+	assert.EqualError(err, "data must not be empty")
+
+	_, err = Fragment([]byte{1}, 4, 0)
+	assert.EqualError(err, "fragment number must be between 1 and 16383")
+}
+
+func TestMatrixLine(t *testing.T) {
+	assert := require.New(t)
+
+	// reference implementation of the TS004 specification
+	assert.Equal([]bool{true, true, false, false, true, false, true, false}, matrixLine(1, 8))
+	assert.Equal([]bool{true, false, false, false, true, false, false, true}, matrixLine(2, 8))
+	assert.Equal([]bool{false, true, false}, matrixLine(1, 3))
+
+	for _, m := range []int{2, 3, 8, 10} {
+		for n := 1; n < 10; n++ {
+			line := matrixLine(n, m)
+			assert.Len(line, m)
+
+			var count int
+			for _, set := range line {
+				if set {
+					count++
+				}
+			}
+			assert.True(count > 0 && count <= m/2)
+			assert.Equal(line, matrixLine(n, m))
+		}
+	}
+}
+
+func TestDataFragmentPayload(t *testing.T) {
+	assert := require.New(t)
+
+	b, err := DataFragmentPayload(2, 5, []byte{1, 2, 3})
+	assert.NoError(err)
+	assert.Equal([]byte{0x08, 0x05, 0x80, 0x01, 0x02, 0x03}, b)
+
+	_, err = DataFragmentPayload(4, 5, nil)
+	assert.EqualError(err, "fragmentation index must be between 0 and 3")
+}
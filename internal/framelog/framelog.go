@@ -3,6 +3,9 @@ package framelog
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-redis/redis/v7"
 	proto "github.com/golang/protobuf/proto"
@@ -11,6 +14,7 @@ import (
 
 	"github.com/brocaar/chirpstack-api/go/v3/gw"
 	"github.com/brocaar/chirpstack-api/go/v3/ns"
+	"github.com/brocaar/chirpstack-network-server/internal/config"
 	"github.com/brocaar/chirpstack-network-server/internal/storage"
 	"github.com/brocaar/lorawan"
 )
@@ -20,12 +24,64 @@ const (
 	gatewayFrameLogDownlinkPubSubKeyTempl = "lora:ns:gw:%s:pubsub:frame:downlink"
 	deviceFrameLogUplinkPubSubKeyTempl    = "lora:ns:device:%s:pubsub:frame:uplink"
 	deviceFrameLogDownlinkPubSubKeyTempl  = "lora:ns:device:%s:pubsub:frame:downlink"
+
+	gatewayFrameLogHistoryKeyTempl = "lora:ns:gw:%s:frame:history"
+	deviceFrameLogHistoryKeyTempl  = "lora:ns:device:%s:frame:history"
+
+	historyUplinkField              = "uplink"
+	historyDownlinkField            = "downlink"
+	historyUplinkRateLimitedField   = "uplink_rate_limited"
+	historyDownlinkRateLimitedField = "downlink_rate_limited"
+)
+
+var (
+	historyMaxLen     int64
+	historyTTL        time.Duration
+	streamReplayCount int64
 )
 
+// Setup configures the package.
+func Setup(conf config.Config) error {
+	historyMaxLen = conf.NetworkServer.FrameLog.HistoryMaxLen
+	historyTTL = conf.NetworkServer.FrameLog.HistoryTTL
+	streamReplayCount = conf.NetworkServer.FrameLog.StreamReplayCount
+
+	return nil
+}
+
 // FrameLog contains either an uplink or downlink frame.
 type FrameLog struct {
+	// ID and Time are only set for frame-logs retrieved from the history.
+	ID   string
+	Time time.Time
+
 	UplinkFrame   *ns.UplinkFrameLog
 	DownlinkFrame *ns.DownlinkFrameLog
+
+	// UplinkRateLimited is set when the device exceeded the uplink rate of
+	// its service-profile. This is only stored in the history.
+	UplinkRateLimited bool
+
+	// DownlinkRateLimited is set when the frame was sent while the device
+	// exceeded the downlink rate of its service-profile. This is only stored
+	// in the history.
+	DownlinkRateLimited bool
+}
+
+// HistoryPage defines the page of the frame-log history to return.
+type HistoryPage struct {
+	// Start and End define the (inclusive) time range. A zero value means
+	// that the range is unbounded.
+	Start time.Time
+	End   time.Time
+
+	// After contains the ID of the last frame-log of the previous page.
+	// When set, Start is ignored.
+	After string
+
+	// Limit defines the max. number of frame-logs to return. When 0, all
+	// frame-logs within the range are returned.
+	Limit int64
 }
 
 // LogUplinkFrameForGateways logs the given frame to all the gateway pub-sub keys.
@@ -49,6 +105,9 @@ func LogUplinkFrameForGateways(ctx context.Context, frame ns.UplinkFrameLog) err
 
 		key := fmt.Sprintf(gatewayFrameLogUplinkPubSubKeyTempl, id)
 		pipe.Publish(key, b)
+		addToHistory(pipe, fmt.Sprintf(gatewayFrameLogHistoryKeyTempl, id), map[string]interface{}{
+			historyUplinkField: b,
+		})
 	}
 
 	_, err := pipe.Exec()
@@ -71,15 +130,21 @@ func LogDownlinkFrameForGateway(ctx context.Context, frame ns.DownlinkFrameLog)
 		return errors.Wrap(err, "marshal downlink frame error")
 	}
 
-	err = storage.RedisClient().Publish(key, b).Err()
-	if err != nil {
+	pipe := storage.RedisClient().Pipeline()
+	pipe.Publish(key, b)
+	addToHistory(pipe, fmt.Sprintf(gatewayFrameLogHistoryKeyTempl, id), map[string]interface{}{
+		historyDownlinkField: b,
+	})
+
+	if _, err := pipe.Exec(); err != nil {
 		return errors.Wrap(err, "publish frame to gateway channel error")
 	}
 	return nil
 }
 
 // LogDownlinkFrameForDevEUI logs the given frame to the device pub-sub key.
-func LogDownlinkFrameForDevEUI(ctx context.Context, devEUI lorawan.EUI64, frame ns.DownlinkFrameLog) error {
+// The rateLimited flag is only stored in the history.
+func LogDownlinkFrameForDevEUI(ctx context.Context, devEUI lorawan.EUI64, frame ns.DownlinkFrameLog, rateLimited bool) error {
 	key := fmt.Sprintf(deviceFrameLogDownlinkPubSubKeyTempl, devEUI)
 
 	b, err := proto.Marshal(&frame)
@@ -87,8 +152,17 @@ func LogDownlinkFrameForDevEUI(ctx context.Context, devEUI lorawan.EUI64, frame
 		return errors.Wrap(err, "marshal downlink frame error")
 	}
 
-	err = storage.RedisClient().Publish(key, b).Err()
-	if err != nil {
+	pipe := storage.RedisClient().Pipeline()
+	pipe.Publish(key, b)
+	values := map[string]interface{}{
+		historyDownlinkField: b,
+	}
+	if rateLimited {
+		values[historyDownlinkRateLimitedField] = "1"
+	}
+	addToHistory(pipe, fmt.Sprintf(deviceFrameLogHistoryKeyTempl, devEUI), values)
+
+	if _, err := pipe.Exec(); err != nil {
 		return errors.Wrap(err, "publish frame to device channel error")
 	}
 
@@ -96,7 +170,8 @@ func LogDownlinkFrameForDevEUI(ctx context.Context, devEUI lorawan.EUI64, frame
 }
 
 // LogUplinkFrameForDevEUI logs the given frame to the pub-sub key of the given DevEUI.
-func LogUplinkFrameForDevEUI(ctx context.Context, devEUI lorawan.EUI64, frame ns.UplinkFrameLog) error {
+// The rateLimited flag is only stored in the history.
+func LogUplinkFrameForDevEUI(ctx context.Context, devEUI lorawan.EUI64, frame ns.UplinkFrameLog, rateLimited bool) error {
 	b, err := proto.Marshal(&frame)
 	if err != nil {
 		return errors.Wrap(err, "marshal uplink frame error")
@@ -104,36 +179,131 @@ func LogUplinkFrameForDevEUI(ctx context.Context, devEUI lorawan.EUI64, frame ns
 
 	key := fmt.Sprintf(deviceFrameLogUplinkPubSubKeyTempl, devEUI)
 
-	err = storage.RedisClient().Publish(key, b).Err()
-	if err != nil {
+	pipe := storage.RedisClient().Pipeline()
+	pipe.Publish(key, b)
+	values := map[string]interface{}{
+		historyUplinkField: b,
+	}
+	if rateLimited {
+		values[historyUplinkRateLimitedField] = "1"
+	}
+	addToHistory(pipe, fmt.Sprintf(deviceFrameLogHistoryKeyTempl, devEUI), values)
+
+	if _, err := pipe.Exec(); err != nil {
 		return errors.Wrap(err, "publish frame to device channel error")
 	}
 	return nil
 }
 
 // GetFrameLogForGateway subscribes to the uplink and downlink frame logs
-// for the given gateway and sends this to the given channel.
+// for the given gateway and sends this to the given channel. When the history
+// is enabled, the last frame logs from the history are sent before the live
+// frame logs.
 func GetFrameLogForGateway(ctx context.Context, gatewayID lorawan.EUI64, frameLogChan chan FrameLog) error {
 	uplinkKey := fmt.Sprintf(gatewayFrameLogUplinkPubSubKeyTempl, gatewayID)
 	downlinkKey := fmt.Sprintf(gatewayFrameLogDownlinkPubSubKeyTempl, gatewayID)
-	return getFrameLogs(ctx, uplinkKey, downlinkKey, frameLogChan)
+	historyKey := fmt.Sprintf(gatewayFrameLogHistoryKeyTempl, gatewayID)
+	return getFrameLogs(ctx, uplinkKey, downlinkKey, historyKey, frameLogChan)
 }
 
 // GetFrameLogForDevice subscribes to the uplink and downlink frame logs
-// for the given device and sends this to the given channel.
+// for the given device and sends this to the given channel. When the history
+// is enabled, the last frame logs from the history are sent before the live
+// frame logs.
 func GetFrameLogForDevice(ctx context.Context, devEUI lorawan.EUI64, frameLogChan chan FrameLog) error {
 	uplinkKey := fmt.Sprintf(deviceFrameLogUplinkPubSubKeyTempl, devEUI)
 	downlinkKey := fmt.Sprintf(deviceFrameLogDownlinkPubSubKeyTempl, devEUI)
-	return getFrameLogs(ctx, uplinkKey, downlinkKey, frameLogChan)
+	historyKey := fmt.Sprintf(deviceFrameLogHistoryKeyTempl, devEUI)
+	return getFrameLogs(ctx, uplinkKey, downlinkKey, historyKey, frameLogChan)
+}
+
+// GetFrameLogHistoryForGateway returns the uplink and downlink frame logs
+// from the history of the given gateway, in chronological order.
+func GetFrameLogHistoryForGateway(ctx context.Context, gatewayID lorawan.EUI64, page HistoryPage) ([]FrameLog, error) {
+	return getFrameLogHistory(fmt.Sprintf(gatewayFrameLogHistoryKeyTempl, gatewayID), page)
+}
+
+// GetFrameLogHistoryForDevice returns the uplink and downlink frame logs
+// from the history of the given device, in chronological order.
+func GetFrameLogHistoryForDevice(ctx context.Context, devEUI lorawan.EUI64, page HistoryPage) ([]FrameLog, error) {
+	return getFrameLogHistory(fmt.Sprintf(deviceFrameLogHistoryKeyTempl, devEUI), page)
+}
+
+func addToHistory(pipe redis.Pipeliner, key string, values map[string]interface{}) {
+	if historyMaxLen <= 0 {
+		return
+	}
+
+	pipe.XAdd(&redis.XAddArgs{
+		Stream:       key,
+		MaxLenApprox: historyMaxLen,
+		Values:       values,
+	})
+
+	if historyTTL != 0 {
+		pipe.PExpire(key, historyTTL)
+	}
+}
+
+func getFrameLogHistory(key string, page HistoryPage) ([]FrameLog, error) {
+	start := "-"
+	end := "+"
+
+	if page.After != "" {
+		next, err := nextStreamID(page.After)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid after id")
+		}
+		start = next
+	} else if !page.Start.IsZero() {
+		start = strconv.FormatInt(page.Start.UnixNano()/int64(time.Millisecond), 10)
+	}
+
+	if !page.End.IsZero() {
+		end = strconv.FormatInt(page.End.UnixNano()/int64(time.Millisecond), 10)
+	}
+
+	var msgs []redis.XMessage
+	var err error
+	if page.Limit > 0 {
+		msgs, err = storage.RedisClient().XRangeN(key, start, end, page.Limit).Result()
+	} else {
+		msgs, err = storage.RedisClient().XRange(key, start, end).Result()
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "read frame-log history error")
+	}
+
+	var out []FrameLog
+	for _, msg := range msgs {
+		fl, err := streamMessageToFrameLog(msg)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, fl)
+	}
+
+	return out, nil
 }
 
-func getFrameLogs(ctx context.Context, uplinkKey, downlinkKey string, frameLogChan chan FrameLog) error {
+func getFrameLogs(ctx context.Context, uplinkKey, downlinkKey, historyKey string, frameLogChan chan FrameLog) error {
 	sub := storage.RedisClient().Subscribe(uplinkKey, downlinkKey)
 	_, err := sub.Receive()
 	if err != nil {
 		return errors.Wrap(err, "subscribe error")
 	}
 
+	// This will also close the channel
+	defer sub.Close()
+
+	// The history is read after subscribing, so that no frame log gets lost.
+	// Frame logs published in between are received twice, these are skipped
+	// by their payload.
+	replayed, err := replayFrameLogHistory(ctx, uplinkKey, downlinkKey, historyKey, frameLogChan)
+	if err != nil {
+		return err
+	}
+
 	ch := sub.Channel()
 
 	for {
@@ -143,6 +313,11 @@ func getFrameLogs(ctx context.Context, uplinkKey, downlinkKey string, frameLogCh
 				continue
 			}
 
+			if _, ok := replayed[msg.Channel+msg.Payload]; ok {
+				delete(replayed, msg.Channel+msg.Payload)
+				continue
+			}
+
 			fl, err := redisMessageToFrameLog(msg, uplinkKey, downlinkKey)
 			if err != nil {
 				log.WithError(err).Error("decode message error")
@@ -150,13 +325,50 @@ func getFrameLogs(ctx context.Context, uplinkKey, downlinkKey string, frameLogCh
 				frameLogChan <- fl
 			}
 		case <-ctx.Done():
-			// This will also close the channel
-			sub.Close()
 			return nil
 		}
 	}
 }
 
+// replayFrameLogHistory sends the last frame logs from the history to the
+// given channel, in chronological order. It returns the replayed payloads,
+// prefixed by the matching pub-sub key.
+func replayFrameLogHistory(ctx context.Context, uplinkKey, downlinkKey, historyKey string, frameLogChan chan FrameLog) (map[string]struct{}, error) {
+	replayed := make(map[string]struct{})
+
+	if historyMaxLen <= 0 || streamReplayCount <= 0 {
+		return replayed, nil
+	}
+
+	msgs, err := storage.RedisClient().XRevRangeN(historyKey, "+", "-", streamReplayCount).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "read frame-log history error")
+	}
+
+	for i := len(msgs) - 1; i >= 0; i-- {
+		fl, err := streamMessageToFrameLog(msgs[i])
+		if err != nil {
+			log.WithError(err).Error("decode frame-log history error")
+			continue
+		}
+
+		if v, ok := msgs[i].Values[historyUplinkField]; ok {
+			replayed[uplinkKey+fmt.Sprintf("%s", v)] = struct{}{}
+		}
+		if v, ok := msgs[i].Values[historyDownlinkField]; ok {
+			replayed[downlinkKey+fmt.Sprintf("%s", v)] = struct{}{}
+		}
+
+		select {
+		case frameLogChan <- fl:
+		case <-ctx.Done():
+			return replayed, nil
+		}
+	}
+
+	return replayed, nil
+}
+
 func redisMessageToFrameLog(msg *redis.Message, uplinkKey, downlinkKey string) (FrameLog, error) {
 	var fl FrameLog
 
@@ -176,3 +388,53 @@ func redisMessageToFrameLog(msg *redis.Message, uplinkKey, downlinkKey string) (
 
 	return fl, nil
 }
+
+func streamMessageToFrameLog(msg redis.XMessage) (FrameLog, error) {
+	fl := FrameLog{
+		ID: msg.ID,
+	}
+
+	if ms, err := strconv.ParseInt(strings.SplitN(msg.ID, "-", 2)[0], 10, 64); err == nil {
+		fl.Time = time.Unix(0, ms*int64(time.Millisecond))
+	}
+
+	if v, ok := msg.Values[historyUplinkField]; ok {
+		fl.UplinkFrame = &ns.UplinkFrameLog{}
+		if err := proto.Unmarshal([]byte(fmt.Sprintf("%s", v)), fl.UplinkFrame); err != nil {
+			return fl, errors.Wrap(err, "unmarshal uplink frame-set error")
+		}
+
+		_, fl.UplinkRateLimited = msg.Values[historyUplinkRateLimitedField]
+	}
+
+	if v, ok := msg.Values[historyDownlinkField]; ok {
+		fl.DownlinkFrame = &ns.DownlinkFrameLog{}
+		if err := proto.Unmarshal([]byte(fmt.Sprintf("%s", v)), fl.DownlinkFrame); err != nil {
+			return fl, errors.Wrap(err, "unmarshal downlink frame error")
+		}
+
+		_, fl.DownlinkRateLimited = msg.Values[historyDownlinkRateLimitedField]
+	}
+
+	return fl, nil
+}
+
+// nextStreamID returns the stream ID following the given ID.
+func nextStreamID(id string) (string, error) {
+	parts := strings.SplitN(id, "-", 2)
+	if len(parts) != 2 {
+		return "", fmt.Errorf("invalid stream id: %s", id)
+	}
+
+	ms, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return "", errors.Wrap(err, "parse stream id error")
+	}
+
+	seq, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return "", errors.Wrap(err, "parse stream id error")
+	}
+
+	return fmt.Sprintf("%d-%d", ms, seq+1), nil
+}
@@ -132,7 +132,7 @@ func (ts *FrameLogTestSuite) TestGetFrameLogForDevice() {
 			},
 		}
 
-		assert.NoError(LogUplinkFrameForDevEUI(ctx, ts.DevEUI, uplinkFrameLog))
+		assert.NoError(LogUplinkFrameForDevEUI(ctx, ts.DevEUI, uplinkFrameLog, false))
 		frameLog := <-logChannel
 		assert.True(proto.Equal(frameLog.UplinkFrame, &uplinkFrameLog))
 	})
@@ -146,7 +146,7 @@ func (ts *FrameLogTestSuite) TestGetFrameLogForDevice() {
 			TxInfo:     &gw.DownlinkTXInfo{},
 		}
 
-		assert.NoError(LogDownlinkFrameForDevEUI(ctx, ts.DevEUI, downlinkFrameLog))
+		assert.NoError(LogDownlinkFrameForDevEUI(ctx, ts.DevEUI, downlinkFrameLog, false))
 		downlinkFrameLog.TxInfo.XXX_sizecache = 0
 
 		assert.Equal(FrameLog{
@@ -155,6 +155,123 @@ func (ts *FrameLogTestSuite) TestGetFrameLogForDevice() {
 	})
 }
 
+func (ts *FrameLogTestSuite) TestFrameLogHistory() {
+	assert := require.New(ts.T())
+	ctx := context.Background()
+
+	conf := test.GetConfig()
+	conf.NetworkServer.FrameLog.HistoryMaxLen = 10
+	conf.NetworkServer.FrameLog.HistoryTTL = time.Hour
+	conf.NetworkServer.FrameLog.StreamReplayCount = 2
+	assert.NoError(Setup(conf))
+	defer Setup(test.GetConfig())
+
+	start := time.Now()
+
+	uplinkFrameLog := ns.UplinkFrameLog{
+		PhyPayload: []byte{1, 2, 3, 4},
+		RxInfo: []*gw.UplinkRXInfo{
+			{
+				GatewayId: ts.GatewayID[:],
+			},
+		},
+	}
+	downlinkFrameLog := ns.DownlinkFrameLog{
+		PhyPayload: []byte{4, 3, 2, 1},
+		GatewayId:  ts.GatewayID[:],
+	}
+	downlinkFrameLog2 := ns.DownlinkFrameLog{
+		PhyPayload: []byte{5, 6, 7, 8},
+		GatewayId:  ts.GatewayID[:],
+	}
+
+	assert.NoError(LogUplinkFrameForGateways(ctx, uplinkFrameLog))
+	assert.NoError(LogUplinkFrameForDevEUI(ctx, ts.DevEUI, uplinkFrameLog, true))
+	assert.NoError(LogDownlinkFrameForGateway(ctx, downlinkFrameLog))
+	assert.NoError(LogDownlinkFrameForDevEUI(ctx, ts.DevEUI, downlinkFrameLog, false))
+	assert.NoError(LogDownlinkFrameForDevEUI(ctx, ts.DevEUI, downlinkFrameLog2, true))
+
+	ts.T().Run("GetFrameLogHistoryForGateway", func(t *testing.T) {
+		assert := require.New(t)
+
+		frameLogs, err := GetFrameLogHistoryForGateway(ctx, ts.GatewayID, HistoryPage{})
+		assert.NoError(err)
+		assert.Len(frameLogs, 2)
+		assert.True(proto.Equal(&uplinkFrameLog, frameLogs[0].UplinkFrame))
+		assert.False(frameLogs[0].UplinkRateLimited)
+		assert.True(proto.Equal(&downlinkFrameLog, frameLogs[1].DownlinkFrame))
+		assert.NotEqual("", frameLogs[0].ID)
+		assert.WithinDuration(start, frameLogs[0].Time, time.Second)
+	})
+
+	ts.T().Run("GetFrameLogHistoryForDevice", func(t *testing.T) {
+		t.Run("Paging", func(t *testing.T) {
+			assert := require.New(t)
+
+			page1, err := GetFrameLogHistoryForDevice(ctx, ts.DevEUI, HistoryPage{Limit: 2})
+			assert.NoError(err)
+			assert.Len(page1, 2)
+			assert.True(proto.Equal(&uplinkFrameLog, page1[0].UplinkFrame))
+			assert.True(page1[0].UplinkRateLimited)
+			assert.True(proto.Equal(&downlinkFrameLog, page1[1].DownlinkFrame))
+			assert.False(page1[1].DownlinkRateLimited)
+
+			page2, err := GetFrameLogHistoryForDevice(ctx, ts.DevEUI, HistoryPage{Limit: 2, After: page1[1].ID})
+			assert.NoError(err)
+			assert.Len(page2, 1)
+			assert.True(proto.Equal(&downlinkFrameLog2, page2[0].DownlinkFrame))
+			assert.True(page2[0].DownlinkRateLimited)
+		})
+
+		t.Run("Time range", func(t *testing.T) {
+			assert := require.New(t)
+
+			frameLogs, err := GetFrameLogHistoryForDevice(ctx, ts.DevEUI, HistoryPage{End: start.Add(-time.Minute)})
+			assert.NoError(err)
+			assert.Len(frameLogs, 0)
+
+			frameLogs, err = GetFrameLogHistoryForDevice(ctx, ts.DevEUI, HistoryPage{Start: start.Add(-time.Second), End: time.Now().Add(time.Second)})
+			assert.NoError(err)
+			assert.Len(frameLogs, 3)
+		})
+	})
+
+	ts.T().Run("GetFrameLogForDevice replays history", func(t *testing.T) {
+		assert := require.New(t)
+
+		logChannel := make(chan FrameLog, 2)
+		cctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		go func() {
+			err := GetFrameLogForDevice(cctx, ts.DevEUI, logChannel)
+			assert.NoError(err)
+		}()
+
+		frameLog := <-logChannel
+		assert.True(proto.Equal(&downlinkFrameLog, frameLog.DownlinkFrame))
+		frameLog = <-logChannel
+		assert.True(proto.Equal(&downlinkFrameLog2, frameLog.DownlinkFrame))
+		assert.True(frameLog.DownlinkRateLimited)
+
+		// live frame logs are sent exactly once
+		downlinkFrameLog3 := ns.DownlinkFrameLog{
+			PhyPayload: []byte{9, 10, 11, 12},
+			GatewayId:  ts.GatewayID[:],
+		}
+		assert.NoError(LogDownlinkFrameForDevEUI(ctx, ts.DevEUI, downlinkFrameLog3, false))
+
+		frameLog = <-logChannel
+		assert.True(proto.Equal(&downlinkFrameLog3, frameLog.DownlinkFrame))
+
+		select {
+		case frameLog = <-logChannel:
+			assert.Fail("unexpected frame-log", "%+v", frameLog)
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+}
+
 func TestFrameLog(t *testing.T) {
 	suite.Run(t, new(FrameLogTestSuite))
 }
@@ -0,0 +1,333 @@
+// Package channelplan generates and validates the gateway configuration
+// (channel-plan) of gateway-profiles.
+package channelplan
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+
+	"github.com/brocaar/chirpstack-api/go/v3/common"
+	"github.com/brocaar/chirpstack-api/go/v3/gw"
+	"github.com/brocaar/chirpstack-network-server/internal/band"
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/lorawan"
+	loraband "github.com/brocaar/lorawan/band"
+)
+
+// Concentrator (SX1301 / SX1302) limits.
+const (
+	maxRadios          = 2
+	maxMultiSFChannels = 8
+	maxLoRaStdChannels = 1
+	maxFSKChannels     = 1
+)
+
+// frequencyRange defines the min. and max. frequency (Hz) of a band.
+type frequencyRange struct {
+	min int
+	max int
+}
+
+// bandFrequencyRanges contains the frequency ranges per band name.
+var bandFrequencyRanges = map[string]frequencyRange{
+	"AS923": {915000000, 928000000},
+	"AU915": {915000000, 928000000},
+	"CN470": {470000000, 510000000},
+	"CN779": {779000000, 787000000},
+	"EU433": {433175000, 434665000},
+	"EU868": {863000000, 870000000},
+	"IN865": {865000000, 867000000},
+	"KR920": {920900000, 923300000},
+	"RU864": {864000000, 870000000},
+	"US915": {902000000, 928000000},
+}
+
+// GatewayConfiguration returns the gateway configuration for the given
+// gateway and gateway-profile.
+func GatewayConfiguration(gatewayID lorawan.EUI64, gp storage.GatewayProfile) (gw.GatewayConfiguration, error) {
+	configPacket := gw.GatewayConfiguration{
+		GatewayId:     gatewayID[:],
+		StatsInterval: ptypes.DurationProto(gp.StatsInterval),
+		Version:       gp.GetVersion(),
+	}
+
+	for _, i := range gp.Channels {
+		c, err := band.Band().GetUplinkChannel(int(i))
+		if err != nil {
+			return configPacket, errors.Wrap(err, "get channel error")
+		}
+
+		gwC := gw.ChannelConfiguration{
+			Frequency:  uint32(c.Frequency),
+			Modulation: common.Modulation_LORA,
+		}
+
+		modConfig := gw.LoRaModulationConfig{}
+
+		for drI := c.MaxDR; drI >= c.MinDR; drI-- {
+			dr, err := band.Band().GetDataRate(drI)
+			if err != nil {
+				return configPacket, errors.Wrap(err, "get data-rate error")
+			}
+
+			modConfig.SpreadingFactors = append(modConfig.SpreadingFactors, uint32(dr.SpreadFactor))
+			modConfig.Bandwidth = uint32(dr.Bandwidth)
+		}
+
+		gwC.ModulationConfig = &gw.ChannelConfiguration_LoraModulationConfig{
+			LoraModulationConfig: &modConfig,
+		}
+
+		configPacket.Channels = append(configPacket.Channels, &gwC)
+	}
+
+	for _, c := range gp.ExtraChannels {
+		gwC := gw.ChannelConfiguration{
+			Frequency: uint32(c.Frequency),
+		}
+
+		switch loraband.Modulation(c.Modulation) {
+		case loraband.LoRaModulation:
+			gwC.Modulation = common.Modulation_LORA
+			modConfig := gw.LoRaModulationConfig{
+				Bandwidth: uint32(c.Bandwidth),
+			}
+
+			for _, sf := range c.SpreadingFactors {
+				modConfig.SpreadingFactors = append(modConfig.SpreadingFactors, uint32(sf))
+			}
+
+			gwC.ModulationConfig = &gw.ChannelConfiguration_LoraModulationConfig{
+				LoraModulationConfig: &modConfig,
+			}
+		case loraband.FSKModulation:
+			gwC.Modulation = common.Modulation_FSK
+			modConfig := gw.FSKModulationConfig{
+				Bandwidth: uint32(c.Bandwidth),
+				Bitrate:   uint32(c.Bitrate),
+			}
+
+			gwC.ModulationConfig = &gw.ChannelConfiguration_FskModulationConfig{
+				FskModulationConfig: &modConfig,
+			}
+		}
+
+		configPacket.Channels = append(configPacket.Channels, &gwC)
+	}
+
+	return configPacket, nil
+}
+
+// Validate validates the channel-plan of the given gateway-profile. It
+// returns an error when the channel-plan can not be configured on a
+// SX1301 / SX1302 based gateway, or when it conflicts with the
+// network-server band configuration.
+func Validate(gp storage.GatewayProfile) error {
+	enabled := make(map[int]struct{})
+	for _, i := range band.Band().GetEnabledUplinkChannelIndices() {
+		enabled[i] = struct{}{}
+	}
+
+	for _, i := range gp.Channels {
+		if _, err := band.Band().GetUplinkChannel(int(i)); err != nil {
+			return fmt.Errorf("channel %d does not exist in the %s band", i, band.Band().Name())
+		}
+
+		if _, ok := enabled[int(i)]; !ok {
+			return fmt.Errorf("channel %d is not enabled in the network-server configuration", i)
+		}
+	}
+
+	for i, c := range gp.ExtraChannels {
+		if err := validateExtraChannel(c); err != nil {
+			return errors.Wrapf(err, "extra channel %d (%d Hz)", i, c.Frequency)
+		}
+	}
+
+	conf, err := GatewayConfiguration(lorawan.EUI64{}, gp)
+	if err != nil {
+		return errors.Wrap(err, "get gateway configuration error")
+	}
+
+	return validateChannels(conf.Channels)
+}
+
+func validateExtraChannel(c storage.ExtraChannel) error {
+	if c.Frequency <= 0 {
+		return errors.New("frequency must be set")
+	}
+
+	switch loraband.Modulation(c.Modulation) {
+	case loraband.LoRaModulation:
+		switch c.Bandwidth {
+		case 125, 250, 500:
+		default:
+			return fmt.Errorf("invalid LoRa bandwidth: %d kHz", c.Bandwidth)
+		}
+
+		if len(c.SpreadingFactors) == 0 {
+			return errors.New("at least one spreading-factor must be set")
+		}
+
+		for _, sf := range c.SpreadingFactors {
+			if sf < 5 || sf > 12 {
+				return fmt.Errorf("invalid spreading-factor: %d", sf)
+			}
+		}
+	case loraband.FSKModulation:
+		if c.Bandwidth <= 0 {
+			return errors.New("bandwidth must be set")
+		}
+
+		if c.Bitrate <= 0 {
+			return errors.New("bitrate must be set")
+		}
+	default:
+		return fmt.Errorf("invalid modulation: %s", c.Modulation)
+	}
+
+	return nil
+}
+
+// channel contains the validation properties of a channel.
+type channel struct {
+	frequency  int
+	bandwidth  int // Hz
+	modulation common.Modulation
+	multiSF    bool
+}
+
+func (c channel) String() string {
+	return fmt.Sprintf("%s channel %d Hz (%d kHz)", c.modulation, c.frequency, c.bandwidth/1000)
+}
+
+func (c channel) minFrequency() int {
+	return c.frequency - c.bandwidth/2
+}
+
+func (c channel) maxFrequency() int {
+	return c.frequency + c.bandwidth/2
+}
+
+// radioCenterRange returns the range of radio center frequencies from
+// which the channel can be received.
+func (c channel) radioCenterRange() (int, int) {
+	half := radioBandwidth(c.bandwidth)/2 - c.bandwidth/2
+	return c.frequency - half, c.frequency + half
+}
+
+// radioBandwidth returns the usable radio bandwidth (Hz) for channels of
+// the given bandwidth.
+func radioBandwidth(bandwidth int) int {
+	switch {
+	case bandwidth <= 125000:
+		return 925000
+	case bandwidth <= 250000:
+		return 1000000
+	default:
+		return 1100000
+	}
+}
+
+func validateChannels(confChannels []*gw.ChannelConfiguration) error {
+	var channels []channel
+	var multiSFCount, loRaStdCount, fskCount int
+
+	for _, c := range confChannels {
+		ch := channel{
+			frequency:  int(c.Frequency),
+			modulation: c.Modulation,
+		}
+
+		switch mc := c.ModulationConfig.(type) {
+		case *gw.ChannelConfiguration_LoraModulationConfig:
+			ch.bandwidth = int(mc.LoraModulationConfig.Bandwidth) * 1000
+			ch.multiSF = ch.bandwidth == 125000 && len(mc.LoraModulationConfig.SpreadingFactors) > 1
+			if ch.multiSF {
+				multiSFCount++
+			} else {
+				loRaStdCount++
+			}
+		case *gw.ChannelConfiguration_FskModulationConfig:
+			ch.bandwidth = int(mc.FskModulationConfig.Bandwidth) * 1000
+			fskCount++
+		}
+
+		channels = append(channels, ch)
+	}
+
+	if multiSFCount > maxMultiSFChannels {
+		return fmt.Errorf("too many multi-SF LoRa channels, max %d, got %d", maxMultiSFChannels, multiSFCount)
+	}
+
+	if loRaStdCount > maxLoRaStdChannels {
+		return fmt.Errorf("too many single-SF LoRa channels, max %d, got %d", maxLoRaStdChannels, loRaStdCount)
+	}
+
+	if fskCount > maxFSKChannels {
+		return fmt.Errorf("too many FSK channels, max %d, got %d", maxFSKChannels, fskCount)
+	}
+
+	if r, ok := bandFrequencyRanges[band.Band().Name()]; ok {
+		for _, c := range channels {
+			if c.minFrequency() < r.min || c.maxFrequency() > r.max {
+				return fmt.Errorf("%s is outside the %s band (%d - %d Hz)", c, band.Band().Name(), r.min, r.max)
+			}
+		}
+	}
+
+	// channels of the same modulation and bandwidth must not overlap
+	for i := range channels {
+		for j := i + 1; j < len(channels); j++ {
+			a, b := channels[i], channels[j]
+			if a.modulation != b.modulation || a.bandwidth != b.bandwidth {
+				continue
+			}
+
+			if a.minFrequency() < b.maxFrequency() && b.minFrequency() < a.maxFrequency() {
+				return fmt.Errorf("%s overlaps with %s", a, b)
+			}
+		}
+	}
+
+	if radios := radioCount(channels); radios > maxRadios {
+		return fmt.Errorf("channels span %d radios, max %d radios are available", radios, maxRadios)
+	}
+
+	return nil
+}
+
+// radioCount returns the min. number of radios needed to receive all the
+// given channels. Each channel defines a range of possible radio center
+// frequencies, the number of radios equals the min. number of center
+// frequencies so that each range contains at least one of them.
+func radioCount(channels []channel) int {
+	type centerRange struct {
+		min int
+		max int
+	}
+
+	var ranges []centerRange
+	for _, c := range channels {
+		min, max := c.radioCenterRange()
+		ranges = append(ranges, centerRange{min: min, max: max})
+	}
+
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].max < ranges[j].max
+	})
+
+	var count int
+	var center int
+	for i, r := range ranges {
+		if i == 0 || r.min > center {
+			count++
+			center = r.max
+		}
+	}
+
+	return count
+}
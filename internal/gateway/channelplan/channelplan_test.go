@@ -0,0 +1,128 @@
+package channelplan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/chirpstack-network-server/internal/test"
+)
+
+func TestValidate(t *testing.T) {
+	test.GetConfig()
+
+	loraChannel := func(freq int, sfs ...int64) storage.ExtraChannel {
+		return storage.ExtraChannel{
+			Modulation:       storage.ModulationLoRa,
+			Frequency:        freq,
+			Bandwidth:        125,
+			SpreadingFactors: sfs,
+		}
+	}
+
+	tests := []struct {
+		name          string
+		gp            storage.GatewayProfile
+		expectedError string
+	}{
+		{
+			name: "valid",
+			gp: storage.GatewayProfile{
+				Channels: []int64{0, 1, 2},
+				ExtraChannels: []storage.ExtraChannel{
+					loraChannel(867100000, 7, 8, 9, 10, 11, 12),
+					loraChannel(867300000, 7, 8, 9, 10, 11, 12),
+					loraChannel(867500000, 7, 8, 9, 10, 11, 12),
+					loraChannel(867700000, 7, 8, 9, 10, 11, 12),
+					loraChannel(867900000, 7, 8, 9, 10, 11, 12),
+					{
+						Modulation:       storage.ModulationLoRa,
+						Frequency:        868300000,
+						Bandwidth:        250,
+						SpreadingFactors: []int64{7},
+					},
+					{
+						Modulation: storage.ModulationFSK,
+						Frequency:  868800000,
+						Bandwidth:  125,
+						Bitrate:    50000,
+					},
+				},
+			},
+		},
+		{
+			name: "unknown channel",
+			gp: storage.GatewayProfile{
+				Channels: []int64{0, 1, 99},
+			},
+			expectedError: "channel 99 does not exist in the EU868 band",
+		},
+		{
+			name: "invalid spreading-factor",
+			gp: storage.GatewayProfile{
+				ExtraChannels: []storage.ExtraChannel{
+					loraChannel(867100000, 13),
+				},
+			},
+			expectedError: "extra channel 0 (867100000 Hz): invalid spreading-factor: 13",
+		},
+		{
+			name: "overlapping channels",
+			gp: storage.GatewayProfile{
+				Channels: []int64{0},
+				ExtraChannels: []storage.ExtraChannel{
+					loraChannel(868150000, 7, 8),
+				},
+			},
+			expectedError: "LORA channel 868100000 Hz (125 kHz) overlaps with LORA channel 868150000 Hz (125 kHz)",
+		},
+		{
+			name: "outside band",
+			gp: storage.GatewayProfile{
+				ExtraChannels: []storage.ExtraChannel{
+					loraChannel(870100000, 7, 8),
+				},
+			},
+			expectedError: "LORA channel 870100000 Hz (125 kHz) is outside the EU868 band (863000000 - 870000000 Hz)",
+		},
+		{
+			name: "too many multi-SF channels",
+			gp: storage.GatewayProfile{
+				Channels: []int64{0, 1, 2},
+				ExtraChannels: []storage.ExtraChannel{
+					loraChannel(867100000, 7, 8),
+					loraChannel(867300000, 7, 8),
+					loraChannel(867500000, 7, 8),
+					loraChannel(867700000, 7, 8),
+					loraChannel(867900000, 7, 8),
+					loraChannel(868700000, 7, 8),
+				},
+			},
+			expectedError: "too many multi-SF LoRa channels, max 8, got 9",
+		},
+		{
+			name: "radio span exceeded",
+			gp: storage.GatewayProfile{
+				ExtraChannels: []storage.ExtraChannel{
+					loraChannel(864100000, 7, 8),
+					loraChannel(867100000, 7, 8),
+					loraChannel(869500000, 7, 8),
+				},
+			},
+			expectedError: "channels span 3 radios, max 2 radios are available",
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			assert := require.New(t)
+			err := Validate(tst.gp)
+			if tst.expectedError == "" {
+				assert.NoError(err)
+			} else {
+				assert.EqualError(err, tst.expectedError)
+			}
+		})
+	}
+}
@@ -19,6 +19,10 @@ var (
 func Setup(c config.Config) error {
 	conf := c.NetworkServer.Gateway
 
+	if conf.OfflineDetection.Enabled && conf.OfflineDetection.Interval <= 0 {
+		return errors.New("offline_detection interval must be > 0")
+	}
+
 	statsHandler = &StatsHandler{}
 	if err := statsHandler.Start(); err != nil {
 		return errors.Wrap(err, "start stats handler error")
@@ -28,6 +32,12 @@ func Setup(c config.Config) error {
 	caKey = conf.CAKey
 	tlsLifetime = conf.ClientCertLifetime
 
+	offlineDetection = conf.OfflineDetection.Enabled
+	offlineInterval = conf.OfflineDetection.Interval
+	offlineMissedStatsIntervals = conf.OfflineDetection.MissedStatsIntervals
+	offlineDefaultStatsInterval = conf.OfflineDetection.DefaultStatsInterval
+	offlineWebhookURL = conf.OfflineDetection.WebhookURL
+
 	return nil
 }
 
@@ -0,0 +1,37 @@
+package gateway
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/brocaar/lorawan"
+)
+
+var (
+	gatewayOfflineVec = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gateway_offline",
+		Help: "Set to 1 when the gateway is offline, 0 when it is online (per gateway).",
+	}, []string{"gateway_id"})
+
+	gatewayOnlineCount = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "gateway_online_count",
+		Help: "The number of online gateways.",
+	})
+
+	gatewayOfflineCount = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "gateway_offline_count",
+		Help: "The number of offline gateways.",
+	})
+)
+
+func gatewayOfflineGauge(gatewayID lorawan.EUI64) prometheus.Gauge {
+	return gatewayOfflineVec.With(prometheus.Labels{"gateway_id": gatewayID.String()})
+}
+
+func gatewayOnlineCountGauge() prometheus.Gauge {
+	return gatewayOnlineCount
+}
+
+func gatewayOfflineCountGauge() prometheus.Gauge {
+	return gatewayOfflineCount
+}
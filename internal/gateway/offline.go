@@ -0,0 +1,188 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brocaar/chirpstack-network-server/internal/backend/integration"
+	"github.com/brocaar/chirpstack-network-server/internal/helpers"
+	"github.com/brocaar/chirpstack-network-server/internal/logging"
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/lorawan"
+)
+
+// Gateway statuses.
+const (
+	StatusOnline  = "online"
+	StatusOffline = "offline"
+)
+
+const webhookTimeout = 10 * time.Second
+
+var (
+	offlineDetection            bool
+	offlineInterval             time.Duration
+	offlineMissedStatsIntervals int
+	offlineDefaultStatsInterval time.Duration
+	offlineWebhookURL           string
+)
+
+// gatewayStatusEvent contains the webhook payload of a gateway status
+// transition.
+type gatewayStatusEvent struct {
+	GatewayID  lorawan.EUI64 `json:"gatewayID"`
+	Status     string        `json:"status"`
+	LastSeenAt time.Time     `json:"lastSeenAt"`
+}
+
+// OfflineDetectionLoop starts a loop which periodically flags the gateways
+// that missed the configured number of stats intervals as offline (and
+// gateways that are sending stats again as online). The loop returns once
+// the given context is cancelled.
+func OfflineDetectionLoop(ctx context.Context) {
+	if !offlineDetection {
+		return
+	}
+
+	helpers.RunLoop(ctx, "gateway offline detection", offlineInterval, func(ctx context.Context) error {
+		return checkGatewayStates(ctx, time.Now())
+	})
+}
+
+func checkGatewayStates(ctx context.Context, now time.Time) error {
+	states, err := storage.GetGatewayStates(ctx, storage.DB(), offlineDefaultStatsInterval)
+	if err != nil {
+		return errors.Wrap(err, "get gateway states error")
+	}
+
+	var onlineCount, offlineCount int
+
+	// reset, so that deleted gateways are removed from the metrics
+	gatewayOfflineVec.Reset()
+
+	for _, st := range states {
+		offline := isGatewayOffline(st, now)
+		if offline {
+			offlineCount++
+			gatewayOfflineGauge(st.GatewayID).Set(1)
+		} else {
+			onlineCount++
+			gatewayOfflineGauge(st.GatewayID).Set(0)
+		}
+
+		if offline == st.Offline {
+			continue
+		}
+
+		// Only the instance which updates the state publishes the event,
+		// in case multiple network-server instances are running.
+		changed, err := storage.SetGatewayOffline(ctx, storage.DB(), st.GatewayID, offline)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"gateway_id": st.GatewayID,
+				"ctx_id":     ctx.Value(logging.ContextIDKey),
+			}).WithError(err).Error("set gateway offline state error")
+			continue
+		}
+		if !changed {
+			continue
+		}
+
+		if err := storage.FlushGatewayCache(ctx, st.GatewayID); err != nil {
+			log.WithFields(log.Fields{
+				"gateway_id": st.GatewayID,
+				"ctx_id":     ctx.Value(logging.ContextIDKey),
+			}).WithError(err).Error("flush gateway cache error")
+		}
+
+		status := StatusOnline
+		if offline {
+			status = StatusOffline
+		}
+
+		log.WithFields(log.Fields{
+			"gateway_id":   st.GatewayID,
+			"status":       status,
+			"last_seen_at": st.LastSeenAt,
+			"ctx_id":       ctx.Value(logging.ContextIDKey),
+		}).Info("gateway status changed")
+
+		publishGatewayStatus(ctx, gatewayStatusEvent{
+			GatewayID:  st.GatewayID,
+			Status:     status,
+			LastSeenAt: st.LastSeenAt,
+		})
+	}
+
+	gatewayOnlineCountGauge().Set(float64(onlineCount))
+	gatewayOfflineCountGauge().Set(float64(offlineCount))
+
+	return nil
+}
+
+// isGatewayOffline returns true when the gateway did not send stats within
+// the configured number of stats intervals.
+func isGatewayOffline(st storage.GatewayState, now time.Time) bool {
+	interval := st.StatsInterval
+	if interval <= 0 {
+		interval = offlineDefaultStatsInterval
+	}
+
+	return now.Sub(st.LastSeenAt) > time.Duration(offlineMissedStatsIntervals)*interval
+}
+
+func publishGatewayStatus(ctx context.Context, event gatewayStatusEvent) {
+	integration.PublishEvent(ctx, integration.Event{
+		Type:      integration.EventStatus,
+		GatewayID: event.GatewayID,
+		Message:   integration.GatewayStatusMessage(event.GatewayID, event.Status, event.LastSeenAt),
+	})
+
+	if offlineWebhookURL == "" {
+		return
+	}
+
+	go func() {
+		if err := postWebhook(ctx, offlineWebhookURL, event); err != nil {
+			log.WithFields(log.Fields{
+				"gateway_id": event.GatewayID,
+				"ctx_id":     ctx.Value(logging.ContextIDKey),
+			}).WithError(err).Error("post gateway status webhook error")
+		}
+	}()
+}
+
+func postWebhook(ctx context.Context, url string, event gatewayStatusEvent) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "marshal json error")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return errors.Wrap(err, "new request error")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "http request error")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("expected 2XX response, got: %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+)
+
+func TestIsGatewayOffline(t *testing.T) {
+	offlineMissedStatsIntervals = 3
+	offlineDefaultStatsInterval = 30 * time.Second
+
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		state    storage.GatewayState
+		expected bool
+	}{
+		{
+			name: "seen within interval",
+			state: storage.GatewayState{
+				LastSeenAt:    now.Add(-time.Minute),
+				StatsInterval: time.Minute,
+			},
+			expected: false,
+		},
+		{
+			name: "missed intervals",
+			state: storage.GatewayState{
+				LastSeenAt:    now.Add(-4 * time.Minute),
+				StatsInterval: time.Minute,
+			},
+			expected: true,
+		},
+		{
+			name: "default stats interval",
+			state: storage.GatewayState{
+				LastSeenAt: now.Add(-2 * time.Minute),
+			},
+			expected: true,
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			assert := require.New(t)
+			assert.Equal(tst.expected, isGatewayOffline(tst.state, now))
+		})
+	}
+}
@@ -4,19 +4,17 @@ import (
 	"context"
 	"time"
 
-	"github.com/golang/protobuf/ptypes"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/brocaar/chirpstack-api/go/v3/as"
-	"github.com/brocaar/chirpstack-api/go/v3/common"
 	"github.com/brocaar/chirpstack-api/go/v3/gw"
 	"github.com/brocaar/chirpstack-network-server/internal/backend/gateway"
-	"github.com/brocaar/chirpstack-network-server/internal/band"
+	"github.com/brocaar/chirpstack-network-server/internal/backend/integration"
+	"github.com/brocaar/chirpstack-network-server/internal/gateway/channelplan"
 	"github.com/brocaar/chirpstack-network-server/internal/helpers"
 	"github.com/brocaar/chirpstack-network-server/internal/logging"
 	"github.com/brocaar/chirpstack-network-server/internal/storage"
-	loraband "github.com/brocaar/lorawan/band"
 )
 
 var ErrAbort = errors.New("abort")
@@ -29,6 +27,7 @@ type statsContext struct {
 
 var tasks = []func(*statsContext) error{
 	getGateway,
+	publishGatewayStatsEvent,
 	updateGatewayState,
 	handleGatewayConfigurationUpdate,
 	forwardGatewayStats,
@@ -126,74 +125,9 @@ func handleGatewayConfigurationUpdate(ctx *statsContext) error {
 		return nil
 	}
 
-	configPacket := gw.GatewayConfiguration{
-		GatewayId:     ctx.gateway.GatewayID[:],
-		StatsInterval: ptypes.DurationProto(gwProfile.StatsInterval),
-		Version:       gwProfile.GetVersion(),
-	}
-
-	for _, i := range gwProfile.Channels {
-		c, err := band.Band().GetUplinkChannel(int(i))
-		if err != nil {
-			return errors.Wrap(err, "get channel error")
-		}
-
-		gwC := gw.ChannelConfiguration{
-			Frequency:  uint32(c.Frequency),
-			Modulation: common.Modulation_LORA,
-		}
-
-		modConfig := gw.LoRaModulationConfig{}
-
-		for drI := c.MaxDR; drI >= c.MinDR; drI-- {
-			dr, err := band.Band().GetDataRate(drI)
-			if err != nil {
-				return errors.Wrap(err, "get data-rate error")
-			}
-
-			modConfig.SpreadingFactors = append(modConfig.SpreadingFactors, uint32(dr.SpreadFactor))
-			modConfig.Bandwidth = uint32(dr.Bandwidth)
-		}
-
-		gwC.ModulationConfig = &gw.ChannelConfiguration_LoraModulationConfig{
-			LoraModulationConfig: &modConfig,
-		}
-
-		configPacket.Channels = append(configPacket.Channels, &gwC)
-	}
-
-	for _, c := range gwProfile.ExtraChannels {
-		gwC := gw.ChannelConfiguration{
-			Frequency: uint32(c.Frequency),
-		}
-
-		switch loraband.Modulation(c.Modulation) {
-		case loraband.LoRaModulation:
-			gwC.Modulation = common.Modulation_LORA
-			modConfig := gw.LoRaModulationConfig{
-				Bandwidth: uint32(c.Bandwidth),
-			}
-
-			for _, sf := range c.SpreadingFactors {
-				modConfig.SpreadingFactors = append(modConfig.SpreadingFactors, uint32(sf))
-			}
-
-			gwC.ModulationConfig = &gw.ChannelConfiguration_LoraModulationConfig{
-				LoraModulationConfig: &modConfig,
-			}
-		case loraband.FSKModulation:
-			gwC.Modulation = common.Modulation_FSK
-			modConfig := gw.FSKModulationConfig{
-				Bandwidth: uint32(c.Bandwidth),
-				Bitrate:   uint32(c.Bitrate),
-			}
-
-			gwC.ModulationConfig = &gw.ChannelConfiguration_FskModulationConfig{
-				FskModulationConfig: &modConfig,
-			}
-		}
-
-		configPacket.Channels = append(configPacket.Channels, &gwC)
+	configPacket, err := channelplan.GatewayConfiguration(ctx.gateway.GatewayID, gwProfile)
+	if err != nil {
+		return errors.Wrap(err, "get gateway configuration error")
 	}
 
 	if err := gateway.Backend().SendGatewayConfigPacket(configPacket); err != nil {
@@ -203,6 +137,16 @@ func handleGatewayConfigurationUpdate(ctx *statsContext) error {
 	return nil
 }
 
+func publishGatewayStatsEvent(ctx *statsContext) error {
+	integration.PublishEvent(ctx.ctx, integration.Event{
+		Type:      integration.EventStats,
+		GatewayID: ctx.gateway.GatewayID,
+		Message:   &ctx.gatewayStats,
+	})
+
+	return nil
+}
+
 func forwardGatewayStats(ctx *statsContext) error {
 	rp, err := storage.GetRoutingProfile(ctx.ctx, storage.DB(), ctx.gateway.RoutingProfileID)
 	if err != nil {
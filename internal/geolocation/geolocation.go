@@ -0,0 +1,322 @@
+// Package geolocation implements the network-side geolocation of devices,
+// based on the meta-data of the gateways receiving the uplink.
+package geolocation
+
+import (
+	"math"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+
+	"github.com/brocaar/chirpstack-api/go/v3/common"
+	"github.com/brocaar/chirpstack-api/go/v3/gw"
+	"github.com/brocaar/chirpstack-network-server/internal/helpers"
+	"github.com/brocaar/lorawan"
+)
+
+const (
+	// speedOfLight in m/s.
+	speedOfLight = 299792458
+
+	// earthRadius in meters.
+	earthRadius = 6371000
+
+	// minTDOAGateways defines the min. number of gateways with a fine
+	// timestamp needed for TDOA multilateration.
+	minTDOAGateways = 3
+
+	// minRSSIGateways defines the min. number of gateways needed for RSSI
+	// multilateration.
+	minRSSIGateways = 3
+
+	// maxIterations defines the max. number of Gauss-Newton iterations.
+	maxIterations = 50
+)
+
+// The RSSI path-loss model parameters. The RSSI at the given reference
+// distance and the path-loss exponent are typical values for a LoRa
+// device in a sub-urban environment.
+var (
+	rssiReference         = -85.0  // dBm
+	rssiReferenceDistance = 1000.0 // meters
+	rssiPathLossExponent  = 2.7
+)
+
+// ErrInsufficientData is returned when the location can't be resolved
+// given the rx-info set.
+var ErrInsufficientData = errors.New("insufficient data to resolve location")
+
+// measurement contains the position of the gateway (in meters, relative to
+// the origin) and the uplink meta-data.
+type measurement struct {
+	X             float64
+	Y             float64
+	Z             float64
+	RSSI          float64
+	FineTimestamp *time.Time
+}
+
+// origin defines the origin of the local coordinate system.
+type origin struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// toLocal returns the x and y position (in meters) relative to the origin
+// using an equirectangular projection.
+func (o origin) toLocal(lat, lon float64) (float64, float64) {
+	x := toRadians(lon-o.Longitude) * math.Cos(toRadians(o.Latitude)) * earthRadius
+	y := toRadians(lat-o.Latitude) * earthRadius
+	return x, y
+}
+
+// toGeo returns the latitude and longitude of the given x and y position
+// (in meters) relative to the origin.
+func (o origin) toGeo(x, y float64) (float64, float64) {
+	lat := o.Latitude + toDegrees(y/earthRadius)
+	lon := o.Longitude + toDegrees(x/(earthRadius*math.Cos(toRadians(o.Latitude))))
+	return lat, lon
+}
+
+// Resolve resolves the location of the device, given the (de-duplicated)
+// rx-info set of the uplink. The rx-info elements must contain the location
+// of the gateway. When at least three gateways provide a (decrypted) fine
+// timestamp, TDOA multilateration is used, else the location is resolved
+// using the RSSI. At least three gateways (with location) are needed, else
+// ErrInsufficientData is returned. The reference altitude is used as
+// altitude of the device.
+func Resolve(rxInfoSet []*gw.UplinkRXInfo, referenceAltitude float64) (common.Location, error) {
+	o, measurements := getMeasurements(rxInfoSet)
+	if len(measurements) < minRSSIGateways {
+		return common.Location{}, ErrInsufficientData
+	}
+
+	var tdoaMeasurements []measurement
+	for _, m := range measurements {
+		if m.FineTimestamp != nil {
+			tdoaMeasurements = append(tdoaMeasurements, m)
+		}
+	}
+
+	var x, y, accuracy float64
+	if len(tdoaMeasurements) >= minTDOAGateways {
+		x, y, accuracy = resolveTDOA(tdoaMeasurements, referenceAltitude)
+	} else {
+		x, y, accuracy = resolveRSSI(measurements, referenceAltitude)
+	}
+
+	if math.IsNaN(x) || math.IsNaN(y) {
+		return common.Location{}, ErrInsufficientData
+	}
+
+	lat, lon := o.toGeo(x, y)
+
+	return common.Location{
+		Latitude:  lat,
+		Longitude: lon,
+		Altitude:  referenceAltitude,
+		Source:    common.LocationSource_GEO_RESOLVER,
+		Accuracy:  uint32(math.Round(accuracy)),
+	}, nil
+}
+
+// getMeasurements returns the measurements for the given rx-info set, and
+// the origin of the local coordinate system. In case a gateway occurs
+// multiple times in the set, the element with a fine timestamp or the best
+// RSSI is used.
+func getMeasurements(rxInfoSet []*gw.UplinkRXInfo) (origin, []measurement) {
+	var o origin
+	var gatewayIDs []lorawan.EUI64
+	rxInfoByGatewayID := make(map[lorawan.EUI64]*gw.UplinkRXInfo)
+
+	for _, rxInfo := range rxInfoSet {
+		loc := rxInfo.GetLocation()
+		if loc == nil || (loc.Latitude == 0 && loc.Longitude == 0) {
+			continue
+		}
+
+		gatewayID := helpers.GetGatewayID(rxInfo)
+		existing, ok := rxInfoByGatewayID[gatewayID]
+		if !ok {
+			gatewayIDs = append(gatewayIDs, gatewayID)
+			rxInfoByGatewayID[gatewayID] = rxInfo
+			continue
+		}
+
+		if getFineTimestamp(existing) == nil && (getFineTimestamp(rxInfo) != nil || rxInfo.Rssi > existing.Rssi) {
+			rxInfoByGatewayID[gatewayID] = rxInfo
+		}
+	}
+
+	if len(gatewayIDs) == 0 {
+		return o, nil
+	}
+
+	for _, id := range gatewayIDs {
+		o.Latitude += rxInfoByGatewayID[id].Location.Latitude
+		o.Longitude += rxInfoByGatewayID[id].Location.Longitude
+	}
+	o.Latitude = o.Latitude / float64(len(gatewayIDs))
+	o.Longitude = o.Longitude / float64(len(gatewayIDs))
+
+	var out []measurement
+	for _, id := range gatewayIDs {
+		rxInfo := rxInfoByGatewayID[id]
+		x, y := o.toLocal(rxInfo.Location.Latitude, rxInfo.Location.Longitude)
+
+		out = append(out, measurement{
+			X:             x,
+			Y:             y,
+			Z:             rxInfo.Location.Altitude,
+			RSSI:          float64(rxInfo.Rssi),
+			FineTimestamp: getFineTimestamp(rxInfo),
+		})
+	}
+
+	return o, out
+}
+
+func getFineTimestamp(rxInfo *gw.UplinkRXInfo) *time.Time {
+	plainTS := rxInfo.GetPlainFineTimestamp()
+	if plainTS == nil || plainTS.GetTime() == nil {
+		return nil
+	}
+
+	ts, err := ptypes.Timestamp(plainTS.GetTime())
+	if err != nil {
+		return nil
+	}
+
+	return &ts
+}
+
+// resolveTDOA resolves the position using the time difference of arrival
+// relative to the first gateway.
+func resolveTDOA(measurements []measurement, z float64) (float64, float64, float64) {
+	ref := measurements[0]
+
+	residuals := func(x, y float64) []float64 {
+		var out []float64
+		refDist := distance(x, y, z, ref)
+
+		for _, m := range measurements[1:] {
+			tdoa := float64(m.FineTimestamp.Sub(*ref.FineTimestamp)) / float64(time.Second)
+			out = append(out, (distance(x, y, z, m)-refDist)-tdoa*speedOfLight)
+		}
+
+		return out
+	}
+
+	x, y := centroid(measurements)
+	return solve(x, y, residuals)
+}
+
+// resolveRSSI resolves the position using the distances estimated from the
+// RSSI using a log-distance path-loss model.
+func resolveRSSI(measurements []measurement, z float64) (float64, float64, float64) {
+	distances := make([]float64, len(measurements))
+	for i, m := range measurements {
+		distances[i] = rssiToDistance(m.RSSI)
+	}
+
+	// weighted centroid, this is also used as initial guess for the
+	// multilateration
+	var x, y, totalWeight float64
+	for i, m := range measurements {
+		w := 1 / (distances[i] * distances[i])
+		x += m.X * w
+		y += m.Y * w
+		totalWeight += w
+	}
+	x = x / totalWeight
+	y = y / totalWeight
+
+	residuals := func(x, y float64) []float64 {
+		out := make([]float64, len(measurements))
+		for i, m := range measurements {
+			out[i] = distance(x, y, z, m) - distances[i]
+		}
+		return out
+	}
+
+	return solve(x, y, residuals)
+}
+
+// solve minimizes the sum of squared residuals using the Gauss-Newton
+// algorithm, starting at the given position. It returns the position and
+// the root mean square of the residuals.
+func solve(x, y float64, residuals func(x, y float64) []float64) (float64, float64, float64) {
+	const h = 0.1 // meters, used for the numerical derivatives
+
+	for i := 0; i < maxIterations; i++ {
+		r := residuals(x, y)
+		rx := residuals(x+h, y)
+		ry := residuals(x, y+h)
+
+		// normal equations: (J^T J) d = -J^T r
+		var a11, a12, a22, b1, b2 float64
+		for j := range r {
+			jx := (rx[j] - r[j]) / h
+			jy := (ry[j] - r[j]) / h
+
+			a11 += jx * jx
+			a12 += jx * jy
+			a22 += jy * jy
+			b1 -= jx * r[j]
+			b2 -= jy * r[j]
+		}
+
+		det := a11*a22 - a12*a12
+		if math.Abs(det) < 1e-12 {
+			break
+		}
+
+		dx := (b1*a22 - b2*a12) / det
+		dy := (a11*b2 - a12*b1) / det
+		x += dx
+		y += dy
+
+		if math.Hypot(dx, dy) < 0.01 {
+			break
+		}
+	}
+
+	var sum float64
+	r := residuals(x, y)
+	for _, v := range r {
+		sum += v * v
+	}
+
+	var rms float64
+	if len(r) != 0 {
+		rms = math.Sqrt(sum / float64(len(r)))
+	}
+
+	return x, y, rms
+}
+
+func rssiToDistance(rssi float64) float64 {
+	return rssiReferenceDistance * math.Pow(10, (rssiReference-rssi)/(10*rssiPathLossExponent))
+}
+
+func distance(x, y, z float64, m measurement) float64 {
+	return math.Sqrt((x-m.X)*(x-m.X) + (y-m.Y)*(y-m.Y) + (z-m.Z)*(z-m.Z))
+}
+
+func centroid(measurements []measurement) (float64, float64) {
+	var x, y float64
+	for _, m := range measurements {
+		x += m.X
+		y += m.Y
+	}
+	return x / float64(len(measurements)), y / float64(len(measurements))
+}
+
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func toDegrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
@@ -0,0 +1,115 @@
+package geolocation
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/require"
+
+	"github.com/brocaar/chirpstack-api/go/v3/common"
+	"github.com/brocaar/chirpstack-api/go/v3/gw"
+)
+
+func TestResolve(t *testing.T) {
+	gateways := []common.Location{
+		{Latitude: 52.01, Longitude: 5.01, Altitude: 20},
+		{Latitude: 52.01, Longitude: 5.05, Altitude: 30},
+		{Latitude: 52.05, Longitude: 5.01, Altitude: 10},
+		{Latitude: 52.05, Longitude: 5.05, Altitude: 25},
+	}
+	device := common.Location{Latitude: 52.02, Longitude: 5.03, Altitude: 5}
+
+	o := origin{Latitude: 52.03, Longitude: 5.03}
+	devX, devY := o.toLocal(device.Latitude, device.Longitude)
+
+	getDistance := func(loc common.Location) float64 {
+		x, y := o.toLocal(loc.Latitude, loc.Longitude)
+		return distance(devX, devY, device.Altitude, measurement{X: x, Y: y, Z: loc.Altitude})
+	}
+
+	t.Run("No gateway location", func(t *testing.T) {
+		assert := require.New(t)
+
+		_, err := Resolve([]*gw.UplinkRXInfo{
+			{GatewayId: []byte{1, 1, 1, 1, 1, 1, 1, 1}, Rssi: -80},
+		}, 0)
+		assert.Equal(ErrInsufficientData, err)
+	})
+
+	t.Run("TDOA", func(t *testing.T) {
+		assert := require.New(t)
+		rxTime := time.Now()
+
+		var rxInfoSet []*gw.UplinkRXInfo
+		for i := range gateways {
+			ts, err := ptypes.TimestampProto(rxTime.Add(time.Duration(getDistance(gateways[i]) / speedOfLight * float64(time.Second))))
+			assert.NoError(err)
+
+			rxInfoSet = append(rxInfoSet, &gw.UplinkRXInfo{
+				GatewayId:         []byte{byte(i), 1, 1, 1, 1, 1, 1, 1},
+				Location:          &gateways[i],
+				Rssi:              -120,
+				FineTimestampType: gw.FineTimestampType_PLAIN,
+				FineTimestamp: &gw.UplinkRXInfo_PlainFineTimestamp{
+					PlainFineTimestamp: &gw.PlainFineTimestamp{
+						Time: ts,
+					},
+				},
+			})
+		}
+
+		loc, err := Resolve(rxInfoSet, device.Altitude)
+		assert.NoError(err)
+		assert.InDelta(device.Latitude, loc.Latitude, 0.0001)
+		assert.InDelta(device.Longitude, loc.Longitude, 0.0001)
+		assert.Equal(device.Altitude, loc.Altitude)
+		assert.Equal(common.LocationSource_GEO_RESOLVER, loc.Source)
+	})
+
+	t.Run("RSSI", func(t *testing.T) {
+		assert := require.New(t)
+
+		// inverse of the path-loss model, so that the estimated distances
+		// are exact
+		var rxInfoSet []*gw.UplinkRXInfo
+		for i := range gateways {
+			d := getDistance(gateways[i])
+			rssi := rssiReference - 10*rssiPathLossExponent*math.Log10(d/rssiReferenceDistance)
+
+			rxInfoSet = append(rxInfoSet, &gw.UplinkRXInfo{
+				GatewayId: []byte{byte(i), 1, 1, 1, 1, 1, 1, 1},
+				Location:  &gateways[i],
+				Rssi:      int32(rssi),
+			})
+		}
+
+		loc, err := Resolve(rxInfoSet, device.Altitude)
+		assert.NoError(err)
+
+		// the RSSI is rounded to an integer, which results in an error of
+		// up to a few percent of the distance
+		assert.InDelta(device.Latitude, loc.Latitude, 0.003)
+		assert.InDelta(device.Longitude, loc.Longitude, 0.003)
+	})
+
+	t.Run("Single gateway", func(t *testing.T) {
+		assert := require.New(t)
+
+		_, err := Resolve([]*gw.UplinkRXInfo{
+			{GatewayId: []byte{1, 1, 1, 1, 1, 1, 1, 1}, Location: &gateways[0], Rssi: -85},
+		}, 0)
+		assert.Equal(ErrInsufficientData, err)
+	})
+
+	t.Run("Two gateways", func(t *testing.T) {
+		assert := require.New(t)
+
+		_, err := Resolve([]*gw.UplinkRXInfo{
+			{GatewayId: []byte{1, 1, 1, 1, 1, 1, 1, 1}, Location: &gateways[0], Rssi: -85},
+			{GatewayId: []byte{2, 1, 1, 1, 1, 1, 1, 1}, Location: &gateways[1], Rssi: -90},
+		}, 0)
+		assert.Equal(ErrInsufficientData, err)
+	})
+}
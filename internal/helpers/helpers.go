@@ -3,11 +3,14 @@ package helpers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/brocaar/chirpstack-network-server/internal/backend/applicationserver"
+	"github.com/brocaar/chirpstack-network-server/internal/logging"
 	"github.com/brocaar/chirpstack-network-server/internal/storage"
 	"github.com/brocaar/lorawan"
 	"github.com/gofrs/uuid"
+	log "github.com/sirupsen/logrus"
 
 	"github.com/brocaar/chirpstack-api/go/v3/as"
 	"github.com/brocaar/chirpstack-api/go/v3/common"
@@ -35,6 +38,33 @@ type DataRateGetter interface {
 	GetFskModulationInfo() *gw.FSKModulationInfo
 }
 
+// RunLoop calls f directly and then every interval, until the given context
+// is cancelled. Each call gets a new context (with context ID) which is not
+// derived from ctx, so that a running call is completed on shutdown. Errors
+// returned by f are logged.
+func RunLoop(ctx context.Context, name string, interval time.Duration, f func(context.Context) error) {
+	for {
+		ctxID, err := uuid.NewV4()
+		if err != nil {
+			log.WithError(err).Error("get new uuid error")
+		}
+		runCtx := context.WithValue(context.Background(), logging.ContextIDKey, ctxID)
+
+		if err := f(runCtx); err != nil {
+			log.WithFields(log.Fields{
+				"ctx_id": ctxID,
+			}).WithError(err).Errorf("%s error", name)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Infof("%s stopped", name)
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
 // SetDownlinkTXInfoDataRate sets the DownlinkTXInfo data-rate.
 func SetDownlinkTXInfoDataRate(txInfo *gw.DownlinkTXInfo, dr int, b band.Band) error {
 	dataRate, err := b.GetDataRate(dr)
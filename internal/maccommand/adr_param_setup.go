@@ -0,0 +1,59 @@
+package maccommand
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brocaar/chirpstack-network-server/internal/logging"
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/lorawan"
+)
+
+// RequestADRParamSetup modifies the ADR_ACK_LIMIT and ADR_ACK_DELAY
+// exponents used by the device for the ADR backoff.
+func RequestADRParamSetup(limitExp, delayExp int) storage.MACCommandBlock {
+	return storage.MACCommandBlock{
+		CID: lorawan.ADRParamSetupReq,
+		MACCommands: []lorawan.MACCommand{
+			{
+				CID: lorawan.ADRParamSetupReq,
+				Payload: &lorawan.ADRParamSetupReqPayload{
+					ADRParam: lorawan.ADRParam{
+						LimitExp: uint8(limitExp),
+						DelayExp: uint8(delayExp),
+					},
+				},
+			},
+		},
+	}
+}
+
+func handleADRParamSetupAns(ctx context.Context, ds *storage.DeviceSession, block storage.MACCommandBlock, pendingBlock *storage.MACCommandBlock) ([]storage.MACCommandBlock, error) {
+	if len(block.MACCommands) != 1 {
+		return nil, fmt.Errorf("exactly one mac-command expected, got: %d", len(block.MACCommands))
+	}
+
+	if pendingBlock == nil || len(pendingBlock.MACCommands) == 0 {
+		return nil, errors.New("expected pending mac-command")
+	}
+
+	req, ok := pendingBlock.MACCommands[0].Payload.(*lorawan.ADRParamSetupReqPayload)
+	if !ok {
+		return nil, fmt.Errorf("expected *lorawan.ADRParamSetupReqPayload, got %T", pendingBlock.MACCommands[0].Payload)
+	}
+
+	ds.ADRAckLimitExp = int(req.ADRParam.LimitExp)
+	ds.ADRAckDelayExp = int(req.ADRParam.DelayExp)
+
+	log.WithFields(log.Fields{
+		"dev_eui":           ds.DevEUI,
+		"adr_ack_limit_exp": ds.ADRAckLimitExp,
+		"adr_ack_delay_exp": ds.ADRAckDelayExp,
+		"ctx_id":            ctx.Value(logging.ContextIDKey),
+	}).Info("adr_param_setup request acknowledged")
+
+	return nil, nil
+}
@@ -0,0 +1,105 @@
+package maccommand
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/lorawan"
+)
+
+func TestADRParamSetup(t *testing.T) {
+	t.Run("ADRParamSetupReq", func(t *testing.T) {
+		assert := require.New(t)
+
+		assert.Equal(storage.MACCommandBlock{
+			CID: lorawan.ADRParamSetupReq,
+			MACCommands: []lorawan.MACCommand{
+				{
+					CID: lorawan.ADRParamSetupReq,
+					Payload: &lorawan.ADRParamSetupReqPayload{
+						ADRParam: lorawan.ADRParam{
+							LimitExp: 7,
+							DelayExp: 4,
+						},
+					},
+				},
+			},
+		}, RequestADRParamSetup(7, 4))
+	})
+
+	t.Run("handleADRParamSetupAns", func(t *testing.T) {
+		tests := []struct {
+			Name                    string
+			DeviceSession           storage.DeviceSession
+			ReceivedMACCommandBlock storage.MACCommandBlock
+			PendingMACCommandBlock  *storage.MACCommandBlock
+			ExpectedDeviceSession   storage.DeviceSession
+			ExpectedError           error
+		}{
+			{
+				Name: "acknowledged",
+				DeviceSession: storage.DeviceSession{
+					ADRAckLimitExp: storage.DefaultADRAckLimitExp,
+					ADRAckDelayExp: storage.DefaultADRAckDelayExp,
+				},
+				ReceivedMACCommandBlock: storage.MACCommandBlock{
+					CID: lorawan.ADRParamSetupAns,
+					MACCommands: []lorawan.MACCommand{
+						{CID: lorawan.ADRParamSetupAns},
+					},
+				},
+				PendingMACCommandBlock: &storage.MACCommandBlock{
+					CID: lorawan.ADRParamSetupReq,
+					MACCommands: []lorawan.MACCommand{
+						{
+							CID: lorawan.ADRParamSetupReq,
+							Payload: &lorawan.ADRParamSetupReqPayload{
+								ADRParam: lorawan.ADRParam{
+									LimitExp: 7,
+									DelayExp: 4,
+								},
+							},
+						},
+					},
+				},
+				ExpectedDeviceSession: storage.DeviceSession{
+					ADRAckLimitExp: 7,
+					ADRAckDelayExp: 4,
+				},
+			},
+			{
+				Name: "acknowledged, but nothing pending",
+				DeviceSession: storage.DeviceSession{
+					ADRAckLimitExp: storage.DefaultADRAckLimitExp,
+					ADRAckDelayExp: storage.DefaultADRAckDelayExp,
+				},
+				ReceivedMACCommandBlock: storage.MACCommandBlock{
+					CID: lorawan.ADRParamSetupAns,
+					MACCommands: []lorawan.MACCommand{
+						{CID: lorawan.ADRParamSetupAns},
+					},
+				},
+				ExpectedError: errors.New("expected pending mac-command"),
+			},
+		}
+
+		for _, tst := range tests {
+			t.Run(tst.Name, func(t *testing.T) {
+				assert := require.New(t)
+
+				ans, err := handleADRParamSetupAns(context.Background(), &tst.DeviceSession, tst.ReceivedMACCommandBlock, tst.PendingMACCommandBlock)
+				if tst.ExpectedError != nil {
+					assert.Equal(tst.ExpectedError.Error(), err.Error())
+					return
+				}
+				assert.NoError(err)
+				assert.Nil(ans)
+				assert.Equal(tst.ExpectedDeviceSession, tst.DeviceSession)
+			})
+		}
+	})
+}
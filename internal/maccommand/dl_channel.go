@@ -0,0 +1,112 @@
+package maccommand
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brocaar/chirpstack-network-server/internal/logging"
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/lorawan"
+)
+
+// RequestDlChannels modifies the RX1 downlink frequencies of the given
+// uplink channels in case of changes between the current and wanted
+// frequencies (both indexed by uplink channel index). The max number of
+// channels to modify must be given. In case of no changes, nil is returned.
+func RequestDlChannels(maxChannels int, currentFrequencies, wantedFrequencies map[int]int) *storage.MACCommandBlock {
+	var out []lorawan.MACCommand
+
+	// sort by channel index
+	var wantedChannelNumbers []int
+	for i := range wantedFrequencies {
+		wantedChannelNumbers = append(wantedChannelNumbers, i)
+	}
+	sort.Ints(wantedChannelNumbers)
+
+	for _, i := range wantedChannelNumbers {
+		wanted := wantedFrequencies[i]
+		if current, ok := currentFrequencies[i]; !ok || current != wanted {
+			out = append(out, lorawan.MACCommand{
+				CID: lorawan.DlChannelReq,
+				Payload: &lorawan.DlChannelReqPayload{
+					ChIndex: uint8(i),
+					Freq:    uint32(wanted),
+				},
+			})
+		}
+	}
+
+	if len(out) > maxChannels {
+		out = out[0:maxChannels]
+	}
+
+	if len(out) == 0 {
+		return nil
+	}
+
+	return &storage.MACCommandBlock{
+		CID:         lorawan.DlChannelReq,
+		MACCommands: storage.MACCommands(out),
+	}
+}
+
+func handleDlChannelAns(ctx context.Context, ds *storage.DeviceSession, block storage.MACCommandBlock, pending *storage.MACCommandBlock) ([]storage.MACCommandBlock, error) {
+	if len(block.MACCommands) == 0 {
+		return nil, errors.New("at least 1 mac-command expected, got none")
+	}
+
+	if pending == nil || len(pending.MACCommands) == 0 {
+		return nil, errors.New("expected pending mac-command")
+	}
+
+	if len(block.MACCommands) != len(pending.MACCommands) {
+		return nil, fmt.Errorf("received %d mac-command answers, but requested %d", len(block.MACCommands), len(pending.MACCommands))
+	}
+
+	for i := range block.MACCommands {
+		pl, ok := block.MACCommands[i].Payload.(*lorawan.DlChannelAnsPayload)
+		if !ok {
+			return nil, fmt.Errorf("expected *lorawan.DlChannelAnsPayload, got %T", block.MACCommands[i].Payload)
+		}
+
+		pendingPL, ok := pending.MACCommands[i].Payload.(*lorawan.DlChannelReqPayload)
+		if !ok {
+			return nil, fmt.Errorf("expected *lorawan.DlChannelReqPayload, got %T", pending.MACCommands[i].Payload)
+		}
+
+		if pl.UplinkFrequencyExists && pl.ChannelFrequencyOK {
+			// reset the error counter
+			delete(ds.MACCommandErrorCount, lorawan.DlChannelAns)
+
+			if ds.DownlinkFrequencies == nil {
+				ds.DownlinkFrequencies = make(map[int]int)
+			}
+			ds.DownlinkFrequencies[int(pendingPL.ChIndex)] = int(pendingPL.Freq)
+
+			log.WithFields(log.Fields{
+				"frequency": pendingPL.Freq,
+				"channel":   pendingPL.ChIndex,
+				"ctx_id":    ctx.Value(logging.ContextIDKey),
+				"dev_eui":   ds.DevEUI,
+			}).Info("dl_channel request acknowledged")
+		} else {
+			// increase error counter
+			ds.MACCommandErrorCount[lorawan.DlChannelAns]++
+
+			log.WithFields(log.Fields{
+				"frequency":               pendingPL.Freq,
+				"channel":                 pendingPL.ChIndex,
+				"uplink_frequency_exists": pl.UplinkFrequencyExists,
+				"channel_frequency_ok":    pl.ChannelFrequencyOK,
+				"ctx_id":                  ctx.Value(logging.ContextIDKey),
+				"dev_eui":                 ds.DevEUI,
+			}).Warning("dl_channel request not acknowledged")
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,227 @@
+package maccommand
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/lorawan"
+)
+
+func TestDlChannel(t *testing.T) {
+	t.Run("DlChannelReq", func(t *testing.T) {
+		tests := []struct {
+			Name                    string
+			CurrentFrequencies      map[int]int
+			WantedFrequencies       map[int]int
+			ExpectedMACCommandBlock *storage.MACCommandBlock
+		}{
+			{
+				Name:               "configure downlink frequencies",
+				CurrentFrequencies: map[int]int{3: 869525000},
+				WantedFrequencies: map[int]int{
+					3: 869525000,
+					4: 869525000,
+					5: 869525000,
+				},
+				ExpectedMACCommandBlock: &storage.MACCommandBlock{
+					CID: lorawan.DlChannelReq,
+					MACCommands: storage.MACCommands{
+						{
+							CID: lorawan.DlChannelReq,
+							Payload: &lorawan.DlChannelReqPayload{
+								ChIndex: 4,
+								Freq:    869525000,
+							},
+						},
+						{
+							CID: lorawan.DlChannelReq,
+							Payload: &lorawan.DlChannelReqPayload{
+								ChIndex: 5,
+								Freq:    869525000,
+							},
+						},
+					},
+				},
+			},
+			{
+				Name:               "modify downlink frequency",
+				CurrentFrequencies: map[int]int{3: 869525000},
+				WantedFrequencies:  map[int]int{3: 867100000},
+				ExpectedMACCommandBlock: &storage.MACCommandBlock{
+					CID: lorawan.DlChannelReq,
+					MACCommands: storage.MACCommands{
+						{
+							CID: lorawan.DlChannelReq,
+							Payload: &lorawan.DlChannelReqPayload{
+								ChIndex: 3,
+								Freq:    867100000,
+							},
+						},
+					},
+				},
+			},
+			{
+				Name:               "max channels exceeded",
+				CurrentFrequencies: map[int]int{},
+				WantedFrequencies: map[int]int{
+					3: 869525000,
+					4: 869525000,
+					5: 869525000,
+					6: 869525000,
+				},
+				ExpectedMACCommandBlock: &storage.MACCommandBlock{
+					CID: lorawan.DlChannelReq,
+					MACCommands: storage.MACCommands{
+						{
+							CID: lorawan.DlChannelReq,
+							Payload: &lorawan.DlChannelReqPayload{
+								ChIndex: 3,
+								Freq:    869525000,
+							},
+						},
+						{
+							CID: lorawan.DlChannelReq,
+							Payload: &lorawan.DlChannelReqPayload{
+								ChIndex: 4,
+								Freq:    869525000,
+							},
+						},
+						{
+							CID: lorawan.DlChannelReq,
+							Payload: &lorawan.DlChannelReqPayload{
+								ChIndex: 5,
+								Freq:    869525000,
+							},
+						},
+					},
+				},
+			},
+			{
+				Name:               "nothing to change",
+				CurrentFrequencies: map[int]int{3: 869525000},
+				WantedFrequencies:  map[int]int{3: 869525000},
+			},
+		}
+
+		for _, tst := range tests {
+			t.Run(tst.Name, func(t *testing.T) {
+				assert := require.New(t)
+				assert.Equal(tst.ExpectedMACCommandBlock, RequestDlChannels(3, tst.CurrentFrequencies, tst.WantedFrequencies))
+			})
+		}
+	})
+
+	t.Run("handleDlChannelAns", func(t *testing.T) {
+		pending := &storage.MACCommandBlock{
+			CID: lorawan.DlChannelReq,
+			MACCommands: storage.MACCommands{
+				{
+					CID: lorawan.DlChannelReq,
+					Payload: &lorawan.DlChannelReqPayload{
+						ChIndex: 3,
+						Freq:    869525000,
+					},
+				},
+			},
+		}
+
+		tests := []struct {
+			Name                    string
+			DeviceSession           storage.DeviceSession
+			ReceivedMACCommandBlock storage.MACCommandBlock
+			PendingMACCommandBlock  *storage.MACCommandBlock
+			ExpectedDeviceSession   storage.DeviceSession
+			ExpectedError           error
+		}{
+			{
+				Name: "acknowledged",
+				DeviceSession: storage.DeviceSession{
+					MACCommandErrorCount: map[lorawan.CID]int{
+						lorawan.DlChannelAns: 1,
+					},
+				},
+				ReceivedMACCommandBlock: storage.MACCommandBlock{
+					CID: lorawan.DlChannelAns,
+					MACCommands: storage.MACCommands{
+						{
+							CID: lorawan.DlChannelAns,
+							Payload: &lorawan.DlChannelAnsPayload{
+								UplinkFrequencyExists: true,
+								ChannelFrequencyOK:    true,
+							},
+						},
+					},
+				},
+				PendingMACCommandBlock: pending,
+				ExpectedDeviceSession: storage.DeviceSession{
+					DownlinkFrequencies: map[int]int{
+						3: 869525000,
+					},
+					MACCommandErrorCount: map[lorawan.CID]int{},
+				},
+			},
+			{
+				Name: "uplink frequency does not exist (nack)",
+				DeviceSession: storage.DeviceSession{
+					MACCommandErrorCount: map[lorawan.CID]int{},
+				},
+				ReceivedMACCommandBlock: storage.MACCommandBlock{
+					CID: lorawan.DlChannelAns,
+					MACCommands: storage.MACCommands{
+						{
+							CID: lorawan.DlChannelAns,
+							Payload: &lorawan.DlChannelAnsPayload{
+								UplinkFrequencyExists: false,
+								ChannelFrequencyOK:    true,
+							},
+						},
+					},
+				},
+				PendingMACCommandBlock: pending,
+				ExpectedDeviceSession: storage.DeviceSession{
+					MACCommandErrorCount: map[lorawan.CID]int{
+						lorawan.DlChannelAns: 1,
+					},
+				},
+			},
+			{
+				Name: "acknowledged, but nothing pending",
+				DeviceSession: storage.DeviceSession{
+					MACCommandErrorCount: map[lorawan.CID]int{},
+				},
+				ReceivedMACCommandBlock: storage.MACCommandBlock{
+					CID: lorawan.DlChannelAns,
+					MACCommands: storage.MACCommands{
+						{
+							CID: lorawan.DlChannelAns,
+							Payload: &lorawan.DlChannelAnsPayload{
+								UplinkFrequencyExists: true,
+								ChannelFrequencyOK:    true,
+							},
+						},
+					},
+				},
+				ExpectedError: errors.New("expected pending mac-command"),
+			},
+		}
+
+		for _, tst := range tests {
+			t.Run(tst.Name, func(t *testing.T) {
+				assert := require.New(t)
+
+				ans, err := handleDlChannelAns(context.Background(), &tst.DeviceSession, tst.ReceivedMACCommandBlock, tst.PendingMACCommandBlock)
+				if tst.ExpectedError != nil {
+					assert.Equal(tst.ExpectedError.Error(), err.Error())
+					return
+				}
+				assert.NoError(err)
+				assert.Nil(ans)
+				assert.Equal(tst.ExpectedDeviceSession, tst.DeviceSession)
+			})
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package maccommand
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brocaar/chirpstack-network-server/internal/logging"
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/lorawan"
+)
+
+// maxMaxDCycle defines the max. MaxDCycle value (the field is 4 bits).
+const maxMaxDCycle = 15
+
+// GetMaxDCycle returns the MaxDCycle value for the given max. duty-cycle
+// (in percent). The aggregated duty-cycle of the device is 1 / 2^MaxDCycle,
+// the returned value is the smallest value which does not exceed the given
+// duty-cycle. In case the duty-cycle is not set (<= 0) or >= 100%, 0 (no
+// limitation) is returned.
+func GetMaxDCycle(maxDutyCycle int) int {
+	if maxDutyCycle <= 0 {
+		return 0
+	}
+
+	var maxDCycle int
+	for maxDCycle < maxMaxDCycle && 100 > maxDutyCycle*(1<<uint(maxDCycle)) {
+		maxDCycle++
+	}
+
+	return maxDCycle
+}
+
+// RequestDutyCycle modifies the max. aggregated duty-cycle of the device.
+func RequestDutyCycle(maxDCycle int) storage.MACCommandBlock {
+	return storage.MACCommandBlock{
+		CID: lorawan.DutyCycleReq,
+		MACCommands: []lorawan.MACCommand{
+			{
+				CID: lorawan.DutyCycleReq,
+				Payload: &lorawan.DutyCycleReqPayload{
+					MaxDCycle: uint8(maxDCycle),
+				},
+			},
+		},
+	}
+}
+
+func handleDutyCycleAns(ctx context.Context, ds *storage.DeviceSession, block storage.MACCommandBlock, pendingBlock *storage.MACCommandBlock) ([]storage.MACCommandBlock, error) {
+	if len(block.MACCommands) != 1 {
+		return nil, fmt.Errorf("exactly one mac-command expected, got: %d", len(block.MACCommands))
+	}
+
+	if pendingBlock == nil || len(pendingBlock.MACCommands) == 0 {
+		return nil, errors.New("expected pending mac-command")
+	}
+
+	req, ok := pendingBlock.MACCommands[0].Payload.(*lorawan.DutyCycleReqPayload)
+	if !ok {
+		return nil, fmt.Errorf("expected *lorawan.DutyCycleReqPayload, got %T", pendingBlock.MACCommands[0].Payload)
+	}
+
+	ds.MaxDCycle = int(req.MaxDCycle)
+	ds.DutyCycleExceeded = false
+
+	log.WithFields(log.Fields{
+		"dev_eui":     ds.DevEUI,
+		"max_d_cycle": ds.MaxDCycle,
+		"ctx_id":      ctx.Value(logging.ContextIDKey),
+	}).Info("duty_cycle request acknowledged")
+
+	return nil, nil
+}
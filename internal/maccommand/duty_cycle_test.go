@@ -0,0 +1,114 @@
+package maccommand
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/lorawan"
+)
+
+func TestDutyCycle(t *testing.T) {
+	t.Run("GetMaxDCycle", func(t *testing.T) {
+		tests := []struct {
+			MaxDutyCycle      int
+			ExpectedMaxDCycle int
+		}{
+			{0, 0},
+			{-1, 0},
+			{100, 0},
+			{150, 0},
+			{50, 1},
+			{30, 2},
+			{10, 4},
+			{1, 7},
+		}
+
+		for _, tst := range tests {
+			assert := require.New(t)
+			assert.Equal(tst.ExpectedMaxDCycle, GetMaxDCycle(tst.MaxDutyCycle), "max duty-cycle: %d", tst.MaxDutyCycle)
+		}
+	})
+
+	t.Run("DutyCycleReq", func(t *testing.T) {
+		assert := require.New(t)
+
+		assert.Equal(storage.MACCommandBlock{
+			CID: lorawan.DutyCycleReq,
+			MACCommands: []lorawan.MACCommand{
+				{
+					CID: lorawan.DutyCycleReq,
+					Payload: &lorawan.DutyCycleReqPayload{
+						MaxDCycle: 4,
+					},
+				},
+			},
+		}, RequestDutyCycle(4))
+	})
+
+	t.Run("handleDutyCycleAns", func(t *testing.T) {
+		tests := []struct {
+			Name                    string
+			DeviceSession           storage.DeviceSession
+			ReceivedMACCommandBlock storage.MACCommandBlock
+			PendingMACCommandBlock  *storage.MACCommandBlock
+			ExpectedDeviceSession   storage.DeviceSession
+			ExpectedError           error
+		}{
+			{
+				Name: "acknowledged",
+				DeviceSession: storage.DeviceSession{
+					DutyCycleExceeded: true,
+				},
+				ReceivedMACCommandBlock: storage.MACCommandBlock{
+					CID: lorawan.DutyCycleAns,
+					MACCommands: []lorawan.MACCommand{
+						{CID: lorawan.DutyCycleAns},
+					},
+				},
+				PendingMACCommandBlock: &storage.MACCommandBlock{
+					CID: lorawan.DutyCycleReq,
+					MACCommands: []lorawan.MACCommand{
+						{
+							CID: lorawan.DutyCycleReq,
+							Payload: &lorawan.DutyCycleReqPayload{
+								MaxDCycle: 4,
+							},
+						},
+					},
+				},
+				ExpectedDeviceSession: storage.DeviceSession{
+					MaxDCycle: 4,
+				},
+			},
+			{
+				Name: "acknowledged, but nothing pending",
+				ReceivedMACCommandBlock: storage.MACCommandBlock{
+					CID: lorawan.DutyCycleAns,
+					MACCommands: []lorawan.MACCommand{
+						{CID: lorawan.DutyCycleAns},
+					},
+				},
+				ExpectedError: errors.New("expected pending mac-command"),
+			},
+		}
+
+		for _, tst := range tests {
+			t.Run(tst.Name, func(t *testing.T) {
+				assert := require.New(t)
+
+				ans, err := handleDutyCycleAns(context.Background(), &tst.DeviceSession, tst.ReceivedMACCommandBlock, tst.PendingMACCommandBlock)
+				if tst.ExpectedError != nil {
+					assert.Equal(tst.ExpectedError.Error(), err.Error())
+					return
+				}
+				assert.NoError(err)
+				assert.Nil(ans)
+				assert.Equal(tst.ExpectedDeviceSession, tst.DeviceSession)
+			})
+		}
+	})
+}
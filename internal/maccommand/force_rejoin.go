@@ -0,0 +1,167 @@
+package maccommand
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brocaar/chirpstack-network-server/internal/logging"
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/lorawan"
+)
+
+// ErrForceRejoinNotSupported is returned when the device does not support
+// the ForceRejoinReq mac-command (e.g. a LoRaWAN 1.0 device).
+var ErrForceRejoinNotSupported = errors.New("ForceRejoinReq requires a LoRaWAN 1.1 device")
+
+// RequestForceRejoin returns a mac-command block to request the device to
+// (immediately) send a rejoin-request of the given type.
+func RequestForceRejoin(rejoinType, dr, maxRetries, period int) storage.MACCommandBlock {
+	return storage.MACCommandBlock{
+		CID: lorawan.ForceRejoinReq,
+		MACCommands: []lorawan.MACCommand{
+			{
+				CID: lorawan.ForceRejoinReq,
+				Payload: &lorawan.ForceRejoinReqPayload{
+					Period:     uint8(period),
+					MaxRetries: uint8(maxRetries),
+					RejoinType: uint8(rejoinType),
+					DR:         uint8(dr),
+				},
+			},
+		},
+	}
+}
+
+// ValidateForceRejoinParameters validates the given ForceRejoinReq
+// parameters.
+func ValidateForceRejoinParameters(rejoinType, dr, maxRetries, period int) error {
+	if rejoinType != 0 && rejoinType != 2 {
+		return fmt.Errorf("rejoin type must be 0 or 2, got: %d", rejoinType)
+	}
+	if dr < 0 || dr > 15 {
+		return fmt.Errorf("dr must be between 0 and 15, got: %d", dr)
+	}
+	if maxRetries < 0 || maxRetries > 7 {
+		return fmt.Errorf("max retries must be between 0 and 7, got: %d", maxRetries)
+	}
+	if period < 0 || period > 7 {
+		return fmt.Errorf("period must be between 0 and 7, got: %d", period)
+	}
+	return nil
+}
+
+// EnqueueForceRejoin enqueues a ForceRejoinReq mac-command for the given
+// DevEUI and stores the force-rejoin so that its progress can be tracked.
+// It returns ErrForceRejoinNotSupported in case the device is not activated
+// as a LoRaWAN 1.1 device.
+func EnqueueForceRejoin(ctx context.Context, db sqlx.Execer, devEUI lorawan.EUI64, rejoinType, dr, maxRetries, period int) error {
+	if err := ValidateForceRejoinParameters(rejoinType, dr, maxRetries, period); err != nil {
+		return errors.Wrap(err, "validate parameters error")
+	}
+
+	if err := checkForceRejoinSupported(ctx, devEUI); err != nil {
+		return err
+	}
+
+	if err := createForceRejoin(ctx, db, devEUI, rejoinType, dr, maxRetries, period); err != nil {
+		return err
+	}
+
+	return enqueueForceRejoinMACCommand(ctx, devEUI, rejoinType, dr, maxRetries, period)
+}
+
+// EnqueueForceRejoinForDeviceProfile enqueues a ForceRejoinReq mac-command
+// for all the activated LoRaWAN 1.1 devices using the given device-profile.
+// It returns the DevEUIs for which the mac-command was enqueued.
+// The force-rejoins are stored within a single transaction, the
+// mac-commands are enqueued after this transaction has been committed.
+func EnqueueForceRejoinForDeviceProfile(ctx context.Context, deviceProfileID uuid.UUID, rejoinType, dr, maxRetries, period int) ([]lorawan.EUI64, error) {
+	if err := ValidateForceRejoinParameters(rejoinType, dr, maxRetries, period); err != nil {
+		return nil, errors.Wrap(err, "validate parameters error")
+	}
+
+	devEUIs, err := storage.GetDevEUIsForDeviceProfile(ctx, storage.DB(), deviceProfileID)
+	if err != nil {
+		return nil, errors.Wrap(err, "get devices for device-profile error")
+	}
+
+	var out []lorawan.EUI64
+	for _, devEUI := range devEUIs {
+		err := checkForceRejoinSupported(ctx, devEUI)
+		if err != nil {
+			if errors.Cause(err) == storage.ErrDoesNotExist || err == ErrForceRejoinNotSupported {
+				log.WithFields(log.Fields{
+					"dev_eui": devEUI,
+					"ctx_id":  ctx.Value(logging.ContextIDKey),
+				}).WithError(err).Info("force_rejoin: skipping device")
+				continue
+			}
+			return nil, errors.Wrapf(err, "dev_eui: %s", devEUI)
+		}
+
+		out = append(out, devEUI)
+	}
+
+	err = storage.Transaction(func(tx sqlx.Ext) error {
+		for _, devEUI := range out {
+			if err := createForceRejoin(ctx, tx, devEUI, rejoinType, dr, maxRetries, period); err != nil {
+				return errors.Wrapf(err, "dev_eui: %s", devEUI)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, devEUI := range out {
+		if err := enqueueForceRejoinMACCommand(ctx, devEUI, rejoinType, dr, maxRetries, period); err != nil {
+			return nil, errors.Wrapf(err, "dev_eui: %s", devEUI)
+		}
+	}
+
+	return out, nil
+}
+
+func checkForceRejoinSupported(ctx context.Context, devEUI lorawan.EUI64) error {
+	ds, err := storage.GetDeviceSession(ctx, devEUI)
+	if err != nil {
+		return errors.Wrap(err, "get device-session error")
+	}
+
+	if ds.GetMACVersion() == lorawan.LoRaWAN1_0 {
+		return ErrForceRejoinNotSupported
+	}
+
+	return nil
+}
+
+func createForceRejoin(ctx context.Context, db sqlx.Execer, devEUI lorawan.EUI64, rejoinType, dr, maxRetries, period int) error {
+	if err := storage.CreateForceRejoin(ctx, db, &storage.ForceRejoin{
+		DevEUI:     devEUI,
+		RejoinType: rejoinType,
+		DR:         dr,
+		MaxRetries: maxRetries,
+		Period:     period,
+	}); err != nil {
+		return errors.Wrap(err, "create force-rejoin error")
+	}
+
+	return nil
+}
+
+func enqueueForceRejoinMACCommand(ctx context.Context, devEUI lorawan.EUI64, rejoinType, dr, maxRetries, period int) error {
+	block := RequestForceRejoin(rejoinType, dr, maxRetries, period)
+	block.External = true
+
+	if err := storage.CreateMACCommandQueueItem(ctx, devEUI, block); err != nil {
+		return errors.Wrap(err, "create mac-command queue item error")
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package maccommand
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/chirpstack-network-server/internal/test"
+	"github.com/brocaar/lorawan"
+)
+
+type ForceRejoinTestSuite struct {
+	suite.Suite
+
+	deviceProfile storage.DeviceProfile
+	devices       []storage.Device
+}
+
+func (ts *ForceRejoinTestSuite) SetupTest() {
+	assert := require.New(ts.T())
+	conf := test.GetConfig()
+	assert.NoError(storage.Setup(conf))
+
+	test.MustResetDB(storage.DB().DB)
+	storage.RedisClient().FlushAll()
+
+	rp := storage.RoutingProfile{}
+	assert.NoError(storage.CreateRoutingProfile(context.Background(), storage.DB(), &rp))
+
+	sp := storage.ServiceProfile{}
+	assert.NoError(storage.CreateServiceProfile(context.Background(), storage.DB(), &sp))
+
+	ts.deviceProfile = storage.DeviceProfile{}
+	assert.NoError(storage.CreateDeviceProfile(context.Background(), storage.DB(), &ts.deviceProfile))
+
+	ts.devices = nil
+	for i, macVersion := range []string{"1.1.0", "1.0.3", ""} {
+		d := storage.Device{
+			RoutingProfileID: rp.ID,
+			ServiceProfileID: sp.ID,
+			DeviceProfileID:  ts.deviceProfile.ID,
+			DevEUI:           lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, byte(i)},
+			Mode:             storage.DeviceModeA,
+		}
+		assert.NoError(storage.CreateDevice(context.Background(), storage.DB(), &d))
+		ts.devices = append(ts.devices, d)
+
+		// the last device is not activated
+		if macVersion == "" {
+			continue
+		}
+
+		assert.NoError(storage.SaveDeviceSession(context.Background(), storage.DeviceSession{
+			DevEUI:     d.DevEUI,
+			DevAddr:    lorawan.DevAddr{1, 2, 3, byte(i)},
+			MACVersion: macVersion,
+		}))
+	}
+}
+
+func (ts *ForceRejoinTestSuite) TestRequestForceRejoin() {
+	assert := require.New(ts.T())
+
+	assert.Equal(storage.MACCommandBlock{
+		CID: lorawan.ForceRejoinReq,
+		MACCommands: []lorawan.MACCommand{
+			{
+				CID: lorawan.ForceRejoinReq,
+				Payload: &lorawan.ForceRejoinReqPayload{
+					Period:     1,
+					MaxRetries: 2,
+					RejoinType: 2,
+					DR:         3,
+				},
+			},
+		},
+	}, RequestForceRejoin(2, 3, 2, 1))
+}
+
+func (ts *ForceRejoinTestSuite) TestEnqueueForceRejoin() {
+	ts.T().Run("Invalid rejoin type", func(t *testing.T) {
+		assert := require.New(t)
+		assert.Error(EnqueueForceRejoin(context.Background(), storage.DB(), ts.devices[0].DevEUI, 1, 3, 2, 1))
+	})
+
+	ts.T().Run("LoRaWAN 1.0 device", func(t *testing.T) {
+		assert := require.New(t)
+		assert.Equal(ErrForceRejoinNotSupported, EnqueueForceRejoin(context.Background(), storage.DB(), ts.devices[1].DevEUI, 2, 3, 2, 1))
+	})
+
+	ts.T().Run("LoRaWAN 1.1 device", func(t *testing.T) {
+		assert := require.New(t)
+		devEUI := ts.devices[0].DevEUI
+
+		assert.NoError(EnqueueForceRejoin(context.Background(), storage.DB(), devEUI, 2, 3, 2, 1))
+
+		items, err := storage.GetMACCommandQueueItems(context.Background(), devEUI)
+		assert.NoError(err)
+		block := RequestForceRejoin(2, 3, 2, 1)
+		block.External = true
+		assert.Equal([]storage.MACCommandBlock{block}, items)
+
+		fr, err := storage.GetForceRejoin(context.Background(), storage.DB(), devEUI)
+		assert.NoError(err)
+		assert.Equal(2, fr.RejoinType)
+		assert.Equal(3, fr.DR)
+		assert.Equal(2, fr.MaxRetries)
+		assert.Equal(1, fr.Period)
+		assert.Nil(fr.SentAt)
+		assert.Nil(fr.CompletedAt)
+	})
+}
+
+func (ts *ForceRejoinTestSuite) TestEnqueueForceRejoinForDeviceProfile() {
+	assert := require.New(ts.T())
+
+	devEUIs, err := EnqueueForceRejoinForDeviceProfile(context.Background(), ts.deviceProfile.ID, 0, 3, 2, 1)
+	assert.NoError(err)
+	assert.Equal([]lorawan.EUI64{ts.devices[0].DevEUI}, devEUIs)
+
+	items, err := storage.GetForceRejoinsForDeviceProfile(context.Background(), storage.DB(), ts.deviceProfile.ID)
+	assert.NoError(err)
+	assert.Len(items, 1)
+	assert.Equal(ts.devices[0].DevEUI, items[0].DevEUI)
+
+	for i, d := range ts.devices {
+		items, err := storage.GetMACCommandQueueItems(context.Background(), d.DevEUI)
+		assert.NoError(err)
+
+		// only the LoRaWAN 1.1 device has the mac-command enqueued
+		if i == 0 {
+			assert.Len(items, 1)
+		} else {
+			assert.Len(items, 0)
+		}
+	}
+}
+
+func TestForceRejoin(t *testing.T) {
+	suite.Run(t, new(ForceRejoinTestSuite))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/brocaar/chirpstack-network-server/internal/backend/integration"
 	"github.com/brocaar/chirpstack-network-server/internal/band"
 	"github.com/brocaar/chirpstack-network-server/internal/logging"
 	"github.com/brocaar/chirpstack-network-server/internal/storage"
@@ -75,6 +76,12 @@ func handleLinkADRAns(ctx context.Context, ds *storage.DeviceSession, block stor
 			"ctx_id":           ctx.Value(logging.ContextIDKey),
 		}).Info("link_adr request acknowledged")
 
+		integration.PublishEvent(ctx, integration.Event{
+			Type:    integration.EventADR,
+			DevEUI:  ds.DevEUI,
+			Message: integration.ADRMessage(ds.DevEUI, ds.DR, ds.TXPowerIndex, int(ds.NbTrans), chans),
+		})
+
 	} else {
 		// increase the error counter
 		ds.MACCommandErrorCount[lorawan.LinkADRAns]++
@@ -41,6 +41,12 @@ func Handle(ctx context.Context, ds *storage.DeviceSession, dp storage.DevicePro
 		return handleRejoinParamSetupAns(ctx, ds, block, pending)
 	case lorawan.DeviceModeInd:
 		return handleDeviceModeInd(ctx, ds, block)
+	case lorawan.ADRParamSetupAns:
+		return handleADRParamSetupAns(ctx, ds, block, pending)
+	case lorawan.DlChannelAns:
+		return handleDlChannelAns(ctx, ds, block, pending)
+	case lorawan.DutyCycleAns:
+		return handleDutyCycleAns(ctx, ds, block, pending)
 	default:
 		return nil, fmt.Errorf("undefined CID %d", block.CID)
 	}
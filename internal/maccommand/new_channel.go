@@ -93,6 +93,10 @@ func handleNewChannelAns(ctx context.Context, ds *storage.DeviceSession, block s
 				MaxDR:     int(pendingPL.MaxDR),
 			}
 
+			// the NewChannelReq resets the downlink frequency of the channel
+			// to the uplink frequency
+			delete(ds.DownlinkFrequencies, int(pendingPL.ChIndex))
+
 			var found bool
 			for _, i := range ds.EnabledUplinkChannels {
 				if i == int(pendingPL.ChIndex) {
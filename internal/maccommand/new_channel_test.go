@@ -217,6 +217,9 @@ func TestNewChannel(t *testing.T) {
 					ExtraUplinkChannels: map[int]band.Channel{
 						3: band.Channel{Frequency: 868700000, MinDR: 3, MaxDR: 5},
 					},
+					DownlinkFrequencies: map[int]int{
+						3: 869525000,
+					},
 					MACCommandErrorCount: map[lorawan.CID]int{
 						lorawan.NewChannelAns: 1,
 					},
@@ -252,6 +255,7 @@ func TestNewChannel(t *testing.T) {
 					ExtraUplinkChannels: map[int]band.Channel{
 						3: band.Channel{Frequency: 868600000, MinDR: 3, MaxDR: 5},
 					},
+					DownlinkFrequencies:  map[int]int{},
 					MACCommandErrorCount: map[lorawan.CID]int{},
 				},
 			},
@@ -74,6 +74,8 @@ func TestReset(t *testing.T) {
 					MinSupportedTXPowerIndex: 0,
 					MaxSupportedTXPowerIndex: 0,
 					NbTrans:                  1,
+					ADRAckLimitExp:           storage.DefaultADRAckLimitExp,
+					ADRAckDelayExp:           storage.DefaultADRAckDelayExp,
 					EnabledUplinkChannels:    []int{0, 1, 2},
 					ChannelFrequencies:       []int{868100000, 868300000, 868500000},
 					PingSlotNb:               4096,
@@ -1,24 +1,187 @@
 package monitoring
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
+	"sync"
+	"time"
 
-	"github.com/brocaar/chirpstack-network-server/internal/storage"
 	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
 )
 
-func healthCheckHandlerFunc(w http.ResponseWriter, r *http.Request) {
-	_, err := storage.RedisClient().Ping().Result()
-	if err != nil {
-		w.WriteHeader(http.StatusServiceUnavailable)
-		w.Write([]byte(errors.Wrap(err, "redis ping error").Error()))
+// healthCheckTimeout defines the max. duration of the health-checks.
+const healthCheckTimeout = 5 * time.Second
+
+// Health-check statuses.
+const (
+	StatusOK          = "ok"
+	StatusError       = "error"
+	StatusDegraded    = "degraded"
+	StatusUnavailable = "unavailable"
+)
+
+// CheckFunc defines a component health-check function. It must return an
+// error when the component is not healthy.
+type CheckFunc func(ctx context.Context) error
+
+// HealthChecker is implemented by components (e.g. gateway backends) which
+// are able to report their health.
+type HealthChecker interface {
+	HealthCheck() error
+}
+
+type check struct {
+	name     string
+	critical bool
+	f        CheckFunc
+}
+
+// ComponentStatus contains the health-check result of a single component.
+type ComponentStatus struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// HealthStatus contains the health-check result of all components.
+type HealthStatus struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentStatus `json:"components"`
+}
+
+var (
+	checksMux       sync.RWMutex
+	livenessChecks  []check
+	readinessChecks = []check{
+		{name: "redis", critical: true, f: redisCheck},
+		{name: "postgresql", critical: true, f: postgreSQLCheck},
 	}
+)
+
+// RegisterLivenessCheck registers a health-check which is used to determine
+// if the network-server is alive. A failing liveness check means that the
+// network-server must be restarted. Liveness checks are also included in
+// the readiness checks.
+func RegisterLivenessCheck(name string, f CheckFunc) {
+	checksMux.Lock()
+	defer checksMux.Unlock()
 
-	err = storage.DB().Ping()
+	livenessChecks = append(livenessChecks, check{name: name, critical: true, f: f})
+}
+
+// RegisterReadinessCheck registers a health-check which is used to determine
+// if the network-server is ready to handle traffic. When critical is false,
+// a failing check only degrades the status, the network-server is still
+// reported as ready.
+func RegisterReadinessCheck(name string, critical bool, f CheckFunc) {
+	checksMux.Lock()
+	defer checksMux.Unlock()
+
+	readinessChecks = append(readinessChecks, check{name: name, critical: critical, f: f})
+}
+
+func livenessHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	checksMux.RLock()
+	checks := livenessChecks
+	checksMux.RUnlock()
+
+	writeHealthStatus(w, runChecks(r.Context(), checks))
+}
+
+func readinessHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	checksMux.RLock()
+	checks := append(append([]check{}, readinessChecks...), livenessChecks...)
+	checksMux.RUnlock()
+
+	writeHealthStatus(w, runChecks(r.Context(), checks))
+}
+
+func writeHealthStatus(w http.ResponseWriter, status HealthStatus) {
+	b, err := json.Marshal(status)
 	if err != nil {
+		log.WithError(err).Error("monitoring: marshal health status error")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if status.Status == StatusUnavailable {
 		w.WriteHeader(http.StatusServiceUnavailable)
-		w.Write([]byte(errors.Wrap(err, "postgresql ping error").Error()))
+	} else {
+		w.WriteHeader(http.StatusOK)
 	}
+	w.Write(b)
+}
 
-	w.WriteHeader(http.StatusOK)
+// runChecks runs the given checks concurrently and returns the aggregated
+// health status. Checks which do not return within the health-check timeout
+// are reported as failed.
+func runChecks(ctx context.Context, checks []check) HealthStatus {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	type result struct {
+		check check
+		err   error
+	}
+
+	// buffered, so that checks returning after the timeout do not block
+	results := make(chan result, len(checks))
+	for i := range checks {
+		go func(c check) {
+			results <- result{check: c, err: c.f(ctx)}
+		}(checks[i])
+	}
+
+	out := HealthStatus{
+		Status:     StatusOK,
+		Components: make(map[string]ComponentStatus),
+	}
+
+	setStatus := func(c check, err error) {
+		if err == nil {
+			out.Components[c.name] = ComponentStatus{Status: StatusOK}
+			return
+		}
+
+		out.Components[c.name] = ComponentStatus{Status: StatusError, Error: err.Error()}
+		if c.critical {
+			out.Status = StatusUnavailable
+		} else if out.Status == StatusOK {
+			out.Status = StatusDegraded
+		}
+	}
+
+	for range checks {
+		select {
+		case res := <-results:
+			setStatus(res.check, res.err)
+		case <-ctx.Done():
+			for _, c := range checks {
+				if _, ok := out.Components[c.name]; !ok {
+					setStatus(c, errors.New("health-check timeout"))
+				}
+			}
+			return out
+		}
+	}
+
+	return out
+}
+
+func redisCheck(ctx context.Context) error {
+	if _, err := storage.RedisClient().Ping().Result(); err != nil {
+		return errors.Wrap(err, "redis ping error")
+	}
+	return nil
+}
+
+func postgreSQLCheck(ctx context.Context) error {
+	if err := storage.DB().PingContext(ctx); err != nil {
+		return errors.Wrap(err, "postgresql ping error")
+	}
+	return nil
 }
@@ -0,0 +1,63 @@
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunChecks(t *testing.T) {
+	okFunc := func(ctx context.Context) error { return nil }
+	errFunc := func(ctx context.Context) error { return errors.New("boom") }
+
+	tests := []struct {
+		name           string
+		checks         []check
+		expectedStatus string
+	}{
+		{
+			name: "all ok",
+			checks: []check{
+				{name: "a", critical: true, f: okFunc},
+				{name: "b", critical: false, f: okFunc},
+			},
+			expectedStatus: StatusOK,
+		},
+		{
+			name: "non-critical failure",
+			checks: []check{
+				{name: "a", critical: true, f: okFunc},
+				{name: "b", critical: false, f: errFunc},
+			},
+			expectedStatus: StatusDegraded,
+		},
+		{
+			name: "critical failure",
+			checks: []check{
+				{name: "a", critical: true, f: errFunc},
+				{name: "b", critical: false, f: errFunc},
+			},
+			expectedStatus: StatusUnavailable,
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			status := runChecks(context.Background(), tst.checks)
+			assert.Equal(tst.expectedStatus, status.Status)
+			assert.Len(status.Components, len(tst.checks))
+
+			for _, c := range tst.checks {
+				if c.f(context.Background()) == nil {
+					assert.Equal(ComponentStatus{Status: StatusOK}, status.Components[c.name])
+				} else {
+					assert.Equal(ComponentStatus{Status: StatusError, Error: "boom"}, status.Components[c.name])
+				}
+			}
+		})
+	}
+}
@@ -43,9 +43,13 @@ func setupNew(c config.Config) error {
 
 	if c.Monitoring.HealthcheckEndpoint {
 		log.WithFields(log.Fields{
-			"endpoint": "/healthcheck",
-		}).Info("monitoring: registering healthcheck endpoint")
-		mux.HandleFunc("/health", healthCheckHandlerFunc)
+			"endpoints": []string{"/health", "/live", "/ready"},
+		}).Info("monitoring: registering healthcheck endpoints")
+
+		// /health is kept for backwards compatibility
+		mux.HandleFunc("/health", readinessHandlerFunc)
+		mux.HandleFunc("/live", livenessHandlerFunc)
+		mux.HandleFunc("/ready", readinessHandlerFunc)
 	}
 
 	server := http.Server{
@@ -0,0 +1,76 @@
+package roaming
+
+import (
+	"github.com/gofrs/uuid"
+
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/lorawan/backend"
+)
+
+// DeviceProfileToBackend converts the given device-profile into a
+// backend.DeviceProfile.
+func DeviceProfileToBackend(dp storage.DeviceProfile) *backend.DeviceProfile {
+	out := backend.DeviceProfile{
+		DeviceProfileID:   dp.ID.String(),
+		SupportsClassB:    dp.SupportsClassB,
+		ClassBTimeout:     dp.ClassBTimeout,
+		PingSlotPeriod:    dp.PingSlotPeriod,
+		PingSlotDR:        dp.PingSlotDR,
+		PingSlotFreq:      backend.Frequency(dp.PingSlotFreq),
+		SupportsClassC:    dp.SupportsClassC,
+		ClassCTimeout:     dp.ClassCTimeout,
+		MACVersion:        dp.MACVersion,
+		RegParamsRevision: dp.RegParamsRevision,
+		RXDelay1:          dp.RXDelay1,
+		RXDROffset1:       dp.RXDROffset1,
+		RXDataRate2:       dp.RXDataRate2,
+		RXFreq2:           backend.Frequency(dp.RXFreq2),
+		MaxEIRP:           dp.MaxEIRP,
+		MaxDutyCycle:      backend.Percentage(dp.MaxDutyCycle),
+		SupportsJoin:      dp.SupportsJoin,
+		RFRegion:          dp.RFRegion,
+		Supports32bitFCnt: dp.Supports32bitFCnt,
+	}
+
+	for _, f := range dp.FactoryPresetFreqs {
+		out.FactoryPresetFreqs = append(out.FactoryPresetFreqs, backend.Frequency(f))
+	}
+
+	return &out
+}
+
+// DeviceProfileFromBackend converts the given backend.DeviceProfile into a
+// device-profile.
+func DeviceProfileFromBackend(dp backend.DeviceProfile) storage.DeviceProfile {
+	out := storage.DeviceProfile{
+		SupportsClassB:    dp.SupportsClassB,
+		ClassBTimeout:     dp.ClassBTimeout,
+		PingSlotPeriod:    dp.PingSlotPeriod,
+		PingSlotDR:        dp.PingSlotDR,
+		PingSlotFreq:      int(dp.PingSlotFreq),
+		SupportsClassC:    dp.SupportsClassC,
+		ClassCTimeout:     dp.ClassCTimeout,
+		MACVersion:        dp.MACVersion,
+		RegParamsRevision: dp.RegParamsRevision,
+		RXDelay1:          dp.RXDelay1,
+		RXDROffset1:       dp.RXDROffset1,
+		RXDataRate2:       dp.RXDataRate2,
+		RXFreq2:           int(dp.RXFreq2),
+		MaxEIRP:           dp.MaxEIRP,
+		MaxDutyCycle:      int(dp.MaxDutyCycle),
+		SupportsJoin:      dp.SupportsJoin,
+		RFRegion:          dp.RFRegion,
+		Supports32bitFCnt: dp.Supports32bitFCnt,
+	}
+
+	// The ID is set by the roaming partner, it is not guaranteed to be an UUID.
+	if id, err := uuid.FromString(dp.DeviceProfileID); err == nil {
+		out.ID = id
+	}
+
+	for _, f := range dp.FactoryPresetFreqs {
+		out.FactoryPresetFreqs = append(out.FactoryPresetFreqs, int(f))
+	}
+
+	return out
+}
@@ -0,0 +1,350 @@
+package roaming
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brocaar/chirpstack-network-server/internal/logging"
+	"github.com/brocaar/lorawan"
+	"github.com/brocaar/lorawan/backend"
+)
+
+// Handover-roaming message types.
+//
+// The lorawan/backend package only implements the passive-roaming messages,
+// therefore the handover-roaming messages are defined here.
+const (
+	HRStartReq backend.MessageType = "HRStartReq"
+	HRStartAns backend.MessageType = "HRStartAns"
+	HRStopReq  backend.MessageType = "HRStopReq"
+	HRStopAns  backend.MessageType = "HRStopAns"
+)
+
+const asyncAnswerKeyTempl = "lora:ns:roaming:async:%d"
+
+// HRStartReqPayload defines the HRStartReq message payload. This is sent by
+// the sNS to the hNS on receiving a join-request of a visiting device.
+// As the sNS serves the device, the RX parameters and CFList of the
+// join-accept are set by the sNS.
+type HRStartReqPayload struct {
+	backend.BasePayload
+	PHYPayload backend.HEXBytes   `json:"PHYPayload"`
+	DevAddr    lorawan.DevAddr    `json:"DevAddr"` // DevAddr assigned by the sNS
+	DLSettings lorawan.DLSettings `json:"DLSettings"`
+	RxDelay    int                `json:"RxDelay"`
+	CFList     backend.HEXBytes   `json:"CFList,omitempty"`
+	ULMetaData backend.ULMetaData `json:"ULMetaData"`
+}
+
+// HRStartAnsPayload defines the HRStartAns message payload.
+type HRStartAnsPayload struct {
+	backend.BasePayloadResult
+	PHYPayload     backend.HEXBytes       `json:"PHYPayload,omitempty"` // join-accept
+	DevEUI         *lorawan.EUI64         `json:"DevEUI,omitempty"`
+	Lifetime       *int                   `json:"Lifetime,omitempty"`
+	SNwkSIntKey    *backend.KeyEnvelope   `json:"SNwkSIntKey,omitempty"` // LoRaWAN 1.1
+	FNwkSIntKey    *backend.KeyEnvelope   `json:"FNwkSIntKey,omitempty"` // LoRaWAN 1.1
+	NwkSEncKey     *backend.KeyEnvelope   `json:"NwkSEncKey,omitempty"`  // LoRaWAN 1.1
+	NwkSKey        *backend.KeyEnvelope   `json:"NwkSKey,omitempty"`     // LoRaWAN 1.0
+	DeviceProfile  *backend.DeviceProfile `json:"DeviceProfile,omitempty"`
+	ServiceProfile *ServiceProfile        `json:"ServiceProfile,omitempty"`
+	DLMetaData     *backend.DLMetaData    `json:"DLMetaData,omitempty"`
+}
+
+// HRStopReqPayload defines the HRStopReq message payload. This is sent by
+// the hNS to the sNS to stop the handover-roaming session.
+type HRStopReqPayload struct {
+	backend.BasePayload
+	DevEUI lorawan.EUI64 `json:"DevEUI"`
+}
+
+// HRStopAnsPayload defines the HRStopAns message payload.
+type HRStopAnsPayload struct {
+	backend.BasePayloadResult
+}
+
+// HandoverClient defines the API client for handover-roaming. It extends the
+// backend.Client with the handover-roaming requests, as these are not
+// implemented by the lorawan/backend package.
+//
+// As the backend.Client only supports TLS certificates stored as files, the
+// roaming requests and answers are sent using the http.Client of the
+// HandoverClient, which is configured using the PEM encoded certificates.
+type HandoverClient interface {
+	backend.Client
+
+	HRStartReq(ctx context.Context, pl HRStartReqPayload) (HRStartAnsPayload, error)
+	HRStopReq(ctx context.Context, pl HRStopReqPayload) (HRStopAnsPayload, error)
+}
+
+type handoverClient struct {
+	backend.Client
+
+	server       string
+	httpClient   *http.Client
+	redisClient  redis.UniversalClient
+	asyncTimeout time.Duration
+}
+
+// newClient creates the API client for the given configuration. The
+// caCert, tlsCert and tlsKey contain the PEM encoded CA certificate and TLS
+// certificate / key, the TLS options of the configuration must not be set.
+func newClient(conf backend.ClientConfig, caCert, tlsCert, tlsKey []byte) (HandoverClient, error) {
+	client, err := backend.NewClient(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient, err := newHTTPClient(caCert, tlsCert, tlsKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "new http client error")
+	}
+
+	return &handoverClient{
+		Client:       client,
+		server:       conf.Server,
+		httpClient:   httpClient,
+		redisClient:  conf.RedisClient,
+		asyncTimeout: conf.AsyncTimeout,
+	}, nil
+}
+
+// HRStartReq sends the HRStartReq.
+func (c *handoverClient) HRStartReq(ctx context.Context, pl HRStartReqPayload) (HRStartAnsPayload, error) {
+	var ans HRStartAnsPayload
+	pl.BasePayload = c.getBasePayload(HRStartReq)
+
+	if err := c.request(ctx, pl.TransactionID, pl, &ans); err != nil {
+		return ans, err
+	}
+
+	return ans, nil
+}
+
+// HRStopReq sends the HRStopReq.
+func (c *handoverClient) HRStopReq(ctx context.Context, pl HRStopReqPayload) (HRStopAnsPayload, error) {
+	var ans HRStopAnsPayload
+	pl.BasePayload = c.getBasePayload(HRStopReq)
+
+	if err := c.request(ctx, pl.TransactionID, pl, &ans); err != nil {
+		return ans, err
+	}
+
+	return ans, nil
+}
+
+// PRStartReq sends the PRStartReq.
+func (c *handoverClient) PRStartReq(ctx context.Context, pl backend.PRStartReqPayload) (backend.PRStartAnsPayload, error) {
+	var ans backend.PRStartAnsPayload
+	pl.BasePayload = c.getBasePayload(backend.PRStartReq)
+
+	if err := c.request(ctx, pl.TransactionID, pl, &ans); err != nil {
+		return ans, err
+	}
+
+	return ans, nil
+}
+
+// PRStopReq sends the PRStopReq.
+func (c *handoverClient) PRStopReq(ctx context.Context, pl backend.PRStopReqPayload) (backend.PRStopAnsPayload, error) {
+	var ans backend.PRStopAnsPayload
+	pl.BasePayload = c.getBasePayload(backend.PRStopReq)
+
+	if err := c.request(ctx, pl.TransactionID, pl, &ans); err != nil {
+		return ans, err
+	}
+
+	return ans, nil
+}
+
+// ProfileReq sends the ProfileReq.
+func (c *handoverClient) ProfileReq(ctx context.Context, pl backend.ProfileReqPayload) (backend.ProfileAnsPayload, error) {
+	var ans backend.ProfileAnsPayload
+	pl.BasePayload = c.getBasePayload(backend.ProfileReq)
+
+	if err := c.request(ctx, pl.TransactionID, pl, &ans); err != nil {
+		return ans, err
+	}
+
+	return ans, nil
+}
+
+// XmitDataReq sends the XmitDataReq.
+func (c *handoverClient) XmitDataReq(ctx context.Context, pl backend.XmitDataReqPayload) (backend.XmitDataAnsPayload, error) {
+	var ans backend.XmitDataAnsPayload
+	pl.BasePayload = c.getBasePayload(backend.XmitDataReq)
+
+	if err := c.request(ctx, pl.TransactionID, pl, &ans); err != nil {
+		return ans, err
+	}
+
+	return ans, nil
+}
+
+// SendAnswer sends the given async answer.
+func (c *handoverClient) SendAnswer(ctx context.Context, pl backend.Answer) error {
+	b, err := json.Marshal(pl)
+	if err != nil {
+		return errors.Wrap(err, "marshal answer error")
+	}
+
+	resp, err := c.post(ctx, b)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// HandleAnswer handles an async answer. The answer is published so that it
+// can be read by the pending request with the same transaction ID.
+func (c *handoverClient) HandleAnswer(ctx context.Context, pl backend.Answer) error {
+	if c.redisClient == nil {
+		return errors.New("redis client must not be nil")
+	}
+
+	b, err := json.Marshal(pl)
+	if err != nil {
+		return errors.Wrap(err, "marshal answer error")
+	}
+
+	key := fmt.Sprintf(asyncAnswerKeyTempl, pl.GetBasePayload().TransactionID)
+
+	pipe := c.redisClient.TxPipeline()
+	pipe.RPush(key, b)
+	pipe.PExpire(key, c.asyncTimeout)
+	if _, err := pipe.Exec(); err != nil {
+		return errors.Wrap(err, "exec error")
+	}
+
+	return nil
+}
+
+func (c *handoverClient) getBasePayload(mType backend.MessageType) backend.BasePayload {
+	return backend.BasePayload{
+		ProtocolVersion: backend.ProtocolVersion1_0,
+		SenderID:        c.GetSenderID(),
+		ReceiverID:      c.GetReceiverID(),
+		TransactionID:   c.GetRandomTransactionID(),
+		MessageType:     mType,
+	}
+}
+
+func (c *handoverClient) request(ctx context.Context, transactionID uint32, req, ans interface{}) error {
+	b, err := json.Marshal(req)
+	if err != nil {
+		return errors.Wrap(err, "marshal request error")
+	}
+
+	log.WithFields(log.Fields{
+		"receiver_id":    c.GetReceiverID(),
+		"server":         c.server,
+		"async_client":   c.IsAsync(),
+		"transaction_id": transactionID,
+		"ctx_id":         ctx.Value(logging.ContextIDKey),
+	}).Debug("roaming: making request")
+
+	resp, err := c.post(ctx, b)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// In case of an async client, the answer is posted to our API and
+	// published by HandleAnswer.
+	if c.IsAsync() {
+		return c.readAsyncAnswer(transactionID, ans)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(ans); err != nil {
+		return errors.Wrap(err, "decode answer error")
+	}
+
+	return nil
+}
+
+func (c *handoverClient) post(ctx context.Context, b []byte) (*http.Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.server, bytes.NewReader(b))
+	if err != nil {
+		return nil, errors.Wrap(err, "new request error")
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, errors.Wrap(err, "http request error")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("expected: 200, got: %d", resp.StatusCode)
+	}
+
+	return resp, nil
+}
+
+func (c *handoverClient) readAsyncAnswer(transactionID uint32, ans interface{}) error {
+	key := fmt.Sprintf(asyncAnswerKeyTempl, transactionID)
+
+	val, err := c.redisClient.BLPop(c.asyncTimeout, key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return errors.New("async answer timeout")
+		}
+		return errors.Wrap(err, "read async answer error")
+	}
+
+	// BLPop returns the key and the value.
+	if len(val) != 2 {
+		return fmt.Errorf("expected 2 values, got: %d", len(val))
+	}
+
+	if err := json.Unmarshal([]byte(val[1]), ans); err != nil {
+		return errors.Wrap(err, "unmarshal answer error")
+	}
+
+	return nil
+}
+
+func newHTTPClient(caCert, tlsCert, tlsKey []byte) (*http.Client, error) {
+	if len(caCert) == 0 && len(tlsCert) == 0 && len(tlsKey) == 0 {
+		return http.DefaultClient, nil
+	}
+
+	tlsConfig := &tls.Config{}
+
+	if len(caCert) != 0 {
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM(caCert) {
+			return nil, errors.New("append ca cert to pool error")
+		}
+
+		tlsConfig.RootCAs = caCertPool
+	}
+
+	if len(tlsCert) != 0 || len(tlsKey) != 0 {
+		cert, err := tls.X509KeyPair(tlsCert, tlsKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse x509 keypair error")
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: tlsConfig,
+		},
+	}, nil
+}
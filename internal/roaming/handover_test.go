@@ -0,0 +1,171 @@
+package roaming
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/brocaar/chirpstack-network-server/internal/config"
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/chirpstack-network-server/internal/test"
+	"github.com/brocaar/lorawan"
+	"github.com/brocaar/lorawan/backend"
+)
+
+func TestHandoverClient(t *testing.T) {
+	assert := require.New(t)
+
+	conf := test.GetConfig()
+	assert.NoError(storage.Setup(conf))
+
+	var client HandoverClient
+	var async bool
+	var requests []HRStopReqPayload
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req HRStopReqPayload
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		requests = append(requests, req)
+
+		ans := HRStopAnsPayload{
+			BasePayloadResult: backend.BasePayloadResult{
+				BasePayload: backend.BasePayload{
+					ProtocolVersion: req.ProtocolVersion,
+					SenderID:        req.ReceiverID,
+					ReceiverID:      req.SenderID,
+					TransactionID:   req.TransactionID,
+					MessageType:     HRStopAns,
+				},
+				Result: backend.Result{
+					ResultCode: backend.Success,
+				},
+			},
+		}
+
+		// The async answer is posted by the receiver to our API, which
+		// hands it to the client.
+		if async {
+			go client.HandleAnswer(context.Background(), ans)
+			return
+		}
+
+		json.NewEncoder(w).Encode(ans)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		Name  string
+		Async bool
+	}{
+		{"Sync", false},
+		{"Async", true},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.Name, func(t *testing.T) {
+			assert := require.New(t)
+			requests = nil
+			async = tst.Async
+
+			conf.NetworkServer.NetID = lorawan.NetID{1, 2, 3}
+			conf.Roaming.Servers = []config.RoamingServer{
+				{
+					NetID:           lorawan.NetID{6, 6, 6},
+					HandoverRoaming: true,
+					Server:          server.URL,
+					Async:           tst.Async,
+					AsyncTimeout:    time.Second,
+				},
+			}
+			assert.NoError(Setup(conf))
+
+			var err error
+			client, err = GetHandoverClientForNetID(lorawan.NetID{6, 6, 6})
+			assert.NoError(err)
+
+			ans, err := client.HRStopReq(context.Background(), HRStopReqPayload{
+				DevEUI: lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8},
+			})
+			assert.NoError(err)
+			assert.Equal(backend.Success, ans.Result.ResultCode)
+
+			assert.Len(requests, 1)
+			assert.Equal(HRStopReq, requests[0].MessageType)
+			assert.Equal("010203", requests[0].SenderID)
+			assert.Equal("060606", requests[0].ReceiverID)
+			assert.Equal(requests[0].TransactionID, ans.TransactionID)
+			assert.Equal(lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8}, requests[0].DevEUI)
+		})
+	}
+}
+
+func TestHandoverClientTLSFromPEM(t *testing.T) {
+	assert := require.New(t)
+
+	conf := test.GetConfig()
+	assert.NoError(storage.Setup(conf))
+	test.MustResetDB(storage.DB().DB)
+
+	var requests []backend.PRStopReqPayload
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req backend.PRStopReqPayload
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		requests = append(requests, req)
+
+		json.NewEncoder(w).Encode(backend.PRStopAnsPayload{
+			BasePayloadResult: backend.BasePayloadResult{
+				BasePayload: backend.BasePayload{
+					ProtocolVersion: req.ProtocolVersion,
+					SenderID:        req.ReceiverID,
+					ReceiverID:      req.SenderID,
+					TransactionID:   req.TransactionID,
+					MessageType:     backend.PRStopAns,
+				},
+				Result: backend.Result{
+					ResultCode: backend.Success,
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	conf.NetworkServer.NetID = lorawan.NetID{1, 2, 3}
+	conf.Roaming.Servers = nil
+	assert.NoError(Setup(conf))
+
+	ra := storage.RoamingAgreement{
+		NetID:          lorawan.NetID{6, 6, 6},
+		PassiveRoaming: true,
+		Server:         server.URL,
+		CACert: string(pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: server.Certificate().Raw,
+		})),
+	}
+	assert.NoError(storage.CreateRoamingAgreement(context.Background(), storage.DB(), &ra))
+	assert.NoError(ReloadAgreements(context.Background()))
+
+	client, err := GetClientForNetID(ra.NetID)
+	assert.NoError(err)
+
+	ans, err := client.PRStopReq(context.Background(), backend.PRStopReqPayload{})
+	assert.NoError(err)
+	assert.Equal(backend.Success, ans.Result.ResultCode)
+
+	assert.Len(requests, 1)
+	assert.Equal(backend.PRStopReq, requests[0].MessageType)
+	assert.Equal("060606", requests[0].ReceiverID)
+}
@@ -1,15 +1,20 @@
 package roaming
 
 import (
+	"context"
 	"encoding/hex"
 	"fmt"
+	"io/ioutil"
+	"sync"
 	"time"
 
 	"github.com/go-redis/redis/v7"
+	"github.com/gofrs/uuid"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/brocaar/chirpstack-network-server/internal/config"
+	"github.com/brocaar/chirpstack-network-server/internal/logging"
 	"github.com/brocaar/chirpstack-network-server/internal/storage"
 	"github.com/brocaar/lorawan"
 	"github.com/brocaar/lorawan/backend"
@@ -19,100 +24,89 @@ import (
 var ErrNoAgreement = errors.New("agreement not found")
 
 type agreement struct {
-	netID                  lorawan.NetID
-	passiveRoaming         bool
-	passiveRoamingLifetime time.Duration
-	passiveRoamingKEKLabel string
-	server                 string
-	client                 backend.Client
+	netID                   lorawan.NetID
+	passiveRoaming          bool
+	passiveRoamingLifetime  time.Duration
+	passiveRoamingKEKLabel  string
+	handoverRoaming         bool
+	handoverRoamingLifetime time.Duration
+	handoverRoamingKEKLabel string
+	client                  HandoverClient
+
+	// updatedAt is set for agreements stored in the database, it is used to
+	// detect changes on reload.
+	updatedAt time.Time
 }
 
 var (
 	resolveNetIDDomainSuffix string
-	roamingEnabled           bool
 	netID                    lorawan.NetID
-	agreements               []agreement
 	keks                     map[string][]byte
-
-	defaultEnabled                bool
-	defaultPassiveRoaming         bool
-	defaultPassiveRoamingLifetime time.Duration
-	defaultPassiveRoamingKEKLabel string
-	defaultAsync                  bool
-	defaultAsyncTimeout           time.Duration
-	defaultServer                 string
-	defaultCACert                 string
-	defaultTLSCert                string
-	defaultTLSKey                 string
+	agreementReloadInterval  time.Duration
+
+	// agreements contains the agreements from the configuration file
+	// (staticAgreements) and the agreements stored in the database.
+	agreementsMux    sync.RWMutex
+	agreements       []agreement
+	staticAgreements []agreement
+	roamingEnabled   bool
+
+	defaultEnabled                 bool
+	defaultPassiveRoaming          bool
+	defaultPassiveRoamingLifetime  time.Duration
+	defaultPassiveRoamingKEKLabel  string
+	defaultHandoverRoaming         bool
+	defaultHandoverRoamingLifetime time.Duration
+	defaultHandoverRoamingKEKLabel string
+	defaultAsync                   bool
+	defaultAsyncTimeout            time.Duration
+	defaultServer                  string
+	defaultCACert                  []byte
+	defaultTLSCert                 []byte
+	defaultTLSKey                  []byte
 )
 
 // Setup configures the roaming package.
 func Setup(c config.Config) error {
 	resolveNetIDDomainSuffix = c.Roaming.ResolveNetIDDomainSuffix
 	netID = c.NetworkServer.NetID
+	agreementReloadInterval = c.Roaming.AgreementReloadInterval
 	keks = make(map[string][]byte)
-	agreements = []agreement{}
+	staticAgreements = []agreement{}
 
 	defaultEnabled = c.Roaming.Default.Enabled
 	defaultPassiveRoaming = c.Roaming.Default.PassiveRoaming
 	defaultPassiveRoamingLifetime = c.Roaming.Default.PassiveRoamingLifetime
 	defaultPassiveRoamingKEKLabel = c.Roaming.Default.PassiveRoamingKEKLabel
+	defaultHandoverRoaming = c.Roaming.Default.HandoverRoaming
+	defaultHandoverRoamingLifetime = c.Roaming.Default.HandoverRoamingLifetime
+	defaultHandoverRoamingKEKLabel = c.Roaming.Default.HandoverRoamingKEKLabel
 	defaultAsync = c.Roaming.Default.Async
 	defaultAsyncTimeout = c.Roaming.Default.AsyncTimeout
 	defaultServer = c.Roaming.Default.Server
-	defaultCACert = c.Roaming.Default.CACert
-	defaultTLSCert = c.Roaming.Default.TLSCert
-	defaultTLSKey = c.Roaming.Default.TLSKey
 
-	if defaultEnabled {
-		roamingEnabled = true
+	var err error
+	defaultCACert, defaultTLSCert, defaultTLSKey, err = readTLSFiles(c.Roaming.Default.CACert, c.Roaming.Default.TLSCert, c.Roaming.Default.TLSKey)
+	if err != nil {
+		return errors.Wrap(err, "read default tls files error")
 	}
 
 	for _, server := range c.Roaming.Servers {
-		roamingEnabled = true
-
-		if server.Server == "" {
-			server.Server = fmt.Sprintf("https://%s%s", server.NetID.String(), resolveNetIDDomainSuffix)
-		}
-
-		log.WithFields(log.Fields{
-			"net_id":                   server.NetID,
-			"passive_roaming":          server.PassiveRoaming,
-			"passive_roaming_lifetime": server.PassiveRoamingLifetime,
-			"server":                   server.Server,
-			"async":                    server.Async,
-			"async_timeout":            server.AsyncTimeout,
-		}).Info("roaming: configuring roaming agreement")
-
-		var redisClient redis.UniversalClient
-		if server.Async {
-			redisClient = storage.RedisClient()
+		caCert, tlsCert, tlsKey, err := readTLSFiles(server.CACert, server.TLSCert, server.TLSKey)
+		if err != nil {
+			return errors.Wrapf(err, "read tls files error for netid: %s", server.NetID)
 		}
 
-		client, err := backend.NewClient(backend.ClientConfig{
-			SenderID:     netID.String(),
-			ReceiverID:   server.NetID.String(),
-			Server:       server.Server,
-			CACert:       server.CACert,
-			TLSCert:      server.TLSCert,
-			TLSKey:       server.TLSKey,
-			AsyncTimeout: server.AsyncTimeout,
-			RedisClient:  redisClient,
-		})
+		a, err := newAgreement(server, caCert, tlsCert, tlsKey)
 		if err != nil {
-			return errors.Wrapf(err, "new roaming client error for netid: %s", server.NetID)
+			return err
 		}
 
-		agreements = append(agreements, agreement{
-			netID:                  server.NetID,
-			passiveRoaming:         server.PassiveRoaming,
-			passiveRoamingLifetime: server.PassiveRoamingLifetime,
-			passiveRoamingKEKLabel: server.PassiveRoamingKEKLabel,
-			client:                 client,
-			server:                 server.Server,
-		})
+		staticAgreements = append(staticAgreements, a)
 	}
 
+	setAgreements(staticAgreements)
+
 	for _, k := range c.Roaming.KEK.Set {
 		kek, err := hex.DecodeString(k.KEK)
 		if err != nil {
@@ -125,21 +119,229 @@ func Setup(c config.Config) error {
 	return nil
 }
 
+// ReloadAgreements reloads the roaming agreements stored in the database.
+// Agreements configured in the configuration file take precedence over the
+// agreements in the database. The client of an agreement is only re-created
+// when the agreement has been updated.
+func ReloadAgreements(ctx context.Context) error {
+	ras, err := storage.GetRoamingAgreements(ctx, storage.DB())
+	if err != nil {
+		return errors.Wrap(err, "get roaming agreements error")
+	}
+
+	agreementsMux.RLock()
+	current := agreements
+	agreementsMux.RUnlock()
+
+	out := make([]agreement, len(staticAgreements))
+	copy(out, staticAgreements)
+
+outer:
+	for _, ra := range ras {
+		for _, a := range staticAgreements {
+			if a.netID == ra.NetID {
+				log.WithFields(log.Fields{
+					"net_id": ra.NetID,
+					"ctx_id": ctx.Value(logging.ContextIDKey),
+				}).Warning("roaming: ignoring database roaming agreement, netid is configured in configuration file")
+				continue outer
+			}
+		}
+
+		for _, a := range current {
+			if a.netID == ra.NetID && a.updatedAt.Equal(ra.UpdatedAt) {
+				out = append(out, a)
+				continue outer
+			}
+		}
+
+		a, err := newRoamingAgreement(ra)
+		if err != nil {
+			log.WithError(err).WithFields(log.Fields{
+				"net_id": ra.NetID,
+				"ctx_id": ctx.Value(logging.ContextIDKey),
+			}).Error("roaming: configure roaming agreement error")
+			continue
+		}
+		out = append(out, a)
+	}
+
+	setAgreements(out)
+
+	return nil
+}
+
+// AgreementReloadLoop reloads the roaming agreements from the database at
+// the configured interval. The loop returns once the given context is
+// cancelled.
+func AgreementReloadLoop(ctx context.Context) {
+	if agreementReloadInterval == 0 {
+		log.Warning("roaming: agreement_reload_interval is not set, roaming agreements will not be reloaded")
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("roaming: agreement reload stopped")
+			return
+		case <-time.After(agreementReloadInterval):
+		}
+
+		reloadCtx := context.Background()
+		ctxID, err := uuid.NewV4()
+		if err != nil {
+			log.WithError(err).Error("get new uuid error")
+		}
+		reloadCtx = context.WithValue(reloadCtx, logging.ContextIDKey, ctxID)
+
+		if err := ReloadAgreements(reloadCtx); err != nil {
+			log.WithFields(log.Fields{
+				"ctx_id": ctxID,
+			}).WithError(err).Error("roaming: reload roaming agreements error")
+		}
+	}
+}
+
+// ValidateRoamingAgreement validates the given roaming agreement by setting
+// up its API client, e.g. to validate the TLS certificates before storing it.
+func ValidateRoamingAgreement(ra storage.RoamingAgreement) error {
+	_, err := newRoamingAgreement(ra)
+	return err
+}
+
+// newRoamingAgreement returns the agreement for the given roaming agreement
+// stored in the database. The TLS certificates and key are stored as PEM.
+func newRoamingAgreement(ra storage.RoamingAgreement) (agreement, error) {
+	a, err := newAgreement(config.RoamingServer{
+		NetID:                   ra.NetID,
+		Async:                   ra.Async,
+		AsyncTimeout:            ra.AsyncTimeout,
+		PassiveRoaming:          ra.PassiveRoaming,
+		PassiveRoamingLifetime:  ra.PassiveRoamingLifetime,
+		PassiveRoamingKEKLabel:  ra.PassiveRoamingKEKLabel,
+		HandoverRoaming:         ra.HandoverRoaming,
+		HandoverRoamingLifetime: ra.HandoverRoamingLifetime,
+		HandoverRoamingKEKLabel: ra.HandoverRoamingKEKLabel,
+		Server:                  ra.Server,
+	}, []byte(ra.CACert), []byte(ra.TLSCert), []byte(ra.TLSKey))
+	if err != nil {
+		return agreement{}, err
+	}
+	a.updatedAt = ra.UpdatedAt
+
+	return a, nil
+}
+
+// newAgreement returns the agreement for the given server configuration. The
+// CACert, TLSCert and TLSKey of the server configuration are not used, the
+// PEM encoded content must be given instead.
+func newAgreement(server config.RoamingServer, caCert, tlsCert, tlsKey []byte) (agreement, error) {
+	if server.Server == "" {
+		server.Server = fmt.Sprintf("https://%s%s", server.NetID.String(), resolveNetIDDomainSuffix)
+	}
+
+	log.WithFields(log.Fields{
+		"net_id":                   server.NetID,
+		"passive_roaming":          server.PassiveRoaming,
+		"passive_roaming_lifetime": server.PassiveRoamingLifetime,
+		"handover_roaming":         server.HandoverRoaming,
+		"server":                   server.Server,
+		"async":                    server.Async,
+		"async_timeout":            server.AsyncTimeout,
+	}).Info("roaming: configuring roaming agreement")
+
+	var redisClient redis.UniversalClient
+	if server.Async {
+		redisClient = storage.RedisClient()
+	}
+
+	client, err := newClient(backend.ClientConfig{
+		SenderID:     netID.String(),
+		ReceiverID:   server.NetID.String(),
+		Server:       server.Server,
+		AsyncTimeout: server.AsyncTimeout,
+		RedisClient:  redisClient,
+	}, caCert, tlsCert, tlsKey)
+	if err != nil {
+		return agreement{}, errors.Wrapf(err, "new roaming client error for netid: %s", server.NetID)
+	}
+
+	return agreement{
+		netID:                   server.NetID,
+		passiveRoaming:          server.PassiveRoaming,
+		passiveRoamingLifetime:  server.PassiveRoamingLifetime,
+		passiveRoamingKEKLabel:  server.PassiveRoamingKEKLabel,
+		handoverRoaming:         server.HandoverRoaming,
+		handoverRoamingLifetime: server.HandoverRoamingLifetime,
+		handoverRoamingKEKLabel: server.HandoverRoamingKEKLabel,
+		client:                  client,
+	}, nil
+}
+
+// readTLSFiles returns the content of the given CA certificate and TLS
+// certificate / key files. Empty paths are skipped.
+func readTLSFiles(caCert, tlsCert, tlsKey string) ([]byte, []byte, []byte, error) {
+	var out [3][]byte
+
+	for i, f := range []string{caCert, tlsCert, tlsKey} {
+		if f == "" {
+			continue
+		}
+
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, nil, nil, errors.Wrapf(err, "read file error: %s", f)
+		}
+		out[i] = b
+	}
+
+	return out[0], out[1], out[2], nil
+}
+
+func setAgreements(a []agreement) {
+	agreementsMux.Lock()
+	defer agreementsMux.Unlock()
+
+	agreements = a
+	roamingEnabled = defaultEnabled || len(agreements) != 0
+}
+
+func getAgreement(clientNetID lorawan.NetID) (agreement, bool) {
+	agreementsMux.RLock()
+	defer agreementsMux.RUnlock()
+
+	for _, a := range agreements {
+		if a.netID == clientNetID {
+			return a, true
+		}
+	}
+
+	return agreement{}, false
+}
+
 // IsRoamingDevAddr returns true when the DevAddr does not match the NetID of
 // the ChirpStack Network Server configuration. In case roaming is disabled,
 // this will always return false.
 // Note that enabling roaming -and- using ABP devices can be problematic when
 // the ABP DevAddr does not match the NetID.
 func IsRoamingDevAddr(devAddr lorawan.DevAddr) bool {
+	agreementsMux.RLock()
+	defer agreementsMux.RUnlock()
+
 	return roamingEnabled && !devAddr.IsNetID(netID)
 }
 
 // GetClientForNetID returns the API client for the given NetID.
 func GetClientForNetID(clientNetID lorawan.NetID) (backend.Client, error) {
-	for _, a := range agreements {
-		if a.netID == clientNetID {
-			return a.client, nil
-		}
+	return GetHandoverClientForNetID(clientNetID)
+}
+
+// GetHandoverClientForNetID returns the API client for the given NetID,
+// including the handover-roaming requests.
+func GetHandoverClientForNetID(clientNetID lorawan.NetID) (HandoverClient, error) {
+	if a, ok := getAgreement(clientNetID); ok {
+		return a.client, nil
 	}
 
 	if defaultEnabled {
@@ -164,16 +366,13 @@ func GetClientForNetID(clientNetID lorawan.NetID) (backend.Client, error) {
 			redisClient = storage.RedisClient()
 		}
 
-		client, err := backend.NewClient(backend.ClientConfig{
+		client, err := newClient(backend.ClientConfig{
 			SenderID:     netID.String(),
 			ReceiverID:   clientNetID.String(),
 			Server:       server,
-			CACert:       defaultCACert,
-			TLSCert:      defaultTLSCert,
-			TLSKey:       defaultTLSKey,
 			AsyncTimeout: defaultAsyncTimeout,
 			RedisClient:  redisClient,
-		})
+		}, defaultCACert, defaultTLSCert, defaultTLSKey)
 		if err != nil {
 			return nil, errors.Wrapf(err, "new roaming client error for netid: %s", clientNetID)
 		}
@@ -186,10 +385,8 @@ func GetClientForNetID(clientNetID lorawan.NetID) (backend.Client, error) {
 // GetPassiveRoamingLifetime returns the passive-roaming lifetime for the
 // given NetID.
 func GetPassiveRoamingLifetime(netID lorawan.NetID) time.Duration {
-	for _, a := range agreements {
-		if a.netID == netID {
-			return a.passiveRoamingLifetime
-		}
+	if a, ok := getAgreement(netID); ok {
+		return a.passiveRoamingLifetime
 	}
 
 	if defaultEnabled {
@@ -208,12 +405,33 @@ func GetKEKKey(label string) ([]byte, error) {
 	return kek, nil
 }
 
+// UnwrapKeyEnvelope returns the key of the given key-envelope. When the
+// KEK label is set, the key is decrypted using the matching KEK.
+func UnwrapKeyEnvelope(ke *backend.KeyEnvelope) (lorawan.AES128Key, error) {
+	var key lorawan.AES128Key
+
+	if ke.KEKLabel == "" {
+		copy(key[:], ke.AESKey[:])
+		return key, nil
+	}
+
+	kek, err := GetKEKKey(ke.KEKLabel)
+	if err != nil {
+		return key, err
+	}
+
+	key, err = ke.Unwrap(kek)
+	if err != nil {
+		return key, errors.Wrap(err, "unwrap key error")
+	}
+
+	return key, nil
+}
+
 // GetPassiveRoamingKEKLabel returns the KEK label for the given NetID or an empty string.
 func GetPassiveRoamingKEKLabel(netID lorawan.NetID) string {
-	for _, a := range agreements {
-		if a.netID == netID {
-			return a.passiveRoamingKEKLabel
-		}
+	if a, ok := getAgreement(netID); ok {
+		return a.passiveRoamingKEKLabel
 	}
 
 	if defaultEnabled {
@@ -225,6 +443,9 @@ func GetPassiveRoamingKEKLabel(netID lorawan.NetID) string {
 
 // GetNetIDsForDevAddr returns the NetIDs matching the given DevAddr.
 func GetNetIDsForDevAddr(devAddr lorawan.DevAddr) []lorawan.NetID {
+	agreementsMux.RLock()
+	defer agreementsMux.RUnlock()
+
 	var out []lorawan.NetID
 
 	for i := range agreements {
@@ -236,3 +457,44 @@ func GetNetIDsForDevAddr(devAddr lorawan.DevAddr) []lorawan.NetID {
 
 	return out
 }
+
+// IsHandoverRoaming returns true when handover-roaming is enabled for the
+// given NetID.
+func IsHandoverRoaming(netID lorawan.NetID) bool {
+	if a, ok := getAgreement(netID); ok {
+		return a.handoverRoaming
+	}
+
+	if defaultEnabled {
+		return defaultHandoverRoaming
+	}
+
+	return false
+}
+
+// GetHandoverRoamingLifetime returns the handover-roaming lifetime for the
+// given NetID.
+func GetHandoverRoamingLifetime(netID lorawan.NetID) time.Duration {
+	if a, ok := getAgreement(netID); ok {
+		return a.handoverRoamingLifetime
+	}
+
+	if defaultEnabled {
+		return defaultHandoverRoamingLifetime
+	}
+
+	return 0
+}
+
+// GetHandoverRoamingKEKLabel returns the KEK label for the given NetID or an empty string.
+func GetHandoverRoamingKEKLabel(netID lorawan.NetID) string {
+	if a, ok := getAgreement(netID); ok {
+		return a.handoverRoamingKEKLabel
+	}
+
+	if defaultEnabled {
+		return defaultHandoverRoamingKEKLabel
+	}
+
+	return ""
+}
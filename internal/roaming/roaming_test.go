@@ -1,10 +1,12 @@
 package roaming
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/brocaar/chirpstack-network-server/internal/config"
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
 	"github.com/brocaar/chirpstack-network-server/internal/test"
 	"github.com/brocaar/lorawan"
 	"github.com/pkg/errors"
@@ -82,6 +84,71 @@ func TestGetClientForNetID(t *testing.T) {
 	})
 }
 
+func TestReloadAgreements(t *testing.T) {
+	assert := require.New(t)
+
+	conf := test.GetConfig()
+	assert.NoError(storage.Setup(conf))
+	test.MustResetDB(storage.DB().DB)
+
+	conf.Roaming.Servers = []config.RoamingServer{
+		{
+			NetID:          lorawan.NetID{6, 6, 6},
+			PassiveRoaming: true,
+		},
+	}
+	assert.NoError(Setup(conf))
+
+	ras := []storage.RoamingAgreement{
+		{
+			NetID:                  lorawan.NetID{6, 6, 6},
+			PassiveRoaming:         true,
+			PassiveRoamingLifetime: time.Minute,
+		},
+		{
+			NetID:                  lorawan.NetID{6, 6, 7},
+			PassiveRoaming:         true,
+			PassiveRoamingLifetime: time.Hour,
+		},
+	}
+	for i := range ras {
+		assert.NoError(storage.CreateRoamingAgreement(context.Background(), storage.DB(), &ras[i]))
+	}
+
+	t.Run("Reload", func(t *testing.T) {
+		assert := require.New(t)
+		assert.NoError(ReloadAgreements(context.Background()))
+
+		c, err := GetClientForNetID(lorawan.NetID{6, 6, 7})
+		assert.NoError(err)
+		assert.NotNil(c)
+
+		// the configuration file takes precedence
+		assert.Equal(time.Duration(0), GetPassiveRoamingLifetime(lorawan.NetID{6, 6, 6}))
+		assert.Equal(time.Hour, GetPassiveRoamingLifetime(lorawan.NetID{6, 6, 7}))
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		assert := require.New(t)
+
+		ras[1].PassiveRoamingLifetime = time.Second
+		assert.NoError(storage.UpdateRoamingAgreement(context.Background(), storage.DB(), &ras[1]))
+		assert.NoError(ReloadAgreements(context.Background()))
+
+		assert.Equal(time.Second, GetPassiveRoamingLifetime(lorawan.NetID{6, 6, 7}))
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		assert := require.New(t)
+
+		assert.NoError(storage.DeleteRoamingAgreement(context.Background(), storage.DB(), ras[1].NetID))
+		assert.NoError(ReloadAgreements(context.Background()))
+
+		_, err := GetClientForNetID(lorawan.NetID{6, 6, 7})
+		assert.Equal(ErrNoAgreement, errors.Cause(err))
+	})
+}
+
 func TestGetPassiveRoamingLifetime(t *testing.T) {
 	assert := require.New(t)
 
@@ -164,6 +231,52 @@ func TestGetPassiveRoamingKEKLabel(t *testing.T) {
 	})
 }
 
+func TestGetHandoverRoamingLifetime(t *testing.T) {
+	assert := require.New(t)
+
+	conf := test.GetConfig()
+	conf.Roaming.Servers = []config.RoamingServer{
+		{
+			NetID:                   lorawan.NetID{6, 6, 6},
+			HandoverRoaming:         true,
+			HandoverRoamingLifetime: time.Hour,
+		},
+	}
+	assert.NoError(Setup(conf))
+
+	t.Run("Roaming agreement", func(t *testing.T) {
+		assert := require.New(t)
+		assert.True(IsHandoverRoaming(lorawan.NetID{6, 6, 6}))
+		assert.Equal(time.Hour, GetHandoverRoamingLifetime(lorawan.NetID{6, 6, 6}))
+	})
+
+	t.Run("No roaming agreement", func(t *testing.T) {
+		assert := require.New(t)
+		assert.False(IsHandoverRoaming(lorawan.NetID{6, 6, 7}))
+		assert.Equal(time.Duration(0), GetHandoverRoamingLifetime(lorawan.NetID{6, 6, 7}))
+	})
+
+	t.Run("Default roaming agreement", func(t *testing.T) {
+		assert := require.New(t)
+
+		conf := test.GetConfig()
+		conf.Roaming.Servers = []config.RoamingServer{
+			{
+				NetID:                   lorawan.NetID{6, 6, 6},
+				HandoverRoaming:         true,
+				HandoverRoamingLifetime: time.Hour,
+			},
+		}
+		conf.Roaming.Default.Enabled = true
+		conf.Roaming.Default.HandoverRoaming = true
+		conf.Roaming.Default.HandoverRoamingLifetime = time.Minute
+		assert.NoError(Setup(conf))
+
+		assert.True(IsHandoverRoaming(lorawan.NetID{6, 6, 7}))
+		assert.Equal(time.Minute, GetHandoverRoamingLifetime(lorawan.NetID{6, 6, 7}))
+	})
+}
+
 func TestGetNetIDsForDevAddr(t *testing.T) {
 	assert := require.New(t)
 	conf := test.GetConfig()
@@ -0,0 +1,94 @@
+package roaming
+
+import (
+	"github.com/gofrs/uuid"
+
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/lorawan/backend"
+)
+
+// ServiceProfile defines the service-profile as exchanged between the hNS
+// and sNS in case of handover-roaming. The lorawan/backend package does not
+// implement the service-profile, therefore it is defined here.
+type ServiceProfile struct {
+	ServiceProfileID       string             `json:"ServiceProfile-ID"`
+	ULRate                 int                `json:"ULRate"`
+	ULBucketSize           int                `json:"ULBucketSize"`
+	ULRatePolicy           storage.RatePolicy `json:"ULRatePolicy"`
+	DLRate                 int                `json:"DLRate"`
+	DLBucketSize           int                `json:"DLBucketSize"`
+	DLRatePolicy           storage.RatePolicy `json:"DLRatePolicy"`
+	AddGWMetadata          bool               `json:"AddGWMetadata"`
+	DevStatusReqFreq       int                `json:"DevStatusReqFreq"`
+	ReportDevStatusBattery bool               `json:"ReportDevStatusBattery"`
+	ReportDevStatusMargin  bool               `json:"ReportDevStatusMargin"`
+	DRMin                  int                `json:"DRMin"`
+	DRMax                  int                `json:"DRMax"`
+	ChannelMask            backend.HEXBytes   `json:"ChannelMask"`
+	PRAllowed              bool               `json:"PRAllowed"`
+	HRAllowed              bool               `json:"HRAllowed"`
+	RAAllowed              bool               `json:"RAAllowed"`
+	NwkGeoLoc              bool               `json:"NwkGeoLoc"`
+	TargetPER              int                `json:"TargetPER"`
+	MinGWDiversity         int                `json:"MinGWDiversity"`
+}
+
+// ServiceProfileToBackend converts the given service-profile into a
+// ServiceProfile.
+func ServiceProfileToBackend(sp storage.ServiceProfile) *ServiceProfile {
+	return &ServiceProfile{
+		ServiceProfileID:       sp.ID.String(),
+		ULRate:                 sp.ULRate,
+		ULBucketSize:           sp.ULBucketSize,
+		ULRatePolicy:           sp.ULRatePolicy,
+		DLRate:                 sp.DLRate,
+		DLBucketSize:           sp.DLBucketSize,
+		DLRatePolicy:           sp.DLRatePolicy,
+		AddGWMetadata:          sp.AddGWMetadata,
+		DevStatusReqFreq:       sp.DevStatusReqFreq,
+		ReportDevStatusBattery: sp.ReportDevStatusBattery,
+		ReportDevStatusMargin:  sp.ReportDevStatusMargin,
+		DRMin:                  sp.DRMin,
+		DRMax:                  sp.DRMax,
+		ChannelMask:            backend.HEXBytes(sp.ChannelMask),
+		PRAllowed:              sp.PRAllowed,
+		HRAllowed:              sp.HRAllowed,
+		RAAllowed:              sp.RAAllowed,
+		NwkGeoLoc:              sp.NwkGeoLoc,
+		TargetPER:              sp.TargetPER,
+		MinGWDiversity:         sp.MinGWDiversity,
+	}
+}
+
+// ServiceProfileFromBackend converts the given ServiceProfile into a
+// service-profile.
+func ServiceProfileFromBackend(sp ServiceProfile) storage.ServiceProfile {
+	out := storage.ServiceProfile{
+		ULRate:                 sp.ULRate,
+		ULBucketSize:           sp.ULBucketSize,
+		ULRatePolicy:           sp.ULRatePolicy,
+		DLRate:                 sp.DLRate,
+		DLBucketSize:           sp.DLBucketSize,
+		DLRatePolicy:           sp.DLRatePolicy,
+		AddGWMetadata:          sp.AddGWMetadata,
+		DevStatusReqFreq:       sp.DevStatusReqFreq,
+		ReportDevStatusBattery: sp.ReportDevStatusBattery,
+		ReportDevStatusMargin:  sp.ReportDevStatusMargin,
+		DRMin:                  sp.DRMin,
+		DRMax:                  sp.DRMax,
+		ChannelMask:            []byte(sp.ChannelMask),
+		PRAllowed:              sp.PRAllowed,
+		HRAllowed:              sp.HRAllowed,
+		RAAllowed:              sp.RAAllowed,
+		NwkGeoLoc:              sp.NwkGeoLoc,
+		TargetPER:              sp.TargetPER,
+		MinGWDiversity:         sp.MinGWDiversity,
+	}
+
+	// The ID is set by the roaming partner, it is not guaranteed to be an UUID.
+	if id, err := uuid.FromString(sp.ServiceProfileID); err == nil {
+		out.ID = id
+	}
+
+	return out
+}
@@ -103,6 +103,50 @@ func GetDevice(ctx context.Context, db sqlx.Queryer, devEUI lorawan.EUI64) (Devi
 	return d, nil
 }
 
+// GetDevices returns a slice of devices, ordered by DevEUI.
+func GetDevices(ctx context.Context, db sqlx.Queryer, limit, offset int) ([]Device, error) {
+	var devices []Device
+	err := sqlx.Select(db, &devices, `
+		select
+			*
+		from
+			device
+		order by
+			dev_eui
+		limit $1
+		offset $2`,
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, handlePSQLError(err, "select error")
+	}
+
+	return devices, nil
+}
+
+// GetDevEUIsForDeviceProfile returns the DevEUIs of the devices using the
+// given device-profile, ordered by DevEUI.
+func GetDevEUIsForDeviceProfile(ctx context.Context, db sqlx.Queryer, deviceProfileID uuid.UUID) ([]lorawan.EUI64, error) {
+	var devEUIs []lorawan.EUI64
+	err := sqlx.Select(db, &devEUIs, `
+		select
+			dev_eui
+		from
+			device
+		where
+			device_profile_id = $1
+		order by
+			dev_eui`,
+		deviceProfileID,
+	)
+	if err != nil {
+		return nil, handlePSQLError(err, "select error")
+	}
+
+	return devEUIs, nil
+}
+
 // UpdateDevice updates the given device.
 func UpdateDevice(ctx context.Context, db sqlx.Execer, d *Device) error {
 	d.UpdatedAt = time.Now()
@@ -280,3 +324,120 @@ func ValidateDevNonce(ctx context.Context, db sqlx.Queryer, joinEUI, devEUI lora
 
 	return nil
 }
+
+// GetLastDevNonce returns the last used (join-request) dev-nonce for the
+// given DevEUI. This is used for LoRaWAN 1.1 devices, for which the
+// dev-nonce is a counter which must increment on every join-request.
+func GetLastDevNonce(ctx context.Context, db sqlx.Queryer, devEUI lorawan.EUI64) (lorawan.DevNonce, error) {
+	var nonce lorawan.DevNonce
+	err := sqlx.Get(db, &nonce, `
+		select
+			last_dev_nonce
+		from
+			device_dev_nonce
+		where
+			dev_eui = $1`,
+		devEUI[:],
+	)
+	if err != nil {
+		return nonce, handlePSQLError(err, "select error")
+	}
+
+	return nonce, nil
+}
+
+// SetLastDevNonce sets the last used (join-request) dev-nonce for the given
+// DevEUI. It returns ErrDevNonceNotIncremented when the given dev-nonce is
+// not greater than the stored dev-nonce.
+func SetLastDevNonce(ctx context.Context, db sqlx.Execer, devEUI lorawan.EUI64, nonce lorawan.DevNonce) error {
+	res, err := db.Exec(`
+		insert into device_dev_nonce (
+			dev_eui,
+			updated_at,
+			last_dev_nonce
+		) values ($1, $2, $3)
+		on conflict (dev_eui)
+			do update
+			set
+				updated_at = excluded.updated_at,
+				last_dev_nonce = excluded.last_dev_nonce
+			where
+				device_dev_nonce.last_dev_nonce < excluded.last_dev_nonce`,
+		devEUI[:],
+		time.Now(),
+		nonce,
+	)
+	if err != nil {
+		return handlePSQLError(err, "insert error")
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return handlePSQLError(err, "get rows affected error")
+	}
+	if ra == 0 {
+		return ErrDevNonceNotIncremented
+	}
+
+	log.WithFields(log.Fields{
+		"dev_eui":   devEUI,
+		"dev_nonce": nonce,
+		"ctx_id":    ctx.Value(logging.ContextIDKey),
+	}).Info("last dev-nonce updated")
+
+	return nil
+}
+
+// DeleteLastDevNonce removes the last used dev-nonce for the given DevEUI,
+// e.g. when the device has been re-provisioned with a different MAC version.
+func DeleteLastDevNonce(ctx context.Context, db sqlx.Execer, devEUI lorawan.EUI64) error {
+	_, err := db.Exec("delete from device_dev_nonce where dev_eui = $1", devEUI[:])
+	if err != nil {
+		return handlePSQLError(err, "delete error")
+	}
+
+	log.WithFields(log.Fields{
+		"dev_eui": devEUI,
+		"ctx_id":  ctx.Value(logging.ContextIDKey),
+	}).Info("last dev-nonce deleted")
+
+	return nil
+}
+
+// DeleteDeviceActivationsBefore removes the device-activations created
+// before the given timestamp. The most recent device-activation of each
+// device is always kept. It returns the number of removed device-activations.
+func DeleteDeviceActivationsBefore(ctx context.Context, db sqlx.Execer, before time.Time) (int64, error) {
+	res, err := db.Exec(`
+		delete from
+			device_activation da
+		where
+			da.created_at < $1
+			and exists (
+				select
+					1
+				from
+					device_activation da2
+				where
+					da2.dev_eui = da.dev_eui
+					and da2.id > da.id
+			)`,
+		before,
+	)
+	if err != nil {
+		return 0, handlePSQLError(err, "delete error")
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return 0, handlePSQLError(err, "get rows affected error")
+	}
+
+	log.WithFields(log.Fields{
+		"before": before,
+		"count":  ra,
+		"ctx_id": ctx.Value(logging.ContextIDKey),
+	}).Info("device-activations pruned")
+
+	return ra, nil
+}
@@ -45,6 +45,9 @@ type DeviceProfile struct {
 	SupportsJoin       bool      `db:"supports_join"`
 	RFRegion           string    `db:"rf_region"`
 	Supports32bitFCnt  bool      `db:"supports_32bit_fcnt"`
+	ADRAlgorithmID     string    `db:"adr_algorithm_id"`
+	ADRAckLimitExp     *int      `db:"adr_ack_limit_exp"` // ADR_ACK_LIMIT = 2^exp, nil = device default
+	ADRAckDelayExp     *int      `db:"adr_ack_delay_exp"` // ADR_ACK_DELAY = 2^exp, nil = device default
 }
 
 // CreateDeviceProfile creates the given device-profile.
@@ -86,8 +89,11 @@ func CreateDeviceProfile(ctx context.Context, db sqlx.Execer, dp *DeviceProfile)
             max_duty_cycle,
             supports_join,
             rf_region,
-            supports_32bit_fcnt
-        ) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)`,
+            supports_32bit_fcnt,
+            adr_algorithm_id,
+            adr_ack_limit_exp,
+            adr_ack_delay_exp
+        ) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25)`,
 		dp.CreatedAt,
 		dp.UpdatedAt,
 		dp.ID,
@@ -110,6 +116,9 @@ func CreateDeviceProfile(ctx context.Context, db sqlx.Execer, dp *DeviceProfile)
 		dp.SupportsJoin,
 		dp.RFRegion,
 		dp.Supports32bitFCnt,
+		dp.ADRAlgorithmID,
+		dp.ADRAckLimitExp,
+		dp.ADRAckDelayExp,
 	)
 	if err != nil {
 		return handlePSQLError(err, "insert error")
@@ -233,7 +242,10 @@ func GetDeviceProfile(ctx context.Context, db sqlx.Queryer, id uuid.UUID) (Devic
             max_duty_cycle,
             supports_join,
             rf_region,
-            supports_32bit_fcnt
+            supports_32bit_fcnt,
+            adr_algorithm_id,
+            adr_ack_limit_exp,
+            adr_ack_delay_exp
         from device_profile
         where
             device_profile_id = $1
@@ -264,6 +276,9 @@ func GetDeviceProfile(ctx context.Context, db sqlx.Queryer, id uuid.UUID) (Devic
 		&dp.SupportsJoin,
 		&dp.RFRegion,
 		&dp.Supports32bitFCnt,
+		&dp.ADRAlgorithmID,
+		&dp.ADRAckLimitExp,
+		&dp.ADRAckDelayExp,
 	)
 	if err != nil {
 		return dp, handlePSQLError(err, "select error")
@@ -302,7 +317,10 @@ func UpdateDeviceProfile(ctx context.Context, db sqlx.Execer, dp *DeviceProfile)
             max_duty_cycle = $18,
             supports_join = $19,
             rf_region = $20,
-            supports_32bit_fcnt = $21
+            supports_32bit_fcnt = $21,
+            adr_algorithm_id = $22,
+            adr_ack_limit_exp = $23,
+            adr_ack_delay_exp = $24
         where
             device_profile_id = $1`,
 		dp.ID,
@@ -326,6 +344,9 @@ func UpdateDeviceProfile(ctx context.Context, db sqlx.Execer, dp *DeviceProfile)
 		dp.SupportsJoin,
 		dp.RFRegion,
 		dp.Supports32bitFCnt,
+		dp.ADRAlgorithmID,
+		dp.ADRAckLimitExp,
+		dp.ADRAckDelayExp,
 	)
 	if err != nil {
 		return handlePSQLError(err, "update error")
@@ -31,6 +31,13 @@ const (
 // UplinkHistorySize contains the number of frames to store
 const UplinkHistorySize = 20
 
+// Default ADR_ACK_LIMIT and ADR_ACK_DELAY exponents as used by the device
+// after activation (ADR_ACK_LIMIT = 64, ADR_ACK_DELAY = 32).
+const (
+	DefaultADRAckLimitExp = 6
+	DefaultADRAckDelayExp = 5
+)
+
 // RXWindow defines the RX window option.
 type RXWindow int8
 
@@ -134,6 +141,7 @@ type DeviceSession struct {
 	EnabledChannels       []int                    // deprecated, migrated by GetDeviceSession
 	EnabledUplinkChannels []int                    // channels that are activated on the node
 	ExtraUplinkChannels   map[int]loraband.Channel // extra uplink channels, configured by the user
+	DownlinkFrequencies   map[int]int              // rx1 frequency per uplink channel index, configured by DlChannelReq
 	ChannelFrequencies    []int                    // frequency of each channel
 	UplinkHistory         []UplinkHistory          // contains the last 20 transmissions
 
@@ -181,6 +189,29 @@ type DeviceSession struct {
 
 	// Device is disabled.
 	IsDisabled bool
+
+	// ADRAckLimitExp and ADRAckDelayExp define the ADR_ACK_LIMIT and
+	// ADR_ACK_DELAY exponents (2^exp) used by the device for the ADR backoff.
+	ADRAckLimitExp int
+	ADRAckDelayExp int
+
+	// MaxDCycle defines the aggregated duty-cycle (1 / 2^MaxDCycle) of the
+	// device, as configured using the DutyCycleReq mac-command. 0 means no
+	// duty-cycle limitation.
+	MaxDCycle int
+
+	// DutyCycleExceeded is set when the device exceeded the max. duty-cycle
+	// of the device-profile. The DutyCycleReq mac-command is (re-)sent until
+	// it has been acknowledged, MaxDCycle holds the acknowledged value.
+	DutyCycleExceeded bool
+
+	// PacketErrorRate holds the packet error rate (in percent) measured over
+	// the uplink history. See GetPacketLossPercentage.
+	PacketErrorRate float64
+
+	// ADRNbTrans holds the NbTrans chosen by the last ADR evaluation. This
+	// differs from NbTrans until the device has acknowledged the LinkADRReq.
+	ADRNbTrans uint8
 }
 
 // AppendUplinkHistory appends an UplinkHistory item and makes sure the list
@@ -252,6 +283,7 @@ func (s *DeviceSession) ResetToBootParameters(dp DeviceProfile) {
 	s.MinSupportedTXPowerIndex = 0
 	s.MaxSupportedTXPowerIndex = 0
 	s.ExtraUplinkChannels = make(map[int]loraband.Channel)
+	s.DownlinkFrequencies = nil
 	s.RXDelay = uint8(dp.RXDelay1)
 	s.RX1DROffset = uint8(dp.RXDROffset1)
 	s.RX2DR = uint8(dp.RXDataRate2)
@@ -261,6 +293,11 @@ func (s *DeviceSession) ResetToBootParameters(dp DeviceProfile) {
 	s.PingSlotDR = dp.PingSlotDR
 	s.PingSlotFrequency = int(dp.PingSlotFreq)
 	s.NbTrans = 1
+	s.ADRNbTrans = 0
+	s.ADRAckLimitExp = DefaultADRAckLimitExp
+	s.ADRAckDelayExp = DefaultADRAckDelayExp
+	s.MaxDCycle = 0
+	s.DutyCycleExceeded = false
 
 	if dp.PingSlotPeriod != 0 {
 		s.PingSlotNb = (1 << 12) / dp.PingSlotPeriod
@@ -323,6 +360,13 @@ func GetFullFCntUp(nextExpectedFullFCnt, truncatedFCntUp uint32) uint32 {
 // SaveDeviceSession saves the device-session. In case it doesn't exist yet
 // it will be created.
 func SaveDeviceSession(ctx context.Context, s DeviceSession) error {
+	return SaveDeviceSessionWithTTL(ctx, s, deviceSessionTTL)
+}
+
+// SaveDeviceSessionWithTTL saves the device-session using the given TTL
+// instead of the configured device-session TTL. This is used for sessions
+// which must not outlive an external lifetime (e.g. handover-roaming).
+func SaveDeviceSessionWithTTL(ctx context.Context, s DeviceSession, ttl time.Duration) error {
 	devAddrKey := fmt.Sprintf(devAddrKeyTempl, s.DevAddr)
 	devSessKey := fmt.Sprintf(deviceSessionKeyTempl, s.DevEUI)
 
@@ -337,6 +381,8 @@ func SaveDeviceSession(ctx context.Context, s DeviceSession) error {
 	// that devAddrKey, pendingDevAddrKey and DevSessKey are on the same Cluster
 	// shard.
 
+	// The DevAddr pointers are shared with other devices, therefore these
+	// always use the configured device-session TTL.
 	pipe := RedisClient().TxPipeline()
 	pipe.SAdd(devAddrKey, s.DevEUI[:])
 	pipe.PExpire(devAddrKey, deviceSessionTTL)
@@ -355,7 +401,7 @@ func SaveDeviceSession(ctx context.Context, s DeviceSession) error {
 		}
 	}
 
-	err = RedisClient().Set(devSessKey, b, deviceSessionTTL).Err()
+	err = RedisClient().Set(devSessKey, b, ttl).Err()
 	if err != nil {
 		return errors.Wrap(err, "set error")
 	}
@@ -693,6 +739,7 @@ func deviceSessionToPB(d DeviceSession) DeviceSessionPB {
 		NbTrans:                  uint32(d.NbTrans),
 
 		ExtraUplinkChannels: make(map[uint32]*DeviceSessionPBChannel),
+		DownlinkFrequencies: make(map[uint32]uint32),
 
 		LastDeviceStatusRequestTimeUnixNs: d.LastDevStatusRequested.UnixNano(),
 
@@ -716,6 +763,16 @@ func deviceSessionToPB(d DeviceSession) DeviceSessionPB {
 		MacCommandErrorCount: make(map[uint32]uint32),
 
 		IsDisabled: d.IsDisabled,
+
+		AdrAckLimitExp: uint32(d.ADRAckLimitExp),
+		AdrAckDelayExp: uint32(d.ADRAckDelayExp),
+		AdrAckExpSet:   true,
+
+		MaxDCycle:         uint32(d.MaxDCycle),
+		DutyCycleExceeded: d.DutyCycleExceeded,
+
+		PacketErrorRate: d.PacketErrorRate,
+		AdrNbTrans:      uint32(d.ADRNbTrans),
 	}
 
 	if d.AppSKeyEvelope != nil {
@@ -737,6 +794,10 @@ func deviceSessionToPB(d DeviceSession) DeviceSessionPB {
 		}
 	}
 
+	for i, f := range d.DownlinkFrequencies {
+		out.DownlinkFrequencies[uint32(i)] = uint32(f)
+	}
+
 	for _, c := range d.ChannelFrequencies {
 		out.ChannelFrequencies = append(out.ChannelFrequencies, uint32(c))
 	}
@@ -818,6 +879,23 @@ func deviceSessionFromPB(d DeviceSessionPB) DeviceSession {
 		MACCommandErrorCount: make(map[lorawan.CID]int),
 
 		IsDisabled: d.IsDisabled,
+
+		ADRAckLimitExp: int(d.AdrAckLimitExp),
+		ADRAckDelayExp: int(d.AdrAckDelayExp),
+
+		MaxDCycle:         int(d.MaxDCycle),
+		DutyCycleExceeded: d.DutyCycleExceeded,
+
+		PacketErrorRate: d.PacketErrorRate,
+		ADRNbTrans:      uint8(d.AdrNbTrans),
+	}
+
+	// Device-sessions stored before the ADR_ACK exponents were added do not
+	// have the exponents set, these devices are still using the default
+	// exponents.
+	if !d.AdrAckExpSet {
+		out.ADRAckLimitExp = DefaultADRAckLimitExp
+		out.ADRAckDelayExp = DefaultADRAckDelayExp
 	}
 
 	if d.LastDeviceStatusRequestTimeUnixNs > 0 {
@@ -854,6 +932,13 @@ func deviceSessionFromPB(d DeviceSessionPB) DeviceSession {
 		}
 	}
 
+	for i, f := range d.DownlinkFrequencies {
+		if out.DownlinkFrequencies == nil {
+			out.DownlinkFrequencies = make(map[int]int)
+		}
+		out.DownlinkFrequencies[int(i)] = int(f)
+	}
+
 	for _, c := range d.ChannelFrequencies {
 		out.ChannelFrequencies = append(out.ChannelFrequencies, int(c))
 	}
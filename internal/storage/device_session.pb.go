@@ -255,7 +255,26 @@ type DeviceSessionPB struct {
 	// Mac-command error counter.
 	MacCommandErrorCount map[uint32]uint32 `protobuf:"bytes,50,rep,name=mac_command_error_count,json=macCommandErrorCount,proto3" json:"mac_command_error_count,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
 	// Device is disabled.
-	IsDisabled           bool     `protobuf:"varint,51,opt,name=is_disabled,json=isDisabled,proto3" json:"is_disabled,omitempty"`
+	IsDisabled bool `protobuf:"varint,51,opt,name=is_disabled,json=isDisabled,proto3" json:"is_disabled,omitempty"`
+	// ADR_ACK_LIMIT exponent (ADR_ACK_LIMIT = 2^exp).
+	AdrAckLimitExp uint32 `protobuf:"varint,52,opt,name=adr_ack_limit_exp,json=adrAckLimitExp,proto3" json:"adr_ack_limit_exp,omitempty"`
+	// ADR_ACK_DELAY exponent (ADR_ACK_DELAY = 2^exp).
+	AdrAckDelayExp uint32 `protobuf:"varint,53,opt,name=adr_ack_delay_exp,json=adrAckDelayExp,proto3" json:"adr_ack_delay_exp,omitempty"`
+	// RX1 downlink frequencies (Hz) per uplink channel index, as configured
+	// using the DlChannelReq mac-command.
+	DownlinkFrequencies map[uint32]uint32 `protobuf:"bytes,54,rep,name=downlink_frequencies,json=downlinkFrequencies,proto3" json:"downlink_frequencies,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// Aggregated duty-cycle exponent (duty-cycle = 1 / 2^MaxDCycle), as
+	// configured using the DutyCycleReq mac-command.
+	MaxDCycle uint32 `protobuf:"varint,55,opt,name=max_d_cycle,json=maxDCycle,proto3" json:"max_d_cycle,omitempty"`
+	// Packet error rate (percent) measured over the uplink history.
+	PacketErrorRate float64 `protobuf:"fixed64,56,opt,name=packet_error_rate,json=packetErrorRate,proto3" json:"packet_error_rate,omitempty"`
+	// NbTrans chosen by the last ADR evaluation.
+	AdrNbTrans uint32 `protobuf:"varint,57,opt,name=adr_nb_trans,json=adrNbTrans,proto3" json:"adr_nb_trans,omitempty"`
+	// The device exceeded the max. duty-cycle of the device-profile.
+	DutyCycleExceeded bool `protobuf:"varint,58,opt,name=duty_cycle_exceeded,json=dutyCycleExceeded,proto3" json:"duty_cycle_exceeded,omitempty"`
+	// The ADR_ACK exponents are set. Device-sessions stored before the
+	// exponents were added decode as 0 / 0 without this flag.
+	AdrAckExpSet         bool     `protobuf:"varint,59,opt,name=adr_ack_exp_set,json=adrAckExpSet,proto3" json:"adr_ack_exp_set,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -615,6 +634,62 @@ func (m *DeviceSessionPB) GetIsDisabled() bool {
 	return false
 }
 
+func (m *DeviceSessionPB) GetAdrAckLimitExp() uint32 {
+	if m != nil {
+		return m.AdrAckLimitExp
+	}
+	return 0
+}
+
+func (m *DeviceSessionPB) GetAdrAckDelayExp() uint32 {
+	if m != nil {
+		return m.AdrAckDelayExp
+	}
+	return 0
+}
+
+func (m *DeviceSessionPB) GetDownlinkFrequencies() map[uint32]uint32 {
+	if m != nil {
+		return m.DownlinkFrequencies
+	}
+	return nil
+}
+
+func (m *DeviceSessionPB) GetMaxDCycle() uint32 {
+	if m != nil {
+		return m.MaxDCycle
+	}
+	return 0
+}
+
+func (m *DeviceSessionPB) GetPacketErrorRate() float64 {
+	if m != nil {
+		return m.PacketErrorRate
+	}
+	return 0
+}
+
+func (m *DeviceSessionPB) GetAdrNbTrans() uint32 {
+	if m != nil {
+		return m.AdrNbTrans
+	}
+	return 0
+}
+
+func (m *DeviceSessionPB) GetDutyCycleExceeded() bool {
+	if m != nil {
+		return m.DutyCycleExceeded
+	}
+	return false
+}
+
+func (m *DeviceSessionPB) GetAdrAckExpSet() bool {
+	if m != nil {
+		return m.AdrAckExpSet
+	}
+	return false
+}
+
 type DeviceGatewayRXInfoSetPB struct {
 	// Device EUI.
 	DevEui []byte `protobuf:"bytes,1,opt,name=dev_eui,json=devEui,proto3" json:"dev_eui,omitempty"`
@@ -878,6 +953,7 @@ func init() {
 	proto.RegisterType((*DeviceSessionPB)(nil), "storage.DeviceSessionPB")
 	proto.RegisterMapType((map[uint32]*DeviceSessionPBChannel)(nil), "storage.DeviceSessionPB.ExtraUplinkChannelsEntry")
 	proto.RegisterMapType((map[uint32]uint32)(nil), "storage.DeviceSessionPB.MacCommandErrorCountEntry")
+	proto.RegisterMapType((map[uint32]uint32)(nil), "storage.DeviceSessionPB.DownlinkFrequenciesEntry")
 	proto.RegisterType((*DeviceGatewayRXInfoSetPB)(nil), "storage.DeviceGatewayRXInfoSetPB")
 	proto.RegisterType((*DeviceGatewayRXInfoPB)(nil), "storage.DeviceGatewayRXInfoPB")
 	proto.RegisterType((*PassiveRoamingDeviceSessionPB)(nil), "storage.PassiveRoamingDeviceSessionPB")
@@ -888,102 +964,111 @@ func init() {
 }
 
 var fileDescriptor_958563bbc6ebadf7 = []byte{
-	// 1541 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x57, 0xef, 0x52, 0x5b, 0xb9,
-	0x15, 0x1f, 0xe3, 0x00, 0xe6, 0xd8, 0x0e, 0x44, 0x40, 0x10, 0x34, 0x2c, 0x8e, 0x93, 0x36, 0xee,
-	0x76, 0xd7, 0x60, 0x6f, 0x76, 0x67, 0xbb, 0x1f, 0x3a, 0x25, 0xd8, 0xd9, 0x32, 0xdb, 0x50, 0xe6,
-	0x42, 0x76, 0xfa, 0x4d, 0x23, 0x5f, 0xc9, 0x44, 0xf5, 0xb5, 0xee, 0xad, 0x24, 0xdb, 0xd7, 0xaf,
-	0xd2, 0x97, 0xe8, 0x3b, 0x74, 0xfa, 0x60, 0x3b, 0xfa, 0x63, 0x63, 0x1b, 0xf8, 0x84, 0xf5, 0xfb,
-	0xfd, 0xce, 0x91, 0x74, 0xee, 0x39, 0x47, 0x07, 0xd8, 0x63, 0x7c, 0x2c, 0x62, 0x4e, 0x34, 0xd7,
-	0x5a, 0xa4, 0xb2, 0x99, 0xa9, 0xd4, 0xa4, 0x68, 0x53, 0x9b, 0x54, 0xd1, 0x3b, 0x7e, 0x74, 0x72,
-	0x97, 0xa6, 0x77, 0x09, 0x3f, 0x75, 0x70, 0x6f, 0xd4, 0x3f, 0x35, 0x62, 0xc8, 0xb5, 0xa1, 0xc3,
-	0xcc, 0x2b, 0x8f, 0x76, 0xe3, 0x74, 0x38, 0x4c, 0xe5, 0xa9, 0xff, 0xe3, 0xc1, 0x3a, 0x83, 0x97,
-	0x1d, 0xe7, 0xf6, 0xc6, 0x7b, 0xbd, 0xfe, 0x70, 0xf1, 0x85, 0x4a, 0xc9, 0x13, 0xf4, 0x0a, 0xb6,
-	0xfa, 0x8a, 0xff, 0x7b, 0xc4, 0x65, 0x3c, 0xc5, 0x85, 0x5a, 0xa1, 0x51, 0x8d, 0xee, 0x01, 0xb4,
-	0x0f, 0x1b, 0x43, 0x21, 0x09, 0x53, 0x78, 0xcd, 0x51, 0xeb, 0x43, 0x21, 0x3b, 0xca, 0xc1, 0x34,
-	0xb7, 0x70, 0x31, 0xc0, 0x34, 0xef, 0xa8, 0xfa, 0x7f, 0x0a, 0x70, 0xb2, 0xb2, 0xcd, 0xe7, 0x2c,
-	0x11, 0x72, 0x70, 0xde, 0x89, 0xfe, 0x26, 0xec, 0x0d, 0xa6, 0x68, 0x17, 0xd6, 0xfb, 0x24, 0x96,
-	0x26, 0xec, 0xf5, 0xac, 0x7f, 0x21, 0x0d, 0x3a, 0x80, 0x4d, 0xeb, 0x4f, 0x4b, 0xbf, 0xcf, 0x5a,
-	0x64, 0xdd, 0xdf, 0x48, 0x85, 0xde, 0xc2, 0x73, 0x93, 0x93, 0x2c, 0x9d, 0x70, 0x45, 0x84, 0x64,
-	0x3c, 0x0f, 0x1b, 0x56, 0x4c, 0x7e, 0x6d, 0xc1, 0x4b, 0x8b, 0xa1, 0x37, 0x50, 0xbd, 0xa3, 0x86,
-	0x4f, 0xe8, 0x94, 0xc4, 0xe9, 0x48, 0x1a, 0xfc, 0xcc, 0x8b, 0x02, 0x78, 0x61, 0xb1, 0xfa, 0xff,
-	0x10, 0x6c, 0xaf, 0x1c, 0x0e, 0x7d, 0x0d, 0x2f, 0x42, 0xb4, 0x33, 0x95, 0xf6, 0x45, 0xc2, 0x89,
-	0x60, 0xee, 0x60, 0x5b, 0xd1, 0xb6, 0x27, 0xae, 0x3d, 0x7e, 0xc9, 0xd0, 0x37, 0x80, 0x34, 0x57,
-	0xab, 0xe2, 0x35, 0x27, 0xde, 0x09, 0xcc, 0x92, 0x5a, 0xa5, 0x23, 0x23, 0xe4, 0xdd, 0xa2, 0xba,
-	0xe8, 0xd5, 0x81, 0xb9, 0x57, 0x1f, 0x42, 0x89, 0xf1, 0x31, 0xa1, 0x8c, 0x29, 0x77, 0xf6, 0x4a,
-	0xb4, 0xc9, 0xf8, 0xf8, 0x9c, 0x31, 0x65, 0x43, 0x63, 0x29, 0x3e, 0x12, 0x78, 0xdd, 0x31, 0x1b,
-	0x8c, 0x8f, 0xbb, 0x23, 0x61, 0x6d, 0xfe, 0x95, 0x0a, 0xe9, 0x98, 0x0d, 0x6f, 0x63, 0xd7, 0x96,
-	0x7a, 0x0b, 0xdb, 0x7d, 0x22, 0x27, 0x03, 0xa2, 0x89, 0x90, 0x86, 0x0c, 0xf8, 0x14, 0x6f, 0x3a,
-	0x45, 0xb9, 0x7f, 0x35, 0x19, 0xdc, 0x5c, 0x4a, 0xf3, 0x0b, 0x9f, 0x5a, 0x95, 0x5e, 0x51, 0x95,
-	0xbc, 0x4a, 0x2f, 0xa8, 0x5e, 0x43, 0xd5, 0x6b, 0xb8, 0x8c, 0x9d, 0x66, 0xcb, 0x69, 0x40, 0x4e,
-	0x06, 0x37, 0x5d, 0x19, 0x5b, 0xc9, 0x5f, 0x01, 0xd1, 0x2c, 0x23, 0xda, 0xd2, 0x84, 0xcb, 0x31,
-	0x4f, 0xd2, 0x8c, 0xe3, 0x6f, 0x6b, 0x85, 0x46, 0xb9, 0xbd, 0xdb, 0x0c, 0x79, 0xf8, 0x0b, 0x9f,
-	0x76, 0x03, 0x15, 0x6d, 0xd3, 0x2c, 0xbb, 0x59, 0x00, 0x10, 0x86, 0x92, 0x4b, 0x0a, 0x32, 0xca,
-	0x30, 0xb8, 0x6f, 0xb7, 0x61, 0xf3, 0xe2, 0x73, 0x86, 0x4e, 0xa0, 0x22, 0x89, 0xe7, 0x58, 0x3a,
-	0x91, 0xb8, 0xec, 0x33, 0x54, 0x7e, 0xbc, 0x90, 0xa6, 0x93, 0x4e, 0xa4, 0x15, 0xd0, 0x45, 0x41,
-	0xc5, 0x0b, 0xe8, 0x5c, 0xf0, 0x0a, 0x20, 0x4e, 0x65, 0xdf, 0x6b, 0xf0, 0x3b, 0x47, 0x97, 0x2c,
-	0x62, 0x15, 0xe8, 0x1d, 0xec, 0xe8, 0x81, 0xc8, 0x82, 0x87, 0xf8, 0x0b, 0x8f, 0x07, 0xb8, 0x5a,
-	0x2b, 0x34, 0x4a, 0x51, 0xd5, 0xe2, 0x56, 0x73, 0x61, 0x41, 0x1b, 0x6e, 0x95, 0x13, 0xc6, 0x13,
-	0x3a, 0xc5, 0xcf, 0x9d, 0x93, 0x4d, 0x95, 0x77, 0xec, 0x12, 0xd5, 0xa1, 0xaa, 0xf2, 0x16, 0x61,
-	0x8a, 0xa4, 0xfd, 0xbe, 0xe6, 0x06, 0x6f, 0x3b, 0xbe, 0xac, 0xf2, 0x56, 0x47, 0xfd, 0xc3, 0x41,
-	0xb6, 0x62, 0x54, 0xde, 0xb6, 0x15, 0xb3, 0xe3, 0x2b, 0x46, 0xe5, 0xed, 0x8e, 0xb2, 0x99, 0x6b,
-	0xe1, 0xfb, 0x0a, 0x7c, 0xe1, 0x33, 0x57, 0xe5, 0xed, 0x8f, 0xf3, 0x22, 0x7c, 0x58, 0x04, 0xe8,
-	0x91, 0x22, 0x78, 0x0e, 0x6b, 0x4c, 0xe1, 0x5d, 0xc7, 0xac, 0x31, 0x85, 0x76, 0xa0, 0x48, 0x99,
-	0xc2, 0x7b, 0xee, 0x32, 0xf6, 0x27, 0xfa, 0x0b, 0xbc, 0x72, 0x55, 0x36, 0xca, 0xb2, 0x54, 0x19,
-	0xce, 0xc8, 0x8a, 0xd7, 0x7d, 0x67, 0x8b, 0x6d, 0xe9, 0xcd, 0x24, 0xb7, 0x8b, 0x3b, 0x1c, 0x42,
-	0x49, 0xf6, 0x88, 0x51, 0x54, 0x6a, 0x7c, 0xe0, 0x43, 0x20, 0x7b, 0xb7, 0x76, 0x89, 0x7e, 0x80,
-	0x03, 0x2e, 0x69, 0x2f, 0xe1, 0x8c, 0x8c, 0x5c, 0xc5, 0x93, 0xd8, 0xf7, 0x17, 0x8d, 0x71, 0xad,
-	0xd8, 0xa8, 0x46, 0xfb, 0x81, 0xf6, 0xfd, 0x20, 0x34, 0x1f, 0x8d, 0x38, 0xec, 0xf3, 0xdc, 0x28,
-	0xfa, 0xc0, 0xea, 0xb0, 0x56, 0x6c, 0x94, 0xdb, 0xad, 0x66, 0x68, 0x7b, 0xcd, 0x95, 0xca, 0x6d,
-	0x76, 0xad, 0xd5, 0xb2, 0xb3, 0xae, 0x34, 0x6a, 0x1a, 0xed, 0xf2, 0x87, 0x0c, 0x3a, 0x85, 0xdd,
-	0xe0, 0x79, 0x1e, 0x6a, 0xc1, 0x35, 0x3e, 0x72, 0x47, 0x43, 0x81, 0xfa, 0x78, 0xcf, 0xa0, 0x5f,
-	0x01, 0x85, 0x13, 0x51, 0xa6, 0xc8, 0x17, 0xdf, 0xbb, 0xf0, 0xef, 0xdc, 0xa1, 0x1a, 0x4f, 0x1d,
-	0x6a, 0xb5, 0xd7, 0x45, 0x3b, 0xde, 0xc7, 0x39, 0x53, 0xb3, 0xee, 0x17, 0xc1, 0xbb, 0x84, 0x6a,
-	0x43, 0x66, 0x3d, 0xde, 0x50, 0x33, 0xd2, 0xc4, 0x6d, 0xac, 0x0d, 0xb1, 0xad, 0x9c, 0x8c, 0xa4,
-	0xc8, 0x89, 0xd4, 0xf8, 0xb8, 0x56, 0x68, 0x14, 0xa3, 0xd7, 0x56, 0x1e, 0xf6, 0x71, 0xe2, 0xc8,
-	0x6b, 0x6f, 0xc5, 0x90, 0x7f, 0x96, 0x22, 0xbf, 0xd2, 0xe8, 0x12, 0xea, 0xde, 0x67, 0x3a, 0x91,
-	0xee, 0xc8, 0x26, 0x27, 0xf3, 0x47, 0x61, 0xee, 0xae, 0xe6, 0xdc, 0x1d, 0x3b, 0x77, 0x41, 0x78,
-	0x9b, 0xdf, 0xce, 0x64, 0xc1, 0xd5, 0x1b, 0xa8, 0xf6, 0x38, 0x8d, 0x53, 0x49, 0x92, 0x34, 0x1e,
-	0x70, 0x86, 0x5f, 0xbb, 0xec, 0xa9, 0x78, 0xf0, 0xef, 0x0e, 0x43, 0x35, 0xa8, 0x64, 0xb6, 0xaf,
-	0xe9, 0x24, 0x35, 0x44, 0xf6, 0x70, 0xdd, 0xa5, 0x02, 0x58, 0xec, 0x26, 0x49, 0xcd, 0x55, 0x6f,
-	0x59, 0xc1, 0x14, 0x7e, 0xb3, 0xac, 0xe8, 0x28, 0xd4, 0x84, 0xdd, 0x7b, 0xc5, 0x7d, 0xf6, 0xbf,
-	0x75, 0xc2, 0x17, 0x33, 0xe1, 0x7d, 0x09, 0x9c, 0x40, 0x79, 0x48, 0x63, 0x32, 0xe6, 0xca, 0x86,
-	0x1a, 0xff, 0xde, 0xf5, 0x51, 0x18, 0xd2, 0xf8, 0x57, 0x8f, 0xb8, 0xdc, 0x16, 0xf2, 0xe9, 0xdc,
-	0xfe, 0x43, 0xc8, 0x6d, 0x21, 0x1f, 0xcf, 0xed, 0xf7, 0xf0, 0x52, 0x71, 0xd7, 0x4f, 0x67, 0x1f,
-	0x23, 0x24, 0x2c, 0xfe, 0xc6, 0x85, 0x60, 0xcf, 0xb3, 0x21, 0xfa, 0x5d, 0xcf, 0xa1, 0x9f, 0xe0,
-	0x68, 0xc5, 0xca, 0x16, 0x98, 0x7b, 0x83, 0x88, 0xc4, 0x0d, 0xb7, 0xe7, 0xcb, 0x25, 0xcb, 0x4f,
-	0x34, 0x77, 0xcf, 0xd1, 0x15, 0xfa, 0x11, 0x0e, 0x1f, 0xb1, 0x75, 0x29, 0x20, 0xf1, 0x1f, 0x9d,
-	0xe9, 0xfe, 0xaa, 0xa9, 0xfd, 0x5e, 0x57, 0xb6, 0x1f, 0x04, 0x4b, 0xbf, 0xd3, 0x19, 0xfe, 0x3a,
-	0x74, 0x0d, 0x87, 0x3a, 0xff, 0x67, 0xe8, 0x1c, 0x8e, 0x33, 0x2e, 0x99, 0x8d, 0x72, 0x50, 0x2f,
-	0x0f, 0x16, 0xf8, 0x4f, 0xae, 0x91, 0x1f, 0x05, 0x51, 0xe4, 0x34, 0x4b, 0x19, 0x8d, 0xbe, 0x05,
-	0xa4, 0x78, 0x9f, 0x2b, 0x2e, 0x63, 0x4e, 0x68, 0x62, 0x84, 0x19, 0x31, 0x8e, 0x9b, 0xb5, 0x42,
-	0xa3, 0x10, 0xbd, 0x98, 0x33, 0xe7, 0x81, 0x40, 0xdf, 0xc3, 0x41, 0x28, 0x1a, 0x36, 0xe1, 0x49,
-	0xe2, 0xef, 0xf2, 0xfe, 0xec, 0x6c, 0xa8, 0xf1, 0xa9, 0x0f, 0xa2, 0xa7, 0x3b, 0x96, 0xb5, 0x57,
-	0x71, 0x1c, 0xfa, 0x33, 0x1c, 0xce, 0x53, 0xf7, 0x81, 0xe1, 0x99, 0x33, 0x7c, 0x39, 0x13, 0xac,
-	0x98, 0xb6, 0x60, 0x3f, 0xec, 0x68, 0x63, 0xc7, 0x85, 0xca, 0xc2, 0xe7, 0x6e, 0xb9, 0x80, 0x84,
-	0x1a, 0xfe, 0x44, 0xf3, 0xae, 0x50, 0x99, 0xff, 0xd0, 0x02, 0x0e, 0x6c, 0x26, 0xd9, 0x57, 0x89,
-	0x4a, 0x46, 0xb8, 0x52, 0xa9, 0x0a, 0x53, 0x43, 0xdb, 0x95, 0x77, 0xfb, 0xc9, 0x9e, 0xf3, 0x89,
-	0xc6, 0x17, 0xde, 0xac, 0x6b, 0xad, 0x5c, 0x9c, 0x7d, 0xd3, 0xd9, 0x1b, 0x3e, 0x42, 0xd9, 0xa4,
-	0x15, 0x9a, 0x30, 0xa1, 0x7d, 0x22, 0x7d, 0xe7, 0xae, 0x02, 0x42, 0x77, 0x02, 0x72, 0x74, 0x07,
-	0xf8, 0xa9, 0x3e, 0x66, 0xdb, 0xb7, 0x7d, 0x6d, 0xfd, 0x94, 0x64, 0x7f, 0xa2, 0xef, 0x61, 0x7d,
-	0x4c, 0x93, 0x11, 0x77, 0x33, 0x47, 0xb9, 0x7d, 0xf2, 0xd4, 0x39, 0x83, 0x9f, 0xc8, 0xab, 0x7f,
-	0x5a, 0xfb, 0xb1, 0x70, 0xf4, 0x33, 0x1c, 0x3e, 0x79, 0xf8, 0x47, 0x76, 0xda, 0x5b, 0xdc, 0xa9,
-	0xba, 0xe0, 0xa8, 0x3e, 0x05, 0xec, 0x77, 0xfb, 0xd9, 0x8f, 0x56, 0xd1, 0x3f, 0x2f, 0x65, 0x3f,
-	0xbd, 0xe1, 0xe6, 0xfa, 0xc3, 0xe2, 0xa4, 0x52, 0x58, 0x9a, 0x54, 0xfc, 0xcb, 0xb4, 0x36, 0x7f,
-	0x99, 0xde, 0xc3, 0xba, 0x30, 0x7c, 0xa8, 0x71, 0xd1, 0x05, 0xfc, 0xab, 0x95, 0x8b, 0x2c, 0xb9,
-	0xbe, 0xfe, 0x10, 0x79, 0x71, 0xfd, 0xbf, 0x05, 0xd8, 0x7f, 0x54, 0x80, 0x8e, 0x01, 0x66, 0xe3,
-	0x5f, 0x18, 0xdf, 0x2a, 0xd1, 0x56, 0x40, 0x2e, 0x19, 0x42, 0xf0, 0x4c, 0x69, 0x2d, 0xdc, 0x01,
-	0xd6, 0x23, 0xf7, 0xdb, 0x3e, 0x65, 0x49, 0xaa, 0xa8, 0x9b, 0x38, 0x8b, 0x2e, 0x9f, 0x37, 0xed,
-	0xda, 0x8e, 0x9c, 0x7b, 0xb0, 0xde, 0x4b, 0xa9, 0x62, 0x61, 0x88, 0xf4, 0x0b, 0x84, 0x61, 0x93,
-	0x4a, 0xc3, 0xa5, 0xa4, 0x6e, 0x0c, 0xab, 0x46, 0xb3, 0xa5, 0x65, 0xe2, 0x54, 0x1a, 0x9e, 0x9b,
-	0xd9, 0x18, 0x16, 0x96, 0xf5, 0xff, 0xaf, 0xc1, 0xf1, 0x35, 0xd5, 0x5a, 0x8c, 0x79, 0x94, 0xd2,
-	0xa1, 0x90, 0x77, 0xab, 0xf3, 0xe7, 0x31, 0x40, 0xa8, 0xc6, 0x85, 0x93, 0x07, 0xe4, 0x92, 0xd9,
-	0xa1, 0x41, 0x72, 0x33, 0x1b, 0x33, 0x2b, 0xd1, 0xba, 0xe4, 0x66, 0x65, 0x5a, 0x2c, 0x3e, 0x39,
-	0x2d, 0x3e, 0x5b, 0xfa, 0x06, 0x5f, 0x41, 0xd9, 0x5e, 0x70, 0x42, 0x25, 0x69, 0x91, 0x96, 0xbb,
-	0x43, 0x29, 0xda, 0x0a, 0x50, 0xab, 0xf5, 0xd8, 0xc8, 0xb8, 0xf1, 0x70, 0x64, 0xfc, 0x01, 0x4a,
-	0x89, 0xe8, 0x73, 0x5b, 0x9f, 0x6e, 0xa2, 0x2c, 0xb7, 0x8f, 0x9a, 0xfe, 0xff, 0x91, 0xe6, 0xec,
-	0xff, 0x91, 0xe6, 0xfc, 0x4d, 0x89, 0xe6, 0xda, 0xa5, 0xf9, 0xae, 0xb4, 0x34, 0xdf, 0xbd, 0x86,
-	0xca, 0x98, 0x26, 0x82, 0x51, 0xc3, 0xc9, 0x50, 0xc4, 0x6e, 0xba, 0x2c, 0x45, 0xe5, 0x19, 0xf6,
-	0x49, 0xc4, 0xbd, 0x0d, 0xe7, 0xfa, 0xbb, 0xdf, 0x02, 0x00, 0x00, 0xff, 0xff, 0x87, 0x31, 0x8b,
-	0x0c, 0x19, 0x0d, 0x00, 0x00,
+	// 1693 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x03, 0x95, 0x57, 0x6b, 0x52, 0x1b, 0x47,
+	0x10, 0x2e, 0xde, 0x30, 0x12, 0xaf, 0xe1, 0x35, 0x28, 0xc6, 0x80, 0x6c, 0xc7, 0x8f, 0xd8, 0x02,
+	0x64, 0xec, 0xd8, 0x4e, 0x55, 0x2a, 0x18, 0x09, 0x87, 0x8a, 0x4d, 0xa8, 0x05, 0xbb, 0xf2, 0x6f,
+	0x6a, 0xb5, 0x3b, 0xc2, 0x1b, 0xad, 0x66, 0x37, 0xbb, 0x2b, 0x90, 0xce, 0x90, 0x1b, 0xe4, 0x12,
+	0xb9, 0x44, 0x0e, 0x96, 0xee, 0x9e, 0x91, 0x90, 0x04, 0x54, 0x2a, 0xbf, 0xa4, 0xf9, 0xfa, 0xeb,
+	0xee, 0x99, 0x9e, 0xee, 0x9e, 0x5e, 0xb6, 0xec, 0xab, 0xcb, 0xc0, 0x53, 0x32, 0x55, 0x69, 0x1a,
+	0x44, 0xba, 0x14, 0x27, 0x51, 0x16, 0xf1, 0xa9, 0x34, 0x8b, 0x12, 0xf7, 0x42, 0x15, 0x36, 0x2f,
+	0xa2, 0xe8, 0x22, 0x54, 0x3b, 0x04, 0xd7, 0x5a, 0xf5, 0x9d, 0x2c, 0x68, 0xaa, 0x34, 0x73, 0x9b,
+	0xb1, 0x61, 0x16, 0x96, 0xbc, 0xa8, 0xd9, 0x8c, 0xf4, 0x8e, 0xf9, 0x31, 0x60, 0xd1, 0x67, 0xab,
+	0x15, 0x32, 0x7b, 0x66, 0xac, 0x9e, 0xbe, 0x3f, 0xfc, 0xea, 0x6a, 0xad, 0x42, 0x7e, 0x8f, 0xcd,
+	0xd4, 0x13, 0xf5, 0x47, 0x4b, 0x69, 0xaf, 0x23, 0x46, 0xb6, 0x46, 0x9e, 0xcc, 0x3a, 0xd7, 0x00,
+	0x5f, 0x61, 0x93, 0xcd, 0x40, 0x4b, 0x3f, 0x11, 0xa3, 0x24, 0x9a, 0x80, 0x55, 0x25, 0x21, 0xd8,
+	0x6d, 0x23, 0x3c, 0x66, 0x61, 0xb7, 0x5d, 0x49, 0x8a, 0x7f, 0x8d, 0xb0, 0xcd, 0x21, 0x37, 0x9f,
+	0xe3, 0x30, 0xd0, 0x8d, 0x83, 0x8a, 0xf3, 0x73, 0x80, 0x27, 0xe8, 0xf0, 0x25, 0x36, 0x51, 0x97,
+	0x9e, 0xce, 0xac, 0xaf, 0xf1, 0xfa, 0xa1, 0xce, 0xf8, 0x1a, 0x9b, 0x42, 0x7b, 0xa9, 0x36, 0x7e,
+	0x46, 0x1d, 0x34, 0x7f, 0xa6, 0x13, 0xfe, 0x90, 0xcd, 0x65, 0x6d, 0x19, 0x47, 0x57, 0x2a, 0x91,
+	0x81, 0xf6, 0x55, 0xdb, 0x3a, 0xcc, 0x67, 0xed, 0x53, 0x04, 0x8f, 0x11, 0xe3, 0x0f, 0xd8, 0xec,
+	0x85, 0x9b, 0xa9, 0x2b, 0xb7, 0x23, 0xbd, 0xa8, 0x05, 0xb6, 0xc7, 0x0d, 0xc9, 0x82, 0x87, 0x88,
+	0x15, 0xff, 0x5c, 0x65, 0xf3, 0x43, 0x9b, 0xe3, 0xcf, 0xd8, 0xa2, 0x8d, 0x36, 0x84, 0xa9, 0x1e,
+	0x84, 0x4a, 0x06, 0x3e, 0x6d, 0x6c, 0xc6, 0x99, 0x37, 0x82, 0x53, 0x83, 0x1f, 0xfb, 0xfc, 0x39,
+	0xe3, 0xa9, 0x4a, 0x86, 0xc9, 0xa3, 0x44, 0x5e, 0xb0, 0x92, 0x01, 0x76, 0x12, 0xb5, 0xb2, 0x40,
+	0x5f, 0xf4, 0xb3, 0xc7, 0x0c, 0xdb, 0x4a, 0xae, 0xd9, 0xeb, 0x6c, 0x1a, 0xdc, 0x49, 0xd7, 0x87,
+	0x88, 0xe2, 0xde, 0xf3, 0xce, 0x14, 0xac, 0x0f, 0x60, 0x89, 0xa1, 0x41, 0x91, 0x6a, 0x05, 0x62,
+	0x82, 0x24, 0x93, 0xb0, 0xac, 0xb6, 0x02, 0xd4, 0xf9, 0x3d, 0x82, 0xbb, 0x41, 0xc9, 0xa4, 0xd1,
+	0xc1, 0x35, 0x8a, 0x1e, 0xb2, 0xf9, 0xba, 0xd4, 0x57, 0x0d, 0x99, 0x42, 0xd0, 0x32, 0xd9, 0x50,
+	0x1d, 0x31, 0x45, 0x8c, 0x5c, 0xfd, 0xe4, 0xaa, 0x71, 0x76, 0xac, 0xb3, 0x5f, 0x54, 0x07, 0x59,
+	0xe9, 0x10, 0x6b, 0xda, 0xb0, 0xd2, 0x3e, 0xd6, 0x36, 0x9b, 0x35, 0x1c, 0xc8, 0x07, 0xe2, 0xcc,
+	0x10, 0x87, 0x01, 0x78, 0x56, 0xd5, 0x1e, 0x52, 0x7e, 0x62, 0xdc, 0x8d, 0x63, 0xa0, 0x80, 0x18,
+	0x68, 0x97, 0x2a, 0x8c, 0x62, 0x25, 0x5e, 0x00, 0x2f, 0x57, 0x5e, 0x2a, 0xd9, 0x3c, 0x04, 0x62,
+	0xd5, 0x8a, 0x9c, 0x79, 0xa0, 0x9f, 0xf5, 0x01, 0x5c, 0xb0, 0x69, 0x4a, 0x0a, 0xd9, 0x8a, 0x05,
+	0xa3, 0xbb, 0x9b, 0xc4, 0xbc, 0xf8, 0x1c, 0xf3, 0x4d, 0x96, 0xd7, 0xd2, 0xc8, 0xfc, 0xe8, 0x4a,
+	0x8b, 0x9c, 0xc9, 0x50, 0x7d, 0x04, 0xe2, 0x0a, 0x00, 0x48, 0x70, 0xfb, 0x09, 0x79, 0x43, 0x70,
+	0x7b, 0x84, 0x7b, 0x8c, 0x79, 0x91, 0xae, 0x1b, 0x8e, 0x78, 0x4c, 0xe2, 0x69, 0x44, 0x90, 0xc1,
+	0x1f, 0xb3, 0x85, 0xb4, 0x11, 0xc4, 0xd6, 0x82, 0xf7, 0x55, 0x79, 0x0d, 0x31, 0x0b, 0x9c, 0x69,
+	0x67, 0x16, 0x71, 0xe4, 0x1c, 0x22, 0x88, 0xe1, 0x4e, 0x20, 0xe3, 0x55, 0xe8, 0x76, 0xc4, 0x1c,
+	0x19, 0x99, 0x4a, 0xda, 0x15, 0x5c, 0xf2, 0x22, 0x9b, 0x4d, 0xda, 0x7b, 0x50, 0x0d, 0x32, 0xaa,
+	0xd7, 0x53, 0x95, 0x89, 0x79, 0x92, 0xe7, 0x00, 0xac, 0x24, 0xbf, 0x12, 0x84, 0x15, 0x93, 0xb4,
+	0xcb, 0x58, 0x31, 0x0b, 0xa6, 0x62, 0x60, 0x05, 0x85, 0xf4, 0x00, 0x55, 0xcb, 0xf2, 0xba, 0x02,
+	0x17, 0x4d, 0xe6, 0x02, 0x78, 0xd4, 0x2b, 0xc2, 0x9b, 0x45, 0xc0, 0x6f, 0x29, 0x82, 0x39, 0x36,
+	0x0a, 0xd6, 0x97, 0x48, 0x02, 0xff, 0xf8, 0x02, 0x1b, 0x73, 0x01, 0x58, 0xa6, 0xc3, 0xe0, 0x5f,
+	0xfe, 0x23, 0xbb, 0x47, 0x55, 0xd6, 0x8a, 0xe3, 0x28, 0xc9, 0x94, 0x2f, 0x87, 0xac, 0xae, 0x90,
+	0xae, 0xc0, 0xd2, 0xeb, 0x52, 0xce, 0xfb, 0x3d, 0x40, 0x08, 0x74, 0x4d, 0x66, 0x89, 0xab, 0x53,
+	0xb1, 0x66, 0x42, 0xa0, 0x6b, 0xe7, 0xb8, 0xe4, 0xaf, 0xd9, 0x9a, 0xd2, 0x6e, 0x2d, 0x04, 0xa3,
+	0x2d, 0xaa, 0x78, 0x08, 0x25, 0xf5, 0x97, 0x54, 0x88, 0xad, 0x31, 0x60, 0xae, 0x58, 0xb1, 0xe9,
+	0x07, 0xb6, 0xf9, 0xa4, 0x5c, 0xb1, 0x15, 0xd5, 0x06, 0x8b, 0x37, 0xb4, 0xd6, 0x41, 0x2b, 0x57,
+	0xde, 0x2b, 0xd9, 0xb6, 0x57, 0x1a, 0xaa, 0xdc, 0x52, 0x15, 0xb5, 0x06, 0x8d, 0x55, 0x75, 0x96,
+	0x74, 0x9c, 0x25, 0x75, 0x53, 0xc2, 0x77, 0xd8, 0x92, 0xb5, 0xdc, 0x0b, 0x75, 0xa0, 0x52, 0x51,
+	0xa0, 0xad, 0x71, 0x2b, 0x3a, 0xba, 0x96, 0xf0, 0x2f, 0x8c, 0xdb, 0x1d, 0x41, 0xe0, 0xe4, 0x57,
+	0xd3, 0xbb, 0xc4, 0x37, 0xb4, 0xa9, 0x27, 0x77, 0x6d, 0x6a, 0xb8, 0xd7, 0x39, 0x0b, 0xc6, 0xc6,
+	0x81, 0x9f, 0x74, 0xbb, 0x9f, 0xc3, 0x1e, 0x87, 0x6e, 0x0a, 0xa9, 0x6a, 0x7b, 0x7c, 0xe6, 0x66,
+	0xad, 0x54, 0x92, 0x63, 0x40, 0xb1, 0x95, 0xcb, 0x96, 0x0e, 0xda, 0x12, 0x22, 0xbc, 0x01, 0x11,
+	0x1e, 0x73, 0xb6, 0x91, 0x6e, 0xfd, 0x10, 0xd9, 0x31, 0xdc, 0x73, 0xa0, 0x7e, 0x06, 0xe6, 0x49,
+	0xca, 0x8f, 0x59, 0xd1, 0xd8, 0x84, 0x6c, 0xa7, 0x2d, 0xc3, 0xb5, 0xf6, 0x1e, 0x85, 0x9e, 0xb9,
+	0x2d, 0x32, 0xb7, 0x41, 0xe6, 0x2c, 0xf1, 0xbc, 0x7d, 0xde, 0xa5, 0x59, 0x53, 0x90, 0x8e, 0x35,
+	0xe5, 0x42, 0x71, 0xc8, 0x30, 0xf2, 0x1a, 0xca, 0x17, 0xdb, 0x94, 0x3d, 0x79, 0x03, 0x7e, 0x24,
+	0x8c, 0x6f, 0xb1, 0x7c, 0x8c, 0x7d, 0x2d, 0x0d, 0xa3, 0x4c, 0xea, 0x9a, 0x28, 0x52, 0x2a, 0x30,
+	0xc4, 0xce, 0x00, 0x3a, 0xa9, 0x0d, 0x32, 0x20, 0x07, 0x1f, 0x0c, 0x32, 0x20, 0xef, 0x4b, 0x6c,
+	0xe9, 0x9a, 0x71, 0x9d, 0xfd, 0x0f, 0x89, 0xb8, 0xd8, 0x25, 0x5e, 0x97, 0xc0, 0x26, 0xcb, 0x35,
+	0x5d, 0x4f, 0x5e, 0xaa, 0x04, 0x43, 0x2d, 0x1e, 0x51, 0x1f, 0x65, 0x00, 0x7d, 0x31, 0x08, 0xe5,
+	0x36, 0x34, 0xc3, 0x3b, 0x73, 0xfb, 0x5b, 0x9b, 0xdb, 0x81, 0xbe, 0x3d, 0xb7, 0xf7, 0xd9, 0x6a,
+	0xa2, 0xa8, 0x9f, 0x76, 0x2f, 0xc3, 0x26, 0xac, 0x78, 0x4e, 0x21, 0x58, 0x36, 0x52, 0x1b, 0xfd,
+	0xaa, 0x91, 0xf1, 0x77, 0xac, 0x30, 0xa4, 0x85, 0x05, 0x46, 0x6f, 0x90, 0xd4, 0xe2, 0x09, 0xf9,
+	0x5c, 0x1d, 0xd0, 0xfc, 0xe4, 0xb6, 0xe9, 0x39, 0x3a, 0xe1, 0x6f, 0xd8, 0xfa, 0x2d, 0xba, 0x94,
+	0x02, 0x5a, 0x3c, 0x25, 0xd5, 0x95, 0x61, 0x55, 0xbc, 0xaf, 0x13, 0xec, 0x07, 0x56, 0xd3, 0x78,
+	0xda, 0x15, 0xcf, 0x6c, 0xd7, 0x20, 0x94, 0xec, 0xef, 0xf2, 0x03, 0xb6, 0x11, 0x2b, 0xed, 0x63,
+	0x94, 0x2d, 0x7b, 0x70, 0xb0, 0x10, 0xdf, 0x51, 0x23, 0x2f, 0x58, 0x92, 0x43, 0x9c, 0x81, 0x8c,
+	0xe6, 0x2f, 0xe0, 0x11, 0x53, 0x75, 0x95, 0xc0, 0x15, 0x28, 0xe9, 0x86, 0x59, 0x90, 0xb5, 0x7c,
+	0x25, 0x4a, 0xa0, 0x37, 0xe2, 0x2c, 0xf6, 0x24, 0x07, 0x56, 0xc0, 0x5f, 0xb1, 0x35, 0x5b, 0x34,
+	0xfe, 0x95, 0x0a, 0x43, 0x73, 0x96, 0xfd, 0xdd, 0xdd, 0x66, 0x2a, 0x76, 0x4c, 0x10, 0x8d, 0xb8,
+	0x82, 0x52, 0x3c, 0x0a, 0xc9, 0xf8, 0x5b, 0xb6, 0xde, 0x4b, 0xdd, 0x1b, 0x8a, 0xbb, 0xa4, 0xb8,
+	0xda, 0x25, 0x0c, 0xa9, 0xee, 0xb1, 0x15, 0xeb, 0x11, 0x63, 0xa7, 0x82, 0x24, 0xb6, 0xd7, 0xbd,
+	0x47, 0x01, 0xb1, 0x35, 0x0c, 0x81, 0xab, 0x82, 0xc8, 0x5c, 0x74, 0xc0, 0xd6, 0x30, 0x93, 0xf0,
+	0x55, 0x72, 0xb5, 0x2f, 0x55, 0x92, 0x44, 0x89, 0x9d, 0x1a, 0xca, 0x54, 0xde, 0xe5, 0x3b, 0x7b,
+	0xce, 0x27, 0xd7, 0x3b, 0x34, 0x6a, 0x55, 0xd4, 0xa2, 0x38, 0x9b, 0xa6, 0xb3, 0xdc, 0xbc, 0x45,
+	0x84, 0x49, 0x1b, 0xa4, 0xd2, 0x0f, 0x52, 0x93, 0x48, 0x2f, 0xe9, 0x28, 0x2c, 0x48, 0x2b, 0x16,
+	0xe1, 0x4f, 0xd9, 0x22, 0xb6, 0x17, 0xd7, 0x6b, 0xc8, 0x30, 0x68, 0x06, 0x90, 0x73, 0xed, 0x58,
+	0xec, 0xd3, 0xd6, 0xe7, 0x40, 0x70, 0xe0, 0x35, 0x3e, 0x22, 0x5c, 0x6d, 0xc7, 0xfd, 0x54, 0x7a,
+	0x83, 0x88, 0xfa, 0xaa, 0x9f, 0x4a, 0x6f, 0x11, 0x52, 0x7d, 0xb6, 0xdc, 0x8b, 0x67, 0x7f, 0xb7,
+	0x7b, 0xfd, 0x1f, 0x2d, 0xb5, 0xdb, 0x16, 0xfa, 0xfa, 0xa0, 0x6d, 0xa9, 0xfe, 0x4d, 0x09, 0xbf,
+	0x8f, 0x15, 0x09, 0x0f, 0xa2, 0xf4, 0x3a, 0x5e, 0xa8, 0xc4, 0xf7, 0xe6, 0xd9, 0xc5, 0x39, 0xf0,
+	0x10, 0x01, 0x1c, 0xad, 0x62, 0xd8, 0xad, 0xca, 0x6c, 0x88, 0x13, 0x98, 0xc5, 0xc4, 0x1b, 0x4a,
+	0x9d, 0x79, 0x23, 0xa0, 0x48, 0x39, 0x00, 0x63, 0xbf, 0xc0, 0xc3, 0xf5, 0x1e, 0x97, 0xb7, 0xa6,
+	0x5f, 0x00, 0x76, 0x62, 0xdf, 0x17, 0xe8, 0x17, 0x7e, 0x2b, 0xeb, 0x18, 0x67, 0x70, 0x76, 0x4f,
+	0x29, 0x1f, 0x42, 0xfa, 0x8e, 0x42, 0xba, 0x88, 0x22, 0xf2, 0x5a, 0xb5, 0x02, 0xfe, 0x88, 0xcd,
+	0x77, 0xc3, 0x05, 0x81, 0x92, 0xf8, 0x28, 0xff, 0x60, 0x5a, 0x99, 0x09, 0x16, 0xc4, 0xe9, 0x4c,
+	0x65, 0x85, 0x0b, 0x26, 0xee, 0x7a, 0x48, 0xf0, 0xfd, 0xc4, 0x71, 0xc7, 0x8c, 0xa9, 0xf8, 0x17,
+	0xf2, 0x7b, 0xe2, 0xd2, 0x0d, 0x5b, 0x8a, 0x86, 0xbe, 0x5c, 0x79, 0xf3, 0xae, 0x48, 0x5a, 0x3b,
+	0x8e, 0x61, 0xbf, 0x1b, 0x7d, 0x33, 0x52, 0xf8, 0xc0, 0xd6, 0xef, 0xcc, 0x9e, 0x5b, 0x3c, 0x2d,
+	0xf7, 0x7b, 0x9a, 0xed, 0x37, 0x74, 0xc4, 0xc4, 0x5d, 0xf7, 0xf4, 0x7f, 0xec, 0x14, 0x3b, 0x60,
+	0x87, 0x76, 0xfd, 0xc1, 0xcc, 0xc8, 0xce, 0x6f, 0xc7, 0xba, 0x1e, 0x41, 0x4c, 0x60, 0x2a, 0xee,
+	0x1b, 0x39, 0x47, 0x06, 0x46, 0x4e, 0x33, 0x62, 0x8c, 0xf6, 0x46, 0x8c, 0x7d, 0x36, 0x11, 0x64,
+	0x0a, 0xaa, 0x74, 0x8c, 0x52, 0xeb, 0xfe, 0x50, 0x40, 0x06, 0x4c, 0x9f, 0xbe, 0x77, 0x0c, 0xb9,
+	0xf8, 0xf7, 0x08, 0x5b, 0xb9, 0x95, 0xc0, 0x37, 0x18, 0xeb, 0xce, 0xf1, 0x76, 0x0e, 0xcf, 0x3b,
+	0x33, 0x16, 0x81, 0x29, 0x99, 0xb3, 0xf1, 0x04, 0x42, 0x4c, 0x1b, 0x98, 0x70, 0xe8, 0x3f, 0xce,
+	0x24, 0x21, 0xf8, 0xa4, 0x4f, 0x87, 0x31, 0xca, 0xae, 0x29, 0x5c, 0xe3, 0xb7, 0x03, 0x1c, 0xbe,
+	0x16, 0xb9, 0x89, 0x6f, 0xbf, 0x06, 0xcc, 0x02, 0x46, 0xcd, 0x29, 0x57, 0x67, 0x4a, 0x6b, 0x97,
+	0xe6, 0x69, 0x98, 0x61, 0xec, 0x12, 0x25, 0xf0, 0xc8, 0x65, 0x30, 0x3f, 0x74, 0xe7, 0x69, 0xbb,
+	0x2c, 0xfe, 0x33, 0xca, 0x36, 0x4e, 0x5d, 0x70, 0x77, 0xa9, 0x9c, 0xc8, 0x85, 0x37, 0xe4, 0x62,
+	0xf8, 0x43, 0x02, 0x76, 0x6e, 0xdb, 0x6a, 0xdf, 0xce, 0x2d, 0x02, 0x3b, 0x87, 0xe9, 0x4f, 0x43,
+	0x25, 0xd8, 0xef, 0x85, 0xbc, 0x33, 0x01, 0xab, 0xa1, 0xb1, 0x7f, 0xec, 0xce, 0xb1, 0x7f, 0x7c,
+	0xe0, 0x0e, 0xa0, 0xee, 0xf0, 0x80, 0x57, 0xae, 0x96, 0x7b, 0x72, 0x8f, 0xce, 0x30, 0xed, 0xcc,
+	0x58, 0x68, 0x6f, 0xef, 0xb6, 0xd9, 0x7f, 0xf2, 0xe6, 0xec, 0xff, 0x1a, 0xc2, 0x16, 0xd4, 0x15,
+	0x36, 0x5a, 0xfa, 0x34, 0xc8, 0x95, 0x0b, 0x25, 0xf3, 0x61, 0x59, 0xea, 0x7e, 0x58, 0x96, 0x7a,
+	0xc3, 0x81, 0xd3, 0xe3, 0x0e, 0x0c, 0xea, 0xd3, 0x03, 0x83, 0xfa, 0x36, 0xcb, 0x43, 0x76, 0x05,
+	0x3e, 0xdc, 0x96, 0x6c, 0x06, 0x1e, 0x7d, 0x26, 0x4c, 0x3b, 0xb9, 0x2e, 0xf6, 0x29, 0xf0, 0x6a,
+	0x93, 0x64, 0xfa, 0xe5, 0xbf, 0x82, 0x03, 0x64, 0xc7, 0xe2, 0x0e, 0x00, 0x00,
 }
@@ -153,6 +153,8 @@ func (ts *StorageTestSuite) TestDeviceSession() {
 		ExtraUplinkChannels:  map[int]loraband.Channel{},
 		RX2Frequency:         869525000,
 		MACCommandErrorCount: make(map[lorawan.CID]int),
+		ADRAckLimitExp:       DefaultADRAckLimitExp,
+		ADRAckDelayExp:       DefaultADRAckDelayExp,
 	}
 
 	ts.T().Run("Get non existing", func(t *testing.T) {
@@ -172,6 +174,34 @@ func (ts *StorageTestSuite) TestDeviceSession() {
 			assert.Equal(s, s2)
 		})
 
+		t.Run("ADR_ACK exponents 0 / 0", func(t *testing.T) {
+			assert := require.New(t)
+
+			s2 := s
+			s2.ADRAckLimitExp = 0
+			s2.ADRAckDelayExp = 0
+			assert.NoError(SaveDeviceSession(context.Background(), s2))
+
+			s2, err := GetDeviceSession(context.Background(), s.DevEUI)
+			assert.NoError(err)
+			assert.Equal(0, s2.ADRAckLimitExp)
+			assert.Equal(0, s2.ADRAckDelayExp)
+		})
+
+		t.Run("Without ADR_ACK exponents", func(t *testing.T) {
+			assert := require.New(t)
+
+			// device-sessions stored before the ADR_ACK exponents were added
+			dsPB := deviceSessionToPB(s)
+			dsPB.AdrAckLimitExp = 0
+			dsPB.AdrAckDelayExp = 0
+			dsPB.AdrAckExpSet = false
+
+			s2 := deviceSessionFromPB(dsPB)
+			assert.Equal(DefaultADRAckLimitExp, s2.ADRAckLimitExp)
+			assert.Equal(DefaultADRAckDelayExp, s2.ADRAckDelayExp)
+		})
+
 		t.Run("Delete", func(t *testing.T) {
 			assert := require.New(t)
 			assert.NoError(DeleteDeviceSession(context.Background(), s.DevEUI))
@@ -51,6 +51,19 @@ func (ts *StorageTestSuite) TestDevice() {
 			assert.Equal(d, dGet)
 		})
 
+		t.Run("GetDevices", func(t *testing.T) {
+			assert := require.New(t)
+
+			devices, err := GetDevices(ctx, ts.Tx(), 10, 0)
+			assert.NoError(err)
+			assert.Len(devices, 1)
+			assert.Equal(d.DevEUI, devices[0].DevEUI)
+
+			devices, err = GetDevices(ctx, ts.Tx(), 10, 1)
+			assert.NoError(err)
+			assert.Len(devices, 0)
+		})
+
 		t.Run("Update", func(t *testing.T) {
 			assert := require.New(t)
 
@@ -163,6 +176,56 @@ func (ts *StorageTestSuite) TestDeviceActivation() {
 			assert := require.New(t)
 			assert.Equal(ErrAlreadyExists, ValidateDevNonce(ctx, ts.Tx(), joinEUI, d.DevEUI, lorawan.DevNonce(513), lorawan.JoinRequestType))
 		})
+
+		t.Run("DeleteDeviceActivationsBefore keeps the last activation", func(t *testing.T) {
+			assert := require.New(t)
+
+			count, err := DeleteDeviceActivationsBefore(ctx, ts.Tx(), time.Now().Add(time.Minute))
+			assert.NoError(err)
+			assert.EqualValues(1, count)
+
+			daGet, err := GetLastDeviceActivationForDevEUI(ctx, ts.Tx(), d.DevEUI)
+			assert.NoError(err)
+			assert.Equal(lorawan.DevNonce(513), daGet.DevNonce)
+
+			assert.NoError(ValidateDevNonce(ctx, ts.Tx(), joinEUI, d.DevEUI, da.DevNonce, lorawan.JoinRequestType))
+		})
 	})
 
+	ts.T().Run("LastDevNonce", func(t *testing.T) {
+		assert := require.New(t)
+
+		_, err := GetLastDevNonce(ctx, ts.Tx(), d.DevEUI)
+		assert.Equal(ErrDoesNotExist, err)
+
+		assert.NoError(SetLastDevNonce(ctx, ts.Tx(), d.DevEUI, 10))
+		nonce, err := GetLastDevNonce(ctx, ts.Tx(), d.DevEUI)
+		assert.NoError(err)
+		assert.Equal(lorawan.DevNonce(10), nonce)
+
+		t.Run("Equal dev-nonce", func(t *testing.T) {
+			assert := require.New(t)
+			assert.Equal(ErrDevNonceNotIncremented, SetLastDevNonce(ctx, ts.Tx(), d.DevEUI, 10))
+		})
+
+		t.Run("Lower dev-nonce", func(t *testing.T) {
+			assert := require.New(t)
+			assert.Equal(ErrDevNonceNotIncremented, SetLastDevNonce(ctx, ts.Tx(), d.DevEUI, 9))
+		})
+
+		t.Run("Incremented dev-nonce", func(t *testing.T) {
+			assert := require.New(t)
+			assert.NoError(SetLastDevNonce(ctx, ts.Tx(), d.DevEUI, 11))
+			nonce, err := GetLastDevNonce(ctx, ts.Tx(), d.DevEUI)
+			assert.NoError(err)
+			assert.Equal(lorawan.DevNonce(11), nonce)
+		})
+
+		t.Run("Delete", func(t *testing.T) {
+			assert := require.New(t)
+			assert.NoError(DeleteLastDevNonce(ctx, ts.Tx(), d.DevEUI))
+			_, err := GetLastDevNonce(ctx, ts.Tx(), d.DevEUI)
+			assert.Equal(ErrDoesNotExist, err)
+		})
+	})
 }
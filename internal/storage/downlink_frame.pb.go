@@ -37,7 +37,9 @@ type DownlinkFrame struct {
 	// Encrypted FOpts (LoRaWAN 1.1).
 	EncryptedFopts bool `protobuf:"varint,7,opt,name=encrypted_fopts,json=encryptedFopts,proto3" json:"encrypted_fopts,omitempty"`
 	// Network session encryption key (for FOpts).
-	NwkSEncKey           []byte   `protobuf:"bytes,8,opt,name=nwk_s_enc_key,json=nwkSEncKey,proto3" json:"nwk_s_enc_key,omitempty"`
+	NwkSEncKey []byte `protobuf:"bytes,8,opt,name=nwk_s_enc_key,json=nwkSEncKey,proto3" json:"nwk_s_enc_key,omitempty"`
+	// Sent while the device exceeded its downlink rate.
+	RateLimited          bool     `protobuf:"varint,9,opt,name=rate_limited,json=rateLimited,proto3" json:"rate_limited,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -124,6 +126,13 @@ func (m *DownlinkFrame) GetNwkSEncKey() []byte {
 	return nil
 }
 
+func (m *DownlinkFrame) GetRateLimited() bool {
+	if m != nil {
+		return m.RateLimited
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*DownlinkFrame)(nil), "storage.DownlinkFrame")
 }
@@ -133,23 +142,23 @@ func init() {
 }
 
 var fileDescriptor_6d3c072e28619f9c = []byte{
-	// 277 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x90, 0x41, 0x4b, 0xc3, 0x30,
-	0x18, 0x86, 0xe9, 0xdc, 0xd6, 0x91, 0xd9, 0xa9, 0x71, 0x60, 0xf0, 0x54, 0xbd, 0xd8, 0xc3, 0xa8,
-	0xa0, 0x17, 0xef, 0xba, 0xc9, 0xf0, 0x22, 0xf5, 0x07, 0x84, 0xda, 0x7c, 0x0d, 0xa1, 0xdd, 0x97,
-	0x92, 0xa6, 0x0b, 0xfd, 0x61, 0xfe, 0x3f, 0x69, 0x3b, 0x06, 0x3d, 0xe6, 0x7d, 0x5e, 0x78, 0x9f,
-	0x7c, 0x64, 0x2d, 0xb4, 0xc3, 0x52, 0x61, 0xc1, 0x73, 0x93, 0x1e, 0x20, 0xae, 0x8c, 0xb6, 0x9a,
-	0xfa, 0xb5, 0xd5, 0x26, 0x95, 0x70, 0xbf, 0x94, 0xee, 0x59, 0xba, 0x21, 0x7d, 0xfc, 0x9b, 0x90,
-	0xe0, 0xe3, 0x54, 0xdf, 0x75, 0x6d, 0xba, 0x26, 0x33, 0xab, 0x0b, 0x40, 0xe6, 0x85, 0x5e, 0x14,
-	0x24, 0xc3, 0x83, 0xde, 0x11, 0x5f, 0xc0, 0x91, 0x43, 0xa3, 0xd8, 0x24, 0xf4, 0xa2, 0xcb, 0x64,
-	0x2e, 0xe0, 0xb8, 0x6d, 0x14, 0xdd, 0x10, 0x7a, 0x68, 0x4a, 0xab, 0xb2, 0xb4, 0xb6, 0x5c, 0x1a,
-	0xdd, 0x54, 0x5c, 0x09, 0x76, 0xd1, 0x77, 0xae, 0xcf, 0xe4, 0xb3, 0x03, 0x7b, 0x41, 0xdf, 0xc8,
-	0x6a, 0x2c, 0xc7, 0xa6, 0xa1, 0x17, 0x2d, 0x5f, 0x6e, 0x62, 0xe9, 0xe2, 0x91, 0x47, 0x12, 0x88,
-	0x91, 0xd6, 0x86, 0x50, 0xa3, 0x1b, 0xab, 0x50, 0xf2, 0xca, 0xe8, 0x5c, 0x95, 0xd0, 0xed, 0xcc,
-	0x86, 0x9d, 0x13, 0xf9, 0x1e, 0xc0, 0x5e, 0xd0, 0x5b, 0x32, 0xcb, 0x79, 0x86, 0x96, 0xcd, 0xfb,
-	0x4f, 0x4c, 0xf3, 0x77, 0xb4, 0xf4, 0x89, 0x5c, 0x01, 0x66, 0xa6, 0xad, 0x2c, 0x08, 0x9e, 0xeb,
-	0xca, 0xd6, 0xcc, 0x0f, 0xbd, 0x68, 0x91, 0xac, 0xce, 0xf1, 0xae, 0x4b, 0xe9, 0x03, 0x09, 0xd0,
-	0x15, 0xbc, 0xe6, 0x80, 0x19, 0x2f, 0xa0, 0x65, 0x8b, 0x7e, 0x86, 0xa0, 0x2b, 0x7e, 0xb6, 0x98,
-	0x7d, 0x41, 0xfb, 0x3b, 0xef, 0xcf, 0xf7, 0xfa, 0x1f, 0x00, 0x00, 0xff, 0xff, 0xae, 0x4d, 0xd6,
-	0x78, 0x6c, 0x01, 0x00, 0x00,
+	// 286 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x03, 0x55, 0x90, 0x41, 0x4f, 0x83, 0x40,
+	0x10, 0x85, 0xd3, 0x5a, 0xa0, 0x2e, 0xa5, 0xea, 0xda, 0xc4, 0x8d, 0x27, 0xf5, 0xa2, 0x07, 0x53,
+	0x13, 0xbd, 0x78, 0xb7, 0xad, 0x31, 0x7a, 0x30, 0xf8, 0x03, 0x36, 0xc8, 0x0e, 0x64, 0x03, 0xec,
+	0x92, 0x65, 0x29, 0xe1, 0x9f, 0xfa, 0x73, 0x1c, 0xa0, 0x69, 0xc2, 0x71, 0xbe, 0xf7, 0x76, 0xde,
+	0x9b, 0x25, 0x2b, 0xa1, 0x1b, 0x95, 0x4b, 0x95, 0xf1, 0xc4, 0x44, 0x05, 0xac, 0x4b, 0xa3, 0xad,
+	0xa6, 0x5e, 0x65, 0xb5, 0x89, 0x52, 0xb8, 0xf6, 0xd3, 0xe6, 0x29, 0x6d, 0x06, 0x7a, 0xf7, 0x37,
+	0x25, 0xc1, 0xe6, 0x60, 0xdf, 0x75, 0x6e, 0xba, 0x22, 0x8e, 0xd5, 0x19, 0x28, 0x36, 0xb9, 0x99,
+	0x3c, 0x04, 0xe1, 0x30, 0xd0, 0x2b, 0xe2, 0x09, 0xd8, 0x73, 0xa8, 0x25, 0x9b, 0x22, 0x5f, 0x84,
+	0x2e, 0x8e, 0xdb, 0x5a, 0xd2, 0x47, 0x42, 0x8b, 0x3a, 0xb7, 0x32, 0x8e, 0x2a, 0xcb, 0x53, 0xa3,
+	0xeb, 0x92, 0x4b, 0xc1, 0x4e, 0x7a, 0xcf, 0xf9, 0x51, 0x79, 0xef, 0x84, 0x0f, 0x41, 0x5f, 0xc9,
+	0x72, 0x5c, 0x8e, 0xcd, 0xd0, 0xe9, 0x3f, 0x5f, 0xac, 0xb1, 0xd1, 0xa8, 0x47, 0x18, 0x88, 0x51,
+	0x2d, 0xcc, 0xc1, 0x1d, 0x56, 0xaa, 0x94, 0x63, 0xf3, 0x44, 0xe6, 0xd0, 0xe5, 0x38, 0x43, 0xce,
+	0x41, 0xf9, 0x1e, 0x04, 0xcc, 0xb9, 0x24, 0x4e, 0xc2, 0x63, 0x65, 0x99, 0xdb, 0x1f, 0x31, 0x4b,
+	0xde, 0x94, 0xa5, 0xf7, 0xe4, 0x0c, 0x54, 0x6c, 0xda, 0xd2, 0x82, 0xe0, 0x89, 0x2e, 0x6d, 0xc5,
+	0x3c, 0x94, 0xe7, 0xe1, 0xf2, 0x88, 0x77, 0x1d, 0xa5, 0xb7, 0x24, 0x50, 0x4d, 0xc6, 0x2b, 0x8e,
+	0x9c, 0x67, 0xd0, 0xb2, 0x79, 0x1f, 0x43, 0x10, 0xfe, 0x6c, 0x55, 0xfc, 0x09, 0x2d, 0x5a, 0x16,
+	0x26, 0xb2, 0xc0, 0x73, 0x59, 0x48, 0x7c, 0xc7, 0x4e, 0xfb, 0x45, 0x7e, 0xc7, 0xbe, 0x06, 0xf4,
+	0xeb, 0xf6, 0x3f, 0xfc, 0xf2, 0x0f, 0x90, 0xa2, 0x74, 0x67, 0x8f, 0x01, 0x00, 0x00,
 }
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+	"github.com/pkg/errors"
+
+	"github.com/brocaar/lorawan"
+)
+
+const deviceAirtimeKeyTempl = "lora:ns:device:%s:airtime"
+
+// AddDeviceUplinkAirtime adds the given airtime to the rolling airtime window
+// of the device. Records older than the given window duration are removed.
+// It returns the total airtime within the window, including the given
+// airtime.
+func AddDeviceUplinkAirtime(ctx context.Context, devEUI lorawan.EUI64, ts time.Time, airtime, window time.Duration) (time.Duration, error) {
+	key := fmt.Sprintf(deviceAirtimeKeyTempl, devEUI)
+	now := ts.UnixNano()
+
+	// the member must be unique within the set, therefore the timestamp is
+	// part of the member
+	member := fmt.Sprintf("%d:%d", now, int64(airtime))
+
+	pipe := RedisClient().TxPipeline()
+	pipe.ZAdd(key, &redis.Z{Score: float64(now), Member: member})
+	pipe.ZRemRangeByScore(key, "-inf", fmt.Sprintf("(%d", now-int64(window)))
+	members := pipe.ZRange(key, 0, -1)
+	pipe.PExpire(key, window)
+	if _, err := pipe.Exec(); err != nil {
+		return 0, errors.Wrap(err, "redis exec error")
+	}
+
+	return sumAirtimeMembers(members.Val())
+}
+
+// GetDeviceUplinkAirtime returns the total uplink airtime of the device
+// within the given window duration.
+func GetDeviceUplinkAirtime(ctx context.Context, devEUI lorawan.EUI64, window time.Duration) (time.Duration, error) {
+	key := fmt.Sprintf(deviceAirtimeKeyTempl, devEUI)
+	min := time.Now().Add(-window).UnixNano()
+
+	members, err := RedisClient().ZRangeByScore(key, &redis.ZRangeBy{
+		Min: fmt.Sprintf("%d", min),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return 0, errors.Wrap(err, "read airtime error")
+	}
+
+	return sumAirtimeMembers(members)
+}
+
+// sumAirtimeMembers returns the sum of the airtime of the given set members,
+// encoded as timestamp:airtime.
+func sumAirtimeMembers(members []string) (time.Duration, error) {
+	var total time.Duration
+	for _, m := range members {
+		parts := strings.SplitN(m, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		d, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			return 0, errors.Wrap(err, "parse airtime error")
+		}
+		total += time.Duration(d)
+	}
+
+	return total, nil
+}
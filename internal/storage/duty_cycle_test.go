@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/brocaar/lorawan"
+)
+
+func (ts *StorageTestSuite) TestDeviceUplinkAirtime() {
+	devEUI := lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8}
+
+	ts.T().Run("Add airtime", func(t *testing.T) {
+		assert := require.New(t)
+		now := time.Now()
+
+		total, err := AddDeviceUplinkAirtime(context.Background(), devEUI, now.Add(-2*time.Hour), time.Second, 3*time.Hour)
+		assert.NoError(err)
+		assert.Equal(time.Second, total)
+
+		total, err = AddDeviceUplinkAirtime(context.Background(), devEUI, now.Add(-30*time.Minute), 2*time.Second, 3*time.Hour)
+		assert.NoError(err)
+		assert.Equal(3*time.Second, total)
+
+		t.Run("Get airtime", func(t *testing.T) {
+			assert := require.New(t)
+
+			total, err := GetDeviceUplinkAirtime(context.Background(), devEUI, time.Hour)
+			assert.NoError(err)
+			assert.Equal(2*time.Second, total)
+		})
+
+		t.Run("Records outside window are removed", func(t *testing.T) {
+			assert := require.New(t)
+
+			total, err := AddDeviceUplinkAirtime(context.Background(), devEUI, now, 100*time.Millisecond, time.Hour)
+			assert.NoError(err)
+			assert.Equal(2100*time.Millisecond, total)
+
+			total, err = GetDeviceUplinkAirtime(context.Background(), devEUI, 3*time.Hour)
+			assert.NoError(err)
+			assert.Equal(2100*time.Millisecond, total)
+		})
+	})
+}
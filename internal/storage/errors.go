@@ -17,6 +17,7 @@ var (
 	ErrInvalidAggregationInterval = errors.New("invalid aggregation interval")
 	ErrInvalidName                = errors.New("invalid gateway name")
 	ErrInvalidFPort               = errors.New("invalid fPort (must be > 0)")
+	ErrDevNonceNotIncremented     = errors.New("dev-nonce did not increment")
 )
 
 func handlePSQLError(err error, description string) error {
@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brocaar/chirpstack-network-server/internal/logging"
+	"github.com/brocaar/lorawan"
+)
+
+// ForceRejoin defines a ForceRejoinReq mac-command sent to a device. It is
+// used to track the progress until the resulting rejoin-request has been
+// processed.
+type ForceRejoin struct {
+	DevEUI      lorawan.EUI64 `db:"dev_eui"`
+	CreatedAt   time.Time     `db:"created_at"`
+	UpdatedAt   time.Time     `db:"updated_at"`
+	RejoinType  int           `db:"rejoin_type"`
+	DR          int           `db:"dr"`
+	MaxRetries  int           `db:"max_retries"`
+	Period      int           `db:"period"`
+	SentAt      *time.Time    `db:"sent_at"`
+	CompletedAt *time.Time    `db:"completed_at"`
+}
+
+// CreateForceRejoin creates the given force-rejoin. An existing force-rejoin
+// for the same device is replaced.
+func CreateForceRejoin(ctx context.Context, db sqlx.Execer, fr *ForceRejoin) error {
+	now := time.Now()
+	fr.CreatedAt = now
+	fr.UpdatedAt = now
+	fr.SentAt = nil
+	fr.CompletedAt = nil
+
+	_, err := db.Exec(`
+		insert into force_rejoin (
+			dev_eui,
+			created_at,
+			updated_at,
+			rejoin_type,
+			dr,
+			max_retries,
+			period,
+			sent_at,
+			completed_at
+		) values ($1, $2, $3, $4, $5, $6, $7, null, null)
+		on conflict (dev_eui)
+			do update
+			set
+				created_at = excluded.created_at,
+				updated_at = excluded.updated_at,
+				rejoin_type = excluded.rejoin_type,
+				dr = excluded.dr,
+				max_retries = excluded.max_retries,
+				period = excluded.period,
+				sent_at = null,
+				completed_at = null`,
+		fr.DevEUI[:],
+		fr.CreatedAt,
+		fr.UpdatedAt,
+		fr.RejoinType,
+		fr.DR,
+		fr.MaxRetries,
+		fr.Period,
+	)
+	if err != nil {
+		return handlePSQLError(err, "insert error")
+	}
+
+	log.WithFields(log.Fields{
+		"dev_eui":     fr.DevEUI,
+		"rejoin_type": fr.RejoinType,
+		"ctx_id":      ctx.Value(logging.ContextIDKey),
+	}).Info("force-rejoin created")
+
+	return nil
+}
+
+// GetForceRejoin returns the force-rejoin for the given DevEUI.
+func GetForceRejoin(ctx context.Context, db sqlx.Queryer, devEUI lorawan.EUI64) (ForceRejoin, error) {
+	var fr ForceRejoin
+	err := sqlx.Get(db, &fr, "select * from force_rejoin where dev_eui = $1", devEUI[:])
+	if err != nil {
+		return fr, handlePSQLError(err, "select error")
+	}
+
+	return fr, nil
+}
+
+// GetForceRejoinsForDeviceProfile returns the force-rejoins of the devices
+// using the given device-profile, ordered by DevEUI.
+func GetForceRejoinsForDeviceProfile(ctx context.Context, db sqlx.Queryer, deviceProfileID uuid.UUID) ([]ForceRejoin, error) {
+	var items []ForceRejoin
+	err := sqlx.Select(db, &items, `
+		select
+			fr.*
+		from
+			force_rejoin fr
+		inner join device d
+			on d.dev_eui = fr.dev_eui
+		where
+			d.device_profile_id = $1
+		order by
+			fr.dev_eui`,
+		deviceProfileID,
+	)
+	if err != nil {
+		return nil, handlePSQLError(err, "select error")
+	}
+
+	return items, nil
+}
+
+// SetForceRejoinSent sets the sent timestamp of the uncompleted force-rejoin
+// of the given DevEUI. This is a no-op in case there is no such force-rejoin.
+func SetForceRejoinSent(ctx context.Context, db sqlx.Execer, devEUI lorawan.EUI64) error {
+	now := time.Now()
+	res, err := db.Exec(`
+		update force_rejoin
+		set
+			updated_at = $2,
+			sent_at = $2
+		where
+			dev_eui = $1
+			and completed_at is null`,
+		devEUI[:],
+		now,
+	)
+	if err != nil {
+		return handlePSQLError(err, "update error")
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return handlePSQLError(err, "get rows affected error")
+	}
+
+	if ra != 0 {
+		log.WithFields(log.Fields{
+			"dev_eui": devEUI,
+			"ctx_id":  ctx.Value(logging.ContextIDKey),
+		}).Info("force-rejoin sent")
+	}
+
+	return nil
+}
+
+// SetForceRejoinCompleted marks the sent and uncompleted force-rejoin of the
+// given DevEUI as completed, when it requested the given rejoin type. This is
+// a no-op in case there is no such force-rejoin, e.g. for periodic rejoin
+// requests or rejoin-requests received before the ForceRejoinReq was sent.
+func SetForceRejoinCompleted(ctx context.Context, db sqlx.Execer, devEUI lorawan.EUI64, rejoinType int) error {
+	now := time.Now()
+	res, err := db.Exec(`
+		update force_rejoin
+		set
+			updated_at = $2,
+			completed_at = $2
+		where
+			dev_eui = $1
+			and rejoin_type = $3
+			and sent_at is not null
+			and completed_at is null`,
+		devEUI[:],
+		now,
+		rejoinType,
+	)
+	if err != nil {
+		return handlePSQLError(err, "update error")
+	}
+
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return handlePSQLError(err, "get rows affected error")
+	}
+
+	if ra != 0 {
+		log.WithFields(log.Fields{
+			"dev_eui": devEUI,
+			"ctx_id":  ctx.Value(logging.ContextIDKey),
+		}).Info("force-rejoin completed")
+	}
+
+	return nil
+}
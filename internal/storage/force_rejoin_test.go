@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/brocaar/lorawan"
+)
+
+func (ts *StorageTestSuite) TestForceRejoin() {
+	assert := require.New(ts.T())
+	ctx := context.Background()
+
+	sp := ServiceProfile{}
+	dp := DeviceProfile{}
+	rp := RoutingProfile{}
+
+	assert.NoError(CreateServiceProfile(ctx, ts.Tx(), &sp))
+	assert.NoError(CreateDeviceProfile(ctx, ts.Tx(), &dp))
+	assert.NoError(CreateRoutingProfile(ctx, ts.Tx(), &rp))
+
+	d := Device{
+		DevEUI:           lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8},
+		ServiceProfileID: sp.ID,
+		DeviceProfileID:  dp.ID,
+		RoutingProfileID: rp.ID,
+	}
+	assert.NoError(CreateDevice(ctx, ts.Tx(), &d))
+
+	ts.T().Run("Create", func(t *testing.T) {
+		assert := require.New(t)
+
+		fr := ForceRejoin{
+			DevEUI:     d.DevEUI,
+			RejoinType: 2,
+			DR:         3,
+			MaxRetries: 4,
+			Period:     5,
+		}
+		assert.NoError(CreateForceRejoin(ctx, ts.Tx(), &fr))
+
+		fr.CreatedAt = fr.CreatedAt.Round(time.Second).UTC()
+		fr.UpdatedAt = fr.UpdatedAt.Round(time.Second).UTC()
+
+		t.Run("Get", func(t *testing.T) {
+			assert := require.New(t)
+
+			frGet, err := GetForceRejoin(ctx, ts.Tx(), d.DevEUI)
+			assert.NoError(err)
+
+			frGet.CreatedAt = frGet.CreatedAt.Round(time.Second).UTC()
+			frGet.UpdatedAt = frGet.UpdatedAt.Round(time.Second).UTC()
+
+			assert.Equal(fr, frGet)
+		})
+
+		t.Run("Get for device-profile", func(t *testing.T) {
+			assert := require.New(t)
+
+			items, err := GetForceRejoinsForDeviceProfile(ctx, ts.Tx(), dp.ID)
+			assert.NoError(err)
+			assert.Len(items, 1)
+			assert.Equal(d.DevEUI, items[0].DevEUI)
+		})
+
+		t.Run("Sent and completed", func(t *testing.T) {
+			assert := require.New(t)
+
+			// a rejoin-request received before the force-rejoin was sent
+			// does not complete it
+			assert.NoError(SetForceRejoinCompleted(ctx, ts.Tx(), d.DevEUI, 2))
+			frGet, err := GetForceRejoin(ctx, ts.Tx(), d.DevEUI)
+			assert.NoError(err)
+			assert.Nil(frGet.CompletedAt)
+
+			assert.NoError(SetForceRejoinSent(ctx, ts.Tx(), d.DevEUI))
+			frGet, err = GetForceRejoin(ctx, ts.Tx(), d.DevEUI)
+			assert.NoError(err)
+			assert.NotNil(frGet.SentAt)
+			assert.Nil(frGet.CompletedAt)
+
+			// a rejoin-request of a different type (e.g. periodic type 0)
+			// does not complete it
+			assert.NoError(SetForceRejoinCompleted(ctx, ts.Tx(), d.DevEUI, 0))
+			frGet, err = GetForceRejoin(ctx, ts.Tx(), d.DevEUI)
+			assert.NoError(err)
+			assert.Nil(frGet.CompletedAt)
+
+			assert.NoError(SetForceRejoinCompleted(ctx, ts.Tx(), d.DevEUI, 2))
+			frGet, err = GetForceRejoin(ctx, ts.Tx(), d.DevEUI)
+			assert.NoError(err)
+			assert.NotNil(frGet.CompletedAt)
+			completedAt := *frGet.CompletedAt
+
+			// a completed force-rejoin is not updated anymore
+			assert.NoError(SetForceRejoinCompleted(ctx, ts.Tx(), d.DevEUI, 2))
+			frGet, err = GetForceRejoin(ctx, ts.Tx(), d.DevEUI)
+			assert.NoError(err)
+			assert.True(completedAt.Equal(*frGet.CompletedAt))
+
+			t.Run("Re-create resets progress", func(t *testing.T) {
+				assert := require.New(t)
+
+				assert.NoError(CreateForceRejoin(ctx, ts.Tx(), &fr))
+				frGet, err := GetForceRejoin(ctx, ts.Tx(), d.DevEUI)
+				assert.NoError(err)
+				assert.Nil(frGet.SentAt)
+				assert.Nil(frGet.CompletedAt)
+			})
+		})
+	})
+
+	ts.T().Run("Get devices for device-profile", func(t *testing.T) {
+		assert := require.New(t)
+
+		devEUIs, err := GetDevEUIsForDeviceProfile(ctx, ts.Tx(), dp.ID)
+		assert.NoError(err)
+		assert.Equal([]lorawan.EUI64{d.DevEUI}, devEUIs)
+	})
+}
@@ -0,0 +1,247 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brocaar/chirpstack-network-server/internal/logging"
+)
+
+// FragmentationSession defines a fragmented data block transport session
+// over a multicast-group.
+type FragmentationSession struct {
+	ID                uuid.UUID  `db:"id"`
+	CreatedAt         time.Time  `db:"created_at"`
+	UpdatedAt         time.Time  `db:"updated_at"`
+	MulticastGroupID  uuid.UUID  `db:"multicast_group_id"`
+	FragIndex         uint8      `db:"frag_index"`
+	FragmentSize      int        `db:"fragment_size"`
+	Redundancy        int        `db:"redundancy"`
+	FragmentCount     int        `db:"fragment_count"` // uncoded + parity fragments
+	FragmentsEnqueued int        `db:"fragments_enqueued"`
+	Data              []byte     `db:"data"`
+	CompletedAt       *time.Time `db:"completed_at"`
+}
+
+// CreateFragmentationSession creates the given fragmentation-session.
+func CreateFragmentationSession(ctx context.Context, db sqlx.Execer, fs *FragmentationSession) error {
+	now := time.Now()
+	fs.CreatedAt = now
+	fs.UpdatedAt = now
+
+	if fs.ID == uuid.Nil {
+		var err error
+		fs.ID, err = uuid.NewV4()
+		if err != nil {
+			return errors.Wrap(err, "new uuid v4 error")
+		}
+	}
+
+	_, err := db.Exec(`
+		insert into fragmentation_session (
+			id,
+			created_at,
+			updated_at,
+			multicast_group_id,
+			frag_index,
+			fragment_size,
+			redundancy,
+			fragment_count,
+			fragments_enqueued,
+			data,
+			completed_at
+		) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		fs.ID,
+		fs.CreatedAt,
+		fs.UpdatedAt,
+		fs.MulticastGroupID,
+		fs.FragIndex,
+		fs.FragmentSize,
+		fs.Redundancy,
+		fs.FragmentCount,
+		fs.FragmentsEnqueued,
+		fs.Data,
+		fs.CompletedAt,
+	)
+	if err != nil {
+		return handlePSQLError(err, "insert error")
+	}
+
+	log.WithFields(log.Fields{
+		"id":                 fs.ID,
+		"multicast_group_id": fs.MulticastGroupID,
+		"ctx_id":             ctx.Value(logging.ContextIDKey),
+	}).Info("fragmentation-session created")
+
+	return nil
+}
+
+// GetFragmentationSession returns the fragmentation-session for the given ID.
+func GetFragmentationSession(ctx context.Context, db sqlx.Queryer, id uuid.UUID) (FragmentationSession, error) {
+	var fs FragmentationSession
+	err := sqlx.Get(db, &fs, `
+		select
+			*
+		from
+			fragmentation_session
+		where
+			id = $1`,
+		id,
+	)
+	if err != nil {
+		return fs, handlePSQLError(err, "select error")
+	}
+
+	return fs, nil
+}
+
+// GetFragmentationSessionsForMulticastGroup returns the fragmentation-sessions
+// of the given multicast-group, ordered by creation time. The data of the
+// sessions is not returned.
+func GetFragmentationSessionsForMulticastGroup(ctx context.Context, db sqlx.Queryer, multicastGroupID uuid.UUID) ([]FragmentationSession, error) {
+	var sessions []FragmentationSession
+	err := sqlx.Select(db, &sessions, `
+		select
+			id,
+			created_at,
+			updated_at,
+			multicast_group_id,
+			frag_index,
+			fragment_size,
+			redundancy,
+			fragment_count,
+			fragments_enqueued,
+			completed_at
+		from
+			fragmentation_session
+		where
+			multicast_group_id = $1
+		order by
+			created_at`,
+		multicastGroupID,
+	)
+	if err != nil {
+		return nil, handlePSQLError(err, "select error")
+	}
+
+	return sessions, nil
+}
+
+// UpdateFragmentationSession updates the progress of the given
+// fragmentation-session.
+func UpdateFragmentationSession(ctx context.Context, db sqlx.Execer, fs *FragmentationSession) error {
+	fs.UpdatedAt = time.Now()
+
+	res, err := db.Exec(`
+		update
+			fragmentation_session
+		set
+			updated_at = $2,
+			fragments_enqueued = $3,
+			completed_at = $4
+		where
+			id = $1`,
+		fs.ID,
+		fs.UpdatedAt,
+		fs.FragmentsEnqueued,
+		fs.CompletedAt,
+	)
+	if err != nil {
+		return handlePSQLError(err, "update error")
+	}
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return handlePSQLError(err, "get rows affected error")
+	}
+	if ra == 0 {
+		return ErrDoesNotExist
+	}
+
+	log.WithFields(log.Fields{
+		"id":                 fs.ID,
+		"fragments_enqueued": fs.FragmentsEnqueued,
+		"ctx_id":             ctx.Value(logging.ContextIDKey),
+	}).Info("fragmentation-session updated")
+
+	return nil
+}
+
+// DeleteFragmentationSession deletes the fragmentation-session matching the
+// given ID.
+func DeleteFragmentationSession(ctx context.Context, db sqlx.Execer, id uuid.UUID) error {
+	res, err := db.Exec(`
+		delete from
+			fragmentation_session
+		where
+			id = $1`,
+		id,
+	)
+	if err != nil {
+		return handlePSQLError(err, "delete error")
+	}
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return handlePSQLError(err, "get rows affected error")
+	}
+	if ra == 0 {
+		return ErrDoesNotExist
+	}
+
+	log.WithFields(log.Fields{
+		"id":     id,
+		"ctx_id": ctx.Value(logging.ContextIDKey),
+	}).Info("fragmentation-session deleted")
+
+	return nil
+}
+
+// GetSchedulableFragmentationSessions returns a slice of not completed
+// fragmentation-sessions for which the multicast-queue is empty, thus for
+// which the next fragment can be enqueued. Only the oldest session of each
+// multicast-group is returned.
+// The returned sessions will be locked for update so that this query can
+// be executed in parallel.
+func GetSchedulableFragmentationSessions(ctx context.Context, db sqlx.Ext, count int) ([]FragmentationSession, error) {
+	var sessions []FragmentationSession
+	err := sqlx.Select(db, &sessions, `
+		select
+			fs.*
+		from
+			fragmentation_session fs
+		where
+			fs.completed_at is null
+			and not exists (
+				select
+					1
+				from
+					multicast_queue mq
+				where
+					mq.multicast_group_id = fs.multicast_group_id
+			)
+			and not exists (
+				select
+					1
+				from
+					fragmentation_session fs2
+				where
+					fs2.multicast_group_id = fs.multicast_group_id
+					and fs2.completed_at is null
+					and fs2.created_at < fs.created_at
+			)
+		order by
+			fs.created_at
+		limit $1
+		for update of fs skip locked`,
+		count,
+	)
+	if err != nil {
+		return nil, handlePSQLError(err, "select error")
+	}
+
+	return sessions, nil
+}
@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/brocaar/lorawan"
+)
+
+func (ts *StorageTestSuite) TestFragmentationSession() {
+	assert := require.New(ts.T())
+
+	mg := ts.GetMulticastGroup()
+	assert.NoError(CreateMulticastGroup(context.Background(), ts.Tx(), &mg))
+
+	rp := RoutingProfile{
+		ASID: "localhost:1234",
+	}
+	assert.NoError(CreateRoutingProfile(context.Background(), ts.Tx(), &rp))
+
+	gw := Gateway{
+		GatewayID:        lorawan.EUI64{1, 1, 1, 1, 1, 1, 1, 1},
+		RoutingProfileID: rp.ID,
+	}
+	assert.NoError(CreateGateway(context.Background(), ts.Tx(), &gw))
+
+	ts.T().Run("Create", func(t *testing.T) {
+		assert := require.New(t)
+
+		fs := FragmentationSession{
+			MulticastGroupID: mg.ID,
+			FragIndex:        1,
+			FragmentSize:     10,
+			Redundancy:       2,
+			FragmentCount:    4,
+			Data:             []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12},
+		}
+		assert.NoError(CreateFragmentationSession(context.Background(), ts.Tx(), &fs))
+
+		fs.CreatedAt = fs.CreatedAt.Round(time.Second).UTC()
+		fs.UpdatedAt = fs.UpdatedAt.Round(time.Second).UTC()
+
+		t.Run("Get", func(t *testing.T) {
+			assert := require.New(t)
+
+			fsGet, err := GetFragmentationSession(context.Background(), ts.Tx(), fs.ID)
+			assert.NoError(err)
+
+			fsGet.CreatedAt = fsGet.CreatedAt.Round(time.Second).UTC()
+			fsGet.UpdatedAt = fsGet.UpdatedAt.Round(time.Second).UTC()
+
+			assert.Equal(fs, fsGet)
+		})
+
+		t.Run("Get for multicast-group", func(t *testing.T) {
+			assert := require.New(t)
+
+			sessions, err := GetFragmentationSessionsForMulticastGroup(context.Background(), ts.Tx(), mg.ID)
+			assert.NoError(err)
+			assert.Len(sessions, 1)
+			assert.Equal(fs.ID, sessions[0].ID)
+			assert.Equal(fs.FragmentCount, sessions[0].FragmentCount)
+			assert.Nil(sessions[0].Data)
+		})
+
+		t.Run("Get schedulable", func(t *testing.T) {
+			assert := require.New(t)
+
+			sessions, err := GetSchedulableFragmentationSessions(context.Background(), ts.Tx(), 10)
+			assert.NoError(err)
+			assert.Len(sessions, 1)
+			assert.Equal(fs.ID, sessions[0].ID)
+
+			// no sessions are returned when the multicast-queue is not empty
+			qi := MulticastQueueItem{
+				ScheduleAt:       time.Now(),
+				MulticastGroupID: mg.ID,
+				GatewayID:        gw.GatewayID,
+				FCnt:             10,
+				FPort:            201,
+			}
+			assert.NoError(CreateMulticastQueueItem(context.Background(), ts.Tx(), &qi))
+
+			sessions, err = GetSchedulableFragmentationSessions(context.Background(), ts.Tx(), 10)
+			assert.NoError(err)
+			assert.Len(sessions, 0)
+
+			assert.NoError(DeleteMulticastQueueItem(context.Background(), ts.Tx(), qi.ID))
+		})
+
+		t.Run("Update", func(t *testing.T) {
+			assert := require.New(t)
+
+			now := time.Now().Round(time.Second).UTC()
+			fs.FragmentsEnqueued = 4
+			fs.CompletedAt = &now
+			assert.NoError(UpdateFragmentationSession(context.Background(), ts.Tx(), &fs))
+
+			fsGet, err := GetFragmentationSession(context.Background(), ts.Tx(), fs.ID)
+			assert.NoError(err)
+			assert.Equal(4, fsGet.FragmentsEnqueued)
+			assert.True(fsGet.CompletedAt.Equal(now))
+
+			sessions, err := GetSchedulableFragmentationSessions(context.Background(), ts.Tx(), 10)
+			assert.NoError(err)
+			assert.Len(sessions, 0)
+		})
+
+		t.Run("Delete", func(t *testing.T) {
+			assert := require.New(t)
+
+			assert.NoError(DeleteFragmentationSession(context.Background(), ts.Tx(), fs.ID))
+			_, err := GetFragmentationSession(context.Background(), ts.Tx(), fs.ID)
+			assert.Equal(ErrDoesNotExist, err)
+		})
+	})
+}
@@ -59,9 +59,19 @@ type Gateway struct {
 	Altitude         float64        `db:"altitude"`
 	TLSCert          []byte         `db:"tls_cert"`
 	GatewayProfileID *uuid.UUID     `db:"gateway_profile_id"`
+	Offline          bool           `db:"offline"`
 	Boards           []GatewayBoard `db:"-"`
 }
 
+// GatewayState contains the last-seen state of a gateway, used for the
+// gateway-offline detection.
+type GatewayState struct {
+	GatewayID     lorawan.EUI64 `db:"gateway_id"`
+	LastSeenAt    time.Time     `db:"last_seen_at"`
+	StatsInterval time.Duration `db:"stats_interval"`
+	Offline       bool          `db:"offline"`
+}
+
 // GatewayBoard holds the gateway board configuration.
 type GatewayBoard struct {
 	FPGAID           *lorawan.EUI64     `db:"fpga_id"`
@@ -329,6 +339,98 @@ func DeleteGateway(ctx context.Context, db sqlx.Execer, id lorawan.EUI64) error
 	return nil
 }
 
+// GetGatewayStates returns the state of all gateways that have been seen at
+// least once. For gateways without gateway-profile, the given default
+// stats interval is returned.
+func GetGatewayStates(ctx context.Context, db sqlx.Queryer, defaultStatsInterval time.Duration) ([]GatewayState, error) {
+	var states []GatewayState
+	err := sqlx.Select(db, &states, `
+		select
+			g.gateway_id,
+			g.last_seen_at,
+			coalesce(gp.stats_interval, $1) as stats_interval,
+			g.offline
+		from gateway g
+		left join gateway_profile gp
+			on gp.gateway_profile_id = g.gateway_profile_id
+		where
+			g.last_seen_at is not null
+		order by
+			g.gateway_id`,
+		defaultStatsInterval,
+	)
+	if err != nil {
+		return nil, handlePSQLError(err, "select error")
+	}
+
+	return states, nil
+}
+
+// SetGatewayOffline sets the offline state of the given gateway. It returns
+// true when the state did change. As the state is only updated when it
+// differs, only one network-server instance observes the transition.
+func SetGatewayOffline(ctx context.Context, db sqlx.Execer, gatewayID lorawan.EUI64, offline bool) (bool, error) {
+	res, err := db.Exec(`
+		update gateway set
+			offline = $2
+		where
+			gateway_id = $1
+			and offline != $2`,
+		gatewayID[:],
+		offline,
+	)
+	if err != nil {
+		return false, handlePSQLError(err, "update error")
+	}
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "get rows affected error")
+	}
+	if ra == 0 {
+		return false, nil
+	}
+
+	log.WithFields(log.Fields{
+		"gateway_id": gatewayID,
+		"offline":    offline,
+		"ctx_id":     ctx.Value(logging.ContextIDKey),
+	}).Info("gateway offline state updated")
+	return true, nil
+}
+
+// GetBeaconGateways returns the online gateways that must emit Class-B
+// beacons. When gatewayProfileIDs is not empty, only gateways using one of
+// the given gateway-profiles are returned.
+func GetBeaconGateways(ctx context.Context, db sqlx.Queryer, gatewayProfileIDs []uuid.UUID) ([]Gateway, error) {
+	// not nil, as a nil slice would be encoded as NULL
+	ids := []string{}
+	for _, id := range gatewayProfileIDs {
+		ids = append(ids, id.String())
+	}
+
+	var gws []Gateway
+	err := sqlx.Select(db, &gws, `
+		select
+			*
+		from gateway
+		where
+			last_seen_at is not null
+			and offline = false
+			and (
+				cardinality($1::uuid[]) = 0
+				or gateway_profile_id = any($1::uuid[])
+			)
+		order by
+			gateway_id`,
+		pq.StringArray(ids),
+	)
+	if err != nil {
+		return nil, handlePSQLError(err, "select error")
+	}
+
+	return gws, nil
+}
+
 // GetGatewaysForIDs returns a map of gateways given a slice of IDs.
 func GetGatewaysForIDs(ctx context.Context, db sqlx.Queryer, ids []lorawan.EUI64) (map[lorawan.EUI64]Gateway, error) {
 	out := make(map[lorawan.EUI64]Gateway)
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/brocaar/lorawan"
+)
+
+const (
+	gatewayAirtimeKeyTempl          = "lora:ns:gw:%s:airtime:%s"
+	gatewayAirtimeDownlinkKeyTempl  = "lora:ns:downlink:%s:airtime"
+	gatewayAirtimeDownlinkSeparator = "|"
+)
+
+// AddGatewayDownlinkAirtime adds the airtime of the given downlink to the
+// rolling airtime window of the gateway and sub-band. Records older than the
+// given window duration are removed. The record can be removed using
+// DeleteGatewayDownlinkAirtime, e.g. when the gateway was not able to
+// transmit the downlink.
+func AddGatewayDownlinkAirtime(ctx context.Context, gatewayID lorawan.EUI64, subBand string, downlinkID uuid.UUID, ts time.Time, airtime, window time.Duration) error {
+	key := fmt.Sprintf(gatewayAirtimeKeyTempl, gatewayID, subBand)
+	downlinkKey := fmt.Sprintf(gatewayAirtimeDownlinkKeyTempl, downlinkID)
+	now := ts.UnixNano()
+	member := fmt.Sprintf("%s:%d", downlinkID, int64(airtime))
+
+	pipe := RedisClient().TxPipeline()
+	pipe.ZAdd(key, &redis.Z{Score: float64(now), Member: member})
+	pipe.ZRemRangeByScore(key, "-inf", fmt.Sprintf("(%d", now-int64(window)))
+	pipe.PExpire(key, window)
+	pipe.Set(downlinkKey, key+gatewayAirtimeDownlinkSeparator+member, window)
+	if _, err := pipe.Exec(); err != nil {
+		return errors.Wrap(err, "redis exec error")
+	}
+
+	return nil
+}
+
+// DeleteGatewayDownlinkAirtime removes the airtime record of the given
+// downlink from the airtime window of the gateway and sub-band.
+// In case the record does not exist, no error is returned.
+func DeleteGatewayDownlinkAirtime(ctx context.Context, downlinkID uuid.UUID) error {
+	downlinkKey := fmt.Sprintf(gatewayAirtimeDownlinkKeyTempl, downlinkID)
+
+	val, err := RedisClient().Get(downlinkKey).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil
+		}
+		return errors.Wrap(err, "get error")
+	}
+
+	parts := strings.SplitN(val, gatewayAirtimeDownlinkSeparator, 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid downlink airtime record: %s", val)
+	}
+
+	pipe := RedisClient().TxPipeline()
+	pipe.ZRem(parts[0], parts[1])
+	pipe.Del(downlinkKey)
+	if _, err := pipe.Exec(); err != nil {
+		return errors.Wrap(err, "redis exec error")
+	}
+
+	return nil
+}
+
+// GetGatewayDownlinkAirtime returns the total downlink airtime of the gateway
+// and sub-band within the given window duration.
+func GetGatewayDownlinkAirtime(ctx context.Context, gatewayID lorawan.EUI64, subBand string, window time.Duration) (time.Duration, error) {
+	key := fmt.Sprintf(gatewayAirtimeKeyTempl, gatewayID, subBand)
+	min := time.Now().Add(-window).UnixNano()
+
+	members, err := RedisClient().ZRangeByScore(key, &redis.ZRangeBy{
+		Min: fmt.Sprintf("%d", min),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return 0, errors.Wrap(err, "read airtime error")
+	}
+
+	return sumAirtimeMembers(members)
+}
@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/brocaar/lorawan"
+)
+
+func (ts *StorageTestSuite) TestGatewayDownlinkAirtime() {
+	gatewayID := lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8}
+	downlinkID1 := uuid.Must(uuid.NewV4())
+	downlinkID2 := uuid.Must(uuid.NewV4())
+
+	ts.T().Run("Add airtime", func(t *testing.T) {
+		assert := require.New(t)
+
+		assert.NoError(AddGatewayDownlinkAirtime(context.Background(), gatewayID, "a", downlinkID1, time.Now(), time.Second, time.Hour))
+		assert.NoError(AddGatewayDownlinkAirtime(context.Background(), gatewayID, "a", downlinkID2, time.Now(), 2*time.Second, time.Hour))
+
+		total, err := GetGatewayDownlinkAirtime(context.Background(), gatewayID, "a", time.Hour)
+		assert.NoError(err)
+		assert.Equal(3*time.Second, total)
+
+		total, err = GetGatewayDownlinkAirtime(context.Background(), gatewayID, "b", time.Hour)
+		assert.NoError(err)
+		assert.Equal(time.Duration(0), total)
+
+		t.Run("Delete airtime", func(t *testing.T) {
+			assert := require.New(t)
+
+			assert.NoError(DeleteGatewayDownlinkAirtime(context.Background(), downlinkID1))
+			assert.NoError(DeleteGatewayDownlinkAirtime(context.Background(), downlinkID1))
+
+			total, err := GetGatewayDownlinkAirtime(context.Background(), gatewayID, "a", time.Hour)
+			assert.NoError(err)
+			assert.Equal(2*time.Second, total)
+		})
+	})
+}
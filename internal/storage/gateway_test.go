@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gofrs/uuid"
 	"github.com/stretchr/testify/require"
 
 	"github.com/brocaar/lorawan"
@@ -115,6 +116,49 @@ func (ts *StorageTestSuite) TestGateway() {
 			assert.Equal(gw, gwGet)
 		})
 
+		t.Run("Offline state", func(t *testing.T) {
+			assert := require.New(t)
+
+			states, err := GetGatewayStates(context.Background(), ts.Tx(), 30*time.Second)
+			assert.NoError(err)
+			assert.Len(states, 1)
+			assert.Equal(gw.GatewayID, states[0].GatewayID)
+			assert.False(states[0].Offline)
+
+			changed, err := SetGatewayOffline(context.Background(), ts.Tx(), gw.GatewayID, true)
+			assert.NoError(err)
+			assert.True(changed)
+
+			changed, err = SetGatewayOffline(context.Background(), ts.Tx(), gw.GatewayID, true)
+			assert.NoError(err)
+			assert.False(changed)
+
+			gwGet, err := GetGateway(context.Background(), ts.Tx(), gw.GatewayID)
+			assert.NoError(err)
+			assert.True(gwGet.Offline)
+
+			changed, err = SetGatewayOffline(context.Background(), ts.Tx(), gw.GatewayID, false)
+			assert.NoError(err)
+			assert.True(changed)
+		})
+
+		t.Run("Beacon gateways", func(t *testing.T) {
+			assert := require.New(t)
+
+			gws, err := GetBeaconGateways(context.Background(), ts.Tx(), nil)
+			assert.NoError(err)
+			assert.Len(gws, 1)
+			assert.Equal(gw.GatewayID, gws[0].GatewayID)
+
+			gws, err = GetBeaconGateways(context.Background(), ts.Tx(), []uuid.UUID{*gw.GatewayProfileID})
+			assert.NoError(err)
+			assert.Len(gws, 1)
+
+			gws, err = GetBeaconGateways(context.Background(), ts.Tx(), []uuid.UUID{uuid.Must(uuid.NewV4())})
+			assert.NoError(err)
+			assert.Len(gws, 0)
+		})
+
 		t.Run("Delete", func(t *testing.T) {
 			assert := require.New(t)
 			assert.NoError(DeleteGateway(context.Background(), ts.Tx(), gw.GatewayID))
@@ -0,0 +1,277 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brocaar/chirpstack-network-server/internal/logging"
+	"github.com/brocaar/lorawan"
+)
+
+const (
+	hrDevAddrKeyTempl       = "lora:ns:hr:devaddr:%s" // pointer from DevAddr to set of session IDs
+	hrDevEUIKeyTempl        = "lora:ns:hr:deveui:%s"  // pointer from DevEUI to set of session IDs
+	hrDeviceSessionKeyTempl = "lora:ns:hr:sess:%s"
+	hrHNSNetIDKeyTempl      = "lora:ns:hr:hns:deveui:%s" // NetID of the sNS serving the device (hNS)
+	hrDeviceQueueKeyTempl   = "lora:ns:hr:queue:%s"      // downlink payloads received from the hNS (sNS)
+)
+
+var hrDeviceSessionKeys = roamingDeviceSessionKeys{
+	devAddr: hrDevAddrKeyTempl,
+	devEUI:  hrDevEUIKeyTempl,
+	session: hrDeviceSessionKeyTempl,
+}
+
+// HandoverRoamingDeviceSession defines the handover-roaming session of a
+// visiting device, for which this network-server is the serving NS (sNS).
+// The MAC-layer state of the device is stored as a DeviceSession, the
+// device-profile and service-profile are received from the hNS.
+type HandoverRoamingDeviceSession struct {
+	SessionID      uuid.UUID
+	NetID          lorawan.NetID // NetID of the hNS
+	DevAddr        lorawan.DevAddr
+	DevEUI         lorawan.EUI64
+	Lifetime       time.Time
+	DeviceProfile  DeviceProfile
+	ServiceProfile ServiceProfile
+}
+
+// SaveHandoverRoamingDeviceSession saves the handover-roaming device-session.
+func SaveHandoverRoamingDeviceSession(ctx context.Context, ds *HandoverRoamingDeviceSession) error {
+	lifetime, err := saveRoamingDeviceSession(hrDeviceSessionKeys, &ds.SessionID, ds.DevAddr, ds.DevEUI, ds.Lifetime, func() ([]byte, error) {
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(ds); err != nil {
+			return nil, errors.Wrap(err, "gob encode handover-roaming device-session error")
+		}
+		return buf.Bytes(), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if lifetime <= 0 {
+		log.WithFields(log.Fields{
+			"dev_eui":    ds.DevEUI,
+			"dev_addr":   ds.DevAddr,
+			"session_id": ds.SessionID,
+			"ctx_id":     ctx.Value(logging.ContextIDKey),
+			"ttl":        lifetime,
+		}).Debug("storage: not saving handover-roaming session, lifetime expired")
+		return nil
+	}
+
+	log.WithFields(log.Fields{
+		"dev_eui":    ds.DevEUI,
+		"dev_addr":   ds.DevAddr,
+		"session_id": ds.SessionID,
+		"ctx_id":     ctx.Value(logging.ContextIDKey),
+		"ttl":        lifetime,
+	}).Info("storage: handover-roaming device-session saved")
+
+	return nil
+}
+
+// GetHandoverRoamingDeviceSession returns the handover-roaming device-session.
+func GetHandoverRoamingDeviceSession(ctx context.Context, id uuid.UUID) (HandoverRoamingDeviceSession, error) {
+	var ds HandoverRoamingDeviceSession
+
+	val, err := getRoamingDeviceSession(hrDeviceSessionKeys, id)
+	if err != nil {
+		return ds, err
+	}
+
+	if err := gob.NewDecoder(bytes.NewReader(val)).Decode(&ds); err != nil {
+		return ds, errors.Wrap(err, "gob decode error")
+	}
+
+	return ds, nil
+}
+
+// GetHandoverRoamingDeviceSessionForDevEUI returns the handover-roaming
+// device-session for the given DevEUI and DevAddr. ErrDoesNotExist is
+// returned when no (non-expired) session matches.
+func GetHandoverRoamingDeviceSessionForDevEUI(ctx context.Context, devEUI lorawan.EUI64, devAddr lorawan.DevAddr) (HandoverRoamingDeviceSession, error) {
+	ids, err := GetHandoverRoamingIDsForDevEUI(ctx, devEUI)
+	if err != nil {
+		return HandoverRoamingDeviceSession{}, err
+	}
+
+	for _, id := range ids {
+		ds, err := GetHandoverRoamingDeviceSession(ctx, id)
+		if err != nil {
+			if err == ErrDoesNotExist {
+				continue
+			}
+			return HandoverRoamingDeviceSession{}, errors.Wrap(err, "get handover-roaming device-session error")
+		}
+
+		if ds.DevAddr == devAddr {
+			return ds, nil
+		}
+	}
+
+	return HandoverRoamingDeviceSession{}, ErrDoesNotExist
+}
+
+// GetHandoverRoamingIDsForDevEUI returns the handover-roaming session IDs for
+// the given DevEUI.
+func GetHandoverRoamingIDsForDevEUI(ctx context.Context, devEUI lorawan.EUI64) ([]uuid.UUID, error) {
+	ids, err := getRoamingDeviceSessionIDs(fmt.Sprintf(hrDevEUIKeyTempl, devEUI))
+	if err != nil {
+		return nil, errors.Wrap(err, "get handover-roaming session ids for deveui error")
+	}
+	return ids, nil
+}
+
+// DeleteHandoverRoamingDeviceSession deletes the handover-roaming
+// device-session and removes its ID from the DevAddr and DevEUI pointers.
+func DeleteHandoverRoamingDeviceSession(ctx context.Context, id uuid.UUID) error {
+	ds, err := GetHandoverRoamingDeviceSession(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := deleteRoamingDeviceSession(hrDeviceSessionKeys, id, ds.DevAddr, ds.DevEUI); err != nil {
+		return err
+	}
+
+	if err := RedisClient().Del(fmt.Sprintf(hrDeviceQueueKeyTempl, ds.DevEUI)).Err(); err != nil {
+		return errors.Wrap(err, "delete handover-roaming device-queue error")
+	}
+
+	log.WithFields(log.Fields{
+		"dev_eui":    ds.DevEUI,
+		"dev_addr":   ds.DevAddr,
+		"session_id": id,
+		"ctx_id":     ctx.Value(logging.ContextIDKey),
+	}).Info("storage: handover-roaming device-session deleted")
+
+	return nil
+}
+
+// SaveHandoverRoamingNetIDForDevEUI stores the NetID of the sNS to which the
+// hNS handed over the given DevEUI.
+func SaveHandoverRoamingNetIDForDevEUI(ctx context.Context, devEUI lorawan.EUI64, netID lorawan.NetID, lifetime time.Duration) error {
+	key := fmt.Sprintf(hrHNSNetIDKeyTempl, devEUI)
+
+	if err := RedisClient().Set(key, netID[:], lifetime).Err(); err != nil {
+		return errors.Wrap(err, "set error")
+	}
+
+	return nil
+}
+
+// GetHandoverRoamingNetIDForDevEUI returns the NetID of the sNS to which the
+// hNS handed over the given DevEUI.
+func GetHandoverRoamingNetIDForDevEUI(ctx context.Context, devEUI lorawan.EUI64) (lorawan.NetID, error) {
+	var netID lorawan.NetID
+	key := fmt.Sprintf(hrHNSNetIDKeyTempl, devEUI)
+
+	val, err := RedisClient().Get(key).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return netID, ErrDoesNotExist
+		}
+		return netID, errors.Wrap(err, "get error")
+	}
+	copy(netID[:], val)
+
+	return netID, nil
+}
+
+// DeleteHandoverRoamingNetIDForDevEUI removes the stored sNS NetID for the
+// given DevEUI.
+func DeleteHandoverRoamingNetIDForDevEUI(ctx context.Context, devEUI lorawan.EUI64) error {
+	key := fmt.Sprintf(hrHNSNetIDKeyTempl, devEUI)
+
+	if err := RedisClient().Del(key).Err(); err != nil {
+		return errors.Wrap(err, "delete error")
+	}
+
+	return nil
+}
+
+// HandoverRoamingDeviceQueueItem defines an application downlink payload
+// received from the hNS, which must be sent by the sNS.
+type HandoverRoamingDeviceQueueItem struct {
+	FPort      uint8
+	FCnt       uint32
+	Confirmed  bool
+	FRMPayload []byte
+}
+
+// CreateHandoverRoamingDeviceQueueItem appends the given item to the
+// handover-roaming device-queue of the given DevEUI. The queue expires
+// together with the handover-roaming device-session.
+func CreateHandoverRoamingDeviceQueueItem(ctx context.Context, devEUI lorawan.EUI64, qi HandoverRoamingDeviceQueueItem, lifetime time.Time) error {
+	ttl := time.Until(lifetime)
+	if ttl <= 0 {
+		return ErrDoesNotExist
+	}
+
+	key := fmt.Sprintf(hrDeviceQueueKeyTempl, devEUI)
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(qi); err != nil {
+		return errors.Wrap(err, "gob encode error")
+	}
+
+	pipe := RedisClient().TxPipeline()
+	pipe.RPush(key, buf.Bytes())
+	pipe.PExpire(key, ttl)
+	if _, err := pipe.Exec(); err != nil {
+		return errors.Wrap(err, "create handover-roaming device-queue item error")
+	}
+
+	log.WithFields(log.Fields{
+		"dev_eui": devEUI,
+		"f_port":  qi.FPort,
+		"f_cnt":   qi.FCnt,
+		"ctx_id":  ctx.Value(logging.ContextIDKey),
+	}).Info("storage: handover-roaming device-queue item created")
+
+	return nil
+}
+
+// PopHandoverRoamingDeviceQueueItem removes and returns the first item of the
+// handover-roaming device-queue of the given DevEUI. ErrDoesNotExist is
+// returned when the queue is empty.
+func PopHandoverRoamingDeviceQueueItem(ctx context.Context, devEUI lorawan.EUI64) (HandoverRoamingDeviceQueueItem, error) {
+	var qi HandoverRoamingDeviceQueueItem
+	key := fmt.Sprintf(hrDeviceQueueKeyTempl, devEUI)
+
+	val, err := RedisClient().LPop(key).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return qi, ErrDoesNotExist
+		}
+		return qi, errors.Wrap(err, "lpop error")
+	}
+
+	if err := gob.NewDecoder(bytes.NewReader(val)).Decode(&qi); err != nil {
+		return qi, errors.Wrap(err, "gob decode error")
+	}
+
+	return qi, nil
+}
+
+// GetHandoverRoamingDeviceQueueItemCount returns the number of items in the
+// handover-roaming device-queue of the given DevEUI.
+func GetHandoverRoamingDeviceQueueItemCount(ctx context.Context, devEUI lorawan.EUI64) (int, error) {
+	key := fmt.Sprintf(hrDeviceQueueKeyTempl, devEUI)
+
+	count, err := RedisClient().LLen(key).Result()
+	if err != nil {
+		return 0, errors.Wrap(err, "llen error")
+	}
+
+	return int(count), nil
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/brocaar/lorawan"
+)
+
+func (ts *StorageTestSuite) TestHandoverRoaming() {
+	ds := HandoverRoamingDeviceSession{
+		NetID:    lorawan.NetID{1, 2, 3},
+		DevAddr:  lorawan.DevAddr{1, 2, 3, 4},
+		DevEUI:   lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8},
+		Lifetime: time.Now().Add(time.Minute).UTC(),
+		DeviceProfile: DeviceProfile{
+			MACVersion:         "1.0.3",
+			RXDelay1:           1,
+			FactoryPresetFreqs: []int{868100000, 868300000, 868500000},
+		},
+		ServiceProfile: ServiceProfile{
+			DRMax:     5,
+			TargetPER: 10,
+		},
+	}
+
+	ts.T().Run("Save", func(t *testing.T) {
+		assert := require.New(t)
+		assert.NoError(SaveHandoverRoamingDeviceSession(context.Background(), &ds))
+		assert.NotEqual(uuid.Nil, ds.SessionID)
+
+		dsGet, err := GetHandoverRoamingDeviceSession(context.Background(), ds.SessionID)
+		assert.NoError(err)
+		assert.True(ds.Lifetime.Equal(dsGet.Lifetime))
+		dsGet.Lifetime = ds.Lifetime
+		assert.Equal(ds, dsGet)
+
+		ids, err := GetHandoverRoamingIDsForDevEUI(context.Background(), ds.DevEUI)
+		assert.NoError(err)
+		assert.Equal([]uuid.UUID{ds.SessionID}, ids)
+	})
+
+	ts.T().Run("Get for DevEUI", func(t *testing.T) {
+		assert := require.New(t)
+
+		dsGet, err := GetHandoverRoamingDeviceSessionForDevEUI(context.Background(), ds.DevEUI, ds.DevAddr)
+		assert.NoError(err)
+		assert.Equal(ds.SessionID, dsGet.SessionID)
+		assert.Equal(ds.ServiceProfile, dsGet.ServiceProfile)
+
+		_, err = GetHandoverRoamingDeviceSessionForDevEUI(context.Background(), ds.DevEUI, lorawan.DevAddr{4, 3, 2, 1})
+		assert.Equal(ErrDoesNotExist, err)
+	})
+
+	ts.T().Run("Delete", func(t *testing.T) {
+		assert := require.New(t)
+		assert.NoError(DeleteHandoverRoamingDeviceSession(context.Background(), ds.SessionID))
+
+		_, err := GetHandoverRoamingDeviceSession(context.Background(), ds.SessionID)
+		assert.Equal(ErrDoesNotExist, err)
+
+		ids, err := GetHandoverRoamingIDsForDevEUI(context.Background(), ds.DevEUI)
+		assert.NoError(err)
+		assert.Len(ids, 0)
+	})
+
+	ts.T().Run("NetID for DevEUI", func(t *testing.T) {
+		assert := require.New(t)
+
+		_, err := GetHandoverRoamingNetIDForDevEUI(context.Background(), ds.DevEUI)
+		assert.Equal(ErrDoesNotExist, err)
+
+		assert.NoError(SaveHandoverRoamingNetIDForDevEUI(context.Background(), ds.DevEUI, lorawan.NetID{3, 2, 1}, time.Minute))
+		netID, err := GetHandoverRoamingNetIDForDevEUI(context.Background(), ds.DevEUI)
+		assert.NoError(err)
+		assert.Equal(lorawan.NetID{3, 2, 1}, netID)
+
+		assert.NoError(DeleteHandoverRoamingNetIDForDevEUI(context.Background(), ds.DevEUI))
+		_, err = GetHandoverRoamingNetIDForDevEUI(context.Background(), ds.DevEUI)
+		assert.Equal(ErrDoesNotExist, err)
+	})
+}
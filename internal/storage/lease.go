@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brocaar/chirpstack-network-server/internal/logging"
+)
+
+const leaseKeyTempl = "lora:ns:lease:%s"
+
+// renewLeaseScript extends the lease TTL, but only when the lease is still
+// held by the given holder.
+const renewLeaseScript = `
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("pexpire", KEYS[1], ARGV[2])
+end
+return 0
+`
+
+// releaseLeaseScript deletes the lease, but only when the lease is held by
+// the given holder.
+const releaseLeaseScript = `
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1])
+end
+return 0
+`
+
+// AcquireLease tries to acquire or renew the lease with the given name for
+// the given holder. It returns true when the holder holds the lease. The
+// lease expires after the given TTL, unless it is renewed before.
+func AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	key := fmt.Sprintf(leaseKeyTempl, name)
+
+	set, err := RedisClient().SetNX(key, holder, ttl).Result()
+	if err != nil {
+		return false, errors.Wrap(err, "set lease error")
+	}
+
+	if set {
+		log.WithFields(log.Fields{
+			"lease":  name,
+			"holder": holder,
+			"ttl":    ttl,
+			"ctx_id": ctx.Value(logging.ContextIDKey),
+		}).Info("storage: lease acquired")
+		return true, nil
+	}
+
+	renewed, err := RedisClient().Eval(renewLeaseScript, []string{key}, holder, int64(ttl/time.Millisecond)).Int()
+	if err != nil {
+		return false, errors.Wrap(err, "renew lease error")
+	}
+
+	return renewed == 1, nil
+}
+
+// ReleaseLease releases the lease with the given name, in case it is held by
+// the given holder.
+func ReleaseLease(ctx context.Context, name, holder string) error {
+	key := fmt.Sprintf(leaseKeyTempl, name)
+
+	released, err := RedisClient().Eval(releaseLeaseScript, []string{key}, holder).Int()
+	if err != nil {
+		return errors.Wrap(err, "release lease error")
+	}
+
+	if released == 1 {
+		log.WithFields(log.Fields{
+			"lease":  name,
+			"holder": holder,
+			"ctx_id": ctx.Value(logging.ContextIDKey),
+		}).Info("storage: lease released")
+	}
+
+	return nil
+}
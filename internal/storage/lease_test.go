@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func (ts *StorageTestSuite) TestLease() {
+	ts.T().Run("Acquire", func(t *testing.T) {
+		assert := require.New(t)
+
+		ok, err := AcquireLease(context.Background(), "test", "a", time.Minute)
+		assert.NoError(err)
+		assert.True(ok)
+
+		t.Run("Renew", func(t *testing.T) {
+			assert := require.New(t)
+
+			ok, err := AcquireLease(context.Background(), "test", "a", time.Minute)
+			assert.NoError(err)
+			assert.True(ok)
+		})
+
+		t.Run("Held by other holder", func(t *testing.T) {
+			assert := require.New(t)
+
+			ok, err := AcquireLease(context.Background(), "test", "b", time.Minute)
+			assert.NoError(err)
+			assert.False(ok)
+		})
+
+		t.Run("Release by other holder", func(t *testing.T) {
+			assert := require.New(t)
+
+			assert.NoError(ReleaseLease(context.Background(), "test", "b"))
+
+			ok, err := AcquireLease(context.Background(), "test", "b", time.Minute)
+			assert.NoError(err)
+			assert.False(ok)
+		})
+
+		t.Run("Release", func(t *testing.T) {
+			assert := require.New(t)
+
+			assert.NoError(ReleaseLease(context.Background(), "test", "a"))
+
+			ok, err := AcquireLease(context.Background(), "test", "b", time.Minute)
+			assert.NoError(err)
+			assert.True(ok)
+		})
+	})
+
+	ts.T().Run("Expire", func(t *testing.T) {
+		assert := require.New(t)
+
+		ok, err := AcquireLease(context.Background(), "test-expire", "a", 100*time.Millisecond)
+		assert.NoError(err)
+		assert.True(ok)
+
+		time.Sleep(200 * time.Millisecond)
+
+		ok, err = AcquireLease(context.Background(), "test-expire", "b", time.Minute)
+		assert.NoError(err)
+		assert.True(ok)
+	})
+}
@@ -20,8 +20,15 @@ const (
 	prDevAddrKeyTempl       = "lora:ns:pr:devaddr:%s" // pointer from DevAddr to set of session IDs (DevAddr are not guaranteed to be unique)
 	prDevEUIKeyTempl        = "lora:ns:pr:deveui:%s"  // pointer from DevEUI to set of session IDs (PRStartAns DevEUI is optional, so it can't be used as main identifier)
 	prDeviceSessionKeyTempl = "lora:ns:pr:sess:%s"
+	prHNSNetIDKeyTempl      = "lora:ns:pr:hns:deveui:%s" // set of NetIDs of the fNSs to which a stateful passive-roaming session was handed out (hNS)
 )
 
+var prDeviceSessionKeys = roamingDeviceSessionKeys{
+	devAddr: prDevAddrKeyTempl,
+	devEUI:  prDevEUIKeyTempl,
+	session: prDeviceSessionKeyTempl,
+}
+
 // PassiveRoamingDeviceSession defines the passive-roaming session.
 type PassiveRoamingDeviceSession struct {
 	SessionID   uuid.UUID
@@ -37,7 +44,23 @@ type PassiveRoamingDeviceSession struct {
 
 // SavePassiveRoamingDeviceSession saves the passive-roaming device-session.
 func SavePassiveRoamingDeviceSession(ctx context.Context, ds *PassiveRoamingDeviceSession) error {
-	lifetime := ds.Lifetime.Sub(time.Now())
+	lifetime, err := saveRoamingDeviceSession(prDeviceSessionKeys, &ds.SessionID, ds.DevAddr, ds.DevEUI, ds.Lifetime, func() ([]byte, error) {
+		dsPB, err := passiveRoamingDeviceSessionToPB(ds)
+		if err != nil {
+			return nil, errors.Wrap(err, "to protobuf error")
+		}
+
+		b, err := proto.Marshal(&dsPB)
+		if err != nil {
+			return nil, errors.Wrap(err, "protobuf marshal error")
+		}
+
+		return b, nil
+	})
+	if err != nil {
+		return err
+	}
+
 	if lifetime <= 0 {
 		log.WithFields(log.Fields{
 			"dev_eui":    ds.DevEUI,
@@ -49,52 +72,6 @@ func SavePassiveRoamingDeviceSession(ctx context.Context, ds *PassiveRoamingDevi
 		return nil
 	}
 
-	if ds.SessionID == uuid.Nil {
-		id, err := uuid.NewV4()
-		if err != nil {
-			return errors.Wrap(err, "new uuid v4 error")
-		}
-		ds.SessionID = id
-	}
-
-	devAddrKey := fmt.Sprintf(prDevAddrKeyTempl, ds.DevAddr)
-	devEUIKey := fmt.Sprintf(prDevEUIKeyTempl, ds.DevEUI)
-	sessKey := fmt.Sprintf(prDeviceSessionKeyTempl, ds.SessionID)
-
-	dsPB, err := passiveRoamingDeviceSessionToPB(ds)
-	if err != nil {
-		return errors.Wrap(err, "to protobuf error")
-	}
-
-	b, err := proto.Marshal(&dsPB)
-	if err != nil {
-		return errors.Wrap(err, "protobuf marshal error")
-	}
-
-	// We need to store a pointer from both the DevAddr and DevEUI to the
-	// passive-roaming device-session ID. This is needed:
-	//  * Because the DevAddr is not guaranteed to be unique
-	//  * Because the DevEUI might not be given (thus is also not guaranteed
-	//    to be an unique identifier).
-	//
-	// But:
-	//  * We need to be able to lookup the session using the DevAddr (potentially
-	//    using the MIC validation).
-	//  * We need to be able to stop a passive-roaming session given a DevEUI.
-	pipe := RedisClient().TxPipeline()
-	pipe.SAdd(devAddrKey, ds.SessionID[:])
-	pipe.SAdd(devEUIKey, ds.SessionID[:])
-	pipe.PExpire(devAddrKey, deviceSessionTTL)
-	pipe.PExpire(devEUIKey, deviceSessionTTL)
-	if _, err := pipe.Exec(); err != nil {
-		return errors.Wrap(err, "exec error")
-	}
-
-	err = RedisClient().Set(sessKey, b, lifetime).Err()
-	if err != nil {
-		return errors.Wrap(err, "set error")
-	}
-
 	log.WithFields(log.Fields{
 		"dev_eui":    ds.DevEUI,
 		"dev_addr":   ds.DevAddr,
@@ -186,37 +163,103 @@ func GetPassiveRoamingDeviceSessionsForDevAddr(ctx context.Context, devAddr lora
 // GetPassiveRoamingIDsForDevAddr returns the passive-roaming session IDs for
 // the given DevAddr.
 func GetPassiveRoamingIDsForDevAddr(ctx context.Context, devAddr lorawan.DevAddr) ([]uuid.UUID, error) {
-	key := fmt.Sprintf(prDevAddrKeyTempl, devAddr)
+	ids, err := getRoamingDeviceSessionIDs(fmt.Sprintf(prDevAddrKeyTempl, devAddr))
+	if err != nil {
+		return nil, errors.Wrap(err, "get passive-roaming session ids for devaddr error")
+	}
+	return ids, nil
+}
+
+// GetPassiveRoamingIDsForDevEUI returns the passive-roaming session IDs for
+// the given DevEUI.
+func GetPassiveRoamingIDsForDevEUI(ctx context.Context, devEUI lorawan.EUI64) ([]uuid.UUID, error) {
+	ids, err := getRoamingDeviceSessionIDs(fmt.Sprintf(prDevEUIKeyTempl, devEUI))
+	if err != nil {
+		return nil, errors.Wrap(err, "get passive-roaming session ids for deveui error")
+	}
+	return ids, nil
+}
+
+// DeletePassiveRoamingDeviceSession deletes the passive-roaming device-session
+// and removes its ID from the DevAddr and DevEUI pointers.
+func DeletePassiveRoamingDeviceSession(ctx context.Context, id uuid.UUID) error {
+	ds, err := GetPassiveRoamingDeviceSession(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := deleteRoamingDeviceSession(prDeviceSessionKeys, id, ds.DevAddr, ds.DevEUI); err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		"dev_eui":    ds.DevEUI,
+		"dev_addr":   ds.DevAddr,
+		"session_id": id,
+		"ctx_id":     ctx.Value(logging.ContextIDKey),
+	}).Info("storage: passive-roaming device-session deleted")
+
+	return nil
+}
+
+// SavePassiveRoamingNetIDForDevEUI stores the NetID of the fNS to which the
+// hNS handed out a stateful passive-roaming session for the given DevEUI.
+// This is used to stop these sessions when the device re-joins.
+func SavePassiveRoamingNetIDForDevEUI(ctx context.Context, devEUI lorawan.EUI64, netID lorawan.NetID, lifetime time.Duration) error {
+	key := fmt.Sprintf(prHNSNetIDKeyTempl, devEUI)
+
+	pipe := RedisClient().TxPipeline()
+	pipe.SAdd(key, netID[:])
+	pipe.PExpire(key, lifetime)
+	if _, err := pipe.Exec(); err != nil {
+		return errors.Wrap(err, "exec error")
+	}
+
+	return nil
+}
+
+// GetPassiveRoamingNetIDsForDevEUI returns the NetIDs of the fNSs to which
+// the hNS handed out a stateful passive-roaming session for the given DevEUI.
+func GetPassiveRoamingNetIDsForDevEUI(ctx context.Context, devEUI lorawan.EUI64) ([]lorawan.NetID, error) {
+	key := fmt.Sprintf(prHNSNetIDKeyTempl, devEUI)
 
 	val, err := RedisClient().SMembers(key).Result()
 	if err != nil {
 		if err == redis.Nil {
 			return nil, nil
 		}
-		return nil, errors.Wrap(err, "get passive-roaming session ids for devaddr error")
+		return nil, errors.Wrap(err, "get passive-roaming netids for deveui error")
 	}
 
-	var out []uuid.UUID
+	var out []lorawan.NetID
 	for i := range val {
-		var id uuid.UUID
-		copy(id[:], []byte(val[i]))
-		out = append(out, id)
+		var netID lorawan.NetID
+		copy(netID[:], []byte(val[i]))
+		out = append(out, netID)
 	}
 
 	return out, nil
 }
 
+// DeletePassiveRoamingNetIDsForDevEUI removes the stored NetIDs for the given
+// DevEUI.
+func DeletePassiveRoamingNetIDsForDevEUI(ctx context.Context, devEUI lorawan.EUI64) error {
+	key := fmt.Sprintf(prHNSNetIDKeyTempl, devEUI)
+
+	if err := RedisClient().Del(key).Err(); err != nil {
+		return errors.Wrap(err, "delete error")
+	}
+
+	return nil
+}
+
 // GetPassiveRoamingDeviceSession returns the passive-roaming device-session.
 func GetPassiveRoamingDeviceSession(ctx context.Context, id uuid.UUID) (PassiveRoamingDeviceSession, error) {
-	key := fmt.Sprintf(prDeviceSessionKeyTempl, id)
 	var dsPB PassiveRoamingDeviceSessionPB
 
-	val, err := RedisClient().Get(key).Bytes()
+	val, err := getRoamingDeviceSession(prDeviceSessionKeys, id)
 	if err != nil {
-		if err == redis.Nil {
-			return PassiveRoamingDeviceSession{}, ErrDoesNotExist
-		}
-		return PassiveRoamingDeviceSession{}, errors.Wrap(err, "get error")
+		return PassiveRoamingDeviceSession{}, err
 	}
 
 	err = proto.Unmarshal(val, &dsPB)
@@ -64,6 +64,55 @@ func (ts *StorageTestSuite) TestPassiveRoaming() {
 		})
 	})
 
+	ts.T().Run("Delete", func(t *testing.T) {
+		assert := require.New(t)
+
+		ds := PassiveRoamingDeviceSession{
+			NetID:    lorawan.NetID{1, 2, 3},
+			DevAddr:  lorawan.DevAddr{1, 2, 3, 4},
+			DevEUI:   lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8},
+			Lifetime: time.Now().Add(time.Minute),
+		}
+		assert.NoError(SavePassiveRoamingDeviceSession(context.Background(), &ds))
+
+		ids, err := GetPassiveRoamingIDsForDevEUI(context.Background(), ds.DevEUI)
+		assert.NoError(err)
+		assert.Equal([]uuid.UUID{ds.SessionID}, ids)
+
+		assert.NoError(DeletePassiveRoamingDeviceSession(context.Background(), ds.SessionID))
+
+		_, err = GetPassiveRoamingDeviceSession(context.Background(), ds.SessionID)
+		assert.Equal(ErrDoesNotExist, err)
+
+		ids, err = GetPassiveRoamingIDsForDevEUI(context.Background(), ds.DevEUI)
+		assert.NoError(err)
+		assert.Len(ids, 0)
+
+		ids, err = GetPassiveRoamingIDsForDevAddr(context.Background(), ds.DevAddr)
+		assert.NoError(err)
+		assert.Len(ids, 0)
+
+		assert.Equal(ErrDoesNotExist, DeletePassiveRoamingDeviceSession(context.Background(), ds.SessionID))
+	})
+
+	ts.T().Run("NetIDs for DevEUI", func(t *testing.T) {
+		assert := require.New(t)
+		devEUI := lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8}
+
+		assert.NoError(SavePassiveRoamingNetIDForDevEUI(context.Background(), devEUI, lorawan.NetID{1, 2, 3}, time.Minute))
+		assert.NoError(SavePassiveRoamingNetIDForDevEUI(context.Background(), devEUI, lorawan.NetID{1, 2, 3}, time.Minute))
+
+		netIDs, err := GetPassiveRoamingNetIDsForDevEUI(context.Background(), devEUI)
+		assert.NoError(err)
+		assert.Equal([]lorawan.NetID{{1, 2, 3}}, netIDs)
+
+		assert.NoError(DeletePassiveRoamingNetIDsForDevEUI(context.Background(), devEUI))
+
+		netIDs, err = GetPassiveRoamingNetIDsForDevEUI(context.Background(), devEUI)
+		assert.NoError(err)
+		assert.Len(netIDs, 0)
+	})
+
 	ts.T().Run("Get for PHYPayload", func(t *testing.T) {
 		assert := require.New(t)
 		id1, err := uuid.NewV4()
@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brocaar/chirpstack-network-server/internal/logging"
+	"github.com/brocaar/lorawan"
+)
+
+const (
+	deviceUplinkRateKeyTempl   = "lora:ns:device:%s:rate:uplink"
+	deviceDownlinkRateKeyTempl = "lora:ns:device:%s:rate:downlink"
+)
+
+// takeTokenScript implements a token-bucket. The bucket is stored as a hash
+// containing the number of tokens and the timestamp (ms) of the last update.
+// The bucket is refilled based on the elapsed time since the last update.
+//
+// KEYS[1]: bucket key
+// ARGV[1]: rate (tokens / hour)
+// ARGV[2]: bucket size
+// ARGV[3]: current timestamp (ms)
+// ARGV[4]: key ttl (ms)
+// ARGV[5]: take (1 to take a token, 0 to only check for a token)
+//
+// It returns 1 when a token is available (and was taken), 0 when the bucket
+// is empty.
+var takeTokenScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local size = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local ttl = tonumber(ARGV[4])
+local take = tonumber(ARGV[5])
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = size
+	ts = now
+end
+
+local elapsed = now - ts
+if elapsed < 0 then
+	elapsed = 0
+end
+
+tokens = math.min(size, tokens + (elapsed * rate / 3600000))
+
+local taken = 0
+if tokens >= 1 then
+	taken = 1
+end
+
+if take == 0 then
+	return taken
+end
+
+if taken == 1 then
+	tokens = tokens - 1
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", tostring(now))
+redis.call("PEXPIRE", KEYS[1], ttl)
+
+return taken
+`)
+
+// TakeDeviceUplinkRateToken takes a token from the uplink token-bucket of the
+// given device, using the ULRate and ULBucketSize of the service-profile.
+// It returns false when the device has exceeded its uplink rate.
+func TakeDeviceUplinkRateToken(ctx context.Context, devEUI lorawan.EUI64, sp ServiceProfile) (bool, error) {
+	key := fmt.Sprintf(deviceUplinkRateKeyTempl, devEUI)
+	taken, err := takeRateToken(key, sp.ULRate, sp.ULBucketSize, true)
+	if err != nil {
+		return false, errors.Wrap(err, "take uplink rate token error")
+	}
+
+	if !taken {
+		log.WithFields(log.Fields{
+			"dev_eui":        devEUI,
+			"ul_rate":        sp.ULRate,
+			"ul_bucket_size": sp.ULBucketSize,
+			"ctx_id":         ctx.Value(logging.ContextIDKey),
+		}).Debug("storage: uplink rate exceeded")
+	}
+
+	return taken, nil
+}
+
+// TakeDeviceDownlinkRateToken takes a token from the downlink token-bucket of
+// the given device, using the DLRate and DLBucketSize of the service-profile.
+// It returns false when the device has exceeded its downlink rate.
+func TakeDeviceDownlinkRateToken(ctx context.Context, devEUI lorawan.EUI64, sp ServiceProfile) (bool, error) {
+	key := fmt.Sprintf(deviceDownlinkRateKeyTempl, devEUI)
+	taken, err := takeRateToken(key, sp.DLRate, sp.DLBucketSize, true)
+	if err != nil {
+		return false, errors.Wrap(err, "take downlink rate token error")
+	}
+
+	if !taken {
+		log.WithFields(log.Fields{
+			"dev_eui":        devEUI,
+			"dl_rate":        sp.DLRate,
+			"dl_bucket_size": sp.DLBucketSize,
+			"ctx_id":         ctx.Value(logging.ContextIDKey),
+		}).Debug("storage: downlink rate exceeded")
+	}
+
+	return taken, nil
+}
+
+// HasDeviceDownlinkRateToken returns if the downlink token-bucket of the given
+// device contains a token, without taking it. This makes it possible to
+// only take the token once the downlink has been sent.
+func HasDeviceDownlinkRateToken(ctx context.Context, devEUI lorawan.EUI64, sp ServiceProfile) (bool, error) {
+	key := fmt.Sprintf(deviceDownlinkRateKeyTempl, devEUI)
+	ok, err := takeRateToken(key, sp.DLRate, sp.DLBucketSize, false)
+	if err != nil {
+		return false, errors.Wrap(err, "get downlink rate token error")
+	}
+
+	return ok, nil
+}
+
+// takeRateToken takes a token from the bucket stored under the given key.
+// When take is false, it only returns if a token is available. The rate is
+// in tokens per hour. A rate of 0 disables rate-limiting. When the bucket
+// size is < 1, a bucket size of 1 is assumed.
+func takeRateToken(key string, rate, bucketSize int, take bool) (bool, error) {
+	if rate <= 0 {
+		return true, nil
+	}
+
+	if bucketSize < 1 {
+		bucketSize = 1
+	}
+
+	// keep the bucket at least for the duration it takes to completely
+	// refill it, after that it is equal to a new (full) bucket
+	ttl := time.Duration(bucketSize) * time.Hour / time.Duration(rate)
+	if ttl < time.Minute {
+		ttl = time.Minute
+	}
+
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+
+	var takeArg int
+	if take {
+		takeArg = 1
+	}
+
+	taken, err := takeTokenScript.Run(RedisClient(), []string{key}, rate, bucketSize, now, int64(ttl/time.Millisecond), takeArg).Int()
+	if err != nil {
+		return false, err
+	}
+
+	return taken == 1, nil
+}
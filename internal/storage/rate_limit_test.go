@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/brocaar/lorawan"
+)
+
+func (ts *StorageTestSuite) TestRateLimit() {
+	devEUI := lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8}
+
+	ts.T().Run("Rate disabled", func(t *testing.T) {
+		assert := require.New(t)
+		sp := ServiceProfile{}
+
+		for i := 0; i < 10; i++ {
+			ok, err := TakeDeviceUplinkRateToken(context.Background(), devEUI, sp)
+			assert.NoError(err)
+			assert.True(ok)
+		}
+	})
+
+	ts.T().Run("Uplink", func(t *testing.T) {
+		assert := require.New(t)
+		sp := ServiceProfile{
+			ULRate:       1,
+			ULBucketSize: 3,
+		}
+
+		for i := 0; i < 3; i++ {
+			ok, err := TakeDeviceUplinkRateToken(context.Background(), devEUI, sp)
+			assert.NoError(err)
+			assert.True(ok)
+		}
+
+		ok, err := TakeDeviceUplinkRateToken(context.Background(), devEUI, sp)
+		assert.NoError(err)
+		assert.False(ok)
+
+		t.Run("Downlink bucket is not affected", func(t *testing.T) {
+			assert := require.New(t)
+			sp := ServiceProfile{
+				DLRate:       1,
+				DLBucketSize: 1,
+			}
+
+			ok, err := TakeDeviceDownlinkRateToken(context.Background(), devEUI, sp)
+			assert.NoError(err)
+			assert.True(ok)
+
+			ok, err = TakeDeviceDownlinkRateToken(context.Background(), devEUI, sp)
+			assert.NoError(err)
+			assert.False(ok)
+		})
+	})
+
+	ts.T().Run("Downlink check does not take token", func(t *testing.T) {
+		assert := require.New(t)
+		devEUI := lorawan.EUI64{8, 7, 6, 5, 4, 3, 2, 1}
+		sp := ServiceProfile{
+			DLRate:       1,
+			DLBucketSize: 1,
+		}
+
+		for i := 0; i < 2; i++ {
+			ok, err := HasDeviceDownlinkRateToken(context.Background(), devEUI, sp)
+			assert.NoError(err)
+			assert.True(ok)
+		}
+
+		ok, err := TakeDeviceDownlinkRateToken(context.Background(), devEUI, sp)
+		assert.NoError(err)
+		assert.True(ok)
+
+		ok, err = HasDeviceDownlinkRateToken(context.Background(), devEUI, sp)
+		assert.NoError(err)
+		assert.False(ok)
+	})
+}
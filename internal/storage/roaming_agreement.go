@@ -0,0 +1,246 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brocaar/chirpstack-network-server/internal/logging"
+	"github.com/brocaar/lorawan"
+)
+
+const roamingAgreementColumns = `
+	net_id,
+	created_at,
+	updated_at,
+	server,
+	ca_cert,
+	tls_cert,
+	tls_key,
+	async,
+	async_timeout,
+	passive_roaming,
+	passive_roaming_lifetime,
+	passive_roaming_kek_label,
+	handover_roaming,
+	handover_roaming_lifetime,
+	handover_roaming_kek_label`
+
+// RoamingAgreement defines a roaming agreement with a roaming partner,
+// identified by its NetID.
+type RoamingAgreement struct {
+	NetID                   lorawan.NetID
+	CreatedAt               time.Time
+	UpdatedAt               time.Time
+	Server                  string
+	CACert                  string
+	TLSCert                 string
+	TLSKey                  string
+	Async                   bool
+	AsyncTimeout            time.Duration
+	PassiveRoaming          bool
+	PassiveRoamingLifetime  time.Duration
+	PassiveRoamingKEKLabel  string
+	HandoverRoaming         bool
+	HandoverRoamingLifetime time.Duration
+	HandoverRoamingKEKLabel string
+}
+
+// CreateRoamingAgreement creates the given roaming agreement.
+func CreateRoamingAgreement(ctx context.Context, db sqlx.Execer, ra *RoamingAgreement) error {
+	now := time.Now()
+	ra.CreatedAt = now
+	ra.UpdatedAt = now
+
+	_, err := db.Exec(`
+		insert into roaming_agreement (`+roamingAgreementColumns+`
+		) values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`,
+		ra.NetID[:],
+		ra.CreatedAt,
+		ra.UpdatedAt,
+		ra.Server,
+		ra.CACert,
+		ra.TLSCert,
+		ra.TLSKey,
+		ra.Async,
+		ra.AsyncTimeout,
+		ra.PassiveRoaming,
+		ra.PassiveRoamingLifetime,
+		ra.PassiveRoamingKEKLabel,
+		ra.HandoverRoaming,
+		ra.HandoverRoamingLifetime,
+		ra.HandoverRoamingKEKLabel,
+	)
+	if err != nil {
+		return handlePSQLError(err, "insert error")
+	}
+
+	log.WithFields(log.Fields{
+		"net_id": ra.NetID,
+		"ctx_id": ctx.Value(logging.ContextIDKey),
+	}).Info("roaming-agreement created")
+
+	return nil
+}
+
+// GetRoamingAgreement returns the roaming agreement for the given NetID.
+func GetRoamingAgreement(ctx context.Context, db sqlx.Queryer, netID lorawan.NetID) (RoamingAgreement, error) {
+	ra, err := scanRoamingAgreement(db.QueryRowx(`
+		select `+roamingAgreementColumns+`
+		from roaming_agreement
+		where net_id = $1`,
+		netID[:],
+	))
+	if err != nil {
+		return ra, handlePSQLError(err, "select error")
+	}
+
+	return ra, nil
+}
+
+// GetRoamingAgreements returns all the roaming agreements.
+func GetRoamingAgreements(ctx context.Context, db sqlx.Queryer) ([]RoamingAgreement, error) {
+	rows, err := db.Queryx(`
+		select ` + roamingAgreementColumns + `
+		from roaming_agreement
+		order by net_id`,
+	)
+	if err != nil {
+		return nil, handlePSQLError(err, "select error")
+	}
+	defer rows.Close()
+
+	var out []RoamingAgreement
+	for rows.Next() {
+		ra, err := scanRoamingAgreement(rows)
+		if err != nil {
+			return nil, handlePSQLError(err, "scan error")
+		}
+		out = append(out, ra)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, handlePSQLError(err, "rows error")
+	}
+
+	return out, nil
+}
+
+// UpdateRoamingAgreement updates the given roaming agreement.
+func UpdateRoamingAgreement(ctx context.Context, db sqlx.Execer, ra *RoamingAgreement) error {
+	ra.UpdatedAt = time.Now()
+
+	res, err := db.Exec(`
+		update roaming_agreement set
+			updated_at = $2,
+			server = $3,
+			ca_cert = $4,
+			tls_cert = $5,
+			tls_key = $6,
+			async = $7,
+			async_timeout = $8,
+			passive_roaming = $9,
+			passive_roaming_lifetime = $10,
+			passive_roaming_kek_label = $11,
+			handover_roaming = $12,
+			handover_roaming_lifetime = $13,
+			handover_roaming_kek_label = $14
+		where
+			net_id = $1`,
+		ra.NetID[:],
+		ra.UpdatedAt,
+		ra.Server,
+		ra.CACert,
+		ra.TLSCert,
+		ra.TLSKey,
+		ra.Async,
+		ra.AsyncTimeout,
+		ra.PassiveRoaming,
+		ra.PassiveRoamingLifetime,
+		ra.PassiveRoamingKEKLabel,
+		ra.HandoverRoaming,
+		ra.HandoverRoamingLifetime,
+		ra.HandoverRoamingKEKLabel,
+	)
+	if err != nil {
+		return handlePSQLError(err, "update error")
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return handlePSQLError(err, "get rows affected error")
+	}
+	if rowsAffected == 0 {
+		return ErrDoesNotExist
+	}
+
+	log.WithFields(log.Fields{
+		"net_id": ra.NetID,
+		"ctx_id": ctx.Value(logging.ContextIDKey),
+	}).Info("roaming-agreement updated")
+
+	return nil
+}
+
+// DeleteRoamingAgreement deletes the roaming agreement for the given NetID.
+func DeleteRoamingAgreement(ctx context.Context, db sqlx.Execer, netID lorawan.NetID) error {
+	res, err := db.Exec("delete from roaming_agreement where net_id = $1", netID[:])
+	if err != nil {
+		return handlePSQLError(err, "delete error")
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return handlePSQLError(err, "get rows affected error")
+	}
+	if rowsAffected == 0 {
+		return ErrDoesNotExist
+	}
+
+	log.WithFields(log.Fields{
+		"net_id": netID,
+		"ctx_id": ctx.Value(logging.ContextIDKey),
+	}).Info("roaming-agreement deleted")
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanRoamingAgreement scans a roaming_agreement row. This is needed as
+// lorawan.NetID does not implement the sql.Scanner interface.
+func scanRoamingAgreement(row rowScanner) (RoamingAgreement, error) {
+	var ra RoamingAgreement
+	var netID []byte
+
+	err := row.Scan(
+		&netID,
+		&ra.CreatedAt,
+		&ra.UpdatedAt,
+		&ra.Server,
+		&ra.CACert,
+		&ra.TLSCert,
+		&ra.TLSKey,
+		&ra.Async,
+		&ra.AsyncTimeout,
+		&ra.PassiveRoaming,
+		&ra.PassiveRoamingLifetime,
+		&ra.PassiveRoamingKEKLabel,
+		&ra.HandoverRoaming,
+		&ra.HandoverRoamingLifetime,
+		&ra.HandoverRoamingKEKLabel,
+	)
+	if err != nil {
+		return ra, err
+	}
+
+	if len(netID) != len(ra.NetID) {
+		return ra, errors.New("invalid net_id length")
+	}
+	copy(ra.NetID[:], netID)
+
+	return ra, nil
+}
@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/brocaar/lorawan"
+)
+
+func (ts *StorageTestSuite) TestRoamingAgreement() {
+	ra := RoamingAgreement{
+		NetID:                  lorawan.NetID{1, 2, 3},
+		Server:                 "https://010203.example.com",
+		CACert:                 "CACERT",
+		TLSCert:                "TLSCERT",
+		TLSKey:                 "TLSKEY",
+		Async:                  true,
+		AsyncTimeout:           time.Second,
+		PassiveRoaming:         true,
+		PassiveRoamingLifetime: time.Hour,
+		PassiveRoamingKEKLabel: "pr-kek",
+	}
+
+	truncate := func(ra *RoamingAgreement) {
+		ra.CreatedAt = ra.CreatedAt.UTC().Truncate(time.Millisecond)
+		ra.UpdatedAt = ra.UpdatedAt.UTC().Truncate(time.Millisecond)
+	}
+
+	ts.T().Run("Create", func(t *testing.T) {
+		assert := require.New(t)
+		assert.NoError(CreateRoamingAgreement(context.Background(), ts.Tx(), &ra))
+		truncate(&ra)
+
+		raGet, err := GetRoamingAgreement(context.Background(), ts.Tx(), ra.NetID)
+		assert.NoError(err)
+		truncate(&raGet)
+		assert.Equal(ra, raGet)
+	})
+
+	ts.T().Run("Get all", func(t *testing.T) {
+		assert := require.New(t)
+
+		ras, err := GetRoamingAgreements(context.Background(), ts.Tx())
+		assert.NoError(err)
+		assert.Len(ras, 1)
+		truncate(&ras[0])
+		assert.Equal(ra, ras[0])
+	})
+
+	ts.T().Run("Update", func(t *testing.T) {
+		assert := require.New(t)
+
+		ra.Async = false
+		ra.PassiveRoaming = false
+		ra.HandoverRoaming = true
+		ra.HandoverRoamingLifetime = time.Minute
+		ra.HandoverRoamingKEKLabel = "hr-kek"
+		assert.NoError(UpdateRoamingAgreement(context.Background(), ts.Tx(), &ra))
+		truncate(&ra)
+
+		raGet, err := GetRoamingAgreement(context.Background(), ts.Tx(), ra.NetID)
+		assert.NoError(err)
+		truncate(&raGet)
+		assert.Equal(ra, raGet)
+	})
+
+	ts.T().Run("Delete", func(t *testing.T) {
+		assert := require.New(t)
+
+		assert.NoError(DeleteRoamingAgreement(context.Background(), ts.Tx(), ra.NetID))
+		assert.Equal(ErrDoesNotExist, DeleteRoamingAgreement(context.Background(), ts.Tx(), ra.NetID))
+
+		_, err := GetRoamingAgreement(context.Background(), ts.Tx(), ra.NetID)
+		assert.Equal(ErrDoesNotExist, err)
+	})
+}
@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/brocaar/lorawan"
+)
+
+// roamingDeviceSessionKeys defines the Redis key templates of a roaming
+// device-session type (passive-roaming or handover-roaming).
+type roamingDeviceSessionKeys struct {
+	devAddr string // pointer from DevAddr to set of session IDs
+	devEUI  string // pointer from DevEUI to set of session IDs
+	session string
+}
+
+// saveRoamingDeviceSession stores the roaming device-session returned by the
+// encode function. When the session ID is not set, it is generated before
+// calling encode. It returns the TTL of the stored session. A TTL <= 0 means
+// that the session has not been stored as its lifetime has expired.
+func saveRoamingDeviceSession(keys roamingDeviceSessionKeys, id *uuid.UUID, devAddr lorawan.DevAddr, devEUI lorawan.EUI64, lifetime time.Time, encode func() ([]byte, error)) (time.Duration, error) {
+	ttl := lifetime.Sub(time.Now())
+	if ttl <= 0 {
+		return ttl, nil
+	}
+
+	if *id == uuid.Nil {
+		newID, err := uuid.NewV4()
+		if err != nil {
+			return 0, errors.Wrap(err, "new uuid v4 error")
+		}
+		*id = newID
+	}
+
+	b, err := encode()
+	if err != nil {
+		return 0, err
+	}
+
+	devAddrKey := fmt.Sprintf(keys.devAddr, devAddr)
+	devEUIKey := fmt.Sprintf(keys.devEUI, devEUI)
+	sessKey := fmt.Sprintf(keys.session, *id)
+
+	// The DevAddr and DevEUI pointers are shared by the sessions of a
+	// device, they must not expire before the session itself.
+	pointerTTL := deviceSessionTTL
+	if ttl > pointerTTL {
+		pointerTTL = ttl
+	}
+
+	// We need to store a pointer from both the DevAddr and DevEUI to the
+	// roaming device-session ID. This is needed:
+	//  * Because the DevAddr is not guaranteed to be unique
+	//  * Because the DevEUI might not be given (thus is also not guaranteed
+	//    to be an unique identifier).
+	//
+	// But:
+	//  * We need to be able to lookup the session using the DevAddr (potentially
+	//    using the MIC validation).
+	//  * We need to be able to stop a roaming session given a DevEUI.
+	pipe := RedisClient().TxPipeline()
+	pipe.SAdd(devAddrKey, id[:])
+	pipe.SAdd(devEUIKey, id[:])
+	pipe.PExpire(devAddrKey, pointerTTL)
+	pipe.PExpire(devEUIKey, pointerTTL)
+	pipe.Set(sessKey, b, ttl)
+	if _, err := pipe.Exec(); err != nil {
+		return 0, errors.Wrap(err, "exec error")
+	}
+
+	return ttl, nil
+}
+
+// getRoamingDeviceSession returns the encoded roaming device-session.
+func getRoamingDeviceSession(keys roamingDeviceSessionKeys, id uuid.UUID) ([]byte, error) {
+	val, err := RedisClient().Get(fmt.Sprintf(keys.session, id)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, ErrDoesNotExist
+		}
+		return nil, errors.Wrap(err, "get error")
+	}
+
+	return val, nil
+}
+
+// getRoamingDeviceSessionIDs returns the session IDs stored under the given
+// DevAddr or DevEUI pointer key.
+func getRoamingDeviceSessionIDs(key string) ([]uuid.UUID, error) {
+	val, err := RedisClient().SMembers(key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "get session ids error")
+	}
+
+	var out []uuid.UUID
+	for i := range val {
+		var id uuid.UUID
+		copy(id[:], []byte(val[i]))
+		out = append(out, id)
+	}
+
+	return out, nil
+}
+
+// deleteRoamingDeviceSession deletes the roaming device-session and removes
+// its ID from the DevAddr and DevEUI pointers.
+func deleteRoamingDeviceSession(keys roamingDeviceSessionKeys, id uuid.UUID, devAddr lorawan.DevAddr, devEUI lorawan.EUI64) error {
+	pipe := RedisClient().TxPipeline()
+	pipe.Del(fmt.Sprintf(keys.session, id))
+	pipe.SRem(fmt.Sprintf(keys.devAddr, devAddr), id[:])
+	pipe.SRem(fmt.Sprintf(keys.devEUI, devEUI), id[:])
+	if _, err := pipe.Exec(); err != nil {
+		return errors.Wrap(err, "exec error")
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/golang/protobuf/ptypes/empty"
 	context "golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 
 	"github.com/brocaar/chirpstack-api/go/v3/as"
 	"github.com/brocaar/chirpstack-network-server/internal/api/client/asclient"
@@ -47,6 +48,10 @@ type ApplicationClient struct {
 	SetDeviceStatusChan     chan as.SetDeviceStatusRequest
 	SetDeviceLocationChan   chan as.SetDeviceLocationRequest
 
+	// HandleDataUpMetadata contains the outgoing gRPC metadata of the last
+	// HandleUplinkData call.
+	HandleDataUpMetadata metadata.MD
+
 	HandleDataUpResponse        empty.Empty
 	HandleProprietaryUpResponse empty.Empty
 	HandleErrorResponse         empty.Empty
@@ -76,6 +81,7 @@ func (t *ApplicationClient) HandleUplinkData(ctx context.Context, in *as.HandleU
 	if t.HandleDataUpErr != nil {
 		return nil, t.HandleDataUpErr
 	}
+	t.HandleDataUpMetadata, _ = metadata.FromOutgoingContext(ctx)
 	t.HandleDataUpChan <- *in
 	return &t.HandleDataUpResponse, nil
 }
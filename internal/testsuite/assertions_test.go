@@ -12,6 +12,7 @@ import (
 	"github.com/brocaar/chirpstack-api/go/v3/as"
 	"github.com/brocaar/chirpstack-api/go/v3/gw"
 	"github.com/brocaar/chirpstack-api/go/v3/nc"
+	"github.com/brocaar/chirpstack-network-server/internal/backend/applicationserver"
 	"github.com/brocaar/chirpstack-network-server/internal/downlink/ack"
 	"github.com/brocaar/chirpstack-network-server/internal/storage"
 	"github.com/brocaar/lorawan"
@@ -242,6 +243,15 @@ func AssertDeviceActivation(da storage.DeviceActivation) Assertion {
 	}
 }
 
+// AssertLastDevNonce asserts the last used dev-nonce.
+func AssertLastDevNonce(nonce lorawan.DevNonce) Assertion {
+	return func(assert *require.Assertions, ts *IntegrationTestSuite) {
+		last, err := storage.GetLastDevNonce(context.Background(), storage.DB(), ts.Device.DevEUI)
+		assert.NoError(err)
+		assert.Equal(nonce, last)
+	}
+}
+
 // AssertASHandleProprietaryUplinkRequest asserts the given proprietary uplink.
 func AssertASHandleProprietaryUplinkRequest(req as.HandleProprietaryUplinkRequest) Assertion {
 	return func(assert *require.Assertions, ts *IntegrationTestSuite) {
@@ -262,6 +272,32 @@ func AssertASHandleUplinkDataRequest(req as.HandleUplinkDataRequest) Assertion {
 	}
 }
 
+// AssertASNoHandleUplinkDataRequest asserts that there is no uplink request.
+func AssertASNoHandleUplinkDataRequest() Assertion {
+	return func(assert *require.Assertions, ts *IntegrationTestSuite) {
+		time.Sleep(100 * time.Millisecond)
+		select {
+		case <-ts.ASClient.HandleDataUpChan:
+			assert.Fail("unexpected uplink request")
+		default:
+		}
+	}
+}
+
+// AssertASHandleUplinkDataRateLimited asserts the rate-limited flag of the
+// last uplink request. This must be used after
+// AssertASHandleUplinkDataRequest.
+func AssertASHandleUplinkDataRateLimited(rateLimited bool) Assertion {
+	return func(assert *require.Assertions, ts *IntegrationTestSuite) {
+		v := ts.ASClient.HandleDataUpMetadata.Get(applicationserver.UplinkRateLimitedMetadataKey)
+		if rateLimited {
+			assert.Equal([]string{"true"}, v)
+		} else {
+			assert.Len(v, 0)
+		}
+	}
+}
+
 // AssertASHandleDownlinkACKRequest asserts the given ack request.
 func AssertASHandleDownlinkACKRequest(req as.HandleDownlinkACKRequest) Assertion {
 	return func(assert *require.Assertions, ts *IntegrationTestSuite) {
@@ -346,6 +382,16 @@ func AssertNCHandleDownlinkMetaDataRequest(req nc.HandleDownlinkMetaDataRequest)
 	}
 }
 
+// AssertNCHandleRejectedUplinkFrameSetRequest asserts the given rejected uplink frame-set request.
+func AssertNCHandleRejectedUplinkFrameSetRequest(req nc.HandleRejectedUplinkFrameSetRequest) Assertion {
+	return func(assert *require.Assertions, ts *IntegrationTestSuite) {
+		r := <-ts.NCClient.HandleRejectedUplinkFrameSetChan
+		if !proto.Equal(&r, &req) {
+			assert.Equal(req, r)
+		}
+	}
+}
+
 // AssertTXPowerIndex asserts the given tx-power index.
 func AssertTXPowerIndex(txPower int) Assertion {
 	return func(assert *require.Assertions, ts *IntegrationTestSuite) {
@@ -1680,6 +1680,311 @@ func (ts *ClassATestSuite) TestLW10AddGWMetadata() {
 	assert.NoError(storage.UpdateServiceProfile(context.Background(), storage.DB(), ts.ServiceProfile))
 }
 
+func (ts *ClassATestSuite) TestLW10MinGWDiversity() {
+	assert := require.New(ts.T())
+
+	ts.CreateDeviceSession(storage.DeviceSession{
+		MACVersion:            "1.0.2",
+		JoinEUI:               lorawan.EUI64{8, 7, 6, 5, 4, 3, 2, 1},
+		DevAddr:               lorawan.DevAddr{1, 2, 3, 4},
+		FNwkSIntKey:           [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		SNwkSIntKey:           [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		NwkSEncKey:            [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		FCntUp:                8,
+		NFCntDown:             5,
+		EnabledUplinkChannels: []int{0, 1, 2},
+		RX2Frequency:          869525000,
+	})
+
+	ts.ServiceProfile.MinGWDiversity = 2
+	assert.NoError(storage.UpdateServiceProfile(context.Background(), storage.DB(), ts.ServiceProfile))
+
+	fPortOne := uint8(1)
+	phy := lorawan.PHYPayload{
+		MHDR: lorawan.MHDR{
+			MType: lorawan.UnconfirmedDataUp,
+			Major: lorawan.LoRaWANR1,
+		},
+		MACPayload: &lorawan.MACPayload{
+			FHDR: lorawan.FHDR{
+				DevAddr: ts.DeviceSession.DevAddr,
+				FCnt:    10,
+			},
+			FPort:      &fPortOne,
+			FRMPayload: []lorawan.Payload{&lorawan.DataPayload{Bytes: []byte{1, 2, 3, 4}}},
+		},
+		MIC: lorawan.MIC{104, 147, 35, 121},
+	}
+	phyB, err := phy.MarshalBinary()
+	assert.NoError(err)
+
+	tests := []ClassATest{
+		{
+			Name:          "uplink received by one gateway (service-profile: min. two gateways)",
+			DeviceSession: *ts.DeviceSession,
+			TXInfo:        ts.TXInfo,
+			RXInfo:        ts.RXInfo,
+			PHYPayload:    phy,
+			Assert: []Assertion{
+				AssertFCntUp(8),
+				AssertNFCntDown(5),
+				AssertNCHandleRejectedUplinkFrameSetRequest(nc.HandleRejectedUplinkFrameSetRequest{
+					FrameSet: &gw.UplinkFrameSet{
+						PhyPayload: phyB,
+						TxInfo:     &ts.TXInfo,
+						RxInfo:     []*gw.UplinkRXInfo{&ts.RXInfo},
+					},
+				}),
+			},
+		},
+	}
+
+	for _, tst := range tests {
+		ts.T().Run(tst.Name, func(t *testing.T) {
+			ts.AssertClassATest(t, tst)
+		})
+	}
+
+	ts.ServiceProfile.MinGWDiversity = 0
+	assert.NoError(storage.UpdateServiceProfile(context.Background(), storage.DB(), ts.ServiceProfile))
+}
+
+func (ts *ClassATestSuite) TestLW10UplinkRateLimit() {
+	assert := require.New(ts.T())
+
+	ts.CreateDeviceSession(storage.DeviceSession{
+		MACVersion:            "1.0.2",
+		JoinEUI:               lorawan.EUI64{8, 7, 6, 5, 4, 3, 2, 1},
+		DevAddr:               lorawan.DevAddr{1, 2, 3, 4},
+		FNwkSIntKey:           [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		SNwkSIntKey:           [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		NwkSEncKey:            [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		FCntUp:                8,
+		NFCntDown:             5,
+		EnabledUplinkChannels: []int{0, 1, 2},
+		RX2Frequency:          869525000,
+	})
+
+	ts.ServiceProfile.ULRate = 1
+	ts.ServiceProfile.ULBucketSize = 1
+
+	fPortOne := uint8(1)
+	phy := lorawan.PHYPayload{
+		MHDR: lorawan.MHDR{
+			MType: lorawan.UnconfirmedDataUp,
+			Major: lorawan.LoRaWANR1,
+		},
+		MACPayload: &lorawan.MACPayload{
+			FHDR: lorawan.FHDR{
+				DevAddr: ts.DeviceSession.DevAddr,
+				FCnt:    10,
+			},
+			FPort:      &fPortOne,
+			FRMPayload: []lorawan.Payload{&lorawan.DataPayload{Bytes: []byte{1, 2, 3, 4}}},
+		},
+		MIC: lorawan.MIC{104, 147, 35, 121},
+	}
+
+	// takes the only token of the bucket
+	exhaustBucket := func(tst *ClassATest) error {
+		_, err := storage.TakeDeviceUplinkRateToken(context.Background(), ts.Device.DevEUI, *ts.ServiceProfile)
+		return err
+	}
+
+	tests := []struct {
+		policy storage.RatePolicy
+		test   ClassATest
+	}{
+		{
+			policy: storage.Drop,
+			test: ClassATest{
+				Name:          "uplink exceeding the rate (service-profile: drop)",
+				BeforeFunc:    exhaustBucket,
+				DeviceSession: *ts.DeviceSession,
+				TXInfo:        ts.TXInfo,
+				RXInfo:        ts.RXInfo,
+				PHYPayload:    phy,
+				Assert: []Assertion{
+					AssertFCntUp(8),
+					AssertNFCntDown(5),
+					AssertASNoHandleUplinkDataRequest(),
+				},
+			},
+		},
+		{
+			policy: storage.Mark,
+			test: ClassATest{
+				Name:          "uplink exceeding the rate (service-profile: mark)",
+				BeforeFunc:    exhaustBucket,
+				DeviceSession: *ts.DeviceSession,
+				TXInfo:        ts.TXInfo,
+				RXInfo:        ts.RXInfo,
+				PHYPayload:    phy,
+				Assert: []Assertion{
+					AssertFCntUp(11),
+					AssertNFCntDown(5),
+					AssertASHandleUplinkDataRequest(as.HandleUplinkDataRequest{
+						DevEui:  ts.Device.DevEUI[:],
+						JoinEui: ts.DeviceSession.JoinEUI[:],
+						FCnt:    10,
+						FPort:   1,
+						Dr:      0,
+						TxInfo:  &ts.TXInfo,
+						RxInfo:  []*gw.UplinkRXInfo{&ts.RXInfo},
+						Data:    []byte{1, 2, 3, 4},
+					}),
+					AssertASHandleUplinkDataRateLimited(true),
+				},
+			},
+		},
+		{
+			policy: storage.Mark,
+			test: ClassATest{
+				Name:          "uplink within the rate (service-profile: mark)",
+				DeviceSession: *ts.DeviceSession,
+				TXInfo:        ts.TXInfo,
+				RXInfo:        ts.RXInfo,
+				PHYPayload:    phy,
+				Assert: []Assertion{
+					AssertFCntUp(11),
+					AssertNFCntDown(5),
+					AssertASHandleUplinkDataRequest(as.HandleUplinkDataRequest{
+						DevEui:  ts.Device.DevEUI[:],
+						JoinEui: ts.DeviceSession.JoinEUI[:],
+						FCnt:    10,
+						FPort:   1,
+						Dr:      0,
+						TxInfo:  &ts.TXInfo,
+						RxInfo:  []*gw.UplinkRXInfo{&ts.RXInfo},
+						Data:    []byte{1, 2, 3, 4},
+					}),
+					AssertASHandleUplinkDataRateLimited(false),
+				},
+			},
+		},
+	}
+
+	for _, tst := range tests {
+		ts.ServiceProfile.ULRatePolicy = tst.policy
+		assert.NoError(storage.UpdateServiceProfile(context.Background(), storage.DB(), ts.ServiceProfile))
+
+		ts.T().Run(tst.test.Name, func(t *testing.T) {
+			ts.AssertClassATest(t, tst.test)
+		})
+	}
+
+	ts.ServiceProfile.ULRate = 0
+	ts.ServiceProfile.ULBucketSize = 0
+	assert.NoError(storage.UpdateServiceProfile(context.Background(), storage.DB(), ts.ServiceProfile))
+}
+
+func (ts *ClassATestSuite) TestLW10DutyCycle() {
+	assert := require.New(ts.T())
+
+	conf := test.GetConfig()
+	conf.NetworkServer.NetworkSettings.DutyCycle.Window = time.Hour
+	assert.NoError(uplink.Setup(conf))
+	defer ts.initConfig()
+
+	ts.DeviceProfile.MaxDutyCycle = 1
+	assert.NoError(storage.UpdateDeviceProfile(context.Background(), storage.DB(), ts.DeviceProfile))
+
+	ts.CreateDeviceSession(storage.DeviceSession{
+		MACVersion:            "1.0.2",
+		JoinEUI:               lorawan.EUI64{8, 7, 6, 5, 4, 3, 2, 1},
+		DevAddr:               lorawan.DevAddr{1, 2, 3, 4},
+		FNwkSIntKey:           [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		SNwkSIntKey:           [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		NwkSEncKey:            [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		FCntUp:                8,
+		NFCntDown:             5,
+		EnabledUplinkChannels: []int{0, 1, 2},
+		RX2Frequency:          869525000,
+		MaxDCycle:             7,
+	})
+
+	fPortOne := uint8(1)
+	phy := lorawan.PHYPayload{
+		MHDR: lorawan.MHDR{
+			MType: lorawan.UnconfirmedDataUp,
+			Major: lorawan.LoRaWANR1,
+		},
+		MACPayload: &lorawan.MACPayload{
+			FHDR: lorawan.FHDR{
+				DevAddr: ts.DeviceSession.DevAddr,
+				FCnt:    10,
+			},
+			FPort:      &fPortOne,
+			FRMPayload: []lorawan.Payload{&lorawan.DataPayload{Bytes: []byte{1, 2, 3, 4}}},
+		},
+		MIC: lorawan.MIC{104, 147, 35, 121},
+	}
+
+	assertPendingDutyCycleReq := func(pending bool) Assertion {
+		return func(assert *require.Assertions, ts *IntegrationTestSuite) {
+			block, err := storage.GetPendingMACCommand(context.Background(), ts.Device.DevEUI, lorawan.DutyCycleReq)
+			assert.NoError(err)
+
+			if !pending {
+				assert.Nil(block)
+				return
+			}
+
+			assert.NotNil(block)
+			assert.Equal(&lorawan.DutyCycleReqPayload{MaxDCycle: 7}, block.MACCommands[0].Payload)
+		}
+	}
+
+	tests := []ClassATest{
+		{
+			Name:          "device within duty-cycle budget (device-profile: 1%)",
+			DeviceSession: *ts.DeviceSession,
+			TXInfo:        ts.TXInfo,
+			RXInfo:        ts.RXInfo,
+			PHYPayload:    phy,
+			Assert: []Assertion{
+				AssertFCntUp(11),
+				AssertNFCntDown(5),
+				assertPendingDutyCycleReq(false),
+			},
+		},
+		{
+			Name: "device exceeds duty-cycle budget (device-profile: 1%)",
+			BeforeFunc: func(tst *ClassATest) error {
+				// the budget is 36 seconds per hour
+				_, err := storage.AddDeviceUplinkAirtime(context.Background(), ts.Device.DevEUI, time.Now(), 40*time.Second, time.Hour)
+				return err
+			},
+			DeviceSession: *ts.DeviceSession,
+			TXInfo:        ts.TXInfo,
+			RXInfo:        ts.RXInfo,
+			PHYPayload:    phy,
+			Assert: []Assertion{
+				AssertFCntUp(11),
+				AssertNFCntDown(6),
+				assertPendingDutyCycleReq(true),
+				func(assert *require.Assertions, ts *IntegrationTestSuite) {
+					ds, err := storage.GetDeviceSession(context.Background(), ts.Device.DevEUI)
+					assert.NoError(err)
+
+					// the acknowledged value is kept
+					assert.Equal(7, ds.MaxDCycle)
+					assert.True(ds.DutyCycleExceeded)
+				},
+			},
+		},
+	}
+
+	for _, tst := range tests {
+		ts.T().Run(tst.Name, func(t *testing.T) {
+			ts.AssertClassATest(t, tst)
+		})
+	}
+
+	ts.DeviceProfile.MaxDutyCycle = 0
+	assert.NoError(storage.UpdateDeviceProfile(context.Background(), storage.DB(), ts.DeviceProfile))
+}
+
 func (ts *ClassATestSuite) TestLW11DeviceQueue() {
 	ts.CreateDeviceSession(storage.DeviceSession{
 		MACVersion:            "1.1.0",
@@ -0,0 +1,1071 @@
+package testsuite
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/brocaar/chirpstack-api/go/v3/common"
+	"github.com/brocaar/chirpstack-api/go/v3/gw"
+	roamingapi "github.com/brocaar/chirpstack-network-server/internal/api/roaming"
+	"github.com/brocaar/chirpstack-network-server/internal/backend/joinserver"
+	"github.com/brocaar/chirpstack-network-server/internal/band"
+	"github.com/brocaar/chirpstack-network-server/internal/config"
+	"github.com/brocaar/chirpstack-network-server/internal/helpers"
+	"github.com/brocaar/chirpstack-network-server/internal/roaming"
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/chirpstack-network-server/internal/test"
+	"github.com/brocaar/chirpstack-network-server/internal/uplink"
+	"github.com/brocaar/lorawan"
+	"github.com/brocaar/lorawan/backend"
+)
+
+// HandoverRoamingSNSTestSuite contains the tests from the sNS POV.
+// This tests the handover-roaming activation of a visiting device, serving
+// its uplinks and downlinks and stopping the handover-roaming on request of
+// the hNS.
+type HandoverRoamingSNSTestSuite struct {
+	IntegrationTestSuite
+
+	// mocked request / response for the hNS, the requests are sent to a
+	// channel as uplinks are forwarded async.
+	hnsServer   *httptest.Server
+	hnsRequest  chan []byte
+	hnsResponse [][]byte
+
+	jsServer   *httptest.Server
+	jsRequest  [][]byte
+	jsResponse [][]byte
+
+	// sNS roaming API endpoint
+	snsServer *httptest.Server
+
+	rxInfo gw.UplinkRXInfo
+	txInfo gw.UplinkTXInfo
+}
+
+func (ts *HandoverRoamingSNSTestSuite) SetupTest() {
+	ts.IntegrationTestSuite.SetupTest()
+
+	ts.hnsRequest = make(chan []byte, 100)
+	ts.hnsResponse = nil
+	ts.jsRequest = nil
+	ts.jsResponse = nil
+}
+
+func (ts *HandoverRoamingSNSTestSuite) SetupSuite() {
+	ts.IntegrationTestSuite.SetupSuite()
+
+	assert := require.New(ts.T())
+
+	ts.CreateGateway(storage.Gateway{
+		GatewayID: lorawan.EUI64{1, 2, 1, 2, 1, 2, 1, 2},
+		Location: storage.GPSPoint{
+			Latitude:  1,
+			Longitude: 2,
+		},
+		Altitude: 3,
+	})
+
+	ts.rxInfo = gw.UplinkRXInfo{
+		GatewayId: ts.Gateway.GatewayID[:],
+		LoraSnr:   7,
+		Rssi:      6,
+		Location: &common.Location{
+			Latitude:  1,
+			Longitude: 2,
+			Altitude:  3,
+		},
+
+		Context: []byte{1, 2, 3, 4},
+	}
+
+	ts.txInfo = gw.UplinkTXInfo{
+		Frequency: 868100000,
+	}
+	assert.NoError(helpers.SetUplinkTXInfoDataRate(&ts.txInfo, 1, band.Band()))
+
+	// setup hNS endpoint
+	ts.hnsServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		ts.hnsRequest <- b
+		w.Write(ts.hnsResponse[0])
+		ts.hnsResponse = ts.hnsResponse[1:]
+	}))
+
+	// setup JS endpoint
+	ts.jsServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		ts.jsRequest = append(ts.jsRequest, b)
+		w.Write(ts.jsResponse[0])
+	}))
+
+	// configure default JS
+	conf := test.GetConfig()
+	conf.JoinServer.Default.Server = ts.jsServer.URL
+	assert.NoError(joinserver.Setup(conf))
+
+	// configure sNS API
+	ts.snsServer = httptest.NewServer(roamingapi.NewAPI(conf.NetworkServer.NetID))
+
+	// configure handover-roaming agreement
+	conf.Roaming.Servers = []config.RoamingServer{
+		{
+			NetID:                   lorawan.NetID{6, 6, 6},
+			Async:                   false,
+			HandoverRoaming:         true,
+			HandoverRoamingLifetime: time.Minute,
+			Server:                  ts.hnsServer.URL,
+		},
+	}
+	assert.NoError(roaming.Setup(conf))
+}
+
+func (ts *HandoverRoamingSNSTestSuite) TearDownSuite() {
+	ts.jsServer.Close()
+	ts.hnsServer.Close()
+	ts.snsServer.Close()
+}
+
+// createHandoverRoamingDeviceSession creates the device-session and
+// handover-roaming device-session as stored by the sNS on activation.
+func (ts *HandoverRoamingSNSTestSuite) createHandoverRoamingDeviceSession(devEUI lorawan.EUI64) storage.DeviceSession {
+	assert := require.New(ts.T())
+	conf := test.GetConfig()
+
+	devAddr := lorawan.DevAddr{1, 2, 3, 4}
+	devAddr.SetAddrPrefix(conf.NetworkServer.NetID)
+
+	ds := storage.DeviceSession{
+		MACVersion:            "1.0.3",
+		DevAddr:               devAddr,
+		JoinEUI:               lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8},
+		DevEUI:                devEUI,
+		FNwkSIntKey:           [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		SNwkSIntKey:           [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		NwkSEncKey:            [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		FCntUp:                10,
+		NFCntDown:             5,
+		RX2DR:                 uint8(conf.NetworkServer.NetworkSettings.RX2DR),
+		RX2Frequency:          conf.NetworkServer.NetworkSettings.RX2Frequency,
+		EnabledUplinkChannels: band.Band().GetStandardUplinkChannelIndices(),
+		NbTrans:               1,
+	}
+	assert.NoError(storage.SaveDeviceSession(context.Background(), ds))
+
+	hrDS := storage.HandoverRoamingDeviceSession{
+		NetID:    lorawan.NetID{6, 6, 6},
+		DevAddr:  devAddr,
+		DevEUI:   devEUI,
+		Lifetime: time.Now().Add(time.Minute),
+		DeviceProfile: storage.DeviceProfile{
+			MACVersion:   "1.0.3",
+			SupportsJoin: true,
+		},
+		ServiceProfile: storage.ServiceProfile{
+			DRMax: 5,
+		},
+	}
+	assert.NoError(storage.SaveHandoverRoamingDeviceSession(context.Background(), &hrDS))
+
+	ts.DeviceSession = &ds
+	return ds
+}
+
+func (ts *HandoverRoamingSNSTestSuite) TestJoinRequest() {
+	assert := require.New(ts.T())
+	conf := test.GetConfig()
+
+	dlFreq1 := 868.1
+	dlFreq2 := 868.2
+	classMode := "A"
+	dataRate1 := 1
+	dataRate2 := 2
+	rxDelay1 := 5
+	lifetime := 60
+	gwCnt := 1
+	devEUI := lorawan.EUI64{8, 7, 6, 5, 4, 3, 2, 1}
+
+	// join-request phypayload
+	phy := lorawan.PHYPayload{
+		MHDR: lorawan.MHDR{
+			MType: lorawan.JoinRequest,
+			Major: lorawan.LoRaWANR1,
+		},
+		MACPayload: &lorawan.JoinRequestPayload{
+			JoinEUI:  lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8},
+			DevEUI:   devEUI,
+			DevNonce: 123,
+		},
+	}
+	phyB, err := phy.MarshalBinary()
+	assert.NoError(err)
+
+	// JS HomeNSAns
+	homeNSAns := backend.HomeNSAnsPayload{
+		BasePayloadResult: backend.BasePayloadResult{
+			Result: backend.Result{
+				ResultCode: backend.Success,
+			},
+		},
+		HNetID: lorawan.NetID{6, 6, 6},
+	}
+	homeNSAnsB, err := json.Marshal(homeNSAns)
+	assert.NoError(err)
+
+	// ULToken
+	ulTokenB, err := proto.Marshal(&ts.rxInfo)
+	assert.NoError(err)
+
+	// hNS HRStartAns
+	hrStartAns := roaming.HRStartAnsPayload{
+		BasePayloadResult: backend.BasePayloadResult{
+			Result: backend.Result{
+				ResultCode: backend.Success,
+			},
+		},
+		PHYPayload: backend.HEXBytes{1, 2, 3, 4},
+		DevEUI:     &devEUI,
+		Lifetime:   &lifetime,
+		NwkSKey: &backend.KeyEnvelope{
+			AESKey: backend.HEXBytes{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8},
+		},
+		DeviceProfile: &backend.DeviceProfile{
+			MACVersion:   "1.0.3",
+			SupportsJoin: true,
+			RFRegion:     "EU868",
+		},
+		ServiceProfile: &roaming.ServiceProfile{
+			DRMax:     5,
+			TargetPER: 10,
+		},
+		DLMetaData: &backend.DLMetaData{
+			DLFreq1:   &dlFreq1,
+			DLFreq2:   &dlFreq2,
+			RXDelay1:  &rxDelay1,
+			ClassMode: &classMode,
+			DataRate1: &dataRate1,
+			DataRate2: &dataRate2,
+			GWInfo: []backend.GWInfoElement{
+				{
+					ID:      backend.HEXBytes(ts.Gateway.GatewayID[:]),
+					ULToken: backend.HEXBytes(ulTokenB),
+				},
+			},
+		},
+	}
+	hrStartAnsB, err := json.Marshal(hrStartAns)
+	assert.NoError(err)
+
+	// the CFList is set by the sNS
+	var cFListB backend.HEXBytes
+	if cFList := band.Band().GetCFList("1.0.0"); cFList != nil {
+		cFListB, err = cFList.MarshalBinary()
+		assert.NoError(err)
+	}
+
+	ts.T().Run("success", func(t *testing.T) {
+		assert := require.New(t)
+
+		ts.jsResponse = [][]byte{homeNSAnsB}
+		ts.hnsResponse = [][]byte{hrStartAnsB}
+
+		// "send" uplink
+		assert.NoError(uplink.HandleUplinkFrame(context.Background(), gw.UplinkFrame{
+			RxInfo:     &ts.rxInfo,
+			TxInfo:     &ts.txInfo,
+			PhyPayload: phyB,
+		}))
+
+		// validate hNS HRStartReq
+		rssi := 6
+		snr := float64(7)
+		lat := float64(1)
+		lon := float64(2)
+		var hrStartReq roaming.HRStartReqPayload
+		assert.NoError(json.Unmarshal(<-ts.hnsRequest, &hrStartReq))
+		assert.NotEqual(0, hrStartReq.TransactionID)
+		hrStartReq.TransactionID = 0
+		var nilTime time.Time
+		assert.False(time.Time(hrStartReq.ULMetaData.RecvTime).Equal(nilTime))
+		hrStartReq.ULMetaData.RecvTime = backend.ISO8601Time(nilTime)
+
+		// the DevAddr is assigned by the sNS
+		devAddr := hrStartReq.DevAddr
+		assert.True(devAddr.IsNetID(conf.NetworkServer.NetID))
+
+		assert.Equal(roaming.HRStartReqPayload{
+			BasePayload: backend.BasePayload{
+				ProtocolVersion: "1.0",
+				SenderID:        "030201",
+				ReceiverID:      "060606",
+				MessageType:     roaming.HRStartReq,
+			},
+			PHYPayload: backend.HEXBytes(phyB),
+			DevAddr:    devAddr,
+			DLSettings: lorawan.DLSettings{
+				RX2DataRate: uint8(conf.NetworkServer.NetworkSettings.RX2DR),
+				RX1DROffset: uint8(conf.NetworkServer.NetworkSettings.RX1DROffset),
+			},
+			RxDelay: conf.NetworkServer.NetworkSettings.RX1Delay,
+			CFList:  cFListB,
+			ULMetaData: backend.ULMetaData{
+				DevEUI:   &devEUI,
+				DataRate: &dataRate1,
+				RFRegion: "EU868",
+				ULFreq:   &dlFreq1,
+				GWCnt:    &gwCnt,
+				GWInfo: []backend.GWInfoElement{
+					{
+						ID:        backend.HEXBytes(ts.Gateway.GatewayID[:]),
+						RSSI:      &rssi,
+						SNR:       &snr,
+						Lat:       &lat,
+						Lon:       &lon,
+						ULToken:   backend.HEXBytes(ulTokenB),
+						DLAllowed: true,
+					},
+				},
+			},
+		}, hrStartReq)
+
+		// validate published join-accept
+		downlinkFrame := <-ts.GWBackend.TXPacketChan
+		assert.Equal(ts.Gateway.GatewayID[:], downlinkFrame.GetGatewayId())
+		assert.Equal([]byte{1, 2, 3, 4}, downlinkFrame.Items[0].PhyPayload)
+
+		// validate the device-session used to serve the device
+		ds, err := storage.GetDeviceSession(context.Background(), devEUI)
+		assert.NoError(err)
+		assert.Equal(devAddr, ds.DevAddr)
+		assert.Equal("1.0.3", ds.MACVersion)
+		assert.Equal(lorawan.AES128Key{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8}, ds.FNwkSIntKey)
+		assert.Equal(lorawan.AES128Key{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8}, ds.SNwkSIntKey)
+		assert.Equal(lorawan.AES128Key{1, 2, 3, 4, 5, 6, 7, 8, 1, 2, 3, 4, 5, 6, 7, 8}, ds.NwkSEncKey)
+		assert.EqualValues(conf.NetworkServer.NetworkSettings.RX2DR, ds.RX2DR)
+		assert.EqualValues(conf.NetworkServer.NetworkSettings.RX1Delay, ds.RXDelay)
+
+		// validate the handover-roaming device-session
+		hrDS, err := storage.GetHandoverRoamingDeviceSessionForDevEUI(context.Background(), devEUI, devAddr)
+		assert.NoError(err)
+		assert.Equal(lorawan.NetID{6, 6, 6}, hrDS.NetID)
+		assert.Equal("1.0.3", hrDS.DeviceProfile.MACVersion)
+		assert.Equal(5, hrDS.ServiceProfile.DRMax)
+		assert.Equal(10, hrDS.ServiceProfile.TargetPER)
+	})
+}
+
+func (ts *HandoverRoamingSNSTestSuite) TestUplink() {
+	assert := require.New(ts.T())
+
+	devEUI := lorawan.EUI64{8, 7, 6, 5, 4, 3, 2, 1}
+	ds := ts.createHandoverRoamingDeviceSession(devEUI)
+
+	// hNS XmitDataAns
+	xmitDataAns := backend.XmitDataAnsPayload{
+		BasePayloadResult: backend.BasePayloadResult{
+			Result: backend.Result{
+				ResultCode: backend.Success,
+			},
+		},
+	}
+	xmitDataAnsB, err := json.Marshal(xmitDataAns)
+	assert.NoError(err)
+
+	ts.T().Run("mac-commands are handled by the sNS, payload is forwarded to the hNS", func(t *testing.T) {
+		assert := require.New(t)
+
+		ts.hnsResponse = [][]byte{xmitDataAnsB}
+
+		uplinkFrame := ts.GetUplinkFrameForFRMPayload(ts.rxInfo, ts.txInfo, lorawan.UnconfirmedDataUp, 10, []byte{1, 2, 3, 4}, &lorawan.MACCommand{CID: lorawan.LinkCheckReq})
+
+		// "send" uplink
+		assert.NoError(uplink.HandleUplinkFrame(context.Background(), uplinkFrame))
+
+		// validate hNS XmitDataReq
+		var xmitDataReq backend.XmitDataReqPayload
+		assert.NoError(json.Unmarshal(<-ts.hnsRequest, &xmitDataReq))
+		assert.Equal(backend.XmitDataReq, xmitDataReq.MessageType)
+		assert.Equal("030201", xmitDataReq.SenderID)
+		assert.Equal("060606", xmitDataReq.ReceiverID)
+		assert.Equal(backend.HEXBytes(uplinkFrame.PhyPayload), xmitDataReq.PHYPayload)
+		assert.NotNil(xmitDataReq.ULMetaData)
+		assert.Equal(ds.DevAddr, *xmitDataReq.ULMetaData.DevAddr)
+		assert.Equal(devEUI, *xmitDataReq.ULMetaData.DevEUI)
+
+		// the downlink frame-counter after the LinkCheckAns downlink
+		assert.Equal(uint32(6), *xmitDataReq.ULMetaData.FCntDown)
+
+		// validate the LinkCheckAns downlink
+		downlinkFrame := <-ts.GWBackend.TXPacketChan
+		assert.Equal(ts.Gateway.GatewayID[:], downlinkFrame.GetGatewayId())
+
+		var phy lorawan.PHYPayload
+		assert.NoError(phy.UnmarshalBinary(downlinkFrame.Items[0].PhyPayload))
+		assert.NoError(phy.DecodeFOptsToMACCommands())
+		macPL, ok := phy.MACPayload.(*lorawan.MACPayload)
+		assert.True(ok)
+		assert.Equal(ds.DevAddr, macPL.FHDR.DevAddr)
+
+		var linkCheckAns bool
+		for _, pl := range macPL.FHDR.FOpts {
+			if mac, ok := pl.(*lorawan.MACCommand); ok && mac.CID == lorawan.LinkCheckAns {
+				linkCheckAns = true
+			}
+		}
+		assert.True(linkCheckAns)
+
+		// validate the device-session
+		ds, err := storage.GetDeviceSession(context.Background(), devEUI)
+		assert.NoError(err)
+		assert.Equal(uint32(11), ds.FCntUp)
+		assert.Equal(uint32(6), ds.NFCntDown)
+	})
+}
+
+func (ts *HandoverRoamingSNSTestSuite) TestXmitDataReqDownlink() {
+	assert := require.New(ts.T())
+	conf := test.GetConfig()
+
+	devEUI := lorawan.EUI64{8, 7, 6, 5, 4, 3, 2, 1}
+	ds := ts.createHandoverRoamingDeviceSession(devEUI)
+
+	// hNS XmitDataAns
+	xmitDataAns := backend.XmitDataAnsPayload{
+		BasePayloadResult: backend.BasePayloadResult{
+			Result: backend.Result{
+				ResultCode: backend.Success,
+			},
+		},
+	}
+	xmitDataAnsB, err := json.Marshal(xmitDataAns)
+	assert.NoError(err)
+
+	// hNS client
+	client, err := backend.NewClient(backend.ClientConfig{
+		SenderID:   "060606",
+		ReceiverID: conf.NetworkServer.NetID.String(),
+		Server:     ts.snsServer.URL,
+	})
+	assert.NoError(err)
+
+	fPort := uint8(20)
+	fCntDown := uint32(5)
+
+	ts.T().Run("unknown device", func(t *testing.T) {
+		assert := require.New(t)
+
+		// the returned error depends on the result-code handling of the
+		// client, only the returned answer is validated
+		unknownDevEUI := lorawan.EUI64{1, 1, 1, 1, 1, 1, 1, 1}
+		resp, _ := client.XmitDataReq(context.Background(), backend.XmitDataReqPayload{
+			FRMPayload: backend.HEXBytes{1, 2, 3},
+			DLMetaData: &backend.DLMetaData{
+				DevEUI:   &unknownDevEUI,
+				FPort:    &fPort,
+				FCntDown: &fCntDown,
+			},
+		})
+		assert.Equal(backend.UnknownDevEUI, resp.Result.ResultCode)
+	})
+
+	ts.T().Run("payload is sent on the next uplink", func(t *testing.T) {
+		assert := require.New(t)
+
+		resp, err := client.XmitDataReq(context.Background(), backend.XmitDataReqPayload{
+			FRMPayload: backend.HEXBytes{1, 2, 3},
+			DLMetaData: &backend.DLMetaData{
+				DevEUI:   &devEUI,
+				FPort:    &fPort,
+				FCntDown: &fCntDown,
+			},
+		})
+		assert.NoError(err)
+		assert.Equal(backend.Success, resp.Result.ResultCode)
+
+		ts.hnsResponse = [][]byte{xmitDataAnsB}
+
+		// "send" uplink
+		uplinkFrame := ts.GetUplinkFrameForFRMPayload(ts.rxInfo, ts.txInfo, lorawan.UnconfirmedDataUp, 10, []byte{1, 2, 3, 4})
+		assert.NoError(uplink.HandleUplinkFrame(context.Background(), uplinkFrame))
+
+		// validate the downlink
+		downlinkFrame := <-ts.GWBackend.TXPacketChan
+		var phy lorawan.PHYPayload
+		assert.NoError(phy.UnmarshalBinary(downlinkFrame.Items[0].PhyPayload))
+		assert.Equal(lorawan.UnconfirmedDataDown, phy.MHDR.MType)
+		macPL, ok := phy.MACPayload.(*lorawan.MACPayload)
+		assert.True(ok)
+		assert.Equal(ds.DevAddr, macPL.FHDR.DevAddr)
+		assert.Equal(uint32(5), macPL.FHDR.FCnt)
+		assert.Equal(fPort, *macPL.FPort)
+		assert.Len(macPL.FRMPayload, 1)
+		assert.Equal([]byte{1, 2, 3}, macPL.FRMPayload[0].(*lorawan.DataPayload).Bytes)
+
+		// the uplink is forwarded with the updated downlink frame-counter
+		var xmitDataReq backend.XmitDataReqPayload
+		assert.NoError(json.Unmarshal(<-ts.hnsRequest, &xmitDataReq))
+		assert.Equal(uint32(6), *xmitDataReq.ULMetaData.FCntDown)
+
+		count, err := storage.GetHandoverRoamingDeviceQueueItemCount(context.Background(), devEUI)
+		assert.NoError(err)
+		assert.Equal(0, count)
+	})
+}
+
+func (ts *HandoverRoamingSNSTestSuite) TestHRStopReq() {
+	assert := require.New(ts.T())
+	conf := test.GetConfig()
+
+	devEUI := lorawan.EUI64{8, 7, 6, 5, 4, 3, 2, 1}
+	ts.createHandoverRoamingDeviceSession(devEUI)
+
+	var ans roaming.HRStopAnsPayload
+	assert.NoError(handoverRoamingRequest(ts.snsServer.URL, roaming.HRStopReqPayload{
+		BasePayload: backend.BasePayload{
+			ProtocolVersion: backend.ProtocolVersion1_0,
+			SenderID:        "060606",
+			ReceiverID:      conf.NetworkServer.NetID.String(),
+			TransactionID:   1234,
+			MessageType:     roaming.HRStopReq,
+		},
+		DevEUI: devEUI,
+	}, &ans))
+	assert.Equal(backend.Success, ans.Result.ResultCode)
+	assert.Equal(uint32(1234), ans.TransactionID)
+
+	ids, err := storage.GetHandoverRoamingIDsForDevEUI(context.Background(), devEUI)
+	assert.NoError(err)
+	assert.Len(ids, 0)
+
+	_, err = storage.GetDeviceSession(context.Background(), devEUI)
+	assert.Equal(storage.ErrDoesNotExist, err)
+}
+
+// HandoverRoamingHNSTestSuite contains the tests from the hNS POV.
+// This tests the handover-roaming activation requested by the sNS, uplinks
+// forwarded by the sNS and stopping the handover-roaming on re-join.
+type HandoverRoamingHNSTestSuite struct {
+	IntegrationTestSuite
+
+	// mocked request / response for the sNS, the requests are sent to a
+	// channel as the HRStopReq is sent async.
+	snsServer   *httptest.Server
+	snsRequest  chan []byte
+	snsResponse [][]byte
+
+	// hNS roaming API endpoint
+	hnsServer *httptest.Server
+
+	// DevAddr assigned by the sNS
+	devAddr lorawan.DevAddr
+}
+
+func (ts *HandoverRoamingHNSTestSuite) SetupTest() {
+	ts.IntegrationTestSuite.SetupTest()
+
+	ts.snsRequest = make(chan []byte, 100)
+	ts.snsResponse = nil
+}
+
+func (ts *HandoverRoamingHNSTestSuite) SetupSuite() {
+	ts.IntegrationTestSuite.SetupSuite()
+	assert := require.New(ts.T())
+
+	ts.JoinAcceptKey = lorawan.AES128Key{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+
+	// sNS mock
+	ts.snsServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		ts.snsRequest <- b
+		w.Write(ts.snsResponse[0])
+		ts.snsResponse = ts.snsResponse[1:]
+	}))
+
+	// configuration
+	conf := test.GetConfig()
+
+	// configure hNS API
+	ts.hnsServer = httptest.NewServer(roamingapi.NewAPI(conf.NetworkServer.NetID))
+
+	// configure handover-roaming agreement with sNS
+	conf.Roaming.Servers = []config.RoamingServer{
+		{
+			NetID:                   lorawan.NetID{6, 6, 6},
+			Async:                   false,
+			HandoverRoaming:         true,
+			HandoverRoamingLifetime: time.Minute,
+			Server:                  ts.snsServer.URL,
+		},
+	}
+	assert.NoError(roaming.Setup(conf))
+
+	ts.devAddr = lorawan.DevAddr{1, 2, 3, 4}
+	ts.devAddr.SetAddrPrefix(lorawan.NetID{6, 6, 6})
+
+	// create test device
+	ts.CreateGateway(storage.Gateway{
+		GatewayID: lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8},
+	})
+	ts.CreateServiceProfile(storage.ServiceProfile{
+		DRMax:     5,
+		HRAllowed: true,
+	})
+	ts.CreateDeviceProfile(storage.DeviceProfile{
+		MACVersion:   "1.0.2",
+		RXDelay1:     1,
+		SupportsJoin: true,
+	})
+	ts.CreateDevice(storage.Device{
+		DevEUI: lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8},
+	})
+}
+
+func (ts *HandoverRoamingHNSTestSuite) TearDownSuite() {
+	ts.snsServer.Close()
+	ts.hnsServer.Close()
+}
+
+// getJoinAnsPayload returns the JoinAnsPayload as returned by the JS for
+// the given DevNonce.
+func (ts *HandoverRoamingHNSTestSuite) getJoinAnsPayload(devNonce lorawan.DevNonce) backend.JoinAnsPayload {
+	assert := require.New(ts.T())
+	conf := test.GetConfig()
+
+	jaPHY := lorawan.PHYPayload{
+		MHDR: lorawan.MHDR{
+			MType: lorawan.JoinAccept,
+			Major: lorawan.LoRaWANR1,
+		},
+		MACPayload: &lorawan.JoinAcceptPayload{
+			JoinNonce: 197121,
+			HomeNetID: conf.NetworkServer.NetID,
+			DevAddr:   ts.devAddr,
+		},
+	}
+	assert.NoError(jaPHY.SetDownlinkJoinMIC(lorawan.JoinRequestType, lorawan.EUI64{8, 7, 6, 5, 4, 3, 2, 1}, devNonce, ts.JoinAcceptKey))
+	assert.NoError(jaPHY.EncryptJoinAcceptPayload(ts.JoinAcceptKey))
+	jaBytes, err := jaPHY.MarshalBinary()
+	assert.NoError(err)
+
+	return backend.JoinAnsPayload{
+		BasePayloadResult: backend.BasePayloadResult{
+			Result: backend.Result{
+				ResultCode: backend.Success,
+			},
+		},
+		PHYPayload: backend.HEXBytes(jaBytes),
+		NwkSKey: &backend.KeyEnvelope{
+			AESKey: []byte{16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1},
+		},
+		AppSKey: &backend.KeyEnvelope{
+			AESKey: []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		},
+	}
+}
+
+// getJoinRequestPHYPayload returns the join-request for the given DevNonce.
+func (ts *HandoverRoamingHNSTestSuite) getJoinRequestPHYPayload(devNonce lorawan.DevNonce) lorawan.PHYPayload {
+	assert := require.New(ts.T())
+
+	phy := lorawan.PHYPayload{
+		MHDR: lorawan.MHDR{
+			MType: lorawan.JoinRequest,
+			Major: lorawan.LoRaWANR1,
+		},
+		MACPayload: &lorawan.JoinRequestPayload{
+			JoinEUI:  lorawan.EUI64{8, 7, 6, 5, 4, 3, 2, 1},
+			DevEUI:   ts.Device.DevEUI,
+			DevNonce: devNonce,
+		},
+	}
+	assert.NoError(phy.SetUplinkJoinMIC(ts.JoinAcceptKey))
+
+	return phy
+}
+
+func (ts *HandoverRoamingHNSTestSuite) TestHRStartReq() {
+	assert := require.New(ts.T())
+	conf := test.GetConfig()
+
+	joinAns := ts.getJoinAnsPayload(1)
+	ts.JSClient.JoinAnsPayload = joinAns
+
+	phyB, err := ts.getJoinRequestPHYPayload(1).MarshalBinary()
+	assert.NoError(err)
+
+	// request
+	ulFreq := 868.1
+	dataRate := 3
+	recvTime := time.Now().Round(time.Second)
+	gwCnt := 1
+
+	var ans roaming.HRStartAnsPayload
+	assert.NoError(handoverRoamingRequest(ts.hnsServer.URL, roaming.HRStartReqPayload{
+		BasePayload: backend.BasePayload{
+			ProtocolVersion: backend.ProtocolVersion1_0,
+			SenderID:        "060606",
+			ReceiverID:      conf.NetworkServer.NetID.String(),
+			TransactionID:   1234,
+			MessageType:     roaming.HRStartReq,
+		},
+		PHYPayload: backend.HEXBytes(phyB),
+		DevAddr:    ts.devAddr,
+		DLSettings: lorawan.DLSettings{
+			RX2DataRate: 3,
+			RX1DROffset: 2,
+		},
+		RxDelay: 2,
+		CFList:  backend.HEXBytes{1, 2, 3},
+		ULMetaData: backend.ULMetaData{
+			DevEUI:   &ts.Device.DevEUI,
+			ULFreq:   &ulFreq,
+			DataRate: &dataRate,
+			RecvTime: backend.ISO8601Time(recvTime),
+			RFRegion: "EU868",
+			GWCnt:    &gwCnt,
+			GWInfo: []backend.GWInfoElement{
+				{
+					ID:        backend.HEXBytes{1, 2, 3, 4, 5, 6, 7, 8},
+					ULToken:   backend.HEXBytes{3, 2, 1},
+					DLAllowed: true,
+				},
+			},
+		},
+	}, &ans))
+
+	// validate HRStartAns
+	assert.Equal(backend.Success, ans.Result.ResultCode)
+	assert.Equal(uint32(1234), ans.TransactionID)
+	assert.Equal(joinAns.PHYPayload, ans.PHYPayload)
+	assert.Equal(ts.Device.DevEUI, *ans.DevEUI)
+	assert.Equal(60, *ans.Lifetime)
+	assert.NotNil(ans.NwkSKey)
+	assert.Equal(joinAns.NwkSKey.AESKey, ans.NwkSKey.AESKey)
+	assert.NotNil(ans.DeviceProfile)
+	assert.Equal("1.0.2", ans.DeviceProfile.MACVersion)
+	assert.NotNil(ans.ServiceProfile)
+	assert.Equal(5, ans.ServiceProfile.DRMax)
+	assert.True(ans.ServiceProfile.HRAllowed)
+	assert.NotNil(ans.DLMetaData)
+	assert.Len(ans.DLMetaData.GWInfo, 1)
+	assert.Equal(backend.HEXBytes{3, 2, 1}, ans.DLMetaData.GWInfo[0].ULToken)
+
+	// validate the JS JoinReq uses the RX parameters and CFList of the sNS
+	jsReq := <-ts.JSClient.JoinReqPayloadChan
+	assert.Equal(ts.devAddr, jsReq.DevAddr)
+	assert.Equal(lorawan.DLSettings{RX2DataRate: 3, RX1DROffset: 2}, jsReq.DLSettings)
+	assert.Equal(2, jsReq.RxDelay)
+	assert.Equal(backend.HEXBytes{1, 2, 3}, jsReq.CFList)
+
+	// the sNS NetID is stored, so that handover-roaming can be stopped on re-join
+	netID, err := storage.GetHandoverRoamingNetIDForDevEUI(context.Background(), ts.Device.DevEUI)
+	assert.NoError(err)
+	assert.Equal(lorawan.NetID{6, 6, 6}, netID)
+
+	// the device-session uses the DevAddr of the sNS
+	ds, err := storage.GetDeviceSession(context.Background(), ts.Device.DevEUI)
+	assert.NoError(err)
+	assert.Equal(ts.devAddr, ds.DevAddr)
+}
+
+func (ts *HandoverRoamingHNSTestSuite) TestXmitDataReqUplink() {
+	assert := require.New(ts.T())
+	conf := test.GetConfig()
+
+	// setup device-session
+	ts.CreateDeviceSession(storage.DeviceSession{
+		JoinEUI:               lorawan.EUI64{8, 7, 6, 5, 4, 3, 2, 1},
+		DevAddr:               ts.devAddr,
+		FNwkSIntKey:           [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		SNwkSIntKey:           [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		NwkSEncKey:            [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		FCntUp:                20,
+		NFCntDown:             10,
+		EnabledUplinkChannels: []int{0, 1, 2},
+		RX2Frequency:          869525000,
+	})
+	assert.NoError(storage.SaveHandoverRoamingNetIDForDevEUI(context.Background(), ts.Device.DevEUI, lorawan.NetID{6, 6, 6}, time.Minute))
+
+	fPort := uint8(10)
+
+	phy := lorawan.PHYPayload{
+		MHDR: lorawan.MHDR{
+			MType: lorawan.ConfirmedDataUp,
+			Major: lorawan.LoRaWANR1,
+		},
+		MACPayload: &lorawan.MACPayload{
+			FHDR: lorawan.FHDR{
+				DevAddr: ts.devAddr,
+				FCnt:    20,
+			},
+			FPort: &fPort,
+		},
+	}
+	assert.NoError(phy.SetUplinkDataMIC(lorawan.LoRaWAN1_0, 0, 0, 0, ts.DeviceSession.FNwkSIntKey, ts.DeviceSession.SNwkSIntKey))
+	phyB, err := phy.MarshalBinary()
+	assert.NoError(err)
+
+	// setup client
+	client, err := backend.NewClient(backend.ClientConfig{
+		SenderID:   "060606",
+		ReceiverID: conf.NetworkServer.NetID.String(),
+		Server:     ts.hnsServer.URL,
+	})
+	assert.NoError(err)
+
+	// request
+	ulFreq := 868.1
+	dataRate := 3
+	recvTime := time.Now().Round(time.Second)
+	gwCnt := 1
+
+	resp, err := client.XmitDataReq(context.Background(), backend.XmitDataReqPayload{
+		PHYPayload: backend.HEXBytes(phyB),
+		ULMetaData: &backend.ULMetaData{
+			DevAddr:  &ts.devAddr,
+			ULFreq:   &ulFreq,
+			DataRate: &dataRate,
+			RecvTime: backend.ISO8601Time(recvTime),
+			RFRegion: "EU868",
+			GWCnt:    &gwCnt,
+			GWInfo: []backend.GWInfoElement{
+				{
+					ID:        backend.HEXBytes{1, 2, 3, 4, 5, 6, 7, 8},
+					DLAllowed: true,
+				},
+			},
+		},
+	})
+	assert.NoError(err)
+	assert.Equal(backend.Success, resp.Result.ResultCode)
+
+	// check uplink was sent to AS
+	asReq := <-ts.ASClient.HandleDataUpChan
+	assert.Equal(ts.Device.DevEUI[:], asReq.DevEui)
+	assert.Equal(uint32(20), asReq.FCnt)
+	assert.Equal(uint32(10), asReq.FPort)
+	assert.True(asReq.ConfirmedUplink)
+
+	// the downlink (ACK) is sent by the sNS, which controls the MAC-layer
+	assert.Len(ts.snsRequest, 0)
+
+	ds, err := storage.GetDeviceSession(context.Background(), ts.Device.DevEUI)
+	assert.NoError(err)
+	assert.Equal(uint32(21), ds.FCntUp)
+	assert.Equal(uint32(10), ds.NFCntDown)
+}
+
+func (ts *HandoverRoamingHNSTestSuite) TestXmitDataReqUplinkForwardsDeviceQueueItem() {
+	assert := require.New(ts.T())
+	conf := test.GetConfig()
+
+	// setup device-session
+	ts.CreateDeviceSession(storage.DeviceSession{
+		JoinEUI:               lorawan.EUI64{8, 7, 6, 5, 4, 3, 2, 1},
+		DevAddr:               ts.devAddr,
+		FNwkSIntKey:           [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		SNwkSIntKey:           [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		NwkSEncKey:            [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		FCntUp:                20,
+		NFCntDown:             10,
+		EnabledUplinkChannels: []int{0, 1, 2},
+		RX2Frequency:          869525000,
+	})
+	assert.NoError(storage.SaveHandoverRoamingNetIDForDevEUI(context.Background(), ts.Device.DevEUI, lorawan.NetID{6, 6, 6}, time.Minute))
+
+	// the sNS has sent two mac-command only downlinks, the AS uses the
+	// frame-counter reported by the sNS
+	assert.NoError(storage.CreateDeviceQueueItem(context.Background(), storage.DB(), &storage.DeviceQueueItem{
+		DevAddr:    ts.devAddr,
+		DevEUI:     ts.Device.DevEUI,
+		FRMPayload: []byte{1, 2, 3},
+		FPort:      5,
+		FCnt:       12,
+	}))
+
+	// sNS XmitDataAns
+	xmitDataAns := backend.XmitDataAnsPayload{
+		BasePayloadResult: backend.BasePayloadResult{
+			Result: backend.Result{
+				ResultCode: backend.Success,
+			},
+		},
+	}
+	xmitDataAnsB, err := json.Marshal(xmitDataAns)
+	assert.NoError(err)
+	ts.snsResponse = [][]byte{xmitDataAnsB}
+
+	fPort := uint8(10)
+	phy := lorawan.PHYPayload{
+		MHDR: lorawan.MHDR{
+			MType: lorawan.UnconfirmedDataUp,
+			Major: lorawan.LoRaWANR1,
+		},
+		MACPayload: &lorawan.MACPayload{
+			FHDR: lorawan.FHDR{
+				DevAddr: ts.devAddr,
+				FCnt:    20,
+			},
+			FPort: &fPort,
+		},
+	}
+	assert.NoError(phy.SetUplinkDataMIC(lorawan.LoRaWAN1_0, 0, 0, 0, ts.DeviceSession.FNwkSIntKey, ts.DeviceSession.SNwkSIntKey))
+	phyB, err := phy.MarshalBinary()
+	assert.NoError(err)
+
+	client, err := backend.NewClient(backend.ClientConfig{
+		SenderID:   "060606",
+		ReceiverID: conf.NetworkServer.NetID.String(),
+		Server:     ts.hnsServer.URL,
+	})
+	assert.NoError(err)
+
+	ulFreq := 868.1
+	dataRate := 3
+	gwCnt := 1
+	fCntDown := uint32(12)
+
+	resp, err := client.XmitDataReq(context.Background(), backend.XmitDataReqPayload{
+		PHYPayload: backend.HEXBytes(phyB),
+		ULMetaData: &backend.ULMetaData{
+			DevEUI:   &ts.Device.DevEUI,
+			DevAddr:  &ts.devAddr,
+			FCntDown: &fCntDown,
+			ULFreq:   &ulFreq,
+			DataRate: &dataRate,
+			RecvTime: backend.ISO8601Time(time.Now().Round(time.Second)),
+			RFRegion: "EU868",
+			GWCnt:    &gwCnt,
+			GWInfo: []backend.GWInfoElement{
+				{
+					ID:        backend.HEXBytes{1, 2, 3, 4, 5, 6, 7, 8},
+					DLAllowed: true,
+				},
+			},
+		},
+	})
+	assert.NoError(err)
+	assert.Equal(backend.Success, resp.Result.ResultCode)
+
+	// validate the sNS XmitDataReq
+	var xmitDataReq backend.XmitDataReqPayload
+	assert.NoError(json.Unmarshal(<-ts.snsRequest, &xmitDataReq))
+	assert.Equal(backend.XmitDataReq, xmitDataReq.MessageType)
+	assert.Equal("060606", xmitDataReq.ReceiverID)
+	assert.Equal(backend.HEXBytes{1, 2, 3}, xmitDataReq.FRMPayload)
+	assert.NotNil(xmitDataReq.DLMetaData)
+	assert.Equal(ts.Device.DevEUI, *xmitDataReq.DLMetaData.DevEUI)
+	assert.Equal(uint8(5), *xmitDataReq.DLMetaData.FPort)
+	assert.Equal(uint32(12), *xmitDataReq.DLMetaData.FCntDown)
+	assert.False(xmitDataReq.DLMetaData.Confirmed)
+
+	// the unconfirmed item has been removed from the queue
+	items, err := storage.GetDeviceQueueItemsForDevEUI(context.Background(), storage.DB(), ts.Device.DevEUI)
+	assert.NoError(err)
+	assert.Len(items, 0)
+
+	ds, err := storage.GetDeviceSession(context.Background(), ts.Device.DevEUI)
+	assert.NoError(err)
+	assert.Equal(uint32(13), ds.NFCntDown)
+}
+
+func (ts *HandoverRoamingHNSTestSuite) TestRejoinStopsHandoverRoaming() {
+	assert := require.New(ts.T())
+	conf := test.GetConfig()
+
+	assert.NoError(storage.SaveHandoverRoamingNetIDForDevEUI(context.Background(), ts.Device.DevEUI, lorawan.NetID{6, 6, 6}, time.Minute))
+
+	ts.JSClient.JoinAnsPayload = ts.getJoinAnsPayload(2)
+
+	// sNS HRStopAns
+	hrStopAns := roaming.HRStopAnsPayload{
+		BasePayloadResult: backend.BasePayloadResult{
+			Result: backend.Result{
+				ResultCode: backend.Success,
+			},
+		},
+	}
+	hrStopAnsB, err := json.Marshal(hrStopAns)
+	assert.NoError(err)
+	ts.snsResponse = [][]byte{hrStopAnsB}
+
+	phyB, err := ts.getJoinRequestPHYPayload(2).MarshalBinary()
+	assert.NoError(err)
+
+	txInfo := gw.UplinkTXInfo{
+		Frequency: 868100000,
+	}
+	assert.NoError(helpers.SetUplinkTXInfoDataRate(&txInfo, 0, band.Band()))
+
+	// "send" the join-request through a gateway of the hNS
+	assert.NoError(uplink.HandleUplinkFrame(context.Background(), gw.UplinkFrame{
+		RxInfo: &gw.UplinkRXInfo{
+			GatewayId: ts.Gateway.GatewayID[:],
+			Context:   []byte{1, 2, 3, 4},
+			Location:  &common.Location{},
+		},
+		TxInfo:     &txInfo,
+		PhyPayload: phyB,
+	}))
+
+	// validate the join-accept downlink
+	downlinkFrame := <-ts.GWBackend.TXPacketChan
+	assert.Equal(ts.Gateway.GatewayID[:], downlinkFrame.GetGatewayId())
+
+	// validate sNS HRStopReq
+	var hrStopReq roaming.HRStopReqPayload
+	select {
+	case b := <-ts.snsRequest:
+		assert.NoError(json.Unmarshal(b, &hrStopReq))
+	case <-time.After(time.Second):
+		assert.Fail("expected HRStopReq")
+	}
+	assert.Equal(roaming.HRStopReq, hrStopReq.MessageType)
+	assert.Equal(conf.NetworkServer.NetID.String(), hrStopReq.SenderID)
+	assert.Equal("060606", hrStopReq.ReceiverID)
+	assert.Equal(ts.Device.DevEUI, hrStopReq.DevEUI)
+
+	_, err = storage.GetHandoverRoamingNetIDForDevEUI(context.Background(), ts.Device.DevEUI)
+	assert.Equal(storage.ErrDoesNotExist, err)
+}
+
+// handoverRoamingRequest posts the given handover-roaming request to the
+// given server and decodes the answer. The backend.Client does not implement
+// the handover-roaming requests.
+func handoverRoamingRequest(server string, req, ans interface{}) error {
+	b, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.Post(server, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("expected: 200, got: %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(ans)
+}
+
+func TestHandoverRoamingSNS(t *testing.T) {
+	suite.Run(t, new(HandoverRoamingSNSTestSuite))
+}
+
+func TestHandoverRoamingHNS(t *testing.T) {
+	suite.Run(t, new(HandoverRoamingHNSTestSuite))
+}
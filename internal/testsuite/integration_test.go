@@ -62,6 +62,7 @@ type OTAATest struct {
 	JoinServerJoinAnsPayloadError error
 	ExtraChannels                 []int
 	DeviceActivations             []storage.DeviceActivation
+	LastDevNonce                  *lorawan.DevNonce
 	DeviceQueueItems              []storage.DeviceQueueItem
 
 	ExpectedError error
@@ -459,6 +460,12 @@ func (ts *IntegrationTestSuite) AssertOTAATest(t *testing.T, tst OTAATest) {
 		assert.NoError(storage.CreateDeviceActivation(context.Background(), storage.DB(), &da))
 	}
 
+	// set the last dev-nonce
+	assert.NoError(storage.DeleteLastDevNonce(context.Background(), storage.DB(), ts.Device.DevEUI))
+	if tst.LastDevNonce != nil {
+		assert.NoError(storage.SetLastDevNonce(context.Background(), storage.DB(), ts.Device.DevEUI, *tst.LastDevNonce))
+	}
+
 	// create device-queue items
 	assert.NoError(storage.FlushDeviceQueueForDevEUI(context.Background(), storage.DB(), ts.Device.DevEUI))
 	for _, qi := range tst.DeviceQueueItems {
@@ -174,7 +174,7 @@ func (ts *OTAATestSuite) TestLW10() {
 				AssertASHandleErrorRequest(as.HandleErrorRequest{
 					DevEui: ts.Device.DevEUI[:],
 					Type:   as.ErrorType_OTAA,
-					Error:  "validate dev-nonce error",
+					Error:  "validate dev-nonce error: dev-nonce 258 has already been used",
 				}),
 			},
 		},
@@ -301,6 +301,8 @@ func (ts *OTAATestSuite) TestLW10() {
 					ExtraUplinkChannels:   map[int]loraband.Channel{},
 					RX2Frequency:          band.Band().GetDefaults().RX2Frequency,
 					NbTrans:               1,
+					ADRAckLimitExp:        storage.DefaultADRAckLimitExp,
+					ADRAckDelayExp:        storage.DefaultADRAckDelayExp,
 					ReferenceAltitude:     5.6,
 					MACCommandErrorCount:  make(map[lorawan.CID]int),
 				}),
@@ -455,6 +457,8 @@ func (ts *OTAATestSuite) TestLW10() {
 					RX2Frequency:          band.Band().GetDefaults().RX2Frequency,
 					SkipFCntValidation:    true,
 					NbTrans:               1,
+					ADRAckLimitExp:        storage.DefaultADRAckLimitExp,
+					ADRAckDelayExp:        storage.DefaultADRAckDelayExp,
 					ReferenceAltitude:     5.6,
 					MACCommandErrorCount:  make(map[lorawan.CID]int),
 				}),
@@ -657,7 +661,40 @@ func (ts *OTAATestSuite) TestLW11() {
 	assert.NoError(err)
 	assert.NoError(jaPHY.DecryptJoinAcceptPayload(ts.JoinAcceptKey))
 
+	lastDevNonce := lorawan.DevNonce(258)
+	higherDevNonce := lorawan.DevNonce(300)
+
 	tests := []OTAATest{
+		{
+			Name:          "dev-nonce equals last dev-nonce",
+			RXInfo:        rxInfo,
+			TXInfo:        txInfo,
+			PHYPayload:    jrPayload,
+			LastDevNonce:  &lastDevNonce,
+			ExpectedError: errors.New("validate dev-nonce error: dev-nonce did not increment"),
+			Assert: []Assertion{
+				AssertASHandleErrorRequest(as.HandleErrorRequest{
+					DevEui: ts.Device.DevEUI[:],
+					Type:   as.ErrorType_OTAA,
+					Error:  "validate dev-nonce error: dev-nonce 258 must be greater than the last used dev-nonce 258",
+				}),
+			},
+		},
+		{
+			Name:          "dev-nonce lower than last dev-nonce",
+			RXInfo:        rxInfo,
+			TXInfo:        txInfo,
+			PHYPayload:    jrPayload,
+			LastDevNonce:  &higherDevNonce,
+			ExpectedError: errors.New("validate dev-nonce error: dev-nonce did not increment"),
+			Assert: []Assertion{
+				AssertASHandleErrorRequest(as.HandleErrorRequest{
+					DevEui: ts.Device.DevEUI[:],
+					Type:   as.ErrorType_OTAA,
+					Error:  "validate dev-nonce error: dev-nonce 258 must be greater than the last used dev-nonce 300",
+				}),
+			},
+		},
 		{
 			Name:       "join-request accepted (rx1 + rx2)",
 			RXInfo:     rxInfo,
@@ -788,11 +825,14 @@ func (ts *OTAATestSuite) TestLW11() {
 					ExtraUplinkChannels:   map[int]loraband.Channel{},
 					RX2Frequency:          band.Band().GetDefaults().RX2Frequency,
 					NbTrans:               1,
+					ADRAckLimitExp:        storage.DefaultADRAckLimitExp,
+					ADRAckDelayExp:        storage.DefaultADRAckDelayExp,
 					ReferenceAltitude:     5.6,
 					MACCommandErrorCount:  make(map[lorawan.CID]int),
 				}),
 				AssertDeviceQueueItems([]storage.DeviceQueueItem{}),
 				AssertDeviceMode(storage.DeviceModeA),
+				AssertLastDevNonce(258),
 			},
 		},
 		{
@@ -940,6 +980,8 @@ func (ts *OTAATestSuite) TestLW11() {
 					ExtraUplinkChannels:   map[int]loraband.Channel{},
 					RX2Frequency:          band.Band().GetDefaults().RX2Frequency,
 					NbTrans:               1,
+					ADRAckLimitExp:        storage.DefaultADRAckLimitExp,
+					ADRAckDelayExp:        storage.DefaultADRAckDelayExp,
 					ReferenceAltitude:     5.6,
 					MACCommandErrorCount:  make(map[lorawan.CID]int),
 				}),
@@ -666,6 +666,78 @@ func (ts *PassiveRoamingFNSTestSuite) TestDownlink() {
 	}, frame)
 }
 
+func (ts *PassiveRoamingFNSTestSuite) TestPRStopReq() {
+	assert := require.New(ts.T())
+	config := test.GetConfig()
+	api := roamingapi.NewAPI(config.NetworkServer.NetID)
+
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	client, err := backend.NewClient(backend.ClientConfig{
+		SenderID:   "060606",
+		ReceiverID: config.NetworkServer.NetID.String(),
+		Server:     server.URL,
+	})
+	assert.NoError(err)
+
+	devEUI := lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8}
+
+	// session started by the hNS sending the PRStopReq
+	ds1 := storage.PassiveRoamingDeviceSession{
+		NetID:    lorawan.NetID{6, 6, 6},
+		DevAddr:  lorawan.DevAddr{1, 2, 3, 4},
+		DevEUI:   devEUI,
+		Lifetime: time.Now().Add(time.Minute),
+	}
+	assert.NoError(storage.SavePassiveRoamingDeviceSession(context.Background(), &ds1))
+
+	// session started by an other hNS
+	ds2 := storage.PassiveRoamingDeviceSession{
+		NetID:    lorawan.NetID{7, 7, 7},
+		DevAddr:  lorawan.DevAddr{1, 2, 3, 4},
+		DevEUI:   devEUI,
+		Lifetime: time.Now().Add(time.Minute),
+	}
+	assert.NoError(storage.SavePassiveRoamingDeviceSession(context.Background(), &ds2))
+
+	// session started by the hNS sending the PRStopReq, without DevEUI
+	ds3 := storage.PassiveRoamingDeviceSession{
+		NetID:    lorawan.NetID{6, 6, 6},
+		DevAddr:  lorawan.DevAddr{1, 2, 3, 4},
+		Lifetime: time.Now().Add(time.Minute),
+	}
+	assert.NoError(storage.SavePassiveRoamingDeviceSession(context.Background(), &ds3))
+
+	// session started by the hNS sending the PRStopReq, for an other device
+	ds4 := storage.PassiveRoamingDeviceSession{
+		NetID:    lorawan.NetID{6, 6, 6},
+		DevAddr:  lorawan.DevAddr{1, 2, 3, 4},
+		DevEUI:   lorawan.EUI64{8, 7, 6, 5, 4, 3, 2, 1},
+		Lifetime: time.Now().Add(time.Minute),
+	}
+	assert.NoError(storage.SavePassiveRoamingDeviceSession(context.Background(), &ds4))
+
+	resp, err := client.PRStopReq(context.Background(), backend.PRStopReqPayload{
+		DevEUI: devEUI,
+	})
+	assert.NoError(err)
+	assert.Equal(backend.Success, resp.Result.ResultCode)
+
+	_, err = storage.GetPassiveRoamingDeviceSession(context.Background(), ds1.SessionID)
+	assert.Equal(storage.ErrDoesNotExist, err)
+
+	_, err = storage.GetPassiveRoamingDeviceSession(context.Background(), ds3.SessionID)
+	assert.Equal(storage.ErrDoesNotExist, err)
+
+	_, err = storage.GetPassiveRoamingDeviceSession(context.Background(), ds4.SessionID)
+	assert.NoError(err)
+
+	ids, err := storage.GetPassiveRoamingIDsForDevEUI(context.Background(), devEUI)
+	assert.NoError(err)
+	assert.Equal([]uuid.UUID{ds2.SessionID}, ids)
+}
+
 // PassiveRoamingSNSTestSuite contains the tests from the hNS POV.
 // This tests uplinks received from a fNS (through the roaming API) and
 // forwarding these uplinks to the application-server. It also tests sending
@@ -939,6 +1011,65 @@ func (ts *PassiveRoamingSNSTestSuite) TestPRStartReqStatefull() {
 	// nothing sent as in case of statefull an XmitDataReq is expected
 	// after the PRStartReq.
 	assert.Equal(0, len(ts.ASClient.HandleDataUpChan))
+
+	// the fNS NetID is stored, so that passive-roaming can be stopped on re-join
+	netIDs, err := storage.GetPassiveRoamingNetIDsForDevEUI(context.Background(), ts.DeviceSession.DevEUI)
+	assert.NoError(err)
+	assert.Equal([]lorawan.NetID{{6, 6, 6}}, netIDs)
+}
+
+func (ts *PassiveRoamingSNSTestSuite) TestProfileReq() {
+	assert := require.New(ts.T())
+	conf := test.GetConfig()
+
+	// the suite device-profile and service-profile are not used, as
+	// these are shared with the other tests
+	sp := storage.ServiceProfile{
+		PRAllowed: true,
+	}
+	assert.NoError(storage.CreateServiceProfile(context.Background(), storage.DB(), &sp))
+
+	dp := storage.DeviceProfile{
+		MACVersion:         "1.0.3",
+		RegParamsRevision:  "A",
+		RXFreq2:            869525000,
+		FactoryPresetFreqs: []int{868100000, 868300000, 868500000},
+		SupportsJoin:       true,
+		RFRegion:           "EU868",
+	}
+	assert.NoError(storage.CreateDeviceProfile(context.Background(), storage.DB(), &dp))
+
+	d := storage.Device{
+		DevEUI:           lorawan.EUI64{2, 2, 3, 4, 5, 6, 7, 8},
+		DeviceProfileID:  dp.ID,
+		ServiceProfileID: sp.ID,
+		RoutingProfileID: ts.RoutingProfile.ID,
+	}
+	assert.NoError(storage.CreateDevice(context.Background(), storage.DB(), &d))
+
+	client, err := backend.NewClient(backend.ClientConfig{
+		SenderID:   "060606",
+		ReceiverID: conf.NetworkServer.NetID.String(),
+		Server:     ts.hnsServer.URL,
+	})
+	assert.NoError(err)
+
+	resp, err := client.ProfileReq(context.Background(), backend.ProfileReqPayload{
+		DevEUI: d.DevEUI,
+	})
+	assert.NoError(err)
+	assert.Equal(backend.Success, resp.Result.ResultCode)
+	assert.Equal(backend.Passive, *resp.RoamingActivationType)
+	assert.NotNil(resp.DeviceProfileTimestamp)
+	assert.Equal(&backend.DeviceProfile{
+		DeviceProfileID:    dp.ID.String(),
+		MACVersion:         "1.0.3",
+		RegParamsRevision:  "A",
+		RXFreq2:            869525000,
+		FactoryPresetFreqs: []backend.Frequency{868100000, 868300000, 868500000},
+		SupportsJoin:       true,
+		RFRegion:           "EU868",
+	}, resp.DeviceProfile)
 }
 
 func (ts *PassiveRoamingSNSTestSuite) TestXmitDataReqUplinkNoDownlink() {
@@ -245,6 +245,8 @@ func (ts *RejoinTestSuite) TestRejoinType0() {
 						},
 						RX2Frequency:          869525000,
 						NbTrans:               1,
+						ADRAckLimitExp:        storage.DefaultADRAckLimitExp,
+						ADRAckDelayExp:        storage.DefaultADRAckDelayExp,
 						EnabledUplinkChannels: []int{0, 1, 2, 3, 4, 5},
 						RXDelay:               1,
 						RX1DROffset:           2,
@@ -366,6 +368,8 @@ func (ts *RejoinTestSuite) TestRejoinType0() {
 						},
 						RX2Frequency:          869525000,
 						NbTrans:               1,
+						ADRAckLimitExp:        storage.DefaultADRAckLimitExp,
+						ADRAckDelayExp:        storage.DefaultADRAckDelayExp,
 						EnabledUplinkChannels: []int{0, 1, 2, 3, 4, 5},
 						RXDelay:               1,
 						RX1DROffset:           2,
@@ -7,17 +7,22 @@ import (
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/metadata"
 
 	"github.com/brocaar/chirpstack-api/go/v3/as"
 	"github.com/brocaar/chirpstack-api/go/v3/common"
+	"github.com/brocaar/chirpstack-api/go/v3/gw"
 	"github.com/brocaar/chirpstack-api/go/v3/nc"
+	"github.com/brocaar/chirpstack-network-server/internal/airtime"
 	"github.com/brocaar/chirpstack-network-server/internal/backend/applicationserver"
 	"github.com/brocaar/chirpstack-network-server/internal/backend/controller"
+	"github.com/brocaar/chirpstack-network-server/internal/backend/integration"
 	"github.com/brocaar/chirpstack-network-server/internal/band"
 	"github.com/brocaar/chirpstack-network-server/internal/config"
 	datadown "github.com/brocaar/chirpstack-network-server/internal/downlink/data"
 	"github.com/brocaar/chirpstack-network-server/internal/downlink/data/classb"
 	"github.com/brocaar/chirpstack-network-server/internal/framelog"
+	"github.com/brocaar/chirpstack-network-server/internal/geolocation"
 	"github.com/brocaar/chirpstack-network-server/internal/helpers"
 	"github.com/brocaar/chirpstack-network-server/internal/logging"
 	"github.com/brocaar/chirpstack-network-server/internal/maccommand"
@@ -37,11 +42,17 @@ var tasks = []func(*dataContext) error{
 	handlePassiveRoamingDevice,
 	getDeviceSessionForPHYPayload,
 	abortOnDeviceIsDisabled,
+	handleHandoverRoamingDevice,
+	handleHandedOverDevice,
 	decryptFOptsMACCommands,
 	decryptFRMPayloadMACCommands,
-	logUplinkFrame,
 	getDeviceProfile,
 	getServiceProfile,
+	checkDutyCycle,
+	checkUplinkRateLimit,
+	logUplinkFrame,
+	checkMinGWDiversity,
+	abortOnUplinkRateLimitDrop,
 	getApplicationServerClientForDataUp,
 	setADR,
 	setUplinkDataRate,
@@ -52,6 +63,7 @@ var tasks = []func(*dataContext) error{
 	storeDeviceGatewayRXInfoSet,
 	appendMetaDataToUplinkHistory,
 	sendFRMPayloadToApplicationServer,
+	resolveDeviceLocation,
 	syncUplinkFCnt,
 	saveDeviceSession,
 	handleUplinkACK,
@@ -61,12 +73,14 @@ var tasks = []func(*dataContext) error{
 var (
 	getDownlinkDataDelay time.Duration
 	disableMACCommands   bool
+	dutyCycleWindow      time.Duration
 )
 
 // Setup configures the package.
 func Setup(conf config.Config) error {
 	getDownlinkDataDelay = conf.NetworkServer.GetDownlinkDataDelay
 	disableMACCommands = conf.NetworkServer.NetworkSettings.DisableMACCommands
+	dutyCycleWindow = conf.NetworkServer.NetworkSettings.DutyCycle.Window
 
 	return nil
 }
@@ -74,14 +88,18 @@ func Setup(conf config.Config) error {
 type dataContext struct {
 	ctx context.Context
 
-	RXPacket                models.RXPacket
-	MACPayload              *lorawan.MACPayload
-	DeviceSession           storage.DeviceSession
-	DeviceProfile           storage.DeviceProfile
-	ServiceProfile          storage.ServiceProfile
-	ApplicationServerClient as.ApplicationServerServiceClient
-	MACCommandResponses     []storage.MACCommandBlock
-	MustSendDownlink        bool
+	RXPacket                     models.RXPacket
+	PHYPayloadBytes              []byte
+	MACPayload                   *lorawan.MACPayload
+	DeviceSession                storage.DeviceSession
+	DeviceProfile                storage.DeviceProfile
+	ServiceProfile               storage.ServiceProfile
+	HandoverRoamingDeviceSession *storage.HandoverRoamingDeviceSession
+	HandedOverNetID              *lorawan.NetID
+	ApplicationServerClient      as.ApplicationServerServiceClient
+	MACCommandResponses          []storage.MACCommandBlock
+	MustSendDownlink             bool
+	UplinkRateLimited            bool
 }
 
 // Handle handles an uplink data frame
@@ -110,10 +128,25 @@ func setContextFromDataPHYPayload(ctx *dataContext) error {
 		return fmt.Errorf("expected *lorawan.MACPayload, got: %T", ctx.RXPacket.PHYPayload.MACPayload)
 	}
 	ctx.MACPayload = macPL
+
+	// Store the PHYPayload as received, as the mac-commands are decrypted
+	// in-place.
+	b, err := ctx.RXPacket.PHYPayload.MarshalBinary()
+	if err != nil {
+		return errors.Wrap(err, "marshal phypayload error")
+	}
+	ctx.PHYPayloadBytes = b
+
 	return nil
 }
 
 func handlePassiveRoamingDevice(ctx *dataContext) error {
+	// Uplinks received from a roaming partner are handled as the hNS. In case
+	// of handover-roaming, the DevAddr has been assigned by the sNS.
+	if ctx.RXPacket.RoamingMetaData != nil {
+		return nil
+	}
+
 	if roaming.IsRoamingDevAddr(ctx.MACPayload.FHDR.DevAddr) {
 		log.WithFields(log.Fields{
 			"dev_addr": ctx.MACPayload.FHDR.DevAddr,
@@ -198,7 +231,7 @@ func logUplinkFrame(ctx *dataContext) error {
 		return errors.Wrap(err, "create uplink frame-log error")
 	}
 
-	if err := framelog.LogUplinkFrameForDevEUI(ctx.ctx, ctx.DeviceSession.DevEUI, uplinkFrameLog); err != nil {
+	if err := framelog.LogUplinkFrameForDevEUI(ctx.ctx, ctx.DeviceSession.DevEUI, uplinkFrameLog, ctx.UplinkRateLimited); err != nil {
 		log.WithError(err).Error("log uplink frame for device error")
 	}
 
@@ -225,6 +258,142 @@ func getServiceProfile(ctx *dataContext) error {
 	return nil
 }
 
+// checkMinGWDiversity rejects the uplink when it has been received by fewer
+// gateways than the min. gateway diversity of the service-profile.
+// The PHYPayload is forwarded to the network-controller as received.
+func checkMinGWDiversity(ctx *dataContext) error {
+	if ctx.ServiceProfile.MinGWDiversity <= 1 {
+		return nil
+	}
+
+	gateways := make(map[lorawan.EUI64]struct{})
+	for _, rxInfo := range ctx.RXPacket.RXInfoSet {
+		gateways[helpers.GetGatewayID(rxInfo)] = struct{}{}
+	}
+
+	if len(gateways) >= ctx.ServiceProfile.MinGWDiversity {
+		return nil
+	}
+
+	log.WithFields(log.Fields{
+		"dev_eui":          ctx.DeviceSession.DevEUI,
+		"gateway_count":    len(gateways),
+		"min_gw_diversity": ctx.ServiceProfile.MinGWDiversity,
+		"ctx_id":           ctx.ctx.Value(logging.ContextIDKey),
+	}).Warning("uplink/data: min. gateway diversity not met, rejecting uplink")
+
+	if _, err := controller.Client().HandleRejectedUplinkFrameSet(ctx.ctx, &nc.HandleRejectedUplinkFrameSetRequest{
+		FrameSet: &gw.UplinkFrameSet{
+			PhyPayload: ctx.PHYPayloadBytes,
+			TxInfo:     ctx.RXPacket.TXInfo,
+			RxInfo:     ctx.RXPacket.RXInfoSet,
+		},
+	}); err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"dev_eui": ctx.DeviceSession.DevEUI,
+			"ctx_id":  ctx.ctx.Value(logging.ContextIDKey),
+		}).Error("uplink/data: call controller HandleRejectedUplinkFrameSet RPC error")
+	}
+
+	return ErrAbort
+}
+
+// checkUplinkRateLimit flags the uplink when the device exceeds the uplink
+// rate of the service-profile. The flag is stored in the uplink frame-log
+// and published as ratelimit integration event. With the Mark policy, it is
+// also forwarded to the application-server.
+func checkUplinkRateLimit(ctx *dataContext) error {
+	ok, err := storage.TakeDeviceUplinkRateToken(ctx.ctx, ctx.DeviceSession.DevEUI, ctx.ServiceProfile)
+	if err != nil {
+		return errors.Wrap(err, "take uplink rate token error")
+	}
+	if ok {
+		return nil
+	}
+
+	ctx.UplinkRateLimited = true
+
+	log.WithFields(log.Fields{
+		"dev_eui":        ctx.DeviceSession.DevEUI,
+		"ul_rate":        ctx.ServiceProfile.ULRate,
+		"ul_bucket_size": ctx.ServiceProfile.ULBucketSize,
+		"ul_rate_policy": ctx.ServiceProfile.ULRatePolicy,
+		"ctx_id":         ctx.ctx.Value(logging.ContextIDKey),
+	}).Warning("uplink/data: uplink rate exceeded")
+
+	integration.PublishEvent(ctx.ctx, integration.Event{
+		Type:    integration.EventRateLimit,
+		DevEUI:  ctx.DeviceSession.DevEUI,
+		Message: integration.UplinkRateLimitMessage(ctx.DeviceSession.DevEUI, ctx.MACPayload.FHDR.FCnt, string(ctx.ServiceProfile.ULRatePolicy)),
+	})
+
+	return nil
+}
+
+// abortOnUplinkRateLimitDrop discards the flagged uplink when the
+// service-profile has the Drop policy. This is done after logging the
+// uplink frame, so that dropped uplinks are visible in the frame-log.
+func abortOnUplinkRateLimitDrop(ctx *dataContext) error {
+	if ctx.UplinkRateLimited && ctx.ServiceProfile.ULRatePolicy == storage.Drop {
+		log.WithFields(log.Fields{
+			"dev_eui": ctx.DeviceSession.DevEUI,
+			"ctx_id":  ctx.ctx.Value(logging.ContextIDKey),
+		}).Info("uplink/data: dropping rate-limited uplink")
+		return ErrAbort
+	}
+
+	return nil
+}
+
+// checkDutyCycle adds the time-on-air of the uplink to the rolling airtime
+// window of the device. When the device exceeds the max. duty-cycle of the
+// device-profile, a DutyCycleReq mac-command is sent with the downlink
+// response. This is done before any step that could abort the flow, as the
+// device used the airtime regardless of how the uplink is handled.
+func checkDutyCycle(ctx *dataContext) error {
+	if ctx.DeviceProfile.MaxDutyCycle <= 0 || dutyCycleWindow == 0 {
+		return nil
+	}
+
+	dr, err := band.Band().GetDataRate(ctx.RXPacket.DR)
+	if err != nil {
+		return errors.Wrap(err, "get data-rate error")
+	}
+
+	toa, err := airtime.CalculateUplinkAirtime(dr, len(ctx.PHYPayloadBytes))
+	if err != nil {
+		return errors.Wrap(err, "calculate airtime error")
+	}
+
+	total, err := storage.AddDeviceUplinkAirtime(ctx.ctx, ctx.DeviceSession.DevEUI, time.Now(), toa, dutyCycleWindow)
+	if err != nil {
+		return errors.Wrap(err, "add device uplink airtime error")
+	}
+
+	// MaxDutyCycle is expressed in percent
+	budget := dutyCycleWindow * time.Duration(ctx.DeviceProfile.MaxDutyCycle) / 100
+	if total <= budget {
+		return nil
+	}
+
+	log.WithFields(log.Fields{
+		"dev_eui":        ctx.DeviceSession.DevEUI,
+		"airtime":        toa,
+		"window_airtime": total,
+		"window":         dutyCycleWindow,
+		"budget":         budget,
+		"max_duty_cycle": ctx.DeviceProfile.MaxDutyCycle,
+		"max_d_cycle":    ctx.DeviceSession.MaxDCycle,
+		"ctx_id":         ctx.ctx.Value(logging.ContextIDKey),
+	}).Warning("uplink/data: device exceeds max. duty-cycle, requesting duty-cycle")
+
+	// The device does not apply the MaxDCycle, or has not received it.
+	// This makes the downlink response (re-)send the DutyCycleReq.
+	ctx.DeviceSession.DutyCycleExceeded = true
+
+	return nil
+}
+
 func setADR(ctx *dataContext) error {
 	ctx.DeviceSession.ADR = ctx.MACPayload.FHDR.FCtrl.ADR
 	return nil
@@ -264,6 +433,7 @@ func appendMetaDataToUplinkHistory(ctx *dataContext) error {
 		MaxSNR:       maxSNR,
 		TXPowerIndex: ctx.DeviceSession.TXPowerIndex,
 	})
+	ctx.DeviceSession.PacketErrorRate = ctx.DeviceSession.GetPacketLossPercentage()
 
 	return nil
 }
@@ -423,6 +593,12 @@ func sendUplinkMetaDataToNetworkController(ctx *dataContext) error {
 		}
 	}
 
+	integration.PublishEvent(ctx.ctx, integration.Event{
+		Type:    integration.EventUp,
+		DevEUI:  ctx.DeviceSession.DevEUI,
+		Message: &req,
+	})
+
 	// send async to controller
 	go func() {
 		_, err := controller.Client().HandleUplinkMetaData(ctx.ctx, &req)
@@ -542,6 +718,14 @@ func sendFRMPayloadToApplicationServer(ctx *dataContext) error {
 		publishDataUpReq.Data = dataPL.Bytes
 	}
 
+	// The HandleUplinkDataRequest message does not provide a field for the
+	// rate-limit flag, therefore it is sent as gRPC metadata (see
+	// applicationserver.UplinkRateLimitedMetadataKey).
+	asCtx := ctx.ctx
+	if ctx.UplinkRateLimited {
+		asCtx = metadata.AppendToOutgoingContext(asCtx, applicationserver.UplinkRateLimitedMetadataKey, "true")
+	}
+
 	go func(ctx context.Context, asClient as.ApplicationServerServiceClient, publishDataUpReq as.HandleUplinkDataRequest) {
 		ctxTimeout, cancel := context.WithTimeout(ctx, applicationClientTimeout)
 		defer cancel()
@@ -551,7 +735,57 @@ func sendFRMPayloadToApplicationServer(ctx *dataContext) error {
 				"ctx_id": ctx.Value(logging.ContextIDKey),
 			}).WithError(err).Error("publish uplink data to application-server error")
 		}
-	}(ctx.ctx, ctx.ApplicationServerClient, publishDataUpReq)
+	}(asCtx, ctx.ApplicationServerClient, publishDataUpReq)
+
+	return nil
+}
+
+// resolveDeviceLocation resolves the location of the device using the
+// meta-data of the receiving gateways and forwards it to the
+// application-server. This is only done when the service-profile has
+// network geolocation enabled.
+func resolveDeviceLocation(ctx *dataContext) error {
+	if !ctx.ServiceProfile.NwkGeoLoc {
+		return nil
+	}
+
+	loc, err := geolocation.Resolve(ctx.RXPacket.RXInfoSet, ctx.DeviceSession.ReferenceAltitude)
+	if err != nil {
+		if err == geolocation.ErrInsufficientData {
+			log.WithFields(log.Fields{
+				"dev_eui": ctx.DeviceSession.DevEUI,
+				"ctx_id":  ctx.ctx.Value(logging.ContextIDKey),
+			}).Debug("uplink/data: insufficient data to resolve device location")
+			return nil
+		}
+
+		log.WithError(err).WithFields(log.Fields{
+			"dev_eui": ctx.DeviceSession.DevEUI,
+			"ctx_id":  ctx.ctx.Value(logging.ContextIDKey),
+		}).Error("uplink/data: resolve device location error")
+		return nil
+	}
+
+	req := as.SetDeviceLocationRequest{
+		DevEui:   ctx.DeviceSession.DevEUI[:],
+		Location: &loc,
+	}
+
+	for _, rxInfo := range ctx.RXPacket.RXInfoSet {
+		uplinkID := helpers.GetUplinkID(rxInfo)
+		req.UplinkIds = append(req.UplinkIds, uplinkID[:])
+	}
+
+	go func(ctx context.Context, asClient as.ApplicationServerServiceClient, req as.SetDeviceLocationRequest) {
+		ctxTimeout, cancel := context.WithTimeout(ctx, applicationClientTimeout)
+		defer cancel()
+
+		if _, err := asClient.SetDeviceLocation(ctxTimeout, &req); err != nil {
+			log.WithFields(log.Fields{
+				"ctx_id": ctx.Value(logging.ContextIDKey),
+			}).WithError(err).Error("send device location to application-server error")
+		}
+	}(ctx.ctx, ctx.ApplicationServerClient, req)
 
 	return nil
 }
@@ -708,20 +942,31 @@ func handleUplinkMACCommands(ctx context.Context, ds *storage.DeviceSession, dp
 			}
 		}
 
+		var data [][]byte
+		for _, cmd := range block.MACCommands {
+			b, err := cmd.MarshalBinary()
+			if err != nil {
+				log.WithFields(logFields).Errorf("marshal mac-command to binary error: %s", err)
+				continue
+			}
+			data = append(data, b)
+		}
+
+		integration.PublishEvent(ctx, integration.Event{
+			Type:   integration.EventMACCommand,
+			DevEUI: ds.DevEUI,
+			Message: &nc.HandleUplinkMACCommandRequest{
+				DevEui:   ds.DevEUI[:],
+				Cid:      uint32(block.CID),
+				Commands: data,
+			},
+		})
+
 		// Report to external controller:
 		//  * in case of proprietary mac-commands
 		//  * in case when the request has been scheduled through the API
 		//  * in case mac-commands are disabled in the ChirpStack Network Server configuration
 		if disableMACCommands || block.CID >= 0x80 || external {
-			var data [][]byte
-			for _, cmd := range block.MACCommands {
-				b, err := cmd.MarshalBinary()
-				if err != nil {
-					log.WithFields(logFields).Errorf("marshal mac-command to binary error: %s", err)
-					continue
-				}
-				data = append(data, b)
-			}
 			_, err := controller.Client().HandleUplinkMACCommand(context.Background(), &nc.HandleUplinkMACCommandRequest{
 				DevEui:   ds.DevEUI[:],
 				Cid:      uint32(block.CID),
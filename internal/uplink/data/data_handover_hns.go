@@ -0,0 +1,239 @@
+package data
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brocaar/chirpstack-api/go/v3/as"
+	"github.com/brocaar/chirpstack-network-server/internal/band"
+	"github.com/brocaar/chirpstack-network-server/internal/logging"
+	"github.com/brocaar/chirpstack-network-server/internal/roaming"
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/lorawan"
+	"github.com/brocaar/lorawan/backend"
+)
+
+// handedOverDeviceTasks contains the tasks for an uplink forwarded by the sNS
+// of a device which has been handed over. As the sNS controls the MAC-layer
+// of the device, the hNS only forwards the application payload to the AS and
+// forwards its device-queue items to the sNS.
+var handedOverDeviceTasks = []func(*dataContext) error{
+	getDeviceProfile,
+	getServiceProfile,
+	logUplinkFrame,
+	getApplicationServerClientForDataUp,
+	sendFRMPayloadToApplicationServer,
+	syncUplinkFCnt,
+	syncHandedOverFCntDown,
+	handleHandedOverUplinkACK,
+	forwardDeviceQueueItemToSNS,
+	saveDeviceSession,
+}
+
+// handleHandedOverDevice handles the uplink as the hNS in case it has been
+// forwarded by the sNS to which the device has been handed over.
+func handleHandedOverDevice(ctx *dataContext) error {
+	if ctx.RXPacket.RoamingMetaData == nil {
+		return nil
+	}
+
+	sNSNetID, err := storage.GetHandoverRoamingNetIDForDevEUI(ctx.ctx, ctx.DeviceSession.DevEUI)
+	if err != nil {
+		if errors.Cause(err) == storage.ErrDoesNotExist {
+			return nil
+		}
+		return errors.Wrap(err, "get handover-roaming netid error")
+	}
+
+	var senderNetID lorawan.NetID
+	if err := senderNetID.UnmarshalText([]byte(ctx.RXPacket.RoamingMetaData.BasePayload.SenderID)); err != nil {
+		return errors.Wrap(err, "decode netid error")
+	}
+
+	if senderNetID != sNSNetID {
+		return nil
+	}
+	ctx.HandedOverNetID = &sNSNetID
+
+	log.WithFields(log.Fields{
+		"dev_eui": ctx.DeviceSession.DevEUI,
+		"net_id":  sNSNetID,
+		"ctx_id":  ctx.ctx.Value(logging.ContextIDKey),
+	}).Info("uplink/data: handling uplink of handed-over device as hNS")
+
+	for _, t := range handedOverDeviceTasks {
+		if err := t(ctx); err != nil {
+			if err == ErrAbort {
+				break
+			}
+			return err
+		}
+	}
+
+	// the flow stops here
+	return ErrAbort
+}
+
+// syncHandedOverFCntDown syncs the network downlink frame-counter with the
+// value reported by the sNS. In case of LoRaWAN 1.0 this counter is used by
+// the AS to encrypt the device-queue items.
+func syncHandedOverFCntDown(ctx *dataContext) error {
+	fCntDown := ctx.RXPacket.RoamingMetaData.ULMetaData.FCntDown
+	if fCntDown == nil {
+		return nil
+	}
+
+	// The counter is never decremented, as the hNS might have forwarded
+	// device-queue items which have not yet been sent by the sNS.
+	if *fCntDown > ctx.DeviceSession.NFCntDown {
+		ctx.DeviceSession.NFCntDown = *fCntDown
+	}
+
+	return nil
+}
+
+// handleHandedOverUplinkACK handles the ACK of a confirmed device-queue item
+// which has been forwarded to the sNS. The MIC of the ACK has been validated
+// using the ConfFCnt, which is set when forwarding the item.
+func handleHandedOverUplinkACK(ctx *dataContext) error {
+	if !ctx.MACPayload.FHDR.FCtrl.ACK {
+		return nil
+	}
+
+	qi, err := storage.GetPendingDeviceQueueItemForDevEUI(ctx.ctx, storage.DB(), ctx.DeviceSession.DevEUI)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"dev_eui": ctx.DeviceSession.DevEUI,
+			"ctx_id":  ctx.ctx.Value(logging.ContextIDKey),
+		}).WithError(err).Error("uplink/data: get device-queue item error")
+		return nil
+	}
+	if qi.FCnt != ctx.DeviceSession.ConfFCnt {
+		log.WithFields(log.Fields{
+			"dev_eui":                ctx.DeviceSession.DevEUI,
+			"device_queue_item_fcnt": qi.FCnt,
+			"conf_fcnt":              ctx.DeviceSession.ConfFCnt,
+			"ctx_id":                 ctx.ctx.Value(logging.ContextIDKey),
+		}).Error("uplink/data: frame-counter of device-queue item out of sync with device-session")
+		return nil
+	}
+
+	if err := storage.DeleteDeviceQueueItem(ctx.ctx, storage.DB(), qi.ID); err != nil {
+		return errors.Wrap(err, "delete device-queue item error")
+	}
+
+	_, err = ctx.ApplicationServerClient.HandleDownlinkACK(ctx.ctx, &as.HandleDownlinkACKRequest{
+		DevEui:       ctx.DeviceSession.DevEUI[:],
+		FCnt:         qi.FCnt,
+		Acknowledged: true,
+	})
+	if err != nil {
+		return errors.Wrap(err, "application-server client error")
+	}
+
+	return nil
+}
+
+// forwardDeviceQueueItemToSNS forwards the next device-queue item to the sNS
+// using a XmitDataReq. The sNS sends it on the next downlink opportunity of
+// the device. Confirmed items are kept pending until the ACK has been
+// forwarded by the sNS, or until the handover-roaming lifetime expires.
+func forwardDeviceQueueItemToSNS(ctx *dataContext) error {
+	var fCnt uint32
+	if ctx.DeviceSession.GetMACVersion() == lorawan.LoRaWAN1_0 {
+		fCnt = ctx.DeviceSession.NFCntDown
+	} else {
+		fCnt = ctx.DeviceSession.AFCntDown
+	}
+
+	// The sNS selects the downlink window, the RX2 data-rate is used as it
+	// has been set by the sNS on activation.
+	plSize, err := band.Band().GetMaxPayloadSizeForDataRateIndex(ctx.DeviceProfile.MACVersion, ctx.DeviceProfile.RegParamsRevision, int(ctx.DeviceSession.RX2DR))
+	if err != nil {
+		return errors.Wrap(err, "get max-payload size error")
+	}
+
+	qi, err := storage.GetNextDeviceQueueItemForDevEUIMaxPayloadSizeAndFCnt(ctx.ctx, storage.DB(), ctx.DeviceSession.DevEUI, plSize.N, fCnt, ctx.DeviceSession.RoutingProfileID)
+	if err != nil {
+		if errors.Cause(err) == storage.ErrDoesNotExist {
+			return nil
+		}
+		return errors.Wrap(err, "get next device-queue item error")
+	}
+
+	netID := *ctx.HandedOverNetID
+	client, err := roaming.GetClientForNetID(netID)
+	if err != nil {
+		return errors.Wrap(err, "get backend client error")
+	}
+
+	req := backend.XmitDataReqPayload{
+		FRMPayload: backend.HEXBytes(qi.FRMPayload),
+		DLMetaData: &backend.DLMetaData{
+			DevEUI:    &ctx.DeviceSession.DevEUI,
+			FPort:     &qi.FPort,
+			FCntDown:  &qi.FCnt,
+			Confirmed: qi.Confirmed,
+		},
+	}
+
+	// In case of an error, the item stays in the queue so that it is
+	// forwarded on the next uplink.
+	if err := xmitDataDownlink(ctx, client, req); err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"dev_eui": ctx.DeviceSession.DevEUI,
+			"net_id":  netID,
+			"f_cnt":   qi.FCnt,
+			"ctx_id":  ctx.ctx.Value(logging.ContextIDKey),
+		}).Error("uplink/data: forward device-queue item to sNS error")
+		return nil
+	}
+
+	log.WithFields(log.Fields{
+		"dev_eui": ctx.DeviceSession.DevEUI,
+		"net_id":  netID,
+		"f_cnt":   qi.FCnt,
+		"ctx_id":  ctx.ctx.Value(logging.ContextIDKey),
+	}).Info("uplink/data: forwarded device-queue item to sNS")
+
+	if ctx.DeviceSession.GetMACVersion() == lorawan.LoRaWAN1_0 {
+		ctx.DeviceSession.NFCntDown = qi.FCnt + 1
+	} else {
+		ctx.DeviceSession.AFCntDown = qi.FCnt + 1
+	}
+
+	if !qi.Confirmed {
+		if err := storage.DeleteDeviceQueueItem(ctx.ctx, storage.DB(), qi.ID); err != nil {
+			return errors.Wrap(err, "delete device-queue item error")
+		}
+		return nil
+	}
+
+	ctx.DeviceSession.ConfFCnt = qi.FCnt
+
+	timeout := time.Now().Add(roaming.GetHandoverRoamingLifetime(netID))
+	qi.IsPending = true
+	qi.TimeoutAfter = &timeout
+
+	if err := storage.UpdateDeviceQueueItem(ctx.ctx, storage.DB(), &qi); err != nil {
+		return errors.Wrap(err, "update device-queue item error")
+	}
+
+	return nil
+}
+
+func xmitDataDownlink(ctx *dataContext, client backend.Client, req backend.XmitDataReqPayload) error {
+	resp, err := client.XmitDataReq(ctx.ctx, req)
+	if err != nil {
+		return errors.Wrap(err, "request error")
+	}
+
+	if resp.Result.ResultCode != backend.Success {
+		return fmt.Errorf("expected: %s, got: %s (%s)", backend.Success, resp.Result.ResultCode, resp.Result.Description)
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package data
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	datadown "github.com/brocaar/chirpstack-network-server/internal/downlink/data"
+	"github.com/brocaar/chirpstack-network-server/internal/logging"
+	"github.com/brocaar/chirpstack-network-server/internal/roaming"
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/lorawan"
+)
+
+// handoverRoamingSNSTasks contains the tasks for an uplink of a visiting
+// device, for which this network-server is the sNS. The sNS controls the
+// MAC-layer of the device (mac-commands, ADR and downlinks), the
+// application payloads are forwarded to the hNS.
+var handoverRoamingSNSTasks = []func(*dataContext) error{
+	decryptFOptsMACCommands,
+	decryptFRMPayloadMACCommands,
+	setHandoverRoamingProfiles,
+	logUplinkFrame,
+	setADR,
+	setUplinkDataRate,
+	handleFOptsMACCommands,
+	handleFRMPayloadMACCommands,
+	storeDeviceGatewayRXInfoSet,
+	appendMetaDataToUplinkHistory,
+	syncUplinkFCnt,
+	saveHandoverRoamingDeviceSession,
+	handleHandoverRoamingDownlink,
+	forwardUplinkToHNS,
+}
+
+// handleHandoverRoamingDevice handles the uplink as the sNS in case it
+// belongs to a visiting device.
+func handleHandoverRoamingDevice(ctx *dataContext) error {
+	if ctx.RXPacket.RoamingMetaData != nil {
+		return nil
+	}
+
+	hrDS, err := storage.GetHandoverRoamingDeviceSessionForDevEUI(ctx.ctx, ctx.DeviceSession.DevEUI, ctx.DeviceSession.DevAddr)
+	if err != nil {
+		if errors.Cause(err) == storage.ErrDoesNotExist {
+			return nil
+		}
+		return errors.Wrap(err, "get handover-roaming device-session error")
+	}
+	ctx.HandoverRoamingDeviceSession = &hrDS
+
+	log.WithFields(log.Fields{
+		"dev_eui":                            hrDS.DevEUI,
+		"dev_addr":                           hrDS.DevAddr,
+		"net_id":                             hrDS.NetID,
+		"handover_roaming_device_session_id": hrDS.SessionID,
+		"ctx_id":                             ctx.ctx.Value(logging.ContextIDKey),
+	}).Info("uplink/data: handling uplink of handover-roaming device as sNS")
+
+	for _, t := range handoverRoamingSNSTasks {
+		if err := t(ctx); err != nil {
+			if err == ErrAbort {
+				break
+			}
+			return err
+		}
+	}
+
+	// the flow stops here
+	return ErrAbort
+}
+
+// setHandoverRoamingProfiles sets the device-profile and service-profile as
+// received from the hNS.
+func setHandoverRoamingProfiles(ctx *dataContext) error {
+	ctx.DeviceProfile = ctx.HandoverRoamingDeviceSession.DeviceProfile
+	ctx.ServiceProfile = ctx.HandoverRoamingDeviceSession.ServiceProfile
+	return nil
+}
+
+// forwardUplinkToHNS forwards the uplink to the hNS, unless it only contains
+// mac-commands. The hNS uses these uplinks to forward its device-queue items
+// and to handle the downlink ACKs. This is done async and after the downlink,
+// so that the downlink frame-counter reported to the hNS is up-to-date.
+func forwardUplinkToHNS(ctx *dataContext) error {
+	if ctx.MACPayload.FPort != nil && *ctx.MACPayload.FPort == 0 && !ctx.MACPayload.FHDR.FCtrl.ACK {
+		return nil
+	}
+
+	netID := ctx.HandoverRoamingDeviceSession.NetID
+	client, err := roaming.GetClientForNetID(netID)
+	if err != nil {
+		return errors.Wrap(err, "get backend client error")
+	}
+
+	// The mac-commands have been decrypted in-place, the hNS must receive
+	// the PHYPayload as sent by the device.
+	var phy lorawan.PHYPayload
+	if err := phy.UnmarshalBinary(ctx.PHYPayloadBytes); err != nil {
+		return errors.Wrap(err, "unmarshal phypayload error")
+	}
+	macPL, ok := phy.MACPayload.(*lorawan.MACPayload)
+	if !ok {
+		return errors.Errorf("expected *lorawan.MACPayload, got: %T", phy.MACPayload)
+	}
+
+	// The device-session has been updated by the downlink flow.
+	ds, err := storage.GetDeviceSession(ctx.ctx, ctx.DeviceSession.DevEUI)
+	if err != nil {
+		return errors.Wrap(err, "get device-session error")
+	}
+
+	rctx := roamingDataContext{
+		ctx:        ctx.ctx,
+		rxPacket:   ctx.RXPacket,
+		macPayload: macPL,
+		devEUI:     &ds.DevEUI,
+		fCntDown:   &ds.NFCntDown,
+	}
+	rctx.rxPacket.PHYPayload = phy
+
+	go func(rctx roamingDataContext) {
+		if err := rctx.xmitDataUplink(client); err != nil {
+			log.WithError(err).WithFields(log.Fields{
+				"dev_addr": rctx.macPayload.FHDR.DevAddr,
+				"net_id":   netID,
+				"ctx_id":   rctx.ctx.Value(logging.ContextIDKey),
+			}).Error("uplink/data: forward handover-roaming uplink to hNS error")
+		}
+	}(rctx)
+
+	return nil
+}
+
+// saveHandoverRoamingDeviceSession saves the device-session, expiring it
+// together with the handover-roaming device-session.
+func saveHandoverRoamingDeviceSession(ctx *dataContext) error {
+	return storage.SaveDeviceSessionWithTTL(ctx.ctx, ctx.DeviceSession, time.Until(ctx.HandoverRoamingDeviceSession.Lifetime))
+}
+
+// handleHandoverRoamingDownlink runs the downlink response flow. A failing
+// downlink is logged, as the uplink must still be forwarded to the hNS.
+func handleHandoverRoamingDownlink(ctx *dataContext) error {
+	time.Sleep(getDownlinkDataDelay)
+	if err := datadown.HandleHandoverRoamingResponse(
+		ctx.ctx,
+		ctx.RXPacket,
+		*ctx.HandoverRoamingDeviceSession,
+		ctx.DeviceSession,
+		ctx.MACPayload.FHDR.FCtrl.ADRACKReq || ctx.MustSendDownlink,
+		ctx.RXPacket.PHYPayload.MHDR.MType == lorawan.ConfirmedDataUp,
+		ctx.MACCommandResponses,
+	); err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"dev_eui": ctx.DeviceSession.DevEUI,
+			"ctx_id":  ctx.ctx.Value(logging.ContextIDKey),
+		}).Error("uplink/data: run handover-roaming response flow error")
+	}
+
+	return nil
+}
@@ -23,6 +23,10 @@ type roamingDataContext struct {
 	rxPacket   models.RXPacket
 	macPayload *lorawan.MACPayload
 
+	// Set by the sNS in case of handover-roaming.
+	devEUI   *lorawan.EUI64
+	fCntDown *uint32
+
 	prDeviceSessions []storage.PassiveRoamingDeviceSession
 }
 
@@ -271,7 +275,9 @@ func (ctx *roamingDataContext) xmitDataUplink(client backend.Client) error {
 	req := backend.XmitDataReqPayload{
 		PHYPayload: backend.HEXBytes(phyB),
 		ULMetaData: &backend.ULMetaData{
+			DevEUI:   ctx.devEUI,
 			DevAddr:  &ctx.macPayload.FHDR.DevAddr,
+			FCntDown: ctx.fCntDown,
 			DataRate: &ctx.rxPacket.DR,
 			ULFreq:   &ulFreq,
 			RecvTime: roaming.RecvTimeFromRXInfo(ctx.rxPacket.RXInfoSet),
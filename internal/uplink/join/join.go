@@ -15,6 +15,7 @@ import (
 	"github.com/brocaar/chirpstack-api/go/v3/as"
 	"github.com/brocaar/chirpstack-api/go/v3/nc"
 	"github.com/brocaar/chirpstack-network-server/internal/backend/controller"
+	"github.com/brocaar/chirpstack-network-server/internal/backend/integration"
 	"github.com/brocaar/chirpstack-network-server/internal/backend/joinserver"
 	"github.com/brocaar/chirpstack-network-server/internal/band"
 	"github.com/brocaar/chirpstack-network-server/internal/config"
@@ -33,6 +34,9 @@ import (
 // ErrAbort is used to abort the flow without error
 var ErrAbort = errors.New("nothing to do")
 
+// prStopReqTimeout defines the max. duration of a PRStopReq sent on re-join.
+const prStopReqTimeout = 10 * time.Second
+
 type joinContext struct {
 	ctx context.Context
 
@@ -48,6 +52,9 @@ type joinContext struct {
 
 	PRStartReqPayload *backend.PRStartReqPayload
 	PRStartAnsPayload *backend.PRStartAnsPayload
+
+	HRStartReqPayload *roaming.HRStartReqPayload
+	HRStartAnsPayload *roaming.HRStartAnsPayload
 }
 
 var (
@@ -56,16 +63,25 @@ var (
 	rx1DROffset int
 	rx1Delay    int
 	keks        map[string][]byte
+
+	deviceActivationRetention     time.Duration
+	deviceActivationPruneInterval time.Duration
 )
 
 // Setup configures the package.
 func Setup(conf config.Config) error {
 	keks = make(map[string][]byte)
 
+	if conf.NetworkServer.DeviceActivation.Retention > 0 && conf.NetworkServer.DeviceActivation.PruneInterval <= 0 {
+		return errors.New("device_activation prune_interval must be > 0 when retention is set")
+	}
+
 	netID = conf.NetworkServer.NetID
 	rx2DR = conf.NetworkServer.NetworkSettings.RX2DR
 	rx1DROffset = conf.NetworkServer.NetworkSettings.RX1DROffset
 	rx1Delay = conf.NetworkServer.NetworkSettings.RX1Delay
+	deviceActivationRetention = conf.NetworkServer.DeviceActivation.Retention
+	deviceActivationPruneInterval = conf.NetworkServer.DeviceActivation.PruneInterval
 
 	for _, k := range conf.JoinServer.KEK.Set {
 		kek, err := hex.DecodeString(k.KEK)
@@ -96,9 +112,12 @@ func Handle(ctx context.Context, rxPacket models.RXPacket) error {
 		jctx.validateNonce,
 		jctx.getRandomDevAddr,
 		jctx.getJoinAcceptFromAS,
+		jctx.setLastDevNonce,
 		jctx.sendUplinkMetaDataToNetworkController,
 		jctx.flushDeviceQueue,
 		jctx.createDeviceSession,
+		jctx.stopPassiveRoaming,
+		jctx.stopHandoverRoaming,
 		jctx.createDeviceActivation,
 		jctx.setDeviceMode,
 		jctx.sendJoinAcceptDownlink,
@@ -131,7 +150,7 @@ func (ctx *joinContext) logJoinRequestFramesCollected() error {
 		return errors.Wrap(err, "create uplink frame-set error")
 	}
 
-	if err := framelog.LogUplinkFrameForDevEUI(ctx.ctx, ctx.JoinRequestPayload.DevEUI, uplinkFrameLog); err != nil {
+	if err := framelog.LogUplinkFrameForDevEUI(ctx.ctx, ctx.JoinRequestPayload.DevEUI, uplinkFrameLog, false); err != nil {
 		log.WithFields(log.Fields{
 			"ctx_id": ctx.ctx.Value("join_ctx"),
 		}).WithError(err).Error("log uplink frame for device error")
@@ -149,7 +168,7 @@ func (ctx *joinContext) getDeviceOrTryRoaming() error {
 				"ctx_id":   ctx.ctx.Value(logging.ContextIDKey),
 				"dev_eui":  ctx.JoinRequestPayload.DevEUI,
 				"join_eui": ctx.JoinRequestPayload.JoinEUI,
-			}).Info("uplink/join: unknown device, try passive-roaming activation")
+			}).Info("uplink/join: unknown device, try roaming activation")
 
 			if err := StartPRFNS(ctx.ctx, ctx.RXPacket, ctx.JoinRequestPayload); err != nil {
 				return err
@@ -193,15 +212,15 @@ func (ctx *joinContext) abortOnDeviceIsDisabled() error {
 }
 
 func (ctx *joinContext) validateNonce() error {
-	// validate that the nonce has not been used yet
-	err := storage.ValidateDevNonce(
-		ctx.ctx,
-		storage.DB(),
-		ctx.JoinRequestPayload.JoinEUI,
-		ctx.JoinRequestPayload.DevEUI,
-		ctx.JoinRequestPayload.DevNonce,
-		lorawan.JoinRequestType,
-	)
+	var reason string
+	var err error
+
+	if strings.HasPrefix(ctx.DeviceProfile.MACVersion, "1.1") {
+		reason, err = ctx.validateIncrementedNonce()
+	} else {
+		reason, err = ctx.validateUnusedNonce()
+	}
+
 	if err != nil {
 		returnErr := errors.Wrap(err, "validate dev-nonce error")
 		asClient, err := helpers.GetASClientForRoutingProfileID(ctx.ctx, ctx.Device.RoutingProfileID)
@@ -214,7 +233,7 @@ func (ctx *joinContext) validateNonce() error {
 			_, err := asClient.HandleError(ctx.ctx, &as.HandleErrorRequest{
 				DevEui: ctx.Device.DevEUI[:],
 				Type:   as.ErrorType_OTAA,
-				Error:  "validate dev-nonce error",
+				Error:  "validate dev-nonce error: " + reason,
 			})
 			if err != nil {
 				log.WithError(err).WithFields(log.Fields{
@@ -230,6 +249,47 @@ func (ctx *joinContext) validateNonce() error {
 	return nil
 }
 
+// validateUnusedNonce validates that the dev-nonce has not been used yet
+// (LoRaWAN 1.0). On error, it returns the rejection reason.
+func (ctx *joinContext) validateUnusedNonce() (string, error) {
+	err := storage.ValidateDevNonce(
+		ctx.ctx,
+		storage.DB(),
+		ctx.JoinRequestPayload.JoinEUI,
+		ctx.JoinRequestPayload.DevEUI,
+		ctx.JoinRequestPayload.DevNonce,
+		lorawan.JoinRequestType,
+	)
+	if err != nil {
+		if err == storage.ErrAlreadyExists {
+			return fmt.Sprintf("dev-nonce %d has already been used", ctx.JoinRequestPayload.DevNonce), err
+		}
+		return "internal error", err
+	}
+
+	return "", nil
+}
+
+// validateIncrementedNonce validates that the dev-nonce is greater than the
+// last used dev-nonce (LoRaWAN 1.1). For devices without last used dev-nonce
+// (e.g. the first join-request), it falls back to validateUnusedNonce.
+// On error, it returns the rejection reason.
+func (ctx *joinContext) validateIncrementedNonce() (string, error) {
+	last, err := storage.GetLastDevNonce(ctx.ctx, storage.DB(), ctx.JoinRequestPayload.DevEUI)
+	if err != nil {
+		if err == storage.ErrDoesNotExist {
+			return ctx.validateUnusedNonce()
+		}
+		return "internal error", errors.Wrap(err, "get last dev-nonce error")
+	}
+
+	if ctx.JoinRequestPayload.DevNonce <= last {
+		return fmt.Sprintf("dev-nonce %d must be greater than the last used dev-nonce %d", ctx.JoinRequestPayload.DevNonce, last), storage.ErrDevNonceNotIncremented
+	}
+
+	return "", nil
+}
+
 func (ctx *joinContext) getRandomDevAddr() error {
 	devAddr, err := storage.GetRandomDevAddr(netID)
 	if err != nil {
@@ -288,6 +348,14 @@ func (ctx *joinContext) getJoinAcceptFromAS() error {
 		CFList:  backend.HEXBytes(cFListB),
 	}
 
+	// In case of handover-roaming, the device is served by the sNS.
+	if ctx.HRStartReqPayload != nil {
+		joinReqPL.DLSettings.RX2DataRate = ctx.HRStartReqPayload.DLSettings.RX2DataRate
+		joinReqPL.DLSettings.RX1DROffset = ctx.HRStartReqPayload.DLSettings.RX1DROffset
+		joinReqPL.RxDelay = ctx.HRStartReqPayload.RxDelay
+		joinReqPL.CFList = ctx.HRStartReqPayload.CFList
+	}
+
 	jsClient, err := joinserver.GetPool().Get(ctx.JoinRequestPayload.JoinEUI)
 	if err != nil {
 		return errors.Wrap(err, "get join-server client error")
@@ -325,6 +393,21 @@ func (ctx *joinContext) getJoinAcceptFromAS() error {
 	return nil
 }
 
+// setLastDevNonce stores the dev-nonce of LoRaWAN 1.1 devices, so that the
+// next join-request can be validated against it. This is done after the
+// join-server accepted the join-request, as the join-server validates the MIC.
+func (ctx *joinContext) setLastDevNonce() error {
+	if !strings.HasPrefix(ctx.DeviceProfile.MACVersion, "1.1") {
+		return nil
+	}
+
+	if err := storage.SetLastDevNonce(ctx.ctx, storage.DB(), ctx.JoinRequestPayload.DevEUI, ctx.JoinRequestPayload.DevNonce); err != nil {
+		return errors.Wrap(err, "set last dev-nonce error")
+	}
+
+	return nil
+}
+
 func (ctx *joinContext) sendUplinkMetaDataToNetworkController() error {
 	if controller.Client() == nil {
 		return nil
@@ -342,6 +425,12 @@ func (ctx *joinContext) sendUplinkMetaDataToNetworkController() error {
 		req.PhyPayloadByteCount = uint32(len(b))
 	}
 
+	integration.PublishEvent(ctx.ctx, integration.Event{
+		Type:    integration.EventUp,
+		DevEUI:  ctx.JoinRequestPayload.DevEUI,
+		Message: &req,
+	})
+
 	// send async to controller
 	go func() {
 		_, err := controller.Client().HandleUplinkMetaData(ctx.ctx, &req)
@@ -390,6 +479,8 @@ func (ctx *joinContext) createDeviceSession() error {
 		PingSlotDR:            ctx.DeviceProfile.PingSlotDR,
 		PingSlotFrequency:     int(ctx.DeviceProfile.PingSlotFreq),
 		NbTrans:               1,
+		ADRAckLimitExp:        storage.DefaultADRAckLimitExp,
+		ADRAckDelayExp:        storage.DefaultADRAckDelayExp,
 		ReferenceAltitude:     ctx.Device.ReferenceAltitude,
 	}
 
@@ -438,37 +529,8 @@ func (ctx *joinContext) createDeviceSession() error {
 		ds.NwkSEncKey = key
 	}
 
-	if cfList := band.Band().GetCFList(ctx.DeviceProfile.MACVersion); cfList != nil && cfList.CFListType == lorawan.CFListChannel {
-		channelPL, ok := cfList.Payload.(*lorawan.CFListChannelPayload)
-		if !ok {
-			return fmt.Errorf("expected *lorawan.CFListChannelPayload, got %T", cfList.Payload)
-		}
-
-		for _, f := range channelPL.Channels {
-			if f == 0 {
-				continue
-			}
-
-			i, err := band.Band().GetUplinkChannelIndex(int(f), false)
-			if err != nil {
-				// if this happens, something is really wrong
-				log.WithError(err).WithFields(log.Fields{
-					"frequency": f,
-				}).Error("unknown cflist frequency")
-				continue
-			}
-
-			// add extra channel to enabled channels
-			ds.EnabledUplinkChannels = append(ds.EnabledUplinkChannels, i)
-
-			// add extra channel to extra uplink channels, so that we can
-			// keep track on frequency and data-rate changes
-			c, err := band.Band().GetUplinkChannel(i)
-			if err != nil {
-				return errors.Wrap(err, "get uplink channel error")
-			}
-			ds.ExtraUplinkChannels[i] = c
-		}
+	if err := setExtraUplinkChannelsFromCFList(&ds, band.Band().GetCFList(ctx.DeviceProfile.MACVersion)); err != nil {
+		return err
 	}
 
 	if ctx.DeviceProfile.PingSlotPeriod != 0 {
@@ -488,6 +550,107 @@ func (ctx *joinContext) createDeviceSession() error {
 	return nil
 }
 
+// setExtraUplinkChannelsFromCFList adds the channels of the given CFList
+// to the enabled and extra uplink channels of the device-session.
+func setExtraUplinkChannelsFromCFList(ds *storage.DeviceSession, cfList *lorawan.CFList) error {
+	if cfList == nil || cfList.CFListType != lorawan.CFListChannel {
+		return nil
+	}
+
+	channelPL, ok := cfList.Payload.(*lorawan.CFListChannelPayload)
+	if !ok {
+		return fmt.Errorf("expected *lorawan.CFListChannelPayload, got %T", cfList.Payload)
+	}
+
+	for _, f := range channelPL.Channels {
+		if f == 0 {
+			continue
+		}
+
+		i, err := band.Band().GetUplinkChannelIndex(int(f), false)
+		if err != nil {
+			// if this happens, something is really wrong
+			log.WithError(err).WithFields(log.Fields{
+				"frequency": f,
+			}).Error("unknown cflist frequency")
+			continue
+		}
+
+		// add extra channel to enabled channels
+		ds.EnabledUplinkChannels = append(ds.EnabledUplinkChannels, i)
+
+		// add extra channel to extra uplink channels, so that we can
+		// keep track on frequency and data-rate changes
+		c, err := band.Band().GetUplinkChannel(i)
+		if err != nil {
+			return errors.Wrap(err, "get uplink channel error")
+		}
+		ds.ExtraUplinkChannels[i] = c
+	}
+
+	return nil
+}
+
+// stopPassiveRoaming sends a PRStopReq to each fNS to which a stateful
+// passive-roaming session was handed out for the device, as these sessions
+// are invalid after the (local) re-join.
+func (ctx *joinContext) stopPassiveRoaming() error {
+	netIDs, err := storage.GetPassiveRoamingNetIDsForDevEUI(ctx.ctx, ctx.Device.DevEUI)
+	if err != nil {
+		return errors.Wrap(err, "get passive-roaming netids error")
+	}
+
+	if len(netIDs) == 0 {
+		return nil
+	}
+
+	if err := storage.DeletePassiveRoamingNetIDsForDevEUI(ctx.ctx, ctx.Device.DevEUI); err != nil {
+		return errors.Wrap(err, "delete passive-roaming netids error")
+	}
+
+	for _, netID := range netIDs {
+		client, err := roaming.GetClientForNetID(netID)
+		if err != nil {
+			log.WithError(err).WithFields(log.Fields{
+				"net_id":  netID,
+				"dev_eui": ctx.Device.DevEUI,
+				"ctx_id":  ctx.ctx.Value(logging.ContextIDKey),
+			}).Warning("uplink/join: get client for netid error")
+			continue
+		}
+
+		// the request runs in the background, therefore it must not use
+		// the context of the join-request
+		reqCtx := context.WithValue(context.Background(), logging.ContextIDKey, ctx.ctx.Value(logging.ContextIDKey))
+
+		go func(ctx context.Context, client backend.Client, netID lorawan.NetID, devEUI lorawan.EUI64) {
+			ctx, cancel := context.WithTimeout(ctx, prStopReqTimeout)
+			defer cancel()
+
+			ans, err := client.PRStopReq(ctx, backend.PRStopReqPayload{
+				DevEUI: devEUI,
+			})
+			if err != nil {
+				log.WithError(err).WithFields(log.Fields{
+					"net_id":  netID,
+					"dev_eui": devEUI,
+					"ctx_id":  ctx.Value(logging.ContextIDKey),
+				}).Error("uplink/join: PRStopReq error")
+				return
+			}
+
+			log.WithFields(log.Fields{
+				"net_id":      netID,
+				"dev_eui":     devEUI,
+				"result_code": ans.Result.ResultCode,
+				"ctx_id":      ctx.Value(logging.ContextIDKey),
+			}).Info("uplink/join: passive-roaming stopped")
+		}(reqCtx, client, netID, ctx.Device.DevEUI)
+	}
+
+	return nil
+}
+
 func (ctx *joinContext) createDeviceActivation() error {
 	da := storage.DeviceActivation{
 		DevEUI:      ctx.DeviceSession.DevEUI,
@@ -544,6 +707,13 @@ func (ctx *joinContext) setPRStartAnsPayload() error {
 	lifetime := int(roaming.GetPassiveRoamingLifetime(netID) / time.Second)
 	fCntUp := uint32(0)
 
+	// keep track of the fNS, so that the session can be stopped on re-join
+	if lifetime != 0 {
+		if err := storage.SavePassiveRoamingNetIDForDevEUI(ctx.ctx, ctx.Device.DevEUI, netID, roaming.GetPassiveRoamingLifetime(netID)); err != nil {
+			return errors.Wrap(err, "save passive-roaming netid error")
+		}
+	}
+
 	// sess keys
 	kekLabel := roaming.GetPassiveRoamingKEKLabel(netID)
 	var kekKey []byte
@@ -568,46 +738,59 @@ func (ctx *joinContext) setPRStartAnsPayload() error {
 		}
 	}
 
+	dlMetaData, err := ctx.getJoinAcceptDLMetaData(ctx.PRStartReqPayload.ULMetaData)
+	if err != nil {
+		return err
+	}
+
+	ctx.PRStartAnsPayload = &backend.PRStartAnsPayload{
+		PHYPayload:  ctx.JoinAnsPayload.PHYPayload,
+		DevEUI:      &ctx.Device.DevEUI,
+		DevAddr:     &ctx.DevAddr,
+		Lifetime:    &lifetime,
+		FNwkSIntKey: fNwkSIntKey,
+		NwkSKey:     nwkSKey,
+		FCntUp:      &fCntUp,
+		DLMetaData:  dlMetaData,
+	}
+
+	return nil
+}
+
+// getJoinAcceptDLMetaData returns the DLMetaData for sending the join-accept
+// through the network-server from which the given ULMetaData was received.
+func (ctx *joinContext) getJoinAcceptDLMetaData(ulMetaData backend.ULMetaData) (*backend.DLMetaData, error) {
 	classA := "A"
 	rxDelay1 := int(band.Band().GetDefaults().JoinAcceptDelay1 / time.Second)
 	rx1DR, err := band.Band().GetRX1DataRateIndex(ctx.RXPacket.DR, 0)
 	if err != nil {
-		return errors.Wrap(err, "get rx1 data-rate error")
+		return nil, errors.Wrap(err, "get rx1 data-rate error")
 	}
 	rx2DR := band.Band().GetDefaults().RX2DataRate
 	dlFreq1, err := band.Band().GetRX1FrequencyForUplinkFrequency(int(ctx.RXPacket.TXInfo.Frequency))
 	if err != nil {
-		return errors.Wrap(err, "get rx1 frequency error")
+		return nil, errors.Wrap(err, "get rx1 frequency error")
 	}
 	dlFreq1Mhz := float64(dlFreq1) / 1000000
 	dlFreq2Mhz := float64(band.Band().GetDefaults().RX2Frequency) / 1000000
 
-	ctx.PRStartAnsPayload = &backend.PRStartAnsPayload{
-		PHYPayload:  ctx.JoinAnsPayload.PHYPayload,
-		DevEUI:      &ctx.Device.DevEUI,
-		DevAddr:     &ctx.DevAddr,
-		Lifetime:    &lifetime,
-		FNwkSIntKey: fNwkSIntKey,
-		NwkSKey:     nwkSKey,
-		FCntUp:      &fCntUp,
-		DLMetaData: &backend.DLMetaData{
-			DevEUI:     &ctx.DeviceSession.DevEUI,
-			DLFreq1:    &dlFreq1Mhz,
-			DLFreq2:    &dlFreq2Mhz,
-			RXDelay1:   &rxDelay1,
-			ClassMode:  &classA,
-			DataRate1:  &rx1DR,
-			DataRate2:  &rx2DR,
-			FNSULToken: ctx.PRStartReqPayload.ULMetaData.FNSULToken,
-		},
+	dlMetaData := backend.DLMetaData{
+		DevEUI:     &ctx.DeviceSession.DevEUI,
+		DLFreq1:    &dlFreq1Mhz,
+		DLFreq2:    &dlFreq2Mhz,
+		RXDelay1:   &rxDelay1,
+		ClassMode:  &classA,
+		DataRate1:  &rx1DR,
+		DataRate2:  &rx2DR,
+		FNSULToken: ulMetaData.FNSULToken,
 	}
 
-	for i := range ctx.PRStartReqPayload.ULMetaData.GWInfo {
-		gwInfo := ctx.PRStartReqPayload.ULMetaData.GWInfo[i]
-		ctx.PRStartAnsPayload.DLMetaData.GWInfo = append(ctx.PRStartAnsPayload.DLMetaData.GWInfo, backend.GWInfoElement{
+	for i := range ulMetaData.GWInfo {
+		gwInfo := ulMetaData.GWInfo[i]
+		dlMetaData.GWInfo = append(dlMetaData.GWInfo, backend.GWInfoElement{
 			ULToken: gwInfo.ULToken,
 		})
 	}
 
-	return nil
+	return &dlMetaData, nil
 }
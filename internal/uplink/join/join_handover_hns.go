@@ -0,0 +1,215 @@
+package join
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brocaar/chirpstack-network-server/internal/logging"
+	"github.com/brocaar/chirpstack-network-server/internal/models"
+	"github.com/brocaar/chirpstack-network-server/internal/roaming"
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/lorawan"
+	"github.com/brocaar/lorawan/backend"
+)
+
+// ErrHandoverRoamingDisallowed is returned when the service-profile of the
+// device does not allow handover-roaming.
+var ErrHandoverRoamingDisallowed = errors.New("handover-roaming is not allowed")
+
+// HandleStartHRHNS handles starting a handover-roaming OTAA activation as the
+// hNS. The join-request is handled as usual, but the session-keys are handed
+// over to the sNS which will serve the device.
+func HandleStartHRHNS(ctx context.Context, hrStartPL roaming.HRStartReqPayload, rxPacket models.RXPacket) (roaming.HRStartAnsPayload, error) {
+	jctx := joinContext{
+		ctx:               ctx,
+		RXPacket:          rxPacket,
+		HRStartReqPayload: &hrStartPL,
+	}
+
+	for _, f := range []func() error{
+		jctx.setContextFromJoinRequestPHYPayload,
+		jctx.logJoinRequestFramesCollected,
+		jctx.getDevice,
+		jctx.getDeviceProfile,
+		jctx.getServiceProfile,
+		jctx.abortOnHandoverRoamingDisallowed,
+		jctx.abortOnDeviceIsDisabled,
+		jctx.validateNonce,
+		jctx.setDevAddrFromHRStartReq,
+		jctx.getJoinAcceptFromAS,
+		jctx.setLastDevNonce,
+		jctx.sendUplinkMetaDataToNetworkController,
+		jctx.flushDeviceQueue,
+		jctx.createDeviceSession,
+		jctx.stopPassiveRoaming,
+		jctx.stopHandoverRoaming,
+		jctx.createDeviceActivation,
+		jctx.setDeviceMode,
+		jctx.setHRStartAnsPayload,
+	} {
+		if err := f(); err != nil {
+			return roaming.HRStartAnsPayload{}, err
+		}
+	}
+
+	if jctx.HRStartAnsPayload != nil {
+		return *jctx.HRStartAnsPayload, nil
+	}
+
+	return roaming.HRStartAnsPayload{}, errors.New("HRStartAnsPayload is not set")
+}
+
+func (ctx *joinContext) getDevice() error {
+	var err error
+	ctx.Device, err = storage.GetDevice(ctx.ctx, storage.DB(), ctx.JoinRequestPayload.DevEUI)
+	if err != nil {
+		return errors.Wrap(err, "get device error")
+	}
+	return nil
+}
+
+func (ctx *joinContext) abortOnHandoverRoamingDisallowed() error {
+	if !ctx.ServiceProfile.HRAllowed {
+		return ErrHandoverRoamingDisallowed
+	}
+	return nil
+}
+
+func (ctx *joinContext) setDevAddrFromHRStartReq() error {
+	// The DevAddr is assigned by the sNS, as it serves the device.
+	ctx.DevAddr = ctx.HRStartReqPayload.DevAddr
+	return nil
+}
+
+// stopHandoverRoaming sends a HRStopReq to the sNS to which the device was
+// handed over, as its session is invalid after the re-join. In case the
+// device re-joins through the same sNS, the session is replaced by the
+// HRStartAns instead.
+func (ctx *joinContext) stopHandoverRoaming() error {
+	sNSNetID, err := storage.GetHandoverRoamingNetIDForDevEUI(ctx.ctx, ctx.Device.DevEUI)
+	if err != nil {
+		if errors.Cause(err) == storage.ErrDoesNotExist {
+			return nil
+		}
+		return errors.Wrap(err, "get handover-roaming netid error")
+	}
+
+	if ctx.HRStartReqPayload != nil {
+		var senderNetID lorawan.NetID
+		if err := senderNetID.UnmarshalText([]byte(ctx.HRStartReqPayload.SenderID)); err != nil {
+			return errors.Wrap(err, "decode netid error")
+		}
+
+		if senderNetID == sNSNetID {
+			return nil
+		}
+	}
+
+	if err := storage.DeleteHandoverRoamingNetIDForDevEUI(ctx.ctx, ctx.Device.DevEUI); err != nil {
+		return errors.Wrap(err, "delete handover-roaming netid error")
+	}
+
+	client, err := roaming.GetHandoverClientForNetID(sNSNetID)
+	if err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"net_id":  sNSNetID,
+			"dev_eui": ctx.Device.DevEUI,
+			"ctx_id":  ctx.ctx.Value(logging.ContextIDKey),
+		}).Warning("uplink/join: get client for netid error")
+		return nil
+	}
+
+	go func(client roaming.HandoverClient, devEUI lorawan.EUI64, netID lorawan.NetID) {
+		ans, err := client.HRStopReq(ctx.ctx, roaming.HRStopReqPayload{
+			DevEUI: devEUI,
+		})
+		if err != nil {
+			log.WithError(err).WithFields(log.Fields{
+				"net_id":  netID,
+				"dev_eui": devEUI,
+				"ctx_id":  ctx.ctx.Value(logging.ContextIDKey),
+			}).Error("uplink/join: HRStopReq error")
+			return
+		}
+
+		log.WithFields(log.Fields{
+			"net_id":      netID,
+			"dev_eui":     devEUI,
+			"result_code": ans.Result.ResultCode,
+			"ctx_id":      ctx.ctx.Value(logging.ContextIDKey),
+		}).Info("uplink/join: handover-roaming stopped")
+	}(client, ctx.Device.DevEUI, sNSNetID)
+
+	return nil
+}
+
+func (ctx *joinContext) setHRStartAnsPayload() error {
+	var sNSNetID lorawan.NetID
+	err := sNSNetID.UnmarshalText([]byte(ctx.HRStartReqPayload.SenderID))
+	if err != nil {
+		return errors.Wrap(err, "decode netid error")
+	}
+
+	lifetimeDuration := roaming.GetHandoverRoamingLifetime(sNSNetID)
+	if lifetimeDuration == 0 {
+		return errors.New("handover-roaming lifetime is not configured")
+	}
+	lifetime := int(lifetimeDuration / time.Second)
+
+	// sess keys
+	kekLabel := roaming.GetHandoverRoamingKEKLabel(sNSNetID)
+	var kekKey []byte
+	if kekLabel != "" {
+		kekKey, err = roaming.GetKEKKey(kekLabel)
+		if err != nil {
+			return errors.Wrap(err, "get kek key error")
+		}
+	}
+
+	dlMetaData, err := ctx.getJoinAcceptDLMetaData(ctx.HRStartReqPayload.ULMetaData)
+	if err != nil {
+		return err
+	}
+
+	ans := roaming.HRStartAnsPayload{
+		PHYPayload:     ctx.JoinAnsPayload.PHYPayload,
+		DevEUI:         &ctx.Device.DevEUI,
+		Lifetime:       &lifetime,
+		DeviceProfile:  roaming.DeviceProfileToBackend(ctx.DeviceProfile),
+		ServiceProfile: roaming.ServiceProfileToBackend(ctx.ServiceProfile),
+		DLMetaData:     dlMetaData,
+	}
+
+	if ctx.DeviceSession.GetMACVersion() == lorawan.LoRaWAN1_0 {
+		ans.NwkSKey, err = backend.NewKeyEnvelope(kekLabel, kekKey, ctx.DeviceSession.NwkSEncKey)
+		if err != nil {
+			return errors.Wrap(err, "new key envelope error")
+		}
+	} else {
+		for _, k := range []struct {
+			key      lorawan.AES128Key
+			envelope **backend.KeyEnvelope
+		}{
+			{ctx.DeviceSession.SNwkSIntKey, &ans.SNwkSIntKey},
+			{ctx.DeviceSession.FNwkSIntKey, &ans.FNwkSIntKey},
+			{ctx.DeviceSession.NwkSEncKey, &ans.NwkSEncKey},
+		} {
+			*k.envelope, err = backend.NewKeyEnvelope(kekLabel, kekKey, k.key)
+			if err != nil {
+				return errors.Wrap(err, "new key envelope error")
+			}
+		}
+	}
+
+	// keep track of the sNS, so that the session can be stopped on re-join
+	if err := storage.SaveHandoverRoamingNetIDForDevEUI(ctx.ctx, ctx.Device.DevEUI, sNSNetID, lifetimeDuration); err != nil {
+		return errors.Wrap(err, "save handover-roaming netid error")
+	}
+
+	ctx.HRStartAnsPayload = &ans
+
+	return nil
+}
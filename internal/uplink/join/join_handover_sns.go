@@ -0,0 +1,274 @@
+package join
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brocaar/chirpstack-network-server/internal/band"
+	dlroaming "github.com/brocaar/chirpstack-network-server/internal/downlink/roaming"
+	"github.com/brocaar/chirpstack-network-server/internal/logging"
+	"github.com/brocaar/chirpstack-network-server/internal/models"
+	"github.com/brocaar/chirpstack-network-server/internal/roaming"
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+	"github.com/brocaar/lorawan"
+	"github.com/brocaar/lorawan/backend"
+	loraband "github.com/brocaar/lorawan/band"
+)
+
+type startHRSNSContext struct {
+	ctx                context.Context
+	rxPacket           models.RXPacket
+	joinRequestPayload *lorawan.JoinRequestPayload
+	homeNetID          lorawan.NetID
+	devAddr            lorawan.DevAddr
+	cFList             *lorawan.CFList
+	hrStartAns         roaming.HRStartAnsPayload
+}
+
+// StartHRSNS initiates the handover-roaming OTAA as the sNS. On success,
+// this network-server serves the visiting device.
+func StartHRSNS(ctx context.Context, rxPacket models.RXPacket, jrPL *lorawan.JoinRequestPayload, homeNetID lorawan.NetID) error {
+	cctx := startHRSNSContext{
+		ctx:                ctx,
+		rxPacket:           rxPacket,
+		joinRequestPayload: jrPL,
+		homeNetID:          homeNetID,
+	}
+
+	for _, f := range []func() error{
+		cctx.getRandomDevAddr,
+		cctx.startRoaming,
+		cctx.saveRoamingSession,
+		cctx.sendJoinAcceptDownlink,
+	} {
+		if err := f(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ctx *startHRSNSContext) getRandomDevAddr() error {
+	// The DevAddr is assigned by the sNS, thus it matches our NetID.
+	devAddr, err := storage.GetRandomDevAddr(netID)
+	if err != nil {
+		return errors.Wrap(err, "get random DevAddr error")
+	}
+	ctx.devAddr = devAddr
+
+	return nil
+}
+
+func (ctx *startHRSNSContext) startRoaming() error {
+	phyB, err := ctx.rxPacket.PHYPayload.MarshalBinary()
+	if err != nil {
+		return errors.Wrap(err, "marshal phypayload error")
+	}
+
+	gwCnt := len(ctx.rxPacket.RXInfoSet)
+	gwInfo, err := roaming.RXInfoToGWInfo(ctx.rxPacket.RXInfoSet)
+	if err != nil {
+		return errors.Wrap(err, "rxinfo to gwinfo error")
+	}
+
+	ulFreq := float64(ctx.rxPacket.TXInfo.Frequency) / 1000000
+
+	// The MAC version of the device is not known before the HRStartAns,
+	// the LoRaWAN 1.0.0 CFList is supported by all MAC versions.
+	var cFListB []byte
+	ctx.cFList = band.Band().GetCFList("1.0.0")
+	if ctx.cFList != nil {
+		cFListB, err = ctx.cFList.MarshalBinary()
+		if err != nil {
+			return errors.Wrap(err, "marshal cflist error")
+		}
+	}
+
+	hrReq := roaming.HRStartReqPayload{
+		PHYPayload: backend.HEXBytes(phyB),
+		DevAddr:    ctx.devAddr,
+		DLSettings: lorawan.DLSettings{
+			RX2DataRate: uint8(rx2DR),
+			RX1DROffset: uint8(rx1DROffset),
+		},
+		RxDelay: rx1Delay,
+		CFList:  backend.HEXBytes(cFListB),
+		ULMetaData: backend.ULMetaData{
+			DevEUI:   &ctx.joinRequestPayload.DevEUI,
+			ULFreq:   &ulFreq,
+			DataRate: &ctx.rxPacket.DR,
+			RecvTime: roaming.RecvTimeFromRXInfo(ctx.rxPacket.RXInfoSet),
+			RFRegion: band.Band().Name(),
+			GWCnt:    &gwCnt,
+			GWInfo:   gwInfo,
+		},
+	}
+
+	log.WithFields(log.Fields{
+		"ctx_id":   ctx.ctx.Value(logging.ContextIDKey),
+		"dev_eui":  ctx.joinRequestPayload.DevEUI,
+		"dev_addr": ctx.devAddr,
+		"net_id":   ctx.homeNetID,
+	}).Info("uplink/join: starting handover-roaming activation")
+
+	client, err := roaming.GetHandoverClientForNetID(ctx.homeNetID)
+	if err != nil {
+		return errors.Wrap(err, "get client for netid error")
+	}
+
+	ctx.hrStartAns, err = client.HRStartReq(ctx.ctx, hrReq)
+	if err != nil {
+		return errors.Wrap(err, "HRStartReq error")
+	}
+
+	if ctx.hrStartAns.Result.ResultCode != backend.Success {
+		return fmt.Errorf("expected: %s, got: %s (%s)", backend.Success, ctx.hrStartAns.Result.ResultCode, ctx.hrStartAns.Result.Description)
+	}
+
+	if ctx.hrStartAns.DLMetaData == nil {
+		return errors.New("DLMetaData must not be nil")
+	}
+
+	if ctx.hrStartAns.Lifetime == nil || *ctx.hrStartAns.Lifetime == 0 {
+		return errors.New("Lifetime must be set")
+	}
+
+	if ctx.hrStartAns.DeviceProfile == nil {
+		return errors.New("DeviceProfile must not be nil")
+	}
+
+	if ctx.hrStartAns.ServiceProfile == nil {
+		return errors.New("ServiceProfile must not be nil")
+	}
+
+	return nil
+}
+
+func (ctx *startHRSNSContext) saveRoamingSession() error {
+	// remove the sessions of a previous activation
+	ids, err := storage.GetHandoverRoamingIDsForDevEUI(ctx.ctx, ctx.joinRequestPayload.DevEUI)
+	if err != nil {
+		return errors.Wrap(err, "get handover-roaming session ids error")
+	}
+	for _, id := range ids {
+		if err := storage.DeleteHandoverRoamingDeviceSession(ctx.ctx, id); err != nil && errors.Cause(err) != storage.ErrDoesNotExist {
+			return errors.Wrap(err, "delete handover-roaming device-session error")
+		}
+	}
+
+	dp := roaming.DeviceProfileFromBackend(*ctx.hrStartAns.DeviceProfile)
+	sp := roaming.ServiceProfileFromBackend(*ctx.hrStartAns.ServiceProfile)
+
+	// The device-status can't be reported, as the application-server is
+	// connected to the hNS.
+	sp.DevStatusReqFreq = 0
+	sp.ReportDevStatusBattery = false
+	sp.ReportDevStatusMargin = false
+
+	// The sNS controls the MAC-layer of the device, using the RX parameters
+	// and CFList of the HRStartReq.
+	ds := storage.DeviceSession{
+		DeviceProfileID:  dp.ID,
+		ServiceProfileID: sp.ID,
+
+		MACVersion:            dp.MACVersion,
+		DevAddr:               ctx.devAddr,
+		JoinEUI:               ctx.joinRequestPayload.JoinEUI,
+		DevEUI:                ctx.joinRequestPayload.DevEUI,
+		RXWindow:              storage.RX1,
+		RXDelay:               uint8(rx1Delay),
+		RX1DROffset:           uint8(rx1DROffset),
+		RX2DR:                 uint8(rx2DR),
+		RX2Frequency:          band.Band().GetDefaults().RX2Frequency,
+		EnabledUplinkChannels: band.Band().GetStandardUplinkChannelIndices(),
+		ExtraUplinkChannels:   make(map[int]loraband.Channel),
+		PingSlotDR:            dp.PingSlotDR,
+		PingSlotFrequency:     dp.PingSlotFreq,
+		NbTrans:               1,
+		ADRAckLimitExp:        storage.DefaultADRAckLimitExp,
+		ADRAckDelayExp:        storage.DefaultADRAckDelayExp,
+	}
+
+	// NwkSKey (LoRaWAN 1.0)
+	if ctx.hrStartAns.NwkSKey != nil {
+		key, err := roaming.UnwrapKeyEnvelope(ctx.hrStartAns.NwkSKey)
+		if err != nil {
+			return errors.Wrap(err, "unwrap NwkSKey error")
+		}
+
+		ds.SNwkSIntKey = key
+		ds.FNwkSIntKey = key
+		ds.NwkSEncKey = key
+	}
+
+	// SNwkSIntKey, FNwkSIntKey and NwkSEncKey (LoRaWAN 1.1)
+	if ctx.hrStartAns.FNwkSIntKey != nil {
+		for _, k := range []struct {
+			name     string
+			envelope *backend.KeyEnvelope
+			key      *lorawan.AES128Key
+		}{
+			{"SNwkSIntKey", ctx.hrStartAns.SNwkSIntKey, &ds.SNwkSIntKey},
+			{"FNwkSIntKey", ctx.hrStartAns.FNwkSIntKey, &ds.FNwkSIntKey},
+			{"NwkSEncKey", ctx.hrStartAns.NwkSEncKey, &ds.NwkSEncKey},
+		} {
+			if k.envelope == nil {
+				return fmt.Errorf("%s must not be nil", k.name)
+			}
+
+			*k.key, err = roaming.UnwrapKeyEnvelope(k.envelope)
+			if err != nil {
+				return errors.Wrapf(err, "unwrap %s error", k.name)
+			}
+		}
+	}
+
+	if err := setExtraUplinkChannelsFromCFList(&ds, ctx.cFList); err != nil {
+		return err
+	}
+
+	if dp.PingSlotPeriod != 0 {
+		ds.PingSlotNb = (1 << 12) / dp.PingSlotPeriod
+	}
+
+	lifetime := time.Now().Add(time.Duration(*ctx.hrStartAns.Lifetime) * time.Second)
+
+	// The device-session must expire together with the handover-roaming
+	// device-session, as the device-profile and service-profile only exist
+	// on the hNS.
+	if err := storage.SaveDeviceSessionWithTTL(ctx.ctx, ds, time.Until(lifetime)); err != nil {
+		return errors.Wrap(err, "save device-session error")
+	}
+
+	if err := storage.FlushMACCommandQueue(ctx.ctx, ds.DevEUI); err != nil {
+		return errors.Wrap(err, "flush mac-command queue error")
+	}
+
+	sess := storage.HandoverRoamingDeviceSession{
+		NetID:          ctx.homeNetID,
+		DevEUI:         ctx.joinRequestPayload.DevEUI,
+		DevAddr:        ctx.devAddr,
+		Lifetime:       lifetime,
+		DeviceProfile:  dp,
+		ServiceProfile: sp,
+	}
+
+	if err := storage.SaveHandoverRoamingDeviceSession(ctx.ctx, &sess); err != nil {
+		return errors.Wrap(err, "save handover-roaming device-session error")
+	}
+
+	return nil
+}
+
+func (ctx *startHRSNSContext) sendJoinAcceptDownlink() error {
+	if err := dlroaming.EmitPRDownlink(ctx.ctx, ctx.rxPacket, ctx.hrStartAns.PHYPayload, *ctx.hrStartAns.DLMetaData); err != nil {
+		return errors.Wrap(err, "send handover-roaming downlink error")
+	}
+
+	return nil
+}
@@ -38,6 +38,7 @@ func StartPRFNS(ctx context.Context, rxPacket models.RXPacket, jrPL *lorawan.Joi
 
 	for _, f := range []func() error{
 		cctx.getHomeNetID,
+		cctx.handoverRoaming,
 		cctx.getNSClient,
 		cctx.startRoaming,
 		cctx.saveRoamingSession,
@@ -76,6 +77,21 @@ func (ctx *startPRFNSContext) getHomeNetID() error {
 	return nil
 }
 
+// handoverRoaming starts the handover-roaming activation in case this has
+// been configured for the home NetID. As the sNS takes over the device, the
+// passive-roaming activation is aborted.
+func (ctx *startPRFNSContext) handoverRoaming() error {
+	if !roaming.IsHandoverRoaming(ctx.homeNetID) {
+		return nil
+	}
+
+	if err := StartHRSNS(ctx.ctx, ctx.rxPacket, ctx.joinRequestPayload, ctx.homeNetID); err != nil {
+		return errors.Wrap(err, "start handover-roaming error")
+	}
+
+	return ErrAbort
+}
+
 func (ctx *startPRFNSContext) getNSClient() error {
 	client, err := roaming.GetClientForNetID(ctx.homeNetID)
 	if err != nil {
@@ -28,6 +28,7 @@ func HandleStartPRHNS(ctx context.Context, prStartPL backend.PRStartReqPayload,
 		jctx.validateNonce,
 		jctx.getRandomDevAddr,
 		jctx.getJoinAcceptFromAS,
+		jctx.setLastDevNonce,
 		jctx.sendUplinkMetaDataToNetworkController,
 		jctx.flushDeviceQueue,
 		jctx.createDeviceSession,
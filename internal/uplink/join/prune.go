@@ -0,0 +1,37 @@
+package join
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/brocaar/chirpstack-network-server/internal/helpers"
+	"github.com/brocaar/chirpstack-network-server/internal/storage"
+)
+
+// PruneEnabled returns true when the device-activation pruning is enabled.
+func PruneEnabled() bool {
+	return deviceActivationRetention > 0
+}
+
+// DeviceActivationPruneLoop starts a loop which periodically removes the
+// device-activations older than the configured retention. The loop returns
+// once the given context is cancelled.
+func DeviceActivationPruneLoop(ctx context.Context) {
+	if !PruneEnabled() {
+		return
+	}
+
+	helpers.RunLoop(ctx, "uplink/join: device-activation pruning", deviceActivationPruneInterval, func(ctx context.Context) error {
+		return pruneDeviceActivations(ctx, time.Now())
+	})
+}
+
+func pruneDeviceActivations(ctx context.Context, now time.Time) error {
+	if _, err := storage.DeleteDeviceActivationsBefore(ctx, storage.DB(), now.Add(-deviceActivationRetention)); err != nil {
+		return errors.Wrap(err, "delete device-activations error")
+	}
+
+	return nil
+}
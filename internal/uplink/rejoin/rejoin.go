@@ -13,6 +13,7 @@ import (
 
 	"github.com/brocaar/chirpstack-api/go/v3/nc"
 	"github.com/brocaar/chirpstack-network-server/internal/backend/controller"
+	"github.com/brocaar/chirpstack-network-server/internal/backend/integration"
 	"github.com/brocaar/chirpstack-network-server/internal/backend/joinserver"
 	"github.com/brocaar/chirpstack-network-server/internal/band"
 	"github.com/brocaar/chirpstack-network-server/internal/config"
@@ -54,6 +55,7 @@ var tasks = []func(*rejoinContext) error{
 	),
 	createDeviceActivation,
 	sendJoinAcceptDownlink,
+	setForceRejoinCompleted,
 }
 
 type rejoinContext struct {
@@ -171,7 +173,7 @@ func logRejoinRequestFramesCollected(ctx *rejoinContext) error {
 		return errors.Wrap(err, "create uplink frame-set error")
 	}
 
-	if err := framelog.LogUplinkFrameForDevEUI(ctx.ctx, ctx.DevEUI, uplinkFrameLog); err != nil {
+	if err := framelog.LogUplinkFrameForDevEUI(ctx.ctx, ctx.DevEUI, uplinkFrameLog, false); err != nil {
 		log.WithError(err).Error("log uplink frame for device error")
 	}
 
@@ -345,6 +347,12 @@ func sendUplinkMetaDataToNetworkController(ctx *rejoinContext) error {
 		req.PhyPayloadByteCount = uint32(len(b))
 	}
 
+	integration.PublishEvent(ctx.ctx, integration.Event{
+		Type:    integration.EventUp,
+		DevEUI:  ctx.DevEUI,
+		Message: &req,
+	})
+
 	// send async to controller
 	go func() {
 		_, err := controller.Client().HandleUplinkMetaData(ctx.ctx, &req)
@@ -393,6 +401,8 @@ func setRejoin0PendingDeviceSession(ctx *rejoinContext) error {
 		PingSlotDR:            ctx.DeviceProfile.PingSlotDR,
 		PingSlotFrequency:     int(ctx.DeviceProfile.PingSlotFreq),
 		NbTrans:               1,
+		ADRAckLimitExp:        storage.DefaultADRAckLimitExp,
+		ADRAckDelayExp:        storage.DefaultADRAckDelayExp,
 	}
 
 	if ctx.RejoinAnsPayload.AppSKey != nil {
@@ -584,6 +594,16 @@ func sendJoinAcceptDownlink(ctx *rejoinContext) error {
 	return nil
 }
 
+// setForceRejoinCompleted marks a force-rejoin (requested using the
+// ForceRejoinReq mac-command) for the device as completed.
+func setForceRejoinCompleted(ctx *rejoinContext) error {
+	if err := storage.SetForceRejoinCompleted(ctx.ctx, storage.DB(), ctx.DevEUI, int(ctx.RejoinType)); err != nil {
+		return errors.Wrap(err, "set force-rejoin completed error")
+	}
+
+	return nil
+}
+
 func errNotSupported(ctx *rejoinContext) error {
 	return fmt.Errorf("rejoin not implemented for type: %s", ctx.RejoinType)
 }
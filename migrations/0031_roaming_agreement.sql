@@ -0,0 +1,21 @@
+-- +migrate Up
+create table roaming_agreement (
+    net_id bytea primary key,
+    created_at timestamp with time zone not null,
+    updated_at timestamp with time zone not null,
+    server varchar(255) not null,
+    ca_cert text not null,
+    tls_cert text not null,
+    tls_key text not null,
+    async boolean not null,
+    async_timeout bigint not null,
+    passive_roaming boolean not null,
+    passive_roaming_lifetime bigint not null,
+    passive_roaming_kek_label varchar(100) not null,
+    handover_roaming boolean not null,
+    handover_roaming_lifetime bigint not null,
+    handover_roaming_kek_label varchar(100) not null
+);
+
+-- +migrate Down
+drop table roaming_agreement;
@@ -0,0 +1,7 @@
+-- +migrate Up
+alter table gateway
+    add column offline boolean not null default false;
+
+-- +migrate Down
+alter table gateway
+    drop column offline;
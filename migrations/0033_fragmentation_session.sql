@@ -0,0 +1,22 @@
+-- +migrate Up
+create table fragmentation_session (
+    id uuid primary key,
+    created_at timestamp with time zone not null,
+    updated_at timestamp with time zone not null,
+    multicast_group_id uuid not null references multicast_group on delete cascade,
+    frag_index smallint not null,
+    fragment_size integer not null,
+    redundancy integer not null,
+    fragment_count integer not null,
+    fragments_enqueued integer not null,
+    data bytea not null,
+    completed_at timestamp with time zone
+);
+
+create index idx_fragmentation_session_multicast_group_id on fragmentation_session(multicast_group_id);
+create index idx_fragmentation_session_completed_at on fragmentation_session(completed_at);
+
+-- +migrate Down
+drop index idx_fragmentation_session_completed_at;
+drop index idx_fragmentation_session_multicast_group_id;
+drop table fragmentation_session;
@@ -0,0 +1,9 @@
+-- +migrate Up
+alter table device_profile
+    add column adr_ack_limit_exp smallint check (adr_ack_limit_exp between 0 and 15),
+    add column adr_ack_delay_exp smallint check (adr_ack_delay_exp between 0 and 15);
+
+-- +migrate Down
+alter table device_profile
+    drop column adr_ack_delay_exp,
+    drop column adr_ack_limit_exp;
@@ -0,0 +1,20 @@
+-- +migrate Up
+create table force_rejoin (
+    dev_eui bytea primary key references device on delete cascade,
+    created_at timestamp with time zone not null,
+    updated_at timestamp with time zone not null,
+    rejoin_type smallint not null,
+    dr smallint not null,
+    max_retries smallint not null,
+    period smallint not null,
+    sent_at timestamp with time zone null,
+    completed_at timestamp with time zone null
+);
+
+create index idx_force_rejoin_created_at on force_rejoin(created_at);
+create index idx_force_rejoin_completed_at on force_rejoin(completed_at);
+
+-- +migrate Down
+drop index idx_force_rejoin_completed_at;
+drop index idx_force_rejoin_created_at;
+drop table force_rejoin;
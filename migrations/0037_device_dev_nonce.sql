@@ -0,0 +1,34 @@
+-- +migrate Up
+create table device_dev_nonce (
+    dev_eui bytea primary key references device on delete cascade,
+    updated_at timestamp with time zone not null,
+    last_dev_nonce integer not null
+);
+
+-- seed the last dev-nonce of LoRaWAN 1.1 devices
+insert into device_dev_nonce (
+    dev_eui,
+    updated_at,
+    last_dev_nonce
+)
+select
+    da.dev_eui,
+    max(da.created_at),
+    max(da.dev_nonce)
+from
+    device_activation da
+inner join device d
+    on d.dev_eui = da.dev_eui
+inner join device_profile dp
+    on dp.device_profile_id = d.device_profile_id
+where
+    dp.mac_version like '1.1%'
+    and da.join_req_type = 255
+group by
+    da.dev_eui;
+
+create index idx_device_activation_created_at on device_activation(created_at);
+
+-- +migrate Down
+drop index idx_device_activation_created_at;
+drop table device_dev_nonce;